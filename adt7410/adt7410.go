@@ -28,6 +28,9 @@ type Device struct {
 	bus     drivers.I2C
 	buf     []byte
 	Address uint8
+
+	// last value read by Update
+	temperature int32
 }
 
 // New returns ADT7410 device for the provided I2C bus using default address.
@@ -74,6 +77,20 @@ func (d *Device) ReadTempF() float32 {
 	return d.ReadTempC()*1.8 + 32.0
 }
 
+// Update reads the temperature. Use Temperature to get the value.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.Temperature != 0 {
+		d.temperature, err = d.ReadTemperature()
+	}
+	return
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
 func (d *Device) writeByte(reg uint8, data byte) {
 	d.buf[0] = reg
 	d.buf[1] = data
@@ -42,6 +42,10 @@ type Device struct {
 	powerCtl   powerCtl
 	dataFormat dataFormat
 	bwRate     bwRate
+	buf        [6]byte
+
+	// last values read by Update
+	accel [3]int32
 }
 
 // New creates a new ADXL345 connection. The I2C bus must already be
@@ -90,7 +94,10 @@ func (d *Device) Restart() {
 // and the sensor is not moving the returned value will be around 1000000 or
 // -1000000.
 func (d *Device) ReadAcceleration() (x int32, y int32, z int32, err error) {
-	rx, ry, rz := d.ReadRawAcceleration()
+	rx, ry, rz, err := d.readRawAcceleration()
+	if err != nil {
+		return 0, 0, 0, err
+	}
 
 	x = d.dataFormat.convertToIS(rx)
 	y = d.dataFormat.convertToIS(ry)
@@ -102,8 +109,15 @@ func (d *Device) ReadAcceleration() (x int32, y int32, z int32, err error) {
 // ReadRawAcceleration reads the sensor values and returns the raw x, y and z axis
 // from the adxl345.
 func (d *Device) ReadRawAcceleration() (x int32, y int32, z int32) {
+	x, y, z, _ = d.readRawAcceleration()
+	return
+}
+
+func (d *Device) readRawAcceleration() (x, y, z int32, err error) {
 	data := []byte{0, 0, 0, 0, 0, 0}
-	d.bus.ReadRegister(uint8(d.Address), REG_DATAX0, data)
+	if err := d.readRegister(REG_DATAX0, data); err != nil {
+		return 0, 0, 0, err
+	}
 
 	x = readIntLE(data[0], data[1])
 	y = readIntLE(data[2], data[3])
@@ -136,17 +150,19 @@ func (d *Device) SetRange(sensorRange Range) bool {
 	return true
 }
 
-// convertToIS adjusts the raw values from the adxl345 with the range configuration
+// convertToIS adjusts the raw values from the adxl345 with the range
+// configuration and returns them in µg. The raw values have 10 bits over the
+// full range.
 func (d *dataFormat) convertToIS(rawValue int32) int32 {
 	switch d.sensorRange {
 	case RANGE_2G:
-		return rawValue * 4 // rawValue * 2 * 1000 / 512
+		return rawValue * 15625 / 4 // rawValue * 2 * 1000000 / 512
 	case RANGE_4G:
-		return rawValue * 8 // rawValue * 4 * 1000 / 512
+		return rawValue * 15625 / 2 // rawValue * 4 * 1000000 / 512
 	case RANGE_8G:
-		return rawValue * 16 // rawValue * 8 * 1000 / 512
+		return rawValue * 15625 // rawValue * 8 * 1000000 / 512
 	case RANGE_16G:
-		return rawValue * 32 // rawValue * 16 * 1000 / 512
+		return rawValue * 31250 // rawValue * 16 * 1000000 / 512
 	default:
 		return 0
 	}
@@ -186,7 +202,39 @@ func (b *bwRate) toByte() (bits uint8) {
 	return bits
 }
 
-// readInt converts two bytes to int16
-func readIntLE(msb byte, lsb byte) int32 {
-	return int32(uint16(msb) | uint16(lsb)<<8)
+// readIntLE converts two little endian bytes to a signed value
+func readIntLE(lsb byte, msb byte) int32 {
+	return int32(int16(uint16(lsb) | uint16(msb)<<8))
+}
+
+// Update reads the acceleration. Use Acceleration to get the value.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.Acceleration != 0 {
+		d.accel[0], d.accel[1], d.accel[2], err = d.ReadAcceleration()
+	}
+	return
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *Device) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
+
+func (d *Device) writeRegister(reg, value uint8) error {
+	d.buf[0] = value
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, d.buf[:1]))
+}
+
+func (d *Device) readRegister(reg uint8, data []byte) error {
+	return drivers.NotResponding(d.bus.ReadRegister(uint8(d.Address), reg, data))
+}
+
+// updateRegister replaces the bits in mask of a register with value.
+func (d *Device) updateRegister(reg, mask, value uint8) error {
+	data := d.buf[:1]
+	if err := d.readRegister(reg, data); err != nil {
+		return err
+	}
+	return d.writeRegister(reg, data[0]&^mask|value&mask)
 }
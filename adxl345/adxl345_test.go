@@ -0,0 +1,162 @@
+package adxl345
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ drivers.Accelerometer = (*Device)(nil)
+
+func TestReadAcceleration(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(AddressLow)
+	copy(fake.Registers[REG_DATAX0:], []byte{0x00, 0xff, 0x80, 0x00, 0x00, 0x01})
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(fake.Registers[REG_BW_RATE], qt.Equals, uint8(0x1A))
+	c.Assert(fake.Registers[REG_POWER_CTL], qt.Equals, uint8(0x08))
+
+	x, y, z, err := dev.ReadAcceleration()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{-1000000, 500000, 1000000})
+}
+
+func TestTap(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(AddressLow)
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(dev.ConfigureTap(TapConfig{
+		Axes:      AXIS_Z,
+		Threshold: 3000000,
+		Duration:  10 * time.Millisecond,
+		Latency:   80 * time.Millisecond,
+		Window:    200 * time.Millisecond,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[REG_THRESH_TAP], qt.Equals, uint8(48))
+	c.Assert(fake.Registers[REG_DUR], qt.Equals, uint8(16))
+	c.Assert(fake.Registers[REG_LATENT], qt.Equals, uint8(64))
+	c.Assert(fake.Registers[REG_WINDOW], qt.Equals, uint8(160))
+	c.Assert(fake.Registers[REG_TAP_AXES], qt.Equals, uint8(0x01))
+
+	fake.Registers[REG_ACT_TAP_STATUS] = 0x40 | ASLEEP | 0x01
+	fake.Registers[REG_INT_SOURCE] = uint8(EVENT_DOUBLE_TAP | EVENT_ACTIVITY | EVENT_FIFO_WATERMARK)
+	source, err := dev.ReadEventSource()
+	c.Assert(err, qt.IsNil)
+	c.Assert(source, qt.DeepEquals, EventSource{
+		Events:       EVENT_DOUBLE_TAP | EVENT_ACTIVITY | EVENT_FIFO_WATERMARK,
+		TapAxes:      AXIS_Z,
+		ActivityAxes: AXIS_X,
+		Asleep:       true,
+	})
+}
+
+func TestActivity(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(AddressLow)
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(dev.ConfigureActivity(ActivityConfig{
+		Axes:      AXIS_X | AXIS_Y | AXIS_Z,
+		Threshold: 1000000,
+		ACCoupled: true,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[REG_THRESH_ACT], qt.Equals, uint8(16))
+	c.Assert(fake.Registers[REG_ACT_INACT_CTL], qt.Equals, uint8(0xF0))
+
+	c.Assert(dev.ConfigureInactivity(ActivityConfig{
+		Axes:      AXIS_X | AXIS_Y,
+		Threshold: 250000,
+		Time:      5 * time.Second,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[REG_THRESH_INACT], qt.Equals, uint8(4))
+	c.Assert(fake.Registers[REG_TIME_INACT], qt.Equals, uint8(5))
+	c.Assert(fake.Registers[REG_ACT_INACT_CTL], qt.Equals, uint8(0xF6))
+
+	c.Assert(dev.ConfigureFreeFall(437500, 150*time.Millisecond), qt.IsNil)
+	c.Assert(fake.Registers[REG_THRESH_FF], qt.Equals, uint8(7))
+	c.Assert(fake.Registers[REG_TIME_FF], qt.Equals, uint8(30))
+}
+
+func TestRouteInterrupts(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(AddressLow)
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(dev.RouteInterrupts(INT1, EVENT_SINGLE_TAP|EVENT_DOUBLE_TAP|EVENT_FREE_FALL), qt.IsNil)
+	c.Assert(fake.Registers[REG_INT_ENABLE], qt.Equals, uint8(0x64))
+	c.Assert(fake.Registers[REG_INT_MAP], qt.Equals, uint8(0x00))
+
+	c.Assert(dev.RouteInterrupts(INT2, EVENT_ACTIVITY|EVENT_INACTIVITY), qt.IsNil)
+	c.Assert(fake.Registers[REG_INT_ENABLE], qt.Equals, uint8(0x7C))
+	c.Assert(fake.Registers[REG_INT_MAP], qt.Equals, uint8(0x18))
+
+	// Replacing the INT1 events keeps the INT2 events.
+	c.Assert(dev.RouteInterrupts(INT1, EVENT_FREE_FALL), qt.IsNil)
+	c.Assert(fake.Registers[REG_INT_ENABLE], qt.Equals, uint8(0x1C))
+	c.Assert(fake.Registers[REG_INT_MAP], qt.Equals, uint8(0x18))
+
+	// Moving an event to INT2 removes it from INT1.
+	c.Assert(dev.RouteInterrupts(INT2, EVENT_FREE_FALL), qt.IsNil)
+	c.Assert(fake.Registers[REG_INT_ENABLE], qt.Equals, uint8(0x04))
+	c.Assert(fake.Registers[REG_INT_MAP], qt.Equals, uint8(0x1C))
+}
+
+func TestFIFO(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(AddressLow)
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(dev.ConfigureFIFO(FIFO_STREAM, 20), qt.IsNil)
+	c.Assert(fake.Registers[REG_FIFO_CTL], qt.Equals, uint8(0x94))
+
+	fake.Registers[REG_FIFO_STATUS] = FIFO_TRIG | 2
+	fake.FIFO[REG_DATAX0] = []byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+		0x00, 0xff, 0x00, 0x00, 0x00, 0x00,
+	}
+	samples := make([]FIFOSample, 4)
+	n, err := dev.ReadFIFO(samples)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 2)
+	c.Assert(samples[:n], qt.DeepEquals, []FIFOSample{
+		{0, 0, 1000000},
+		{-1000000, 0, 0},
+	})
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(AddressLow)
+
+	dev := New(bus)
+	dev.Configure()
+
+	busErr := errors.New("bus error")
+	fake.Err = busErr
+	_, err := dev.ReadEventSource()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	err = dev.ConfigureTap(TapConfig{})
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	_, _, _, err = dev.ReadAcceleration()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	err = dev.Update(drivers.Acceleration)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+}
@@ -0,0 +1,183 @@
+package adxl345
+
+import "time"
+
+// Event is a bitmask of events detected by the device.
+type Event uint8
+
+// Axis is a bitmask of the accelerometer axes.
+type Axis uint8
+
+// InterruptPin selects the INT1 or INT2 pin.
+type InterruptPin uint8
+
+// TapConfig configures single and double tap detection. Enable the taps to
+// detect with RouteInterrupts.
+type TapConfig struct {
+	// Axes enables tap detection on the given axes.
+	Axes Axis
+
+	// Threshold is the acceleration of a tap in µg, with a resolution of
+	// 62.5mg.
+	Threshold int32
+
+	// Duration is the maximum duration of a tap, with a resolution of
+	// 625µs up to 159ms. Zero disables tap detection.
+	Duration time.Duration
+
+	// Latency is the time after a tap in which no other tap is detected,
+	// and Window the time after the latency in which the second tap of a
+	// double tap must start. Both have a resolution of 1.25ms up to 318ms.
+	// Zero disables double tap detection.
+	Latency time.Duration
+	Window  time.Duration
+}
+
+// ActivityConfig configures activity or inactivity detection. Enable the
+// events with RouteInterrupts.
+type ActivityConfig struct {
+	// Axes enables detection on the given axes. Activity is detected when
+	// any of the axes exceeds the threshold, inactivity when all of them
+	// stay below it.
+	Axes Axis
+
+	// Threshold is the acceleration threshold in µg, with a resolution of
+	// 62.5mg.
+	Threshold int32
+
+	// ACCoupled compares the acceleration to the acceleration at the start
+	// of detection instead of to zero, so that the orientation of the
+	// device does not matter.
+	ACCoupled bool
+
+	// Time is the time the acceleration must stay below the threshold for
+	// inactivity, with a resolution of 1s up to 255s. It is not used for
+	// activity, which is detected immediately.
+	Time time.Duration
+}
+
+// EventSource holds the events decoded from the source registers of the
+// device.
+type EventSource struct {
+	// Events are the events that are set.
+	Events Event
+
+	// TapAxes are the axes involved in the first tap event, and
+	// ActivityAxes the axes involved in the activity event.
+	TapAxes      Axis
+	ActivityAxes Axis
+
+	// Asleep is set when the device is in sleep mode.
+	Asleep bool
+}
+
+// ConfigureTap sets up single and double tap detection.
+func (d *Device) ConfigureTap(config TapConfig) error {
+	if err := d.writeRegister(REG_THRESH_TAP, clamp(config.Threshold/62500, 1, 255)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(REG_DUR, clamp(int32(config.Duration/(625*time.Microsecond)), 0, 255)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(REG_LATENT, clamp(int32(config.Latency/(1250*time.Microsecond)), 0, 255)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(REG_WINDOW, clamp(int32(config.Window/(1250*time.Microsecond)), 0, 255)); err != nil {
+		return err
+	}
+	return d.updateRegister(REG_TAP_AXES, 0x07, uint8(config.Axes))
+}
+
+// ConfigureActivity sets up activity detection.
+func (d *Device) ConfigureActivity(config ActivityConfig) error {
+	if err := d.writeRegister(REG_THRESH_ACT, clamp(config.Threshold/62500, 0, 255)); err != nil {
+		return err
+	}
+	ctl := uint8(config.Axes&0x07) << 4
+	if config.ACCoupled {
+		ctl |= ACT_AC
+	}
+	return d.updateRegister(REG_ACT_INACT_CTL, 0xF0, ctl)
+}
+
+// ConfigureInactivity sets up inactivity detection.
+func (d *Device) ConfigureInactivity(config ActivityConfig) error {
+	if err := d.writeRegister(REG_THRESH_INACT, clamp(config.Threshold/62500, 0, 255)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(REG_TIME_INACT, clamp(int32(config.Time/time.Second), 0, 255)); err != nil {
+		return err
+	}
+	ctl := uint8(config.Axes & 0x07)
+	if config.ACCoupled {
+		ctl |= INACT_AC
+	}
+	return d.updateRegister(REG_ACT_INACT_CTL, 0x0F, ctl)
+}
+
+// ConfigureFreeFall sets up free-fall detection, which is triggered when
+// the acceleration of all axes stays below threshold, in µg, for the given
+// duration. The threshold has a resolution of 62.5mg, values between 300mg
+// and 600mg are recommended. The duration has a resolution of 5ms up to
+// 1.275s, values between 100ms and 350ms are recommended.
+func (d *Device) ConfigureFreeFall(threshold int32, duration time.Duration) error {
+	if err := d.writeRegister(REG_THRESH_FF, clamp(threshold/62500, 0, 255)); err != nil {
+		return err
+	}
+	return d.writeRegister(REG_TIME_FF, clamp(int32(duration/(5*time.Millisecond)), 0, 255))
+}
+
+// RouteInterrupts enables the events that drive the given interrupt pin,
+// replacing the events routed to it before. Each event drives a single pin,
+// routing it to one pin removes it from the other.
+func (d *Device) RouteInterrupts(pin InterruptPin, events Event) error {
+	data := d.buf[:2]
+	if err := d.readRegister(REG_INT_ENABLE, data); err != nil {
+		return err
+	}
+	enable, mapping := data[0], data[1]
+	// A set bit in REG_INT_MAP routes the event to INT2.
+	if pin == INT2 {
+		enable = enable&^mapping | uint8(events)
+		mapping |= uint8(events)
+	} else {
+		enable = enable&mapping | uint8(events)
+		mapping &^= uint8(events)
+	}
+	// Map the events before enabling them, to not trigger the wrong pin.
+	if err := d.writeRegister(REG_INT_MAP, mapping); err != nil {
+		return err
+	}
+	return d.writeRegister(REG_INT_ENABLE, enable)
+}
+
+// ReadEventSource returns the events that are currently set, with the axes
+// that caused them. Reading the event source clears the tap, activity,
+// inactivity and free-fall events. The data ready and FIFO events stay set
+// while the condition holds.
+func (d *Device) ReadEventSource() (source EventSource, err error) {
+	data := d.buf[:1]
+	// The axes must be read before the events are cleared.
+	if err := d.readRegister(REG_ACT_TAP_STATUS, data); err != nil {
+		return source, err
+	}
+	source.TapAxes = Axis(data[0] & 0x07)
+	source.ActivityAxes = Axis(data[0]>>4) & 0x07
+	source.Asleep = data[0]&ASLEEP != 0
+
+	if err := d.readRegister(REG_INT_SOURCE, data); err != nil {
+		return source, err
+	}
+	source.Events = Event(data[0])
+	return source, nil
+}
+
+func clamp(v, min, max int32) uint8 {
+	if v < min {
+		return uint8(min)
+	}
+	if v > max {
+		return uint8(max)
+	}
+	return uint8(v)
+}
@@ -0,0 +1,55 @@
+package adxl345
+
+// FIFOMode selects how the FIFO collects samples.
+type FIFOMode uint8
+
+// FIFOSample is an acceleration sample read from the FIFO, in µg.
+type FIFOSample struct {
+	X, Y, Z int32
+}
+
+// ConfigureFIFO empties the FIFO and starts collecting samples in the given
+// mode. In FIFO and stream mode, EVENT_FIFO_WATERMARK is set when the FIFO
+// holds the given number of samples. In trigger mode, samples is the number
+// of samples kept from before the trigger event. FIFO_BYPASS disables the
+// FIFO.
+func (d *Device) ConfigureFIFO(mode FIFOMode, samples uint8) error {
+	// Switching to bypass mode empties the FIFO.
+	if err := d.writeRegister(REG_FIFO_CTL, uint8(FIFO_BYPASS)); err != nil {
+		return err
+	}
+	return d.writeRegister(REG_FIFO_CTL, uint8(mode)&0xC0|samples&0x1F)
+}
+
+// FIFOCount returns the number of unread samples in the FIFO.
+func (d *Device) FIFOCount() (int, error) {
+	data := d.buf[:1]
+	if err := d.readRegister(REG_FIFO_STATUS, data); err != nil {
+		return 0, err
+	}
+	return int(data[0] & 0x3F), nil
+}
+
+// ReadFIFO reads up to len(samples) samples from the FIFO, oldest first, and
+// returns the number of samples read.
+func (d *Device) ReadFIFO(samples []FIFOSample) (n int, err error) {
+	count, err := d.FIFOCount()
+	if err != nil {
+		return 0, err
+	}
+	if count > len(samples) {
+		count = len(samples)
+	}
+	data := d.buf[:6]
+	for n = 0; n < count; n++ {
+		// Each read of the data registers pops a sample from the FIFO.
+		if err := d.readRegister(REG_DATAX0, data); err != nil {
+			return n, err
+		}
+		s := &samples[n]
+		s.X = d.dataFormat.convertToIS(readIntLE(data[0], data[1]))
+		s.Y = d.dataFormat.convertToIS(readIntLE(data[2], data[3]))
+		s.Z = d.dataFormat.convertToIS(readIntLE(data[4], data[5]))
+	}
+	return n, nil
+}
@@ -48,7 +48,7 @@ const (
 	REG_POWER_CTL      = 0x2D // R/W,   00000000,   Power-saving features control
 	REG_INT_ENABLE     = 0x2E // R/W,   00000000,   Interrupt enable control
 	REG_INT_MAP        = 0x2F // R/W,   00000000,   Interrupt mapping control
-	REG_INT_SOUCE      = 0x30 // R,     00000010,   Source of interrupts
+	REG_INT_SOURCE     = 0x30 // R,     00000010,   Source of interrupts
 	REG_DATA_FORMAT    = 0x31 // R/W,   00000000,   Data format control
 	REG_DATAX0         = 0x32 // R,     00000000,   X-Axis Data 0
 	REG_DATAX1         = 0x33 // R,     00000000,   X-Axis Data 1
@@ -58,4 +58,52 @@ const (
 	REG_DATAZ1         = 0x37 // R,     00000000,   Z-Axis Data 1
 	REG_FIFO_CTL       = 0x38 // R/W,   00000000,   FIFO control
 	REG_FIFO_STATUS    = 0x39 // R,     00000000,   FIFO status
+
+	// Deprecated: use REG_INT_SOURCE.
+	REG_INT_SOUCE = REG_INT_SOURCE
+)
+
+// Register bits.
+const (
+	ACT_AC   = 0x80 // REG_ACT_INACT_CTL
+	INACT_AC = 0x08 // REG_ACT_INACT_CTL
+
+	ASLEEP = 0x08 // REG_ACT_TAP_STATUS
+
+	FIFO_TRIG = 0x80 // REG_FIFO_STATUS
 )
+
+// Events, with the bit values of REG_INT_ENABLE, REG_INT_MAP and
+// REG_INT_SOURCE.
+const (
+	EVENT_DATA_READY     Event = 0x80
+	EVENT_SINGLE_TAP     Event = 0x40
+	EVENT_DOUBLE_TAP     Event = 0x20
+	EVENT_ACTIVITY       Event = 0x10
+	EVENT_INACTIVITY     Event = 0x08
+	EVENT_FREE_FALL      Event = 0x04
+	EVENT_FIFO_WATERMARK Event = 0x02
+	EVENT_FIFO_OVERRUN   Event = 0x01
+)
+
+// Axes, with the bit values of REG_TAP_AXES.
+const (
+	AXIS_X Axis = 0x04
+	AXIS_Y Axis = 0x02
+	AXIS_Z Axis = 0x01
+)
+
+const (
+	INT1 InterruptPin = iota
+	INT2
+)
+
+const (
+	FIFO_BYPASS  FIFOMode = 0x00
+	FIFO_FIFO    FIFOMode = 0x40 // stop collecting when full
+	FIFO_STREAM  FIFOMode = 0x80 // overwrite the oldest samples when full
+	FIFO_TRIGGER FIFOMode = 0xC0 // keep samples from before an INT1 event
+)
+
+// FIFOSize is the number of samples the FIFO holds.
+const FIFOSize = 32
@@ -0,0 +1,156 @@
+// Package ahrs implements attitude and heading reference system (AHRS)
+// filters, which fuse gyroscope, accelerometer and magnetometer readings
+// into an orientation.
+//
+// The filters take readings in the units returned by the drivers in this
+// repository: angular velocity in µ°/s, acceleration in µg and magnetic
+// field in nT. The axes of the three sensors must be aligned before they
+// are passed to a filter; some combined sensors, such as the LSM9DS1, use
+// different axes for the magnetometer.
+//
+// The filters use fixed-point integer arithmetic, since most
+// microcontrollers have no floating point unit.
+//
+// The orientation is a rotation from the sensor frame to the earth frame,
+// in which x points to magnetic north and z points up. Without a
+// magnetometer the heading is relative to the heading at start.
+//
+// Madgwick: https://x-io.co.uk/downloads/madgwick_internal_report.pdf
+//
+// Mahony: https://hal.archives-ouvertes.fr/hal-00488376/document
+package ahrs // import "tinygo.org/x/drivers/ahrs"
+
+import "time"
+
+// Filter is an AHRS filter.
+type Filter interface {
+	// Update fuses a gyroscope, accelerometer and magnetometer reading
+	// taken dt after the previous one. A zero magnetic field is ignored.
+	Update(gx, gy, gz, ax, ay, az, mx, my, mz int32, dt time.Duration)
+
+	// UpdateIMU fuses a gyroscope and accelerometer reading taken dt after
+	// the previous one.
+	UpdateIMU(gx, gy, gz, ax, ay, az int32, dt time.Duration)
+
+	// Quaternion returns the current orientation.
+	Quaternion() Quaternion
+
+	// SetQuaternion sets the current orientation, for example to the
+	// orientation returned by FromAccelMag to skip the convergence of the
+	// filter at start.
+	SetQuaternion(q Quaternion)
+}
+
+// QuaternionOne is the value of a quaternion component of 1.
+const QuaternionOne = 1 << 30
+
+// Quaternion is a unit quaternion that describes an orientation. The
+// components are fixed-point numbers with 30 fractional bits, so that
+// QuaternionOne is 1.
+type Quaternion struct {
+	W, X, Y, Z int32
+}
+
+// Identity is the orientation of a level sensor with its x axis pointing to
+// magnetic north.
+var Identity = Quaternion{W: QuaternionOne}
+
+// FromEuler returns the orientation of the given roll, pitch and yaw in µ°
+// (micro-degrees), applied in yaw, pitch, roll order.
+func FromEuler(roll, pitch, yaw int32) Quaternion {
+	return fromEuler(radians(roll), radians(pitch), radians(yaw))
+}
+
+func fromEuler(roll, pitch, yaw fixed) Quaternion {
+	sr, cr := sincos(roll / 2)
+	sp, cp := sincos(pitch / 2)
+	sy, cy := sincos(yaw / 2)
+	return quaternion(
+		mul(mul(cr, cp), cy)+mul(mul(sr, sp), sy),
+		mul(mul(sr, cp), cy)-mul(mul(cr, sp), sy),
+		mul(mul(cr, sp), cy)+mul(mul(sr, cp), sy),
+		mul(mul(cr, cp), sy)-mul(mul(sr, sp), cy),
+	)
+}
+
+// FromAccelMag returns the orientation of a sensor at rest from its
+// acceleration in µg and magnetic field in nT. A zero magnetic field
+// returns a yaw of zero.
+func FromAccelMag(ax, ay, az, mx, my, mz int32) Quaternion {
+	roll, pitch, hx, hy := tilt(ax, ay, az, mx, my, mz)
+	return fromEuler(roll, pitch, atan2(-int64(hy), int64(hx)))
+}
+
+// tilt returns the roll and pitch of a sensor at rest from its
+// acceleration, and the direction of the magnetic field rotated to the
+// horizontal plane.
+func tilt(ax, ay, az, mx, my, mz int32) (roll, pitch, hx, hy fixed) {
+	roll = atan2(int64(ay), int64(az))
+	pitch = atan2(-int64(ax), int64(isqrt(uint64(int64(ay)*int64(ay))+uint64(int64(az)*int64(az)))))
+	if mx == 0 && my == 0 && mz == 0 {
+		return roll, pitch, 0, 0
+	}
+	sr, cr := sincos(roll)
+	sp, cp := sincos(pitch)
+	fmx, fmy, fmz := normalize3(mx, my, mz)
+	hx = mul(cp, fmx) + mul(sp, mul(sr, fmy)+mul(cr, fmz))
+	hy = mul(cr, fmy) - mul(sr, fmz)
+	return roll, pitch, hx, hy
+}
+
+// Euler returns the roll, pitch and yaw of the orientation in µ°
+// (micro-degrees). Roll and yaw are between -180° and 180°, pitch is
+// between -90° and 90°. Yaw is counterclockwise from magnetic north when
+// seen from above.
+func (q Quaternion) Euler() (roll, pitch, yaw int32) {
+	// The products have 60 fractional bits.
+	w, x, y, z := int64(q.W), int64(q.X), int64(q.Y), int64(q.Z)
+	const unit = 1 << 60
+	roll = microDegrees(atan2(2*(w*x+y*z), unit-2*(x*x+y*y)))
+	sp := (2 * (w*y - z*x)) >> 30
+	if sp > QuaternionOne {
+		sp = QuaternionOne
+	} else if sp < -QuaternionOne {
+		sp = -QuaternionOne
+	}
+	pitch = microDegrees(atan2(sp, int64(isqrt(uint64(unit-sp*sp)))))
+	yaw = microDegrees(atan2(2*(w*z+x*y), unit-2*(y*y+z*z)))
+	return roll, pitch, yaw
+}
+
+// Multiply returns the rotation q followed by the rotation r in the frame
+// rotated by q.
+func (q Quaternion) Multiply(r Quaternion) Quaternion {
+	qw, qx, qy, qz := int64(q.W), int64(q.X), int64(q.Y), int64(q.Z)
+	rw, rx, ry, rz := int64(r.W), int64(r.X), int64(r.Y), int64(r.Z)
+	return Quaternion{
+		W: int32((qw*rw - qx*rx - qy*ry - qz*rz + 1<<29) >> 30),
+		X: int32((qw*rx + qx*rw + qy*rz - qz*ry + 1<<29) >> 30),
+		Y: int32((qw*ry - qx*rz + qy*rw + qz*rx + 1<<29) >> 30),
+		Z: int32((qw*rz + qx*ry - qy*rx + qz*rw + 1<<29) >> 30),
+	}
+}
+
+// Conjugate returns the inverse rotation of q.
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{W: q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+// components returns the components of q as fixed values.
+func (q Quaternion) components() (w, x, y, z fixed) {
+	const shift = 30 - fracBits
+	return fixed(q.W) >> shift, fixed(q.X) >> shift, fixed(q.Y) >> shift, fixed(q.Z) >> shift
+}
+
+// quaternion returns the orientation of the components scaled to unit
+// length. The components must not all be zero.
+func quaternion(w, x, y, z fixed) Quaternion {
+	n := hypot4(w, x, y, z)
+	const shift = 30 - fracBits
+	return Quaternion{
+		W: int32(div(w, n) << shift),
+		X: int32(div(x, n) << shift),
+		Y: int32(div(y, n) << shift),
+		Z: int32(div(z, n) << shift),
+	}
+}
@@ -0,0 +1,447 @@
+package ahrs
+
+import (
+	"bufio"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+var (
+	_ Filter = (*Madgwick)(nil)
+	_ Filter = (*Mahony)(nil)
+)
+
+// reading is a line of a sensor trace: the time since the previous reading
+// and the gyroscope (µ°/s), accelerometer (µg) and magnetometer (nT)
+// readings, in the sensor axes.
+type reading struct {
+	dt      time.Duration
+	g, a, m [3]int32
+}
+
+// The traces in testdata are synthesized by gentraces, so the true
+// orientation is known. Traces recorded with examples/ahrs/record go in
+// testdata/recorded and are checked by TestRecordedTraces.
+//go:generate go run ./internal/cmd/gentraces
+
+// readTrace reads a sensor trace. Each line holds the time since the
+// previous reading in µs followed by the nine sensor values, separated by
+// commas. Lines starting with '#' are ignored.
+func readTrace(c *qt.C, path string) []reading {
+	f, err := os.Open(path)
+	c.Assert(err, qt.IsNil)
+	defer f.Close()
+	var trace []reading
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		c.Assert(fields, qt.HasLen, 10, qt.Commentf("line %q", line))
+		var v [10]int32
+		for i, field := range fields {
+			n, err := strconv.ParseInt(strings.TrimSpace(field), 10, 32)
+			c.Assert(err, qt.IsNil)
+			v[i] = int32(n)
+		}
+		trace = append(trace, reading{
+			dt: time.Duration(v[0]) * time.Microsecond,
+			g:  [3]int32{v[1], v[2], v[3]},
+			a:  [3]int32{v[4], v[5], v[6]},
+			m:  [3]int32{v[7], v[8], v[9]},
+		})
+	}
+	c.Assert(scanner.Err(), qt.IsNil)
+	c.Assert(trace, qt.Not(qt.HasLen), 0)
+	return trace
+}
+
+// assertEuler asserts that the orientation is within tolerance µ° of the
+// given roll and pitch and within yawTolerance µ° of the given yaw.
+func assertEuler(c *qt.C, q Quaternion, roll, pitch, yaw, tolerance, yawTolerance int32) {
+	c.Helper()
+	r, p, y := q.Euler()
+	c.Assert(angleDiff(r, roll) <= tolerance, qt.IsTrue, qt.Commentf("roll %d, want %d", r, roll))
+	c.Assert(angleDiff(p, pitch) <= tolerance, qt.IsTrue, qt.Commentf("pitch %d, want %d", p, pitch))
+	c.Assert(angleDiff(y, yaw) <= yawTolerance, qt.IsTrue, qt.Commentf("yaw %d, want %d", y, yaw))
+}
+
+// angleDiff returns the absolute difference between two angles in µ°.
+func angleDiff(a, b int32) int32 {
+	d := (int64(a) - int64(b)) % 360e6
+	if d > 180e6 {
+		d -= 360e6
+	} else if d < -180e6 {
+		d += 360e6
+	}
+	if d < 0 {
+		d = -d
+	}
+	return int32(d)
+}
+
+func TestFixed(t *testing.T) {
+	c := qt.New(t)
+	for _, v := range []uint64{0, 1, 2, 3, 4, 1<<62 - 1, 1 << 62, math.MaxUint64} {
+		r := isqrt(v)
+		c.Assert(r*r <= v && (r+1)*(r+1) > v || r == math.MaxUint32, qt.IsTrue, qt.Commentf("isqrt(%d) = %d", v, r))
+	}
+	for deg := int32(-720); deg <= 720; deg += 15 {
+		a := radians(deg * 1e6)
+		sin, cos := sincos(a)
+		want := float64(deg) * math.Pi / 180
+		c.Assert(math.Abs(float64(sin)/float64(one)-math.Sin(want)) < 1e-7, qt.IsTrue, qt.Commentf("sin %d°", deg))
+		c.Assert(math.Abs(float64(cos)/float64(one)-math.Cos(want)) < 1e-7, qt.IsTrue, qt.Commentf("cos %d°", deg))
+		if deg > -180 && deg <= 180 {
+			c.Assert(angleDiff(microDegrees(atan2(int64(sin), int64(cos))), deg*1e6) <= 1, qt.IsTrue, qt.Commentf("atan2 %d°", deg))
+		}
+	}
+}
+
+func TestEuler(t *testing.T) {
+	c := qt.New(t)
+	for _, e := range [][3]int32{
+		{0, 0, 0},
+		{20e6, -10e6, 60e6},
+		{-170e6, 45e6, -135e6},
+		{90e6, 80e6, 179e6},
+	} {
+		q := FromEuler(e[0], e[1], e[2])
+		assertEuler(c, q, e[0], e[1], e[2], 10000, 10000)
+	}
+
+	// A rotation about z followed by a rotation about the rotated x axis.
+	q := FromEuler(0, 0, 90e6).Multiply(FromEuler(45e6, 0, 0))
+	assertEuler(c, q, 45e6, 0, 90e6, 10000, 10000)
+	assertEuler(c, q.Multiply(q.Conjugate()), 0, 0, 0, 10000, 10000)
+}
+
+func TestFromAccelMag(t *testing.T) {
+	c := qt.New(t)
+	trace := readTrace(c, "testdata/rest.csv")
+	r := trace[0]
+	q := FromAccelMag(r.a[0], r.a[1], r.a[2], r.m[0], r.m[1], r.m[2])
+	assertEuler(c, q, 20e6, -10e6, 60e6, 1e6, 2e6)
+
+	// Without a magnetometer the yaw is zero.
+	q = FromAccelMag(r.a[0], r.a[1], r.a[2], 0, 0, 0)
+	assertEuler(c, q, 20e6, -10e6, 0, 1e6, 1)
+}
+
+func TestBiasEstimator(t *testing.T) {
+	c := qt.New(t)
+	var b BiasEstimator
+	_, _, _, ok := b.Bias()
+	c.Assert(ok, qt.IsFalse)
+
+	windows := 0
+	for _, r := range readTrace(c, "testdata/rest.csv") {
+		if b.Update(r.g[0], r.g[1], r.g[2], r.a[0], r.a[1], r.a[2]) {
+			windows++
+		}
+	}
+	c.Assert(windows, qt.Equals, 10)
+	x, y, z, ok := b.Bias()
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(angleDiff(x, 400000) < 10000, qt.IsTrue, qt.Commentf("x %d", x))
+	c.Assert(angleDiff(y, -250000) < 10000, qt.IsTrue, qt.Commentf("y %d", y))
+	c.Assert(angleDiff(z, 150000) < 10000, qt.IsTrue, qt.Commentf("z %d", z))
+	x, y, z = b.Correct(400000, -250000, 150000)
+	c.Assert(angleDiff(x, 0) < 10000 && angleDiff(y, 0) < 10000 && angleDiff(z, 0) < 10000, qt.IsTrue)
+
+	// Readings taken while rotating are not used.
+	b = BiasEstimator{}
+	windows = 0
+	for i, r := range readTrace(c, "testdata/motion.csv") {
+		if b.Update(r.g[0], r.g[1], r.g[2], r.a[0], r.a[1], r.a[2]) {
+			windows++
+			c.Assert(i%100, qt.Equals, 99, qt.Commentf("window ending at reading %d", i))
+		}
+	}
+	c.Assert(windows, qt.Equals, 4)
+	x, y, z, _ = b.Bias()
+	c.Assert(angleDiff(x, 200000) < 10000, qt.IsTrue, qt.Commentf("x %d", x))
+	c.Assert(angleDiff(y, -150000) < 10000, qt.IsTrue, qt.Commentf("y %d", y))
+	c.Assert(angleDiff(z, 100000) < 10000, qt.IsTrue, qt.Commentf("z %d", z))
+}
+
+// replay feeds a trace to a filter, removing the gyroscope bias estimated
+// at rest.
+func replay(c *qt.C, f Filter, path string, useMag bool) {
+	var b BiasEstimator
+	for _, r := range readTrace(c, path) {
+		b.Update(r.g[0], r.g[1], r.g[2], r.a[0], r.a[1], r.a[2])
+		gx, gy, gz := b.Correct(r.g[0], r.g[1], r.g[2])
+		if useMag {
+			f.Update(gx, gy, gz, r.a[0], r.a[1], r.a[2], r.m[0], r.m[1], r.m[2], r.dt)
+		} else {
+			f.UpdateIMU(gx, gy, gz, r.a[0], r.a[1], r.a[2], r.dt)
+		}
+	}
+}
+
+func TestMadgwick(t *testing.T) {
+	c := qt.New(t)
+
+	// The filter converges from the identity orientation.
+	f := NewMadgwick(500)
+	replay(c, f, "testdata/rest.csv", true)
+	assertEuler(c, f.Quaternion(), 20e6, -10e6, 60e6, 1e6, 1e6)
+
+	// The filter tracks a rotation about z followed by a rotation about
+	// the rotated x axis.
+	f = NewMadgwick(100)
+	replay(c, f, "testdata/motion.csv", true)
+	assertEuler(c, f.Quaternion(), 45e6, 0, 90e6, 2e6, 2e6)
+
+	// Without a magnetometer the yaw follows the gyroscope.
+	f = NewMadgwick(100)
+	replay(c, f, "testdata/motion.csv", false)
+	assertEuler(c, f.Quaternion(), 45e6, 0, 90e6, 2e6, 5e6)
+}
+
+func TestMahony(t *testing.T) {
+	c := qt.New(t)
+
+	// The magnetometer feedback converges slowly from far off, start at the
+	// orientation of the first reading.
+	trace := readTrace(c, "testdata/rest.csv")
+	r := trace[0]
+	f := NewMahony(1000, 0)
+	f.SetQuaternion(FromAccelMag(r.a[0], r.a[1], r.a[2], r.m[0], r.m[1], r.m[2]))
+	replay(c, f, "testdata/rest.csv", true)
+	assertEuler(c, f.Quaternion(), 20e6, -10e6, 60e6, 1e6, 1e6)
+
+	f = NewMahony(500, 0)
+	replay(c, f, "testdata/motion.csv", true)
+	assertEuler(c, f.Quaternion(), 45e6, 0, 90e6, 2e6, 2e6)
+
+	f = NewMahony(500, 0)
+	replay(c, f, "testdata/motion.csv", false)
+	assertEuler(c, f.Quaternion(), 45e6, 0, 90e6, 2e6, 5e6)
+
+	// The integral feedback estimates the gyroscope bias.
+	f = NewMahony(1000, 1000)
+	f.SetQuaternion(FromAccelMag(r.a[0], r.a[1], r.a[2], r.m[0], r.m[1], r.m[2]))
+	for _, r := range trace {
+		f.Update(r.g[0], r.g[1], r.g[2], r.a[0], r.a[1], r.a[2], r.m[0], r.m[1], r.m[2], r.dt)
+	}
+	x, y, z := f.GyroBias()
+	c.Assert(angleDiff(x, 400000) < 50000, qt.IsTrue, qt.Commentf("x %d", x))
+	c.Assert(angleDiff(y, -250000) < 50000, qt.IsTrue, qt.Commentf("y %d", y))
+	c.Assert(angleDiff(z, 150000) < 50000, qt.IsTrue, qt.Commentf("z %d", z))
+	assertEuler(c, f.Quaternion(), 20e6, -10e6, 60e6, 1e6, 1e6)
+}
+
+func eulerToQuaternion(roll, pitch, yaw float64) (w, x, y, z float64) {
+	sr, cr := math.Sincos(roll * math.Pi / 360)
+	sp, cp := math.Sincos(pitch * math.Pi / 360)
+	sy, cy := math.Sincos(yaw * math.Pi / 360)
+	return cr*cp*cy + sr*sp*sy, sr*cp*cy - cr*sp*sy, cr*sp*cy + sr*cp*sy, cr*cp*sy - sr*sp*cy
+}
+
+func multiply(aw, ax, ay, az, bw, bx, by, bz float64) (w, x, y, z float64) {
+	return aw*bw - ax*bx - ay*by - az*bz,
+		aw*bx + ax*bw + ay*bz - az*by,
+		aw*by - ax*bz + ay*bw + az*bx,
+		aw*bz + ax*by - ay*bx + az*bw
+}
+
+// rotateToSensor returns the vector v in the earth frame in the sensor
+// frame of the orientation q.
+func rotateToSensor(w, x, y, z float64, v [3]float64) [3]float64 {
+	tw, tx, ty, tz := multiply(w, -x, -y, -z, 0, v[0], v[1], v[2])
+	_, rx, ry, rz := multiply(tw, tx, ty, tz, w, x, y, z)
+	return [3]float64{rx, ry, rz}
+}
+
+// distortedField returns magnetometer readings in nT of a sensor rotated
+// through random orientations in a 48µT field, distorted by the soft-iron
+// matrix and hard-iron offset, with ±100nT of noise.
+func distortedField(n int, soft [3][3]float64, offset [3]float64) [][3]int32 {
+	rnd := rand.New(rand.NewSource(1))
+	readings := make([][3]int32, n)
+	for i := range readings {
+		var v [3]float64
+		for {
+			v = [3]float64{rnd.Float64()*2 - 1, rnd.Float64()*2 - 1, rnd.Float64()*2 - 1}
+			if l := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2]); l > 0.1 && l <= 1 {
+				v = [3]float64{v[0] / l * 48000, v[1] / l * 48000, v[2] / l * 48000}
+				break
+			}
+		}
+		for j, row := range soft {
+			d := row[0]*v[0] + row[1]*v[1] + row[2]*v[2] + offset[j] + rnd.Float64()*200 - 100
+			readings[i][j] = int32(d)
+		}
+	}
+	return readings
+}
+
+func TestMagCalibrator(t *testing.T) {
+	c := qt.New(t)
+	soft := [3][3]float64{
+		{1.25, 0.1, -0.05},
+		{0.1, 0.85, 0.08},
+		{-0.05, 0.08, 1.05},
+	}
+	offset := [3]float64{12000, -8000, 3500}
+
+	var cal MagCalibrator
+	_, err := cal.Fit()
+	c.Assert(err, qt.Equals, errCalibrationSamples)
+	for _, r := range distortedField(500, soft, offset) {
+		cal.Add(r[0], r[1], r[2])
+	}
+	c.Assert(cal.Samples(), qt.Equals, 500)
+	result, err := cal.Fit()
+	c.Assert(err, qt.IsNil)
+	for i := range offset {
+		c.Assert(math.Abs(float64(result.Offset[i])-offset[i]) < 100, qt.IsTrue, qt.Commentf("offset %v", result.Offset))
+	}
+
+	// The corrected readings lie on a sphere.
+	for _, r := range distortedField(100, soft, offset) {
+		x, y, z := result.Apply(r[0], r[1], r[2])
+		l := math.Sqrt(float64(x)*float64(x) + float64(y)*float64(y) + float64(z)*float64(z))
+		c.Assert(math.Abs(l-float64(result.Field)) < 0.01*float64(result.Field), qt.IsTrue, qt.Commentf("length %f, field %d", l, result.Field))
+	}
+
+	// The heading of a level sensor is correct after calibration, and wrong
+	// without it.
+	var worst int32
+	for yaw := 0; yaw < 360; yaw += 15 {
+		w, qx, qy, qz := eulerToQuaternion(0, 0, float64(yaw))
+		m := rotateToSensor(w, qx, qy, qz, [3]float64{20000, 0, -45000})
+		var r [3]int32
+		for j, row := range soft {
+			r[j] = int32(row[0]*m[0] + row[1]*m[1] + row[2]*m[2] + offset[j])
+		}
+		want := int32((360 - yaw) % 360 * 1e6)
+		x, y, z := result.Apply(r[0], r[1], r[2])
+		h := Heading(0, 0, 1e6, x, y, z)
+		c.Assert(angleDiff(h, want) < 1e6, qt.IsTrue, qt.Commentf("yaw %d: heading %d", yaw, h))
+		if d := angleDiff(Heading(0, 0, 1e6, r[0], r[1], r[2]), want); d > worst {
+			worst = d
+		}
+	}
+	c.Assert(worst > 10e6, qt.IsTrue)
+
+	hardIron, err := cal.FitHardIron()
+	c.Assert(err, qt.IsNil)
+	c.Assert(hardIron.Matrix, qt.Equals, NoCalibration.Matrix)
+	for i := range offset {
+		c.Assert(math.Abs(float64(hardIron.Offset[i])-offset[i]) < 2000, qt.IsTrue, qt.Commentf("offset %v", hardIron.Offset))
+	}
+
+	// Readings in a plane do not describe an ellipsoid.
+	cal.Reset()
+	for i := 0; i < 100; i++ {
+		s, co := math.Sincos(float64(i) * math.Pi / 50)
+		cal.Add(int32(40000*co), int32(40000*s), -20000)
+	}
+	_, err = cal.Fit()
+	c.Assert(err, qt.Equals, errCalibrationFit)
+}
+
+func TestMagCalibrationBinary(t *testing.T) {
+	c := qt.New(t)
+	cal := MagCalibration{
+		Offset: [3]int32{12000, -8000, 3500},
+		Matrix: [3][3]float32{{0.8, -0.1, 0.05}, {-0.1, 1.2, -0.09}, {0.05, -0.09, 0.95}},
+		Field:  47500,
+	}
+	data, err := cal.MarshalBinary()
+	c.Assert(err, qt.IsNil)
+	c.Assert(data, qt.HasLen, MagCalibrationSize)
+
+	var restored MagCalibration
+	c.Assert(restored.UnmarshalBinary(data), qt.IsNil)
+	c.Assert(restored, qt.Equals, cal)
+
+	// Corrupted data or erased storage is rejected.
+	data[10] ^= 1
+	c.Assert(restored.UnmarshalBinary(data), qt.Equals, errCalibrationFormat)
+	erased := make([]byte, MagCalibrationSize)
+	for i := range erased {
+		erased[i] = 0xff
+	}
+	c.Assert(restored.UnmarshalBinary(erased), qt.Equals, errCalibrationFormat)
+	c.Assert(restored.UnmarshalBinary(data[:20]), qt.Equals, errCalibrationFormat)
+	c.Assert(restored, qt.Equals, cal)
+}
+
+func TestHeading(t *testing.T) {
+	c := qt.New(t)
+	field := [3]float64{20000, 0, -45000}
+	for _, e := range [][3]float64{
+		{0, 0, 0},
+		{0, 0, -90},
+		{30, -20, -135},
+		{-40, 35, 100},
+	} {
+		w, x, y, z := eulerToQuaternion(e[0], e[1], e[2])
+		a := rotateToSensor(w, x, y, z, [3]float64{0, 0, 1e6})
+		m := rotateToSensor(w, x, y, z, field)
+		h := Heading(int32(a[0]), int32(a[1]), int32(a[2]), int32(m[0]), int32(m[1]), int32(m[2]))
+		want := int32(math.Mod(360-e[2], 360) * 1e6)
+		c.Assert(h >= 0 && h < 360e6, qt.IsTrue)
+		c.Assert(angleDiff(h, want) < 1000, qt.IsTrue, qt.Commentf("%v: heading %d, want %d", e, h, want))
+	}
+}
+
+// traceOrientation returns the final orientation noted in a recorded trace
+// by a comment such as "# orientation: 0, 0, 90", in degrees.
+func traceOrientation(c *qt.C, path string) (roll, pitch, yaw int32) {
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, qt.IsNil)
+	for _, line := range strings.Split(string(data), "\n") {
+		const prefix = "# orientation:"
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		fields := strings.Split(line[len(prefix):], ",")
+		c.Assert(fields, qt.HasLen, 3, qt.Commentf("line %q", line))
+		var v [3]int32
+		for i, field := range fields {
+			n, err := strconv.ParseInt(strings.TrimSpace(field), 10, 32)
+			c.Assert(err, qt.IsNil)
+			v[i] = int32(n) * 1e6
+		}
+		return v[0], v[1], v[2]
+	}
+	c.Fatalf("%s: no orientation comment", path)
+	return
+}
+
+func TestRecordedTraces(t *testing.T) {
+	c := qt.New(t)
+	paths, err := filepath.Glob("testdata/recorded/*.csv")
+	c.Assert(err, qt.IsNil)
+	if len(paths) == 0 {
+		c.Skip("no recorded traces in testdata/recorded")
+	}
+	for _, path := range paths {
+		roll, pitch, yaw := traceOrientation(c, path)
+		// The orientation of a board placed by hand is known to a few
+		// degrees.
+		r := readTrace(c, path)[0]
+		start := FromAccelMag(r.a[0], r.a[1], r.a[2], r.m[0], r.m[1], r.m[2])
+		for _, f := range []Filter{NewMadgwick(100), NewMahony(500, 0)} {
+			f.SetQuaternion(start)
+			replay(c, f, path, true)
+			assertEuler(c, f.Quaternion(), roll, pitch, yaw, 5e6, 10e6)
+		}
+	}
+}
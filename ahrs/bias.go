@@ -0,0 +1,112 @@
+package ahrs
+
+// BiasEstimator estimates the gyroscope bias from readings taken while the
+// device is at rest. It uses integer arithmetic only.
+//
+// The device is considered at rest while neither the angular velocity nor
+// the acceleration of any axis varies more than a threshold, and the
+// angular velocity is not larger than the largest expected bias. The bias
+// is the average angular velocity of a window of readings at rest, and is
+// updated after every such window. A slow rotation at a constant rate
+// cannot be told apart from rest.
+type BiasEstimator struct {
+	// Samples is the number of readings at rest averaged into an
+	// estimate. Zero uses 100.
+	Samples int
+
+	// GyroThreshold is the maximum variation of the angular velocity of
+	// an axis at rest in µ°/s. Zero uses 1°/s.
+	GyroThreshold int32
+
+	// AccelThreshold is the maximum variation of the acceleration of an
+	// axis at rest in µg. Zero uses 50mg.
+	AccelThreshold int32
+
+	// MaxBias is the largest expected gyroscope bias of an axis in µ°/s.
+	// Zero uses 10°/s.
+	MaxBias int32
+
+	bias  [3]int32
+	valid bool
+
+	// The window of readings at rest.
+	n        int
+	sum      [3]int64
+	min, max [6]int32
+}
+
+// Update adds a gyroscope and accelerometer reading. It returns true when
+// the reading completes a window at rest and the bias was updated.
+func (b *BiasEstimator) Update(gx, gy, gz, ax, ay, az int32) bool {
+	v := [6]int32{gx, gy, gz, ax, ay, az}
+	gyroThreshold, accelThreshold := b.GyroThreshold, b.AccelThreshold
+	if gyroThreshold == 0 {
+		gyroThreshold = 1000000
+	}
+	if accelThreshold == 0 {
+		accelThreshold = 50000
+	}
+	maxBias := b.MaxBias
+	if maxBias == 0 {
+		maxBias = 10000000
+	}
+	for _, g := range v[:3] {
+		if g > maxBias || g < -maxBias {
+			// The device is rotating.
+			b.n = 0
+			return false
+		}
+	}
+	for i := range v {
+		threshold := gyroThreshold
+		if i >= 3 {
+			threshold = accelThreshold
+		}
+		if b.n > 0 && (v[i]-b.min[i] > threshold || b.max[i]-v[i] > threshold) {
+			// The device moved, start a new window.
+			b.n = 0
+		}
+	}
+	if b.n == 0 {
+		b.sum = [3]int64{}
+		b.min, b.max = v, v
+	}
+	for i := range v {
+		if v[i] < b.min[i] {
+			b.min[i] = v[i]
+		}
+		if v[i] > b.max[i] {
+			b.max[i] = v[i]
+		}
+	}
+	for i := 0; i < 3; i++ {
+		b.sum[i] += int64(v[i])
+	}
+	b.n++
+
+	samples := b.Samples
+	if samples <= 0 {
+		samples = 100
+	}
+	if b.n < samples {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		b.bias[i] = int32(b.sum[i] / int64(b.n))
+	}
+	b.valid = true
+	b.n = 0
+	return true
+}
+
+// Bias returns the estimated gyroscope bias in µ°/s, and whether a window
+// at rest has been seen yet.
+func (b *BiasEstimator) Bias() (x, y, z int32, ok bool) {
+	return b.bias[0], b.bias[1], b.bias[2], b.valid
+}
+
+// Correct returns the gyroscope reading in µ°/s with the estimated bias
+// removed.
+func (b *BiasEstimator) Correct(gx, gy, gz int32) (x, y, z int32) {
+	return gx - b.bias[0], gy - b.bias[1], gz - b.bias[2]
+}
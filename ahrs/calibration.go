@@ -0,0 +1,372 @@
+package ahrs
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var (
+	errCalibrationSamples = errors.New("ahrs: not enough magnetometer readings to calibrate")
+	errCalibrationFit     = errors.New("ahrs: magnetometer readings do not describe an ellipsoid")
+	errCalibrationFormat  = errors.New("ahrs: invalid magnetometer calibration data")
+)
+
+// MagCalibrationSize is the size in bytes of a serialized MagCalibration.
+const MagCalibrationSize = 58
+
+// minCalibrationSamples is the smallest number of readings fitted. The fit
+// has 9 unknowns, but needs many more readings to average out noise.
+const minCalibrationSamples = 20
+
+// calibrationScale converts nT to µT, which keeps the sums of the least
+// squares fit well within the precision of a float64.
+const calibrationScale = 1e-3
+
+// MagCalibration corrects the hard-iron and soft-iron distortion of a
+// magnetometer. Hard-iron distortion, caused by magnetized parts near the
+// sensor, adds a constant offset to all readings. Soft-iron distortion,
+// caused by ferromagnetic parts near the sensor and by differences in the
+// gain of the axes, stretches the sphere of readings of a rotated sensor
+// into an ellipsoid.
+//
+// A MagCalibration can be stored, for example in an at24cx EEPROM or in
+// flash, with MarshalBinary and restored with UnmarshalBinary. The zero
+// value is not a valid calibration; use NoCalibration instead.
+type MagCalibration struct {
+	// Offset is the hard-iron offset in nT, which is subtracted from the
+	// readings.
+	Offset [3]int32
+
+	// Matrix is the soft-iron correction, which is applied to the readings
+	// after the offset is subtracted.
+	Matrix [3][3]float32
+
+	// Field is the strength in nT of the magnetic field the readings were
+	// fitted to, which is the length of every corrected reading.
+	Field int32
+}
+
+// NoCalibration is a MagCalibration that does not change the readings.
+var NoCalibration = MagCalibration{
+	Matrix: [3][3]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+}
+
+// Apply returns the corrected magnetic field in nT of a reading in nT.
+func (c *MagCalibration) Apply(x, y, z int32) (int32, int32, int32) {
+	v := [3]float32{
+		float32(x - c.Offset[0]),
+		float32(y - c.Offset[1]),
+		float32(z - c.Offset[2]),
+	}
+	var r [3]int32
+	for i, row := range c.Matrix {
+		r[i] = int32(row[0]*v[0] + row[1]*v[1] + row[2]*v[2])
+	}
+	return r[0], r[1], r[2]
+}
+
+// MarshalBinary returns the calibration as MagCalibrationSize bytes: a
+// 4-byte header, the offset and field as little endian int32 values, the
+// matrix as little endian float32 values in row order and a Fletcher-16
+// checksum.
+func (c *MagCalibration) MarshalBinary() ([]byte, error) {
+	data := make([]byte, MagCalibrationSize)
+	copy(data, calibrationHeader[:])
+	for i, v := range c.Offset {
+		binary.LittleEndian.PutUint32(data[4+i*4:], uint32(v))
+	}
+	binary.LittleEndian.PutUint32(data[16:], uint32(c.Field))
+	for i, row := range c.Matrix {
+		for j, v := range row {
+			binary.LittleEndian.PutUint32(data[20+(i*3+j)*4:], math.Float32bits(v))
+		}
+	}
+	binary.LittleEndian.PutUint16(data[56:], fletcher16(data[:56]))
+	return data, nil
+}
+
+// UnmarshalBinary restores a calibration stored by MarshalBinary. It
+// returns an error if the data is not a calibration, for example because
+// the EEPROM or flash was never written.
+func (c *MagCalibration) UnmarshalBinary(data []byte) error {
+	if len(data) != MagCalibrationSize || string(data[:4]) != string(calibrationHeader[:]) ||
+		binary.LittleEndian.Uint16(data[56:]) != fletcher16(data[:56]) {
+		return errCalibrationFormat
+	}
+	var r MagCalibration
+	for i := range r.Offset {
+		r.Offset[i] = int32(binary.LittleEndian.Uint32(data[4+i*4:]))
+	}
+	r.Field = int32(binary.LittleEndian.Uint32(data[16:]))
+	for i := range r.Matrix {
+		for j := range r.Matrix[i] {
+			v := math.Float32frombits(binary.LittleEndian.Uint32(data[20+(i*3+j)*4:]))
+			if f := float64(v); math.IsNaN(f) || math.IsInf(f, 0) {
+				return errCalibrationFormat
+			}
+			r.Matrix[i][j] = v
+		}
+	}
+	*c = r
+	return nil
+}
+
+// calibrationHeader starts a serialized MagCalibration: a magic number and
+// the version of the format.
+var calibrationHeader = [4]byte{'M', 'C', 1, 0}
+
+// fletcher16 returns the Fletcher-16 checksum of data.
+func fletcher16(data []byte) uint16 {
+	var a, b uint16
+	for _, v := range data {
+		a = (a + uint16(v)) % 255
+		b = (b + a) % 255
+	}
+	return b<<8 | a
+}
+
+// MagCalibrator fits a MagCalibration to magnetometer readings taken while
+// the device is slowly rotated through as many orientations as possible,
+// for example by drawing a figure eight in the air while turning it. The
+// readings must be taken in the final mounting position, since the parts
+// around the sensor cause the distortion.
+//
+// The readings are not stored; a MagCalibrator uses a fixed amount of
+// memory however many readings are added.
+type MagCalibrator struct {
+	n        int
+	ata      [9][9]float64
+	atb      [9]float64
+	min, max [3]int32
+}
+
+// Add adds a magnetometer reading in nT.
+func (c *MagCalibrator) Add(x, y, z int32) {
+	v := [3]int32{x, y, z}
+	if c.n == 0 {
+		c.min, c.max = v, v
+	}
+	for i := range v {
+		if v[i] < c.min[i] {
+			c.min[i] = v[i]
+		}
+		if v[i] > c.max[i] {
+			c.max[i] = v[i]
+		}
+	}
+	fx, fy, fz := float64(x)*calibrationScale, float64(y)*calibrationScale, float64(z)*calibrationScale
+	row := [9]float64{fx * fx, fy * fy, fz * fz, 2 * fx * fy, 2 * fx * fz, 2 * fy * fz, 2 * fx, 2 * fy, 2 * fz}
+	for i := range row {
+		for j := i; j < len(row); j++ {
+			c.ata[i][j] += row[i] * row[j]
+		}
+		c.atb[i] += row[i]
+	}
+	c.n++
+}
+
+// Samples returns the number of readings added.
+func (c *MagCalibrator) Samples() int {
+	return c.n
+}
+
+// Reset removes all readings.
+func (c *MagCalibrator) Reset() {
+	*c = MagCalibrator{}
+}
+
+// Fit returns the calibration that maps the readings onto a sphere. It
+// fits an ellipsoid to the readings, whose center is the hard-iron offset
+// and whose shape is the soft-iron distortion. The radius of the sphere is
+// chosen so that the ellipsoid and the sphere have the same volume.
+//
+// Fit returns an error if the readings do not cover enough orientations,
+// in which case FitHardIron may still work.
+func (c *MagCalibrator) Fit() (MagCalibration, error) {
+	if c.n < minCalibrationSamples {
+		return MagCalibration{}, errCalibrationSamples
+	}
+
+	// Solve the normal equations of the least squares fit of
+	//   Ax² + By² + Cz² + 2Dxy + 2Exz + 2Fyz + 2Gx + 2Hy + 2Iz = 1
+	var a [9][9]float64
+	for i := range a {
+		for j := range a[i] {
+			if j >= i {
+				a[i][j] = c.ata[i][j]
+			} else {
+				a[i][j] = c.ata[j][i]
+			}
+		}
+	}
+	p, ok := solve(a, c.atb)
+	if !ok {
+		return MagCalibration{}, errCalibrationFit
+	}
+	m := [3][3]float64{
+		{p[0], p[3], p[4]},
+		{p[3], p[1], p[5]},
+		{p[4], p[5], p[2]},
+	}
+	values, vectors := eigen(m)
+	for _, v := range values {
+		if !(v > 0) {
+			return MagCalibration{}, errCalibrationFit
+		}
+	}
+
+	// The center is -M⁻¹v, and the ellipsoid is (x-c)ᵀM(x-c) = 1 + cᵀMc.
+	var center [3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			var inv float64
+			for k := 0; k < 3; k++ {
+				inv += vectors[i][k] * vectors[j][k] / values[k]
+			}
+			center[i] -= inv * p[6+j]
+		}
+	}
+	scale := 1.0
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			scale += center[i] * m[i][j] * center[j]
+		}
+	}
+	if !(scale > 0) {
+		return MagCalibration{}, errCalibrationFit
+	}
+
+	// The correction is the square root of M/scale, which maps the
+	// ellipsoid onto the unit sphere, times the radius of the sphere.
+	radius := 1.0
+	for k := range values {
+		values[k] /= scale
+		radius /= math.Sqrt(values[k])
+	}
+	radius = math.Cbrt(radius)
+	var r MagCalibration
+	for i := 0; i < 3; i++ {
+		r.Offset[i] = int32(math.Round(center[i] / calibrationScale))
+		for j := 0; j < 3; j++ {
+			var v float64
+			for k := 0; k < 3; k++ {
+				v += vectors[i][k] * vectors[j][k] * math.Sqrt(values[k])
+			}
+			r.Matrix[i][j] = float32(v * radius)
+		}
+	}
+	r.Field = int32(math.Round(radius / calibrationScale))
+	return r, nil
+}
+
+// FitHardIron returns a calibration that only corrects the hard-iron
+// offset, which is the middle of the smallest and largest reading of each
+// axis. It needs the device to be rotated a full turn around at least two
+// axes, but fewer orientations than Fit.
+func (c *MagCalibrator) FitHardIron() (MagCalibration, error) {
+	if c.n < minCalibrationSamples {
+		return MagCalibration{}, errCalibrationSamples
+	}
+	r := NoCalibration
+	var radius int64
+	for i := range r.Offset {
+		r.Offset[i] = int32((int64(c.min[i]) + int64(c.max[i])) / 2)
+		radius += (int64(c.max[i]) - int64(c.min[i])) / 2
+	}
+	r.Field = int32(radius / 3)
+	return r, nil
+}
+
+// solve solves the linear system ax = b with Gaussian elimination. It
+// returns false if the system has no unique solution.
+func solve(a [9][9]float64, b [9]float64) ([9]float64, bool) {
+	const n = len(b)
+	var largest float64
+	for i := range a {
+		largest = math.Max(largest, math.Abs(a[i][i]))
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if !(math.Abs(a[pivot][col]) > largest*1e-12) {
+			return b, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+		for row := col + 1; row < n; row++ {
+			f := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+			b[row] -= f * b[col]
+		}
+	}
+	var x [9]float64
+	for row := n - 1; row >= 0; row-- {
+		v := b[row]
+		for k := row + 1; k < n; k++ {
+			v -= a[row][k] * x[k]
+		}
+		x[row] = v / a[row][row]
+	}
+	return x, true
+}
+
+// eigen returns the eigenvalues and eigenvectors, as the columns of a
+// matrix, of a symmetric matrix using the Jacobi eigenvalue algorithm.
+func eigen(m [3][3]float64) (values [3]float64, vectors [3][3]float64) {
+	vectors = [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	for sweep := 0; sweep < 50; sweep++ {
+		off := m[0][1]*m[0][1] + m[0][2]*m[0][2] + m[1][2]*m[1][2]
+		if off == 0 {
+			break
+		}
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+				// Rotate the plane of p and q to zero m[p][q].
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				cs := 1 / math.Sqrt(t*t+1)
+				sn := t * cs
+				for k := 0; k < 3; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p] = cs*mkp - sn*mkq
+					m[k][q] = sn*mkp + cs*mkq
+				}
+				for k := 0; k < 3; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k] = cs*mpk - sn*mqk
+					m[q][k] = sn*mpk + cs*mqk
+				}
+				for k := 0; k < 3; k++ {
+					vkp, vkq := vectors[k][p], vectors[k][q]
+					vectors[k][p] = cs*vkp - sn*vkq
+					vectors[k][q] = sn*vkp + cs*vkq
+				}
+			}
+		}
+	}
+	return [3]float64{m[0][0], m[1][1], m[2][2]}, vectors
+}
+
+// Heading returns the compass heading of a sensor at rest from its
+// acceleration in µg and its calibrated magnetic field in nT. The heading
+// is the direction the x axis of the sensor points to in µ°
+// (micro-degrees), clockwise from magnetic north, between 0° and 360°. The
+// tilt of the sensor is compensated, so it need not be level.
+func Heading(ax, ay, az, mx, my, mz int32) int32 {
+	_, _, hx, hy := tilt(ax, ay, az, mx, my, mz)
+	h := microDegrees(atan2(int64(hy), int64(hx)))
+	if h < 0 {
+		h += 360000000
+	}
+	return h
+}
@@ -0,0 +1,173 @@
+package ahrs
+
+// fixed is a fixed-point number with fracBits fractional bits. The filters
+// use it instead of floating point, which most microcontrollers emulate in
+// software. Products of two values fit as long as their magnitude is below
+// 2^(63-2*fracBits).
+type fixed int64
+
+const (
+	fracBits       = 28
+	one      fixed = 1 << fracBits
+
+	pi     fixed = 843314857 // π << fracBits
+	halfPi fixed = 421657428 // π/2 << fracBits
+)
+
+// mul returns a*b.
+func mul(a, b fixed) fixed {
+	return a * b >> fracBits
+}
+
+// div returns a/b. The magnitude of a must be below 2^(63-2*fracBits).
+func div(a, b fixed) fixed {
+	return (a << fracBits) / b
+}
+
+// fromMilli converts thousandths to fixed.
+func fromMilli(v int32) fixed {
+	return fixed(v) << fracBits / 1000
+}
+
+// scaleTime returns v multiplied by dt in µs, for example the change of an
+// angle over dt at the rate v.
+func scaleTime(v fixed, dt int64) fixed {
+	return v * fixed(dt) / 1000000
+}
+
+// radians converts µ° (micro-degrees) to radians.
+func radians(v int32) fixed {
+	// 78602642 is π/180e6 << 52.
+	return fixed(int64(v) * 78602642 >> (52 - fracBits))
+}
+
+// microDegrees converts radians to µ°, rounded to the nearest value.
+func microDegrees(v fixed) int32 {
+	// 916732472 is 180e6/π << (32-fracBits).
+	return int32((int64(v)*916732472 + 1<<31) >> 32)
+}
+
+func abs(v fixed) fixed {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// isqrt returns the integer square root of v.
+func isqrt(v uint64) uint64 {
+	var r uint64
+	bit := uint64(1) << 62
+	for bit > v {
+		bit >>= 2
+	}
+	for bit != 0 {
+		if v >= r+bit {
+			v -= r + bit
+			r = r>>1 + bit
+		} else {
+			r >>= 1
+		}
+		bit >>= 2
+	}
+	return r
+}
+
+// hypot4 returns the length of the vector (a, b, c, d). Large values are
+// scaled down before they are squared.
+func hypot4(a, b, c, d fixed) fixed {
+	var shift uint
+	for m := abs(a) | abs(b) | abs(c) | abs(d); m>>shift >= 1<<30; {
+		shift++
+	}
+	a, b, c, d = a>>shift, b>>shift, c>>shift, d>>shift
+	return fixed(isqrt(uint64(a*a+b*b+c*c+d*d))) << shift
+}
+
+// normalize3 returns the vector scaled to unit length. The vector must not
+// be zero.
+func normalize3(x, y, z int32) (fixed, fixed, fixed) {
+	fx, fy, fz := fixed(x), fixed(y), fixed(z)
+	n := fixed(isqrt(uint64(fx*fx) + uint64(fy*fy) + uint64(fz*fz)))
+	return div(fx, n), div(fy, n), div(fz, n)
+}
+
+// atanTable holds atan(2^-i) << fracBits for the CORDIC iterations.
+var atanTable = [fracBits]fixed{
+	210828714, 124459457, 65760959, 33381290, 16755422, 8385879, 4193963,
+	2097109, 1048571, 524287, 262144, 131072, 65536, 32768, 16384, 8192,
+	4096, 2048, 1024, 512, 256, 128, 64, 32, 16, 8, 4, 2,
+}
+
+// cordicGain is the inverse of the gain of the CORDIC iterations << 30.
+const cordicGain = 652032874
+
+// atan2 returns the angle of the vector (x, y) in radians, between -π and
+// π. The vector may use any scale.
+func atan2(y, x int64) fixed {
+	if x == 0 && y == 0 {
+		return 0
+	}
+	// Scale the vector to about 2^40, which leaves room for the growth of
+	// the CORDIC iterations.
+	for m := abs(fixed(x)) | abs(fixed(y)); m >= 1<<41; m >>= 1 {
+		x, y = x>>1, y>>1
+	}
+	for m := abs(fixed(x)) | abs(fixed(y)); m < 1<<40; m <<= 1 {
+		x, y = x<<1, y<<1
+	}
+	// Rotate the vector to the right half-plane.
+	var angle fixed
+	if x < 0 {
+		if y >= 0 {
+			x, y, angle = y, -x, halfPi
+		} else {
+			x, y, angle = -y, x, -halfPi
+		}
+	}
+	for i, a := range atanTable {
+		if y > 0 {
+			x, y = x+y>>uint(i), y-x>>uint(i)
+			angle += a
+		} else {
+			x, y = x-y>>uint(i), y+x>>uint(i)
+			angle -= a
+		}
+	}
+	return angle
+}
+
+// sincos returns the sine and cosine of an angle in radians.
+func sincos(angle fixed) (sin, cos fixed) {
+	angle %= 2 * pi
+	if angle > pi {
+		angle -= 2 * pi
+	} else if angle < -pi {
+		angle += 2 * pi
+	}
+	// Rotate the angle to the right half-plane.
+	negate := false
+	if angle > halfPi {
+		angle -= pi
+		negate = true
+	} else if angle < -halfPi {
+		angle += pi
+		negate = true
+	}
+	var x, y int64 = cordicGain, 0
+	for i, a := range atanTable {
+		if angle > 0 {
+			x, y = x-y>>uint(i), y+x>>uint(i)
+			angle -= a
+		} else {
+			x, y = x+y>>uint(i), y-x>>uint(i)
+			angle += a
+		}
+	}
+	// Round from 30 to fracBits fractional bits.
+	sin, cos = fixed(y+2)>>2, fixed(x+2)>>2
+	if negate {
+		sin, cos = -sin, -cos
+	}
+	return sin, cos
+}
@@ -0,0 +1,108 @@
+// Command gentraces writes the synthesized sensor traces in the testdata
+// directory of the ahrs package. The readings follow a known motion, with a
+// constant gyroscope bias and gaussian noise, so the tests know the true
+// orientation. Traces recorded with examples/ahrs/record use the same
+// format.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// gyroScale converts µ°/s to rad/s.
+const gyroScale = math.Pi / 180 / 1e6
+
+// segment is a part of a synthesized motion: a constant angular velocity
+// in µ°/s in the sensor axes.
+type segment struct {
+	duration time.Duration
+	rate     [3]float64
+}
+
+func main() {
+	generateTrace("testdata/rest.csv",
+		"at rest with a roll of 20°, a pitch of -10° and a yaw of 60° for 10s",
+		[3]float64{20, -10, 60}, [3]float64{400000, -250000, 150000}, []segment{
+			{10 * time.Second, [3]float64{}},
+		})
+	generateTrace("testdata/motion.csv",
+		"level and pointing north, at rest for 1s, rotating about z at 90°/s for 1s, "+
+			"at rest for 1s, rotating about x at 45°/s for 1s and at rest for 2s; "+
+			"the final orientation has a roll of 45°, a pitch of 0° and a yaw of 90°",
+		[3]float64{0, 0, 0}, [3]float64{200000, -150000, 100000}, []segment{
+			{time.Second, [3]float64{}},
+			{time.Second, [3]float64{0, 0, 90e6}},
+			{time.Second, [3]float64{}},
+			{time.Second, [3]float64{45e6, 0, 0}},
+			{2 * time.Second, [3]float64{}},
+		})
+}
+
+func generateTrace(path, description string, euler, bias [3]float64, segments []segment) {
+	const dt = 10 * time.Millisecond
+	// The magnetic field points north and down.
+	field := [3]float64{20000, 0, -45000}
+	rng := rand.New(rand.NewSource(1))
+
+	w, x, y, z := eulerToQuaternion(euler[0], euler[1], euler[2])
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Synthesized trace, 100Hz: %s.\n", description)
+	fmt.Fprintf(&b, "# Gyroscope bias %.0f, %.0f, %.0f µ°/s.\n", bias[0], bias[1], bias[2])
+	b.WriteString("# dt (µs), gyroscope (µ°/s), accelerometer (µg), magnetometer (nT)\n")
+	for _, s := range segments {
+		for i := time.Duration(0); i < s.duration; i += dt {
+			// Rotate the sensor by the angular velocity over dt.
+			rx, ry, rz := s.rate[0]*gyroScale, s.rate[1]*gyroScale, s.rate[2]*gyroScale
+			angle := math.Sqrt(rx*rx+ry*ry+rz*rz) * dt.Seconds()
+			if angle > 0 {
+				sin, cos := math.Sincos(angle / 2)
+				n := sin / (angle / dt.Seconds())
+				w, x, y, z = multiply(w, x, y, z, cos, rx*n, ry*n, rz*n)
+			}
+			a := rotateToSensor(w, x, y, z, [3]float64{0, 0, 1e6})
+			m := rotateToSensor(w, x, y, z, field)
+			fmt.Fprintf(&b, "%d", dt/time.Microsecond)
+			for i := range s.rate {
+				fmt.Fprintf(&b, ",%.0f", s.rate[i]+bias[i]+rng.NormFloat64()*30000)
+			}
+			for i := range a {
+				fmt.Fprintf(&b, ",%.0f", a[i]+rng.NormFloat64()*3000)
+			}
+			for i := range m {
+				fmt.Fprintf(&b, ",%.0f", m[i]+rng.NormFloat64()*150)
+			}
+			b.WriteString("\n")
+		}
+	}
+	if err := ioutil.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func eulerToQuaternion(roll, pitch, yaw float64) (w, x, y, z float64) {
+	sr, cr := math.Sincos(roll * math.Pi / 360)
+	sp, cp := math.Sincos(pitch * math.Pi / 360)
+	sy, cy := math.Sincos(yaw * math.Pi / 360)
+	return cr*cp*cy + sr*sp*sy, sr*cp*cy - cr*sp*sy, cr*sp*cy + sr*cp*sy, cr*cp*sy - sr*sp*cy
+}
+
+func multiply(aw, ax, ay, az, bw, bx, by, bz float64) (w, x, y, z float64) {
+	return aw*bw - ax*bx - ay*by - az*bz,
+		aw*bx + ax*bw + ay*bz - az*by,
+		aw*by - ax*bz + ay*bw + az*bx,
+		aw*bz + ax*by - ay*bx + az*bw
+}
+
+// rotateToSensor returns the vector v in the earth frame in the sensor
+// frame of the orientation q.
+func rotateToSensor(w, x, y, z float64, v [3]float64) [3]float64 {
+	tw, tx, ty, tz := multiply(w, -x, -y, -z, 0, v[0], v[1], v[2])
+	_, rx, ry, rz := multiply(tw, tx, ty, tz, w, x, y, z)
+	return [3]float64{rx, ry, rz}
+}
@@ -0,0 +1,171 @@
+package ahrs
+
+import "time"
+
+// Madgwick is the gradient descent filter by Sebastian Madgwick. It
+// corrects the integrated gyroscope readings towards the orientation
+// measured by the accelerometer and magnetometer.
+type Madgwick struct {
+	// Beta is the gain of the correction in mrad/s. Higher values converge
+	// faster and follow the accelerometer and magnetometer noise more.
+	Beta int32
+
+	q Quaternion
+}
+
+// NewMadgwick returns a Madgwick filter with the given gain in mrad/s,
+// starting at the Identity orientation. A gain of 100 is a good starting
+// point.
+func NewMadgwick(beta int32) *Madgwick {
+	return &Madgwick{Beta: beta, q: Identity}
+}
+
+// Quaternion returns the current orientation.
+func (f *Madgwick) Quaternion() Quaternion {
+	return f.q
+}
+
+// SetQuaternion sets the current orientation.
+func (f *Madgwick) SetQuaternion(q Quaternion) {
+	f.q = quaternion(q.components())
+}
+
+// Update fuses a gyroscope, accelerometer and magnetometer reading taken dt
+// after the previous one. A zero magnetic field is ignored.
+func (f *Madgwick) Update(gx, gy, gz, ax, ay, az, mx, my, mz int32, dt time.Duration) {
+	if mx == 0 && my == 0 && mz == 0 {
+		f.UpdateIMU(gx, gy, gz, ax, ay, az, dt)
+		return
+	}
+	q0, q1, q2, q3 := f.q.components()
+	wx, wy, wz := radians(gx), radians(gy), radians(gz)
+
+	// Rate of change of the quaternion from the gyroscope.
+	qDot0 := (-mul(q1, wx) - mul(q2, wy) - mul(q3, wz)) / 2
+	qDot1 := (mul(q0, wx) + mul(q2, wz) - mul(q3, wy)) / 2
+	qDot2 := (mul(q0, wy) - mul(q1, wz) + mul(q3, wx)) / 2
+	qDot3 := (mul(q0, wz) + mul(q1, wy) - mul(q2, wx)) / 2
+
+	if ax != 0 || ay != 0 || az != 0 {
+		fax, fay, faz := normalize3(ax, ay, az)
+		fmx, fmy, fmz := normalize3(mx, my, mz)
+
+		_2q0mx := 2 * mul(q0, fmx)
+		_2q0my := 2 * mul(q0, fmy)
+		_2q0mz := 2 * mul(q0, fmz)
+		_2q1mx := 2 * mul(q1, fmx)
+		_2q0 := 2 * q0
+		_2q1 := 2 * q1
+		_2q2 := 2 * q2
+		_2q3 := 2 * q3
+		_2q0q2 := 2 * mul(q0, q2)
+		_2q2q3 := 2 * mul(q2, q3)
+		q0q0 := mul(q0, q0)
+		q0q1 := mul(q0, q1)
+		q0q2 := mul(q0, q2)
+		q0q3 := mul(q0, q3)
+		q1q1 := mul(q1, q1)
+		q1q2 := mul(q1, q2)
+		q1q3 := mul(q1, q3)
+		q2q2 := mul(q2, q2)
+		q2q3 := mul(q2, q3)
+		q3q3 := mul(q3, q3)
+
+		// Reference direction of the magnetic field in the earth frame.
+		hx := mul(fmx, q0q0) - mul(_2q0my, q3) + mul(_2q0mz, q2) + mul(fmx, q1q1) +
+			mul(mul(_2q1, fmy), q2) + mul(mul(_2q1, fmz), q3) - mul(fmx, q2q2) - mul(fmx, q3q3)
+		hy := mul(_2q0mx, q3) + mul(fmy, q0q0) - mul(_2q0mz, q1) + mul(_2q1mx, q2) -
+			mul(fmy, q1q1) + mul(fmy, q2q2) + mul(mul(_2q2, fmz), q3) - mul(fmy, q3q3)
+		_2bx := hypot4(hx, hy, 0, 0)
+		_2bz := -mul(_2q0mx, q2) + mul(_2q0my, q1) + mul(fmz, q0q0) + mul(_2q1mx, q3) -
+			mul(fmz, q1q1) + mul(mul(_2q2, fmy), q3) - mul(fmz, q2q2) + mul(fmz, q3q3)
+		_4bx := 2 * _2bx
+		_4bz := 2 * _2bz
+
+		// Errors of the estimated gravity and magnetic field directions.
+		ex := 2*q1q3 - _2q0q2 - fax
+		ey := 2*q0q1 + _2q2q3 - fay
+		ez := one - 2*q1q1 - 2*q2q2 - faz
+		emx := mul(_2bx, one/2-q2q2-q3q3) + mul(_2bz, q1q3-q0q2) - fmx
+		emy := mul(_2bx, q1q2-q0q3) + mul(_2bz, q0q1+q2q3) - fmy
+		emz := mul(_2bx, q0q2+q1q3) + mul(_2bz, one/2-q1q1-q2q2) - fmz
+
+		// Gradient of the objective function.
+		s0 := -mul(_2q2, ex) + mul(_2q1, ey) - mul(mul(_2bz, q2), emx) +
+			mul(-mul(_2bx, q3)+mul(_2bz, q1), emy) + mul(mul(_2bx, q2), emz)
+		s1 := mul(_2q3, ex) + mul(_2q0, ey) - mul(4*q1, ez) + mul(mul(_2bz, q3), emx) +
+			mul(mul(_2bx, q2)+mul(_2bz, q0), emy) + mul(mul(_2bx, q3)-mul(_4bz, q1), emz)
+		s2 := -mul(_2q0, ex) + mul(_2q3, ey) - mul(4*q2, ez) + mul(-mul(_4bx, q2)-mul(_2bz, q0), emx) +
+			mul(mul(_2bx, q1)+mul(_2bz, q3), emy) + mul(mul(_2bx, q0)-mul(_4bz, q2), emz)
+		s3 := mul(_2q1, ex) + mul(_2q2, ey) + mul(-mul(_4bx, q3)+mul(_2bz, q1), emx) +
+			mul(-mul(_2bx, q0)+mul(_2bz, q2), emy) + mul(mul(_2bx, q1), emz)
+		qDot0, qDot1, qDot2, qDot3 = f.correct(qDot0, qDot1, qDot2, qDot3, s0, s1, s2, s3)
+	}
+	f.integrate(qDot0, qDot1, qDot2, qDot3, dt)
+}
+
+// UpdateIMU fuses a gyroscope and accelerometer reading taken dt after the
+// previous one.
+func (f *Madgwick) UpdateIMU(gx, gy, gz, ax, ay, az int32, dt time.Duration) {
+	q0, q1, q2, q3 := f.q.components()
+	wx, wy, wz := radians(gx), radians(gy), radians(gz)
+
+	// Rate of change of the quaternion from the gyroscope.
+	qDot0 := (-mul(q1, wx) - mul(q2, wy) - mul(q3, wz)) / 2
+	qDot1 := (mul(q0, wx) + mul(q2, wz) - mul(q3, wy)) / 2
+	qDot2 := (mul(q0, wy) - mul(q1, wz) + mul(q3, wx)) / 2
+	qDot3 := (mul(q0, wz) + mul(q1, wy) - mul(q2, wx)) / 2
+
+	if ax != 0 || ay != 0 || az != 0 {
+		fax, fay, faz := normalize3(ax, ay, az)
+
+		_2q0 := 2 * q0
+		_2q1 := 2 * q1
+		_2q2 := 2 * q2
+		_2q3 := 2 * q3
+		_4q0 := 4 * q0
+		_4q1 := 4 * q1
+		_4q2 := 4 * q2
+		_8q1 := 8 * q1
+		_8q2 := 8 * q2
+		q0q0 := mul(q0, q0)
+		q1q1 := mul(q1, q1)
+		q2q2 := mul(q2, q2)
+		q3q3 := mul(q3, q3)
+
+		// Gradient of the objective function.
+		s0 := mul(_4q0, q2q2) + mul(_2q2, fax) + mul(_4q0, q1q1) - mul(_2q1, fay)
+		s1 := mul(_4q1, q3q3) - mul(_2q3, fax) + 4*mul(q0q0, q1) - mul(_2q0, fay) - _4q1 +
+			mul(_8q1, q1q1) + mul(_8q1, q2q2) + mul(_4q1, faz)
+		s2 := 4*mul(q0q0, q2) + mul(_2q0, fax) + mul(_4q2, q3q3) - mul(_2q3, fay) - _4q2 +
+			mul(_8q2, q1q1) + mul(_8q2, q2q2) + mul(_4q2, faz)
+		s3 := 4*mul(q1q1, q3) - mul(_2q1, fax) + 4*mul(q2q2, q3) - mul(_2q2, fay)
+		qDot0, qDot1, qDot2, qDot3 = f.correct(qDot0, qDot1, qDot2, qDot3, s0, s1, s2, s3)
+	}
+	f.integrate(qDot0, qDot1, qDot2, qDot3, dt)
+}
+
+// correct applies the normalized gradient step s to the rate of change of
+// the quaternion.
+func (f *Madgwick) correct(qDot0, qDot1, qDot2, qDot3, s0, s1, s2, s3 fixed) (fixed, fixed, fixed, fixed) {
+	n := hypot4(s0, s1, s2, s3)
+	if n == 0 {
+		// The estimate matches the measurement.
+		return qDot0, qDot1, qDot2, qDot3
+	}
+	beta := fromMilli(f.Beta)
+	return qDot0 - mul(beta, div(s0, n)), qDot1 - mul(beta, div(s1, n)),
+		qDot2 - mul(beta, div(s2, n)), qDot3 - mul(beta, div(s3, n))
+}
+
+// integrate adds the rate of change of the quaternion over dt.
+func (f *Madgwick) integrate(qDot0, qDot1, qDot2, qDot3 fixed, dt time.Duration) {
+	t := dt.Microseconds()
+	q0, q1, q2, q3 := f.q.components()
+	f.q = quaternion(
+		q0+scaleTime(qDot0, t),
+		q1+scaleTime(qDot1, t),
+		q2+scaleTime(qDot2, t),
+		q3+scaleTime(qDot3, t),
+	)
+}
@@ -0,0 +1,144 @@
+package ahrs
+
+import "time"
+
+// Mahony is the complementary filter by Robert Mahony. It corrects the
+// gyroscope readings with a proportional and integral feedback of the error
+// between the estimated and measured gravity and magnetic field directions.
+// The integral feedback estimates the gyroscope bias.
+type Mahony struct {
+	// Kp is the proportional gain in thousandths. Higher values follow the
+	// accelerometer and magnetometer faster.
+	Kp int32
+
+	// Ki is the integral gain in thousandths. Zero disables bias
+	// estimation.
+	Ki int32
+
+	q        Quaternion
+	integral [3]fixed // rad/s
+}
+
+// NewMahony returns a Mahony filter with the given gains in thousandths,
+// starting at the Identity orientation. Gains of 500 and 100 are a good
+// starting point.
+func NewMahony(kp, ki int32) *Mahony {
+	return &Mahony{Kp: kp, Ki: ki, q: Identity}
+}
+
+// Quaternion returns the current orientation.
+func (f *Mahony) Quaternion() Quaternion {
+	return f.q
+}
+
+// SetQuaternion sets the current orientation.
+func (f *Mahony) SetQuaternion(q Quaternion) {
+	f.q = quaternion(q.components())
+}
+
+// GyroBias returns the gyroscope bias in µ°/s estimated by the integral
+// feedback.
+func (f *Mahony) GyroBias() (x, y, z int32) {
+	return microDegrees(-f.integral[0]), microDegrees(-f.integral[1]), microDegrees(-f.integral[2])
+}
+
+// Update fuses a gyroscope, accelerometer and magnetometer reading taken dt
+// after the previous one. A zero magnetic field is ignored.
+func (f *Mahony) Update(gx, gy, gz, ax, ay, az, mx, my, mz int32, dt time.Duration) {
+	if mx == 0 && my == 0 && mz == 0 {
+		f.UpdateIMU(gx, gy, gz, ax, ay, az, dt)
+		return
+	}
+	var ex, ey, ez fixed
+	if ax != 0 || ay != 0 || az != 0 {
+		fax, fay, faz := normalize3(ax, ay, az)
+		fmx, fmy, fmz := normalize3(mx, my, mz)
+		q0, q1, q2, q3 := f.q.components()
+
+		q0q0 := mul(q0, q0)
+		q0q1 := mul(q0, q1)
+		q0q2 := mul(q0, q2)
+		q0q3 := mul(q0, q3)
+		q1q1 := mul(q1, q1)
+		q1q2 := mul(q1, q2)
+		q1q3 := mul(q1, q3)
+		q2q2 := mul(q2, q2)
+		q2q3 := mul(q2, q3)
+		q3q3 := mul(q3, q3)
+
+		// Reference direction of the magnetic field in the earth frame.
+		hx := 2 * (mul(fmx, one/2-q2q2-q3q3) + mul(fmy, q1q2-q0q3) + mul(fmz, q1q3+q0q2))
+		hy := 2 * (mul(fmx, q1q2+q0q3) + mul(fmy, one/2-q1q1-q3q3) + mul(fmz, q2q3-q0q1))
+		bx := hypot4(hx, hy, 0, 0)
+		bz := 2 * (mul(fmx, q1q3-q0q2) + mul(fmy, q2q3+q0q1) + mul(fmz, one/2-q1q1-q2q2))
+
+		// Estimated directions of gravity and the magnetic field.
+		halfvx := q1q3 - q0q2
+		halfvy := q0q1 + q2q3
+		halfvz := q0q0 - one/2 + q3q3
+		halfwx := mul(bx, one/2-q2q2-q3q3) + mul(bz, q1q3-q0q2)
+		halfwy := mul(bx, q1q2-q0q3) + mul(bz, q0q1+q2q3)
+		halfwz := mul(bx, q0q2+q1q3) + mul(bz, one/2-q1q1-q2q2)
+
+		// The error is the cross product of the estimated and measured
+		// directions.
+		ex = (mul(fay, halfvz) - mul(faz, halfvy)) + (mul(fmy, halfwz) - mul(fmz, halfwy))
+		ey = (mul(faz, halfvx) - mul(fax, halfvz)) + (mul(fmz, halfwx) - mul(fmx, halfwz))
+		ez = (mul(fax, halfvy) - mul(fay, halfvx)) + (mul(fmx, halfwy) - mul(fmy, halfwx))
+	}
+	f.integrate(gx, gy, gz, ex, ey, ez, dt)
+}
+
+// UpdateIMU fuses a gyroscope and accelerometer reading taken dt after the
+// previous one.
+func (f *Mahony) UpdateIMU(gx, gy, gz, ax, ay, az int32, dt time.Duration) {
+	var ex, ey, ez fixed
+	if ax != 0 || ay != 0 || az != 0 {
+		fax, fay, faz := normalize3(ax, ay, az)
+		q0, q1, q2, q3 := f.q.components()
+
+		// Estimated direction of gravity.
+		halfvx := mul(q1, q3) - mul(q0, q2)
+		halfvy := mul(q0, q1) + mul(q2, q3)
+		halfvz := mul(q0, q0) - one/2 + mul(q3, q3)
+
+		// The error is the cross product of the estimated and measured
+		// directions.
+		ex = mul(fay, halfvz) - mul(faz, halfvy)
+		ey = mul(faz, halfvx) - mul(fax, halfvz)
+		ez = mul(fax, halfvy) - mul(fay, halfvx)
+	}
+	f.integrate(gx, gy, gz, ex, ey, ez, dt)
+}
+
+// integrate applies the feedback of the half error e to the gyroscope
+// reading and integrates it over dt.
+func (f *Mahony) integrate(gx, gy, gz int32, ex, ey, ez fixed, dt time.Duration) {
+	t := dt.Microseconds()
+	wx, wy, wz := radians(gx), radians(gy), radians(gz)
+	if f.Ki > 0 {
+		ki := 2 * fromMilli(f.Ki)
+		f.integral[0] += scaleTime(mul(ki, ex), t)
+		f.integral[1] += scaleTime(mul(ki, ey), t)
+		f.integral[2] += scaleTime(mul(ki, ez), t)
+		wx += f.integral[0]
+		wy += f.integral[1]
+		wz += f.integral[2]
+	} else {
+		f.integral = [3]fixed{}
+	}
+	kp := 2 * fromMilli(f.Kp)
+	wx += mul(kp, ex)
+	wy += mul(kp, ey)
+	wz += mul(kp, ez)
+
+	// Integrate the rate of change of the quaternion.
+	wx, wy, wz = scaleTime(wx, t)/2, scaleTime(wy, t)/2, scaleTime(wz, t)/2
+	q0, q1, q2, q3 := f.q.components()
+	f.q = quaternion(
+		q0+(-mul(q1, wx)-mul(q2, wy)-mul(q3, wz)),
+		q1+(mul(q0, wx)+mul(q2, wz)-mul(q3, wy)),
+		q2+(mul(q0, wy)-mul(q1, wz)+mul(q3, wx)),
+		q3+(mul(q0, wz)+mul(q1, wy)-mul(q2, wx)),
+	)
+}
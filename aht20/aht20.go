@@ -106,3 +106,22 @@ func (d *Device) Celsius() float32 {
 func (d *Device) DeciCelsius() int32 {
 	return ((int32(d.temp) * 2000) / 0x100000) - 500
 }
+
+// Update reads the temperature and humidity, which the AHT20 always
+// measures together. Use Temperature and Humidity to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Humidity) == 0 {
+		return nil
+	}
+	return d.Read()
+}
+
+// Temperature in milli degrees celsius
+func (d *Device) Temperature() int32 {
+	return int32((int64(d.temp)*200000)/0x100000) - 50000
+}
+
+// Relative humidity in hundredths of a percent
+func (d *Device) Humidity() int32 {
+	return int32((int64(d.humidity) * 10000) / 0x100000)
+}
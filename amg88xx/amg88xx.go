@@ -47,6 +47,7 @@ func (d *Device) Configure(cfg Config) {
 }
 
 // ReadPixels returns the 64 values (8x8 grid) of the sensor converted to  millicelsius
+// Temperatures above 32.767°C do not fit in an int16, use ReadFrame instead.
 func (d *Device) ReadPixels(buffer *[64]int16) {
 	d.bus.ReadRegister(uint8(d.Address), PIXEL_OFFSET, d.data)
 	for i := 0; i < 64; i++ {
@@ -0,0 +1,164 @@
+package amg88xx
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ drivers.Displayer = (*tester.Display)(nil)
+
+func TestReadFrame(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, AddressHigh)
+	// The mock has no register 0xFF, the last pixel reads from the start.
+	fake.Pointer = tester.Pointer{WrapFrom: 0xFE, WrapTo: PIXEL_OFFSET}
+	bus.AddDevice(fake)
+	for i, raw := range []uint16{0x064, 0xFFC, 0x140} {
+		fake.Registers[PIXEL_OFFSET+2*i] = uint8(raw)
+		fake.Registers[PIXEL_OFFSET+2*i+1] = uint8(raw >> 8)
+	}
+
+	dev := New(bus)
+	var frame Frame
+	c.Assert(dev.ReadFrame(&frame), qt.IsNil)
+	c.Assert(frame[:4], qt.DeepEquals, []int32{25000, -1000, 80000, 0})
+
+	fake.Err = errors.New("nack")
+	err := dev.ReadFrame(&frame)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
+
+// gradient returns a frame that warms up by step per column from 20°C.
+func gradient(step int32) *Frame {
+	var frame Frame
+	for i := range frame {
+		frame[i] = 20000 + int32(i%8)*step
+	}
+	return &frame
+}
+
+func TestUpscale(t *testing.T) {
+	c := qt.New(t)
+	frame := gradient(1000)
+	frame[63] = 40000
+	for _, interpolation := range []Interpolation{BILINEAR, BICUBIC} {
+		dst := make([]int32, 15*15)
+		frame.Upscale(dst, 15, 15, interpolation)
+		// The corners are the corners of the frame.
+		c.Assert(dst[0], qt.Equals, frame[0])
+		c.Assert(dst[14], qt.Equals, frame[7])
+		c.Assert(dst[14*15], qt.Equals, frame[56])
+		c.Assert(dst[15*15-1], qt.Equals, frame[63])
+		// Every other pixel of a 15 pixel wide image is a pixel of the
+		// frame, and both interpolations follow a linear gradient.
+		c.Assert(dst[2*15+6], qt.Equals, frame[8+3])
+		c.Assert(dst[2*15+7], qt.Equals, int32(23500))
+	}
+
+	// Next to the hot corner, bicubic follows the curve of the edge and
+	// overshoots below the gradient, bilinear does not.
+	c.Assert(frame.Sample(13, 14, 15, 15, BILINEAR), qt.Equals, int32(33000))
+	c.Assert(frame.Sample(13, 14, 15, 15, BICUBIC), qt.Equals, int32(33063))
+	c.Assert(frame.Sample(11, 14, 15, 15, BILINEAR), qt.Equals, int32(25500))
+	c.Assert(frame.Sample(11, 14, 15, 15, BICUBIC), qt.Equals, int32(24688))
+
+	var single Frame
+	single[0] = 30000
+	c.Assert(single.Sample(0, 0, 1, 1, BICUBIC), qt.Equals, int32(30000))
+}
+
+func TestColorMap(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(IRON.Color(0, 0, 1000), qt.Equals, color.RGBA{0, 0, 0, 255})
+	c.Assert(IRON.Color(2000, 0, 1000), qt.Equals, color.RGBA{255, 255, 255, 255})
+	c.Assert(RAINBOW.Color(-5, 0, 1000), qt.Equals, color.RGBA{0, 0, 255, 255})
+	c.Assert(RAINBOW.Color(1000, 0, 1000), qt.Equals, color.RGBA{255, 0, 0, 255})
+	c.Assert(GRAYSCALE.Color(500, 0, 1000), qt.Equals, color.RGBA{127, 127, 127, 255})
+	c.Assert(GRAYSCALE.Color(500, 1000, 1000), qt.Equals, color.RGBA{0, 0, 0, 255})
+}
+
+func TestRender(t *testing.T) {
+	c := qt.New(t)
+	frame := gradient(500)
+	frame[27], frame[28], frame[35], frame[36] = 32000, 34000, 33000, 36000
+
+	display := tester.NewDisplay(64, 48, tester.ColorModeRGB888)
+	r := Renderer{ColorMap: IRON, Interpolation: BICUBIC}
+	c.Assert(r.Render(display, frame), qt.IsNil)
+	c.Assert(display.Displays, qt.Equals, 1)
+	display.AssertGolden(c, "testdata/heatmap.png", 0)
+
+	// The color map spans the frame.
+	c.Assert(display.Pixel(0, 0), qt.Equals, color.RGBA{0, 0, 0, 255})
+	min, max := r.Span(frame)
+	c.Assert([]int32{min, max}, qt.DeepEquals, []int32{20000, 36000})
+
+	// A uniform frame is not stretched to the whole color map.
+	var uniform Frame
+	for i := range uniform {
+		uniform[i] = 21000 + int32(i%2)*100
+	}
+	min, max = r.Span(&uniform)
+	c.Assert([]int32{min, max}, qt.DeepEquals, []int32{20050, 22050})
+
+	// Fixed spans and areas of the display.
+	display.Clear()
+	r = Renderer{ColorMap: GRAYSCALE, Min: 20000, Max: 23500, X: 8, Y: 4, Width: 8, Height: 8, FlipX: true}
+	c.Assert(r.Render(display, frame), qt.IsNil)
+	c.Assert(display.Pixel(8, 4), qt.Equals, color.RGBA{255, 255, 255, 255})
+	c.Assert(display.Pixel(15, 4), qt.Equals, color.RGBA{0, 0, 0, 255})
+	c.Assert(display.Pixel(16, 4), qt.Equals, color.RGBA{0, 0, 0, 255})
+	c.Assert(display.Pixel(7, 4), qt.Equals, color.RGBA{0, 0, 0, 255})
+}
+
+func TestDetector(t *testing.T) {
+	c := qt.New(t)
+	var d Detector
+	background := gradient(100)
+	c.Assert(d.Update(background), qt.Equals, 0)
+	c.Assert(d.Present(), qt.IsFalse)
+
+	// A person at pixels 18, 19, 26 and 27 and a smaller warm spot at 47.
+	frame := *background
+	frame[18] += 4000
+	frame[19] += 4000
+	frame[26] += 4000
+	frame[27] += 8000
+	frame[47] += 2000
+	frame[0] += 1000 // not above the threshold
+	c.Assert(d.Update(&frame), qt.Equals, 2)
+	c.Assert(d.Present(), qt.IsTrue)
+	c.Assert(d.Mask(), qt.Equals, uint64(1<<18|1<<19|1<<26|1<<27|1<<47))
+	c.Assert(d.Hotspots(), qt.DeepEquals, []Hotspot{
+		{X: 2600, Y: 2600, Pixels: 4, Max: frame[27]},
+		{X: 7000, Y: 5000, Pixels: 1, Max: frame[47]},
+	})
+
+	// The background learns the pixels that are not part of a hotspot.
+	bg := d.Background()
+	c.Assert(bg[0], qt.Equals, background[0]+1000/16)
+	c.Assert(bg[27], qt.Equals, background[27])
+
+	d.MinPixels = 2
+	c.Assert(d.Update(&frame), qt.Equals, 1)
+	c.Assert(d.Hotspots()[0].Pixels, qt.Equals, 4)
+
+	// The limit catches pixels that warmed up slowly with the background.
+	d = Detector{Limit: 20650}
+	d.Update(background)
+	c.Assert(d.Update(background), qt.Equals, 1)
+	c.Assert(d.Mask(), qt.Equals, uint64(0x8080808080808080))
+	c.Assert(d.Hotspots()[0].X, qt.Equals, int32(7000))
+	c.Assert(d.Hotspots()[0].Y, qt.Equals, int32(3500))
+
+	d.Reset()
+	c.Assert(d.Present(), qt.IsFalse)
+	c.Assert(d.Update(background), qt.Equals, 1)
+	c.Assert(d.Mask(), qt.Equals, uint64(0x8080808080808080))
+}
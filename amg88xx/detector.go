@@ -0,0 +1,175 @@
+package amg88xx
+
+// Hotspot is a group of adjacent pixels warmer than the background.
+type Hotspot struct {
+	// X and Y are the centroid of the pixels, weighted by their
+	// temperature above the background, in thousandths of a pixel from 0
+	// to 7000.
+	X, Y int32
+
+	// Pixels is the number of pixels.
+	Pixels int
+
+	// Max is the highest temperature of the pixels in millicelsius.
+	Max int32
+}
+
+// Detector detects hotspots, such as people or overheating parts, by
+// comparing frames with a model of the background of the scene.
+//
+// The background is the first frame, and then follows the pixels that are
+// not part of a hotspot with a running average. Pixels of a hotspot are
+// not learned into the background however long they stay, so Reset must be
+// called when the scene changes for good.
+type Detector struct {
+	// Threshold is how much warmer than the background a pixel must be in
+	// millicelsius to be part of a hotspot. Zero uses 1.5°C.
+	Threshold int32
+
+	// Limit is the temperature in millicelsius above which a pixel is
+	// always part of a hotspot, even if the background is as warm. Zero
+	// disables the limit.
+	Limit int32
+
+	// MinPixels is the smallest number of pixels of a hotspot. Zero uses 1.
+	MinPixels int
+
+	// Adaptation is the number of frames over which the background follows
+	// a change of the scene. Zero uses 16.
+	Adaptation int32
+
+	background Frame
+	valid      bool
+	mask       uint64
+	hotspots   [32]Hotspot
+	count      int
+}
+
+// Update compares frame with the background, finds the hotspots of the
+// frame and updates the background. It returns the number of hotspots.
+func (d *Detector) Update(frame *Frame) int {
+	if !d.valid {
+		d.SetBackground(frame)
+	}
+	threshold := d.Threshold
+	if threshold == 0 {
+		threshold = 1500
+	}
+	adaptation := d.Adaptation
+	if adaptation <= 0 {
+		adaptation = 16
+	}
+
+	d.mask = 0
+	for i, v := range frame {
+		if v-d.background[i] >= threshold || (d.Limit != 0 && v >= d.Limit) {
+			d.mask |= 1 << uint(i)
+		} else {
+			d.background[i] += (v - d.background[i]) / adaptation
+		}
+	}
+	d.findHotspots(frame)
+	return d.count
+}
+
+// findHotspots groups the pixels of the mask into hotspots.
+func (d *Detector) findHotspots(frame *Frame) {
+	minPixels := d.MinPixels
+	if minPixels <= 0 {
+		minPixels = 1
+	}
+	d.count = 0
+	seen := uint64(0)
+	var stack [64]uint8
+	for start := 0; start < 64; start++ {
+		if d.mask&(1<<uint(start)) == 0 || seen&(1<<uint(start)) != 0 {
+			continue
+		}
+		// Flood fill the 4-connected pixels of the mask.
+		var spot Hotspot
+		var sum, sumX, sumY int64
+		spot.Max = frame[start]
+		n := 0
+		stack[n] = uint8(start)
+		n++
+		seen |= 1 << uint(start)
+		for n > 0 {
+			n--
+			i := int(stack[n])
+			x, y := i%8, i/8
+			spot.Pixels++
+			if frame[i] > spot.Max {
+				spot.Max = frame[i]
+			}
+			// Weigh pixels at least as much as one at the threshold, so a
+			// pixel over the limit but not over the background counts.
+			w := int64(frame[i] - d.background[i])
+			if w < 1 {
+				w = 1
+			}
+			sum += w
+			sumX += w * int64(x)
+			sumY += w * int64(y)
+			for _, j := range [4]int{i - 8, i + 8, i - 1, i + 1} {
+				if j < 0 || j >= 64 || (j/8 != y && j%8 != x) {
+					continue
+				}
+				bit := uint64(1) << uint(j)
+				if d.mask&bit == 0 || seen&bit != 0 {
+					continue
+				}
+				seen |= bit
+				stack[n] = uint8(j)
+				n++
+			}
+		}
+		if spot.Pixels < minPixels {
+			continue
+		}
+		spot.X = int32((sumX*1000 + sum/2) / sum)
+		spot.Y = int32((sumY*1000 + sum/2) / sum)
+		// Insertion sort, largest first: there are only a few hotspots.
+		j := d.count
+		for ; j > 0 && spot.Pixels > d.hotspots[j-1].Pixels; j-- {
+			d.hotspots[j] = d.hotspots[j-1]
+		}
+		d.hotspots[j] = spot
+		d.count++
+	}
+}
+
+// Hotspots returns the hotspots found by the last Update, largest first.
+// The slice is only valid until the next Update.
+func (d *Detector) Hotspots() []Hotspot {
+	return d.hotspots[:d.count]
+}
+
+// Present returns whether the last Update found a hotspot.
+func (d *Detector) Present() bool {
+	return d.count > 0
+}
+
+// Mask returns the pixels of the hotspots found by the last Update, with bit
+// i set for pixel i of the frame.
+func (d *Detector) Mask() uint64 {
+	return d.mask
+}
+
+// Background returns the model of the background.
+func (d *Detector) Background() Frame {
+	return d.background
+}
+
+// SetBackground replaces the model of the background with frame.
+func (d *Detector) SetBackground(frame *Frame) {
+	d.background = *frame
+	d.valid = true
+}
+
+// Reset forgets the background, so that the next frame becomes the
+// background.
+func (d *Detector) Reset() {
+	d.valid = false
+	d.mask = 0
+	d.count = 0
+}
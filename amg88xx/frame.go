@@ -0,0 +1,144 @@
+package amg88xx
+
+import "tinygo.org/x/drivers"
+
+// Frame is a thermal image of the 8x8 pixels of the sensor in millicelsius,
+// row by row.
+type Frame [64]int32
+
+// Interpolation selects how the pixels between the pixels of the sensor are
+// computed when a frame is upscaled.
+type Interpolation uint8
+
+const (
+	// BILINEAR interpolates linearly between the 4 nearest pixels.
+	BILINEAR Interpolation = iota
+	// BICUBIC interpolates with Catmull-Rom splines through the 16 nearest
+	// pixels, which gives smoother gradients but may overshoot next to
+	// sharp edges.
+	BICUBIC
+)
+
+// ReadFrame reads the pixels of the sensor into frame. Unlike ReadPixels,
+// it covers the whole range of the sensor.
+func (d *Device) ReadFrame(frame *Frame) error {
+	if len(d.data) != 128 {
+		d.data = make([]uint8, 128)
+	}
+	err := d.bus.ReadRegister(uint8(d.Address), PIXEL_OFFSET, d.data)
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
+	for i := range frame {
+		// 12-bit two's complement in 0.25°C.
+		raw := int16(uint16(d.data[2*i+1])<<12|uint16(d.data[2*i])<<4) >> 4
+		frame[i] = int32(raw) * PIXEL_TEMP_CONVERSION
+	}
+	return nil
+}
+
+// Range returns the lowest and highest temperatures of the frame.
+func (f *Frame) Range() (min, max int32) {
+	min, max = f[0], f[0]
+	for _, v := range f[1:] {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return min, max
+}
+
+// Upscale interpolates the frame to an image of width×height pixels, stored
+// row by row in dst. The corner pixels of the image are the corner pixels of
+// the frame.
+func (f *Frame) Upscale(dst []int32, width, height int, interpolation Interpolation) {
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dst[y*width+x] = f.Sample(x, y, width, height, interpolation)
+		}
+	}
+}
+
+// Sample returns the temperature at pixel x, y of the frame upscaled to
+// width×height pixels, without computing the rest of the image.
+func (f *Frame) Sample(x, y, width, height int, interpolation Interpolation) int32 {
+	ix, tx := position(x, width)
+	iy, ty := position(y, height)
+	if interpolation == BICUBIC {
+		var wx, wy [4]int64
+		cubicWeights(&wx, tx)
+		cubicWeights(&wy, ty)
+		var sum int64
+		for j := 0; j < 4; j++ {
+			row := clampIndex(iy+j-1) * 8
+			var line int64
+			for i := 0; i < 4; i++ {
+				line += wx[i] * int64(f[row+clampIndex(ix+i-1)])
+			}
+			sum += wy[j] * line
+		}
+		return int32(roundShift(sum, 2*weightBits))
+	}
+	row0, row1 := iy*8, clampIndex(iy+1)*8
+	col1 := clampIndex(ix + 1)
+	top := int64(f[row0+ix])*(fracOne-tx) + int64(f[row0+col1])*tx
+	bottom := int64(f[row1+ix])*(fracOne-tx) + int64(f[row1+col1])*tx
+	return int32(roundShift(top*(fracOne-ty)+bottom*ty, 2*fracBits))
+}
+
+const (
+	// fracBits is the number of fractional bits of positions between the
+	// pixels of a frame.
+	fracBits = 8
+	fracOne  = 1 << fracBits
+
+	// weightBits is the number of fractional bits of the bicubic weights.
+	weightBits = 12
+)
+
+// position returns the pixel of the frame before pixel i of n pixels of an
+// upscaled image, and the fractional distance to it.
+func position(i, n int) (int, int64) {
+	if n <= 1 {
+		return 0, 0
+	}
+	pos := i * 7 * fracOne / (n - 1)
+	return pos >> fracBits, int64(pos & (fracOne - 1))
+}
+
+// cubicWeights stores the Catmull-Rom weights of the 4 pixels around the
+// fractional position t.
+func cubicWeights(w *[4]int64, t int64) {
+	// The weights for t in [0, 1), multiplied by 2 and by fracOne³.
+	t2 := t * t
+	t3 := t2 * t
+	const one = fracOne
+	w0 := -t3 + 2*t2*one - t*one*one
+	w1 := 3*t3 - 5*t2*one + 2*one*one*one
+	w2 := -3*t3 + 4*t2*one + t*one*one
+	const shift = 3*fracBits + 1 - weightBits
+	w[0] = roundShift(w0, shift)
+	w[1] = roundShift(w1, shift)
+	w[2] = roundShift(w2, shift)
+	// The weights must add up to exactly one.
+	w[3] = 1<<weightBits - w[0] - w[1] - w[2]
+}
+
+// clampIndex clamps a row or column index to the frame.
+func clampIndex(i int) int {
+	if i < 0 {
+		return 0
+	}
+	if i > 7 {
+		return 7
+	}
+	return i
+}
+
+// roundShift divides v by 2^shift, rounding to the nearest integer.
+func roundShift(v int64, shift uint) int64 {
+	return (v + 1<<(shift-1)) >> shift
+}
@@ -0,0 +1,165 @@
+package amg88xx
+
+import (
+	"image/color"
+
+	"tinygo.org/x/drivers"
+)
+
+// ColorMap maps temperatures to colors.
+type ColorMap uint8
+
+const (
+	// IRON goes from black through purple, red and yellow to white, like
+	// the iron palette of thermal cameras.
+	IRON ColorMap = iota
+	// RAINBOW goes from blue through cyan, green and yellow to red.
+	RAINBOW
+	// GRAYSCALE goes from black to white.
+	GRAYSCALE
+)
+
+// colorStop is a color of a gradient at a position from 0 to 255.
+type colorStop struct {
+	pos     uint8
+	r, g, b uint8
+}
+
+var (
+	ironStops = []colorStop{
+		{0, 0, 0, 0},
+		{40, 32, 0, 108},
+		{90, 150, 0, 150},
+		{140, 225, 40, 60},
+		{190, 255, 130, 0},
+		{235, 255, 220, 40},
+		{255, 255, 255, 255},
+	}
+	rainbowStops = []colorStop{
+		{0, 0, 0, 255},
+		{64, 0, 255, 255},
+		{128, 0, 255, 0},
+		{192, 255, 255, 0},
+		{255, 255, 0, 0},
+	}
+	grayscaleStops = []colorStop{
+		{0, 0, 0, 0},
+		{255, 255, 255, 255},
+	}
+)
+
+// Color returns the color of the temperature v in a map that spans the
+// temperatures from min to max. Temperatures outside of the span get the
+// color of its ends.
+func (m ColorMap) Color(v, min, max int32) color.RGBA {
+	var pos int32
+	switch {
+	case max <= min || v <= min:
+		pos = 0
+	case v >= max:
+		pos = 255
+	default:
+		pos = int32(int64(v-min) * 255 / int64(max-min))
+	}
+	return m.color(uint8(pos))
+}
+
+// color returns the color at position pos of the gradient.
+func (m ColorMap) color(pos uint8) color.RGBA {
+	stops := ironStops
+	switch m {
+	case RAINBOW:
+		stops = rainbowStops
+	case GRAYSCALE:
+		stops = grayscaleStops
+	}
+	for i := 1; i < len(stops); i++ {
+		a, b := stops[i-1], stops[i]
+		if pos > b.pos {
+			continue
+		}
+		t, n := int32(pos-a.pos), int32(b.pos-a.pos)
+		return color.RGBA{
+			R: lerp(a.r, b.r, t, n),
+			G: lerp(a.g, b.g, t, n),
+			B: lerp(a.b, b.b, t, n),
+			A: 255,
+		}
+	}
+	last := stops[len(stops)-1]
+	return color.RGBA{last.r, last.g, last.b, 255}
+}
+
+// lerp interpolates from a to b at t of n.
+func lerp(a, b uint8, t, n int32) uint8 {
+	return uint8(int32(a) + (int32(b)-int32(a))*t/n)
+}
+
+// Renderer draws frames as heat maps on a display.
+type Renderer struct {
+	// ColorMap is the color map of the heat map.
+	ColorMap ColorMap
+
+	// Interpolation is how the frame is upscaled to the heat map.
+	Interpolation Interpolation
+
+	// Min and Max are the temperatures in millicelsius at the ends of the
+	// color map. When both are zero, the color map spans the temperatures
+	// of each frame.
+	Min, Max int32
+
+	// MinSpan is the smallest span of the color map when it follows the
+	// temperatures of each frame, so that the noise of a uniform scene is
+	// not shown as contrast. Zero uses 2°C.
+	MinSpan int32
+
+	// X, Y, Width and Height are the area of the display that the heat map
+	// covers. A zero width or height covers the whole display.
+	X, Y, Width, Height int16
+
+	// FlipX and FlipY mirror the heat map, to match how the sensor is
+	// mounted.
+	FlipX, FlipY bool
+}
+
+// Span returns the temperatures at the ends of the color map for frame.
+func (r *Renderer) Span(frame *Frame) (min, max int32) {
+	if r.Min != 0 || r.Max != 0 {
+		return r.Min, r.Max
+	}
+	min, max = frame.Range()
+	span := r.MinSpan
+	if span == 0 {
+		span = 2000
+	}
+	if max-min < span {
+		mid := min + (max-min)/2
+		min, max = mid-span/2, mid-span/2+span
+	}
+	return min, max
+}
+
+// Render draws frame as a heat map on display, then calls Display.
+func (r *Renderer) Render(display drivers.Displayer, frame *Frame) error {
+	x0, y0, width, height := r.X, r.Y, r.Width, r.Height
+	if width == 0 || height == 0 {
+		x0, y0 = 0, 0
+		width, height = display.Size()
+	}
+	min, max := r.Span(frame)
+	for y := 0; y < int(height); y++ {
+		sy := y
+		if r.FlipY {
+			sy = int(height) - 1 - y
+		}
+		for x := 0; x < int(width); x++ {
+			sx := x
+			if r.FlipX {
+				sx = int(width) - 1 - x
+			}
+			v := frame.Sample(sx, sy, int(width), int(height), r.Interpolation)
+			display.SetPixel(x0+int16(x), y0+int16(y), r.ColorMap.Color(v, min, max))
+		}
+	}
+	return display.Display()
+}
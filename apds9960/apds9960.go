@@ -155,21 +155,24 @@ func (d *Device) EnableProximity() {
 }
 
 // ProximityAvailable reports if proximity data is available
-func (d *Device) ProximityAvailable() bool {
-	if d.mode == MODE_PROXIMITY && d.readStatus("PVALID") {
-		return true
+func (d *Device) ProximityAvailable() (bool, error) {
+	if d.mode != MODE_PROXIMITY {
+		return false, nil
 	}
-	return false
+	return d.readStatus("PVALID")
 }
 
 // ReadProximity reads proximity data (0~255)
-func (d *Device) ReadProximity() (proximity int32) {
+func (d *Device) ReadProximity() (proximity int32, err error) {
 	if d.mode != MODE_PROXIMITY {
-		return 0
+		return 0, nil
 	}
 	data := []byte{0}
-	d.bus.ReadRegister(d.Address, APDS9960_PDATA_REG, data)
-	return 255 - int32(data[0])
+	err = d.readRegister(APDS9960_PDATA_REG, data)
+	if err != nil {
+		return 0, err
+	}
+	return 255 - int32(data[0]), nil
 }
 
 // EnableColor starts the color engine
@@ -182,27 +185,31 @@ func (d *Device) EnableColor() {
 }
 
 // ColorAvailable reports if color data is available
-func (d *Device) ColorAvailable() bool {
-	if d.mode == MODE_COLOR && d.readStatus("AVALID") {
-		return true
+func (d *Device) ColorAvailable() (bool, error) {
+	if d.mode != MODE_COLOR {
+		return false, nil
 	}
-	return false
+	return d.readStatus("AVALID")
 }
 
 // ReadColor reads color data (red, green, blue, clear color/brightness)
-func (d *Device) ReadColor() (r int32, g int32, b int32, clear int32) {
+func (d *Device) ReadColor() (r int32, g int32, b int32, clear int32, err error) {
 	if d.mode != MODE_COLOR {
 		return
 	}
 	data := []byte{0, 0, 0, 0, 0, 0, 0, 0}
-	d.bus.ReadRegister(d.Address, APDS9960_CDATAL_REG, data[:1])
-	d.bus.ReadRegister(d.Address, APDS9960_CDATAH_REG, data[1:2])
-	d.bus.ReadRegister(d.Address, APDS9960_RDATAL_REG, data[2:3])
-	d.bus.ReadRegister(d.Address, APDS9960_RDATAH_REG, data[3:4])
-	d.bus.ReadRegister(d.Address, APDS9960_GDATAL_REG, data[4:5])
-	d.bus.ReadRegister(d.Address, APDS9960_GDATAH_REG, data[5:6])
-	d.bus.ReadRegister(d.Address, APDS9960_BDATAL_REG, data[6:7])
-	d.bus.ReadRegister(d.Address, APDS9960_BDATAH_REG, data[7:])
+	regs := [...]uint8{
+		APDS9960_CDATAL_REG, APDS9960_CDATAH_REG,
+		APDS9960_RDATAL_REG, APDS9960_RDATAH_REG,
+		APDS9960_GDATAL_REG, APDS9960_GDATAH_REG,
+		APDS9960_BDATAL_REG, APDS9960_BDATAH_REG,
+	}
+	for i, reg := range regs {
+		err = d.readRegister(reg, data[i:i+1])
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
 	clear = int32(uint16(data[1])<<8 | uint16(data[0]))
 	r = int32(uint16(data[3])<<8 | uint16(data[2]))
 	g = int32(uint16(data[5])<<8 | uint16(data[4]))
@@ -226,33 +233,42 @@ func (d *Device) EnableGesture() {
 }
 
 // GestureAvailable reports if gesture data is available
-func (d *Device) GestureAvailable() bool {
+func (d *Device) GestureAvailable() (bool, error) {
 	if d.mode != MODE_GESTURE {
-		return false
+		return false, nil
 	}
 
 	data := []byte{0, 0, 0, 0}
 
 	// check GVALID
-	d.bus.ReadRegister(d.Address, APDS9960_GSTATUS_REG, data[:1])
+	if err := d.readRegister(APDS9960_GSTATUS_REG, data[:1]); err != nil {
+		return false, err
+	}
 	if data[0]&0x01 == 0 {
-		return false
+		return false, nil
 	}
 
 	// get number of data sets available in FIFO
-	d.bus.ReadRegister(d.Address, APDS9960_GFLVL_REG, data[:1])
+	if err := d.readRegister(APDS9960_GFLVL_REG, data[:1]); err != nil {
+		return false, err
+	}
 	availableDataSets := data[0]
 	if availableDataSets == 0 {
-		return false
+		return false, nil
+	}
+	if availableDataSets > 32 {
+		availableDataSets = 32
 	}
 
 	// read up, down, left and right proximity data from FIFO
 	var dataSets [32][4]uint8
 	for i := uint8(0); i < availableDataSets; i++ {
-		d.bus.ReadRegister(d.Address, APDS9960_GFIFO_U_REG, data[:1])
-		d.bus.ReadRegister(d.Address, APDS9960_GFIFO_D_REG, data[1:2])
-		d.bus.ReadRegister(d.Address, APDS9960_GFIFO_L_REG, data[2:3])
-		d.bus.ReadRegister(d.Address, APDS9960_GFIFO_R_REG, data[3:4])
+		regs := [...]uint8{APDS9960_GFIFO_U_REG, APDS9960_GFIFO_D_REG, APDS9960_GFIFO_L_REG, APDS9960_GFIFO_R_REG}
+		for j, reg := range regs {
+			if err := d.readRegister(reg, data[j:j+1]); err != nil {
+				return false, err
+			}
+		}
 		for j := uint8(0); j < 4; j++ {
 			dataSets[i][j] = data[j]
 		}
@@ -304,7 +320,7 @@ func (d *Device) GestureAvailable() bool {
 		}
 	}
 
-	return d.gesture.detected != GESTURE_NONE
+	return d.gesture.detected != GESTURE_NONE, nil
 }
 
 // ReadGesture reads last gesture data
@@ -392,28 +408,36 @@ func (d *Device) enable(cfg enableConfig) {
 	}
 }
 
-func (d *Device) readStatus(param string) bool {
+func (d *Device) readStatus(param string) (bool, error) {
 	data := []byte{0}
-	d.bus.ReadRegister(d.Address, APDS9960_STATUS_REG, data)
+	if err := d.readRegister(APDS9960_STATUS_REG, data); err != nil {
+		return false, err
+	}
 
 	switch param {
 	case "CPSAT":
-		return data[0]>>7&0x01 == 1
+		return data[0]>>7&0x01 == 1, nil
 	case "PGSAT":
-		return data[0]>>6&0x01 == 1
+		return data[0]>>6&0x01 == 1, nil
 	case "PINT":
-		return data[0]>>5&0x01 == 1
+		return data[0]>>5&0x01 == 1, nil
 	case "AINT":
-		return data[0]>>4&0x01 == 1
+		return data[0]>>4&0x01 == 1, nil
 	case "PVALID":
-		return data[0]>>1&0x01 == 1
+		return data[0]>>1&0x01 == 1, nil
 	case "AVALID":
-		return data[0]&0x01 == 1
+		return data[0]&0x01 == 1, nil
 	default:
-		return false
+		return false, nil
 	}
 }
 
+// readRegister reads data from reg, reporting bus errors as
+// drivers.ErrNotResponding.
+func (d *Device) readRegister(reg uint8, data []byte) error {
+	return drivers.NotResponding(d.bus.ReadRegister(d.Address, reg, data))
+}
+
 func getPulseLength(l uint8) uint8 {
 	switch l {
 	case 4:
@@ -0,0 +1,71 @@
+package apds9960
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestReadProximity(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, ADPS9960_ADDRESS)
+	fake.Registers[APDS9960_STATUS_REG] = 0x02
+	fake.Registers[APDS9960_PDATA_REG] = 55
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.EnableProximity()
+	ok, err := dev.ProximityAvailable()
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	p, err := dev.ReadProximity()
+	c.Assert(err, qt.IsNil)
+	c.Assert(p, qt.Equals, int32(200))
+}
+
+func TestReadColor(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, ADPS9960_ADDRESS)
+	copy(fake.Registers[APDS9960_CDATAL_REG:], []uint8{0x00, 0x04, 0x01, 0x00, 0x02, 0x00, 0x03, 0x00})
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.EnableColor()
+	r, g, b, clear, err := dev.ReadColor()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int32{r, g, b, clear}, qt.DeepEquals, []int32{1, 2, 3, 1024})
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, ADPS9960_ADDRESS)
+	bus.AddDevice(fake)
+	busErr := errors.New("bus error")
+
+	dev := New(bus)
+	dev.EnableProximity()
+	fake.Err = busErr
+	_, err := dev.ProximityAvailable()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	_, err = dev.ReadProximity()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+
+	fake.Err = nil
+	dev.EnableColor()
+	fake.Err = busErr
+	_, _, _, _, err = dev.ReadColor()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+
+	fake.Err = nil
+	dev.EnableGesture()
+	fake.Err = busErr
+	_, err = dev.GestureAvailable()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
@@ -18,6 +18,9 @@ type Device struct {
 	bus     drivers.I2C
 	Address uint16
 	mode    SamplingMode
+
+	// last value read by Update
+	illuminance int32
 }
 
 // New creates a new bh1750 connection. The I2C bus must already be
@@ -39,17 +42,23 @@ func (d *Device) Configure() {
 }
 
 // RawSensorData returns the raw value from the bh1750
-func (d *Device) RawSensorData() uint16 {
-
+func (d *Device) RawSensorData() (uint16, error) {
 	buf := []byte{1, 0}
-	d.bus.Tx(d.Address, nil, buf)
-	return (uint16(buf[0]) << 8) | uint16(buf[1])
+	err := d.bus.Tx(d.Address, nil, buf)
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return (uint16(buf[0]) << 8) | uint16(buf[1]), nil
 }
 
-// Illuminance returns the adjusted value in mlx (milliLux)
-func (d *Device) Illuminance() int32 {
-
-	lux := uint32(d.RawSensorData())
+// ReadIlluminance reads the illuminance and returns the adjusted value in
+// mlx (milliLux).
+func (d *Device) ReadIlluminance() (int32, error) {
+	raw, err := d.RawSensorData()
+	if err != nil {
+		return 0, err
+	}
+	lux := uint32(raw)
 	var coef uint32
 	if d.mode == CONTINUOUS_HIGH_RES_MODE || d.mode == ONE_TIME_HIGH_RES_MODE {
 		coef = HIGH_RES
@@ -60,7 +69,29 @@ func (d *Device) Illuminance() int32 {
 	}
 	// 100 * coef * lux * (5/6)
 	// 5/6 = measurement accuracy as per the datasheet
-	return int32(250 * coef * lux / 3)
+	return int32(250 * coef * lux / 3), nil
+}
+
+// Illuminance reads the illuminance and returns the adjusted value in mlx
+// (milliLux). It returns 0 if the device does not respond, use
+// ReadIlluminance to get the error.
+func (d *Device) Illuminance() int32 {
+	lux, _ := d.ReadIlluminance()
+	return lux
+}
+
+// Update reads the illuminance. Use LightLevel to get the value.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.Illuminance != 0 {
+		d.illuminance, err = d.ReadIlluminance()
+	}
+	return
+}
+
+// LightLevel returns the illuminance in mlx (milliLux) read by the last
+// call to Update.
+func (d *Device) LightLevel() int32 {
+	return d.illuminance
 }
 
 // SetMode changes the reading mode for the sensor
@@ -0,0 +1,51 @@
+package bh1750
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ drivers.LightSensor = (*Device)(nil)
+
+func TestReadIlluminance(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDeviceCmd(c, Address)
+	fake.Commands = map[uint8]*tester.Cmd{
+		POWER_ON:                        {Command: []byte{POWER_ON}, Mask: []byte{0xff}},
+		uint8(CONTINUOUS_HIGH_RES_MODE): {Command: []byte{uint8(CONTINUOUS_HIGH_RES_MODE)}, Mask: []byte{0xff}, Response: []byte{0x01, 0x2c}},
+	}
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.Configure()
+
+	lux, err := dev.ReadIlluminance()
+	c.Assert(err, qt.IsNil)
+	c.Assert(lux, qt.Equals, int32(250*HIGH_RES*300/3))
+
+	// The mock only responds once to each command.
+	dev.SetMode(CONTINUOUS_HIGH_RES_MODE)
+	c.Assert(dev.Update(drivers.Illuminance), qt.IsNil)
+	c.Assert(dev.LightLevel(), qt.Equals, lux)
+
+	dev.SetMode(CONTINUOUS_HIGH_RES_MODE)
+	c.Assert(dev.Illuminance(), qt.Equals, lux)
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	dev := New(bus)
+
+	_, err := dev.ReadIlluminance()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	err = dev.Update(drivers.Illuminance)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(dev.Illuminance(), qt.Equals, int32(0))
+}
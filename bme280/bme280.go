@@ -6,11 +6,51 @@
 package bme280
 
 import (
+	"errors"
 	"math"
+	"time"
 
 	"tinygo.org/x/drivers"
 )
 
+var errMeasurementTimeout = errors.New("bme280: measurement did not complete")
+
+// Oversampling is the oversampling ratio of a measurement.
+type Oversampling byte
+
+// Mode is the power mode.
+type Mode byte
+
+// Standby is the inactive period between measurements in normal mode.
+type Standby byte
+
+// Filter is the coefficient of the IIR filter applied to the temperature
+// and pressure measurements.
+type Filter byte
+
+// Config contains the measurement settings of the BME280. Each zero field
+// selects the setting used by Configure: 16x oversampling of every
+// measurement in normal mode, with the shortest standby time and without
+// filter. As SAMPLING_SKIPPED is zero, measurements cannot be skipped: the
+// compensation of pressure and humidity needs the temperature anyway.
+type Config struct {
+	Temperature Oversampling
+	Pressure    Oversampling
+	Humidity    Oversampling
+	Mode        Mode
+	Standby     Standby
+	Filter      Filter
+}
+
+var defaultConfig = Config{
+	Temperature: SAMPLING_16X,
+	Pressure:    SAMPLING_16X,
+	Humidity:    SAMPLING_16X,
+	Mode:        MODE_NORMAL,
+	Standby:     STANDBY_0_5MS,
+	Filter:      FILTER_OFF,
+}
+
 // calibrationCoefficients reads at startup and stores the calibration coefficients
 type calibrationCoefficients struct {
 	t1 uint16
@@ -38,6 +78,12 @@ type Device struct {
 	bus                     drivers.I2C
 	Address                 uint16
 	calibrationCoefficients calibrationCoefficients
+	Config                  Config
+
+	// last values read by Update
+	temperature int32
+	pressure    int32
+	humidity    int32
 }
 
 // New creates a new BME280 connection. The I2C bus must already be
@@ -53,24 +99,48 @@ func New(bus drivers.I2C) Device {
 
 // Configure sets up the device for communication and
 // read the calibration coefficientes.
-func (d *Device) Configure() {
+func (d *Device) Configure() error {
+	return d.ConfigureWithSettings(Config{})
+}
+
+// ConfigureWithSettings reads the calibration coefficients and sets up the
+// device with the given measurement settings. Zero fields select the same
+// settings as Configure.
+//
+// In MODE_FORCED the device sleeps between measurements, which makes it
+// suitable for battery powered devices. Every read then starts a single
+// measurement and waits for it to complete.
+func (d *Device) ConfigureWithSettings(config Config) error {
+	if config.Temperature == SAMPLING_SKIPPED {
+		config.Temperature = defaultConfig.Temperature
+	}
+	if config.Pressure == SAMPLING_SKIPPED {
+		config.Pressure = defaultConfig.Pressure
+	}
+	if config.Humidity == SAMPLING_SKIPPED {
+		config.Humidity = defaultConfig.Humidity
+	}
+	if config.Mode == MODE_SLEEP {
+		config.Mode = defaultConfig.Mode
+	}
+	d.Config = config
 
 	var data [24]byte
 	err := d.bus.ReadRegister(uint8(d.Address), REG_CALIBRATION, data[:])
 	if err != nil {
-		return
+		return drivers.NotResponding(err)
 	}
 
 	var h1 [1]byte
 	err = d.bus.ReadRegister(uint8(d.Address), REG_CALIBRATION_H1, h1[:])
 	if err != nil {
-		return
+		return drivers.NotResponding(err)
 	}
 
 	var h2lsb [7]byte
 	err = d.bus.ReadRegister(uint8(d.Address), REG_CALIBRATION_H2LSB, h2lsb[:])
 	if err != nil {
-		return
+		return drivers.NotResponding(err)
 	}
 
 	d.calibrationCoefficients.t1 = readUintLE(data[0], data[1])
@@ -93,10 +163,25 @@ func (d *Device) Configure() {
 	d.calibrationCoefficients.h4 = 0 + (int16(h2lsb[3]) << 4) | (int16(h2lsb[4] & 0x0F))
 	d.calibrationCoefficients.h5 = 0 + (int16(h2lsb[5]) << 4) | (int16(h2lsb[4]) >> 4)
 
-	d.bus.WriteRegister(uint8(d.Address), CTRL_HUMIDITY_ADDR, []byte{0x3f})
-	d.bus.WriteRegister(uint8(d.Address), CTRL_MEAS_ADDR, []byte{0xB7})
-	d.bus.WriteRegister(uint8(d.Address), CTRL_CONFIG, []byte{0x00})
-
+	// The config register is only guaranteed to be written in sleep mode,
+	// and the humidity settings only take effect after writing ctrl_meas.
+	err = d.writeRegister(CTRL_MEAS_ADDR, byte(MODE_SLEEP))
+	if err != nil {
+		return err
+	}
+	err = d.writeRegister(CTRL_HUMIDITY_ADDR, byte(d.Config.Humidity))
+	if err != nil {
+		return err
+	}
+	err = d.writeRegister(CTRL_CONFIG, byte(d.Config.Standby)<<5|byte(d.Config.Filter)<<2)
+	if err != nil {
+		return err
+	}
+	if d.Config.Mode == MODE_FORCED {
+		// Measurements are started by readData.
+		return nil
+	}
+	return d.writeRegister(CTRL_MEAS_ADDR, d.ctrlMeas(d.Config.Mode))
 }
 
 // Connected returns whether a BME280 has been found.
@@ -151,12 +236,68 @@ func (d *Device) ReadHumidity() (int32, error) {
 //
 //	https://github.com/adafruit/Adafruit_BME280_Library
 func (d *Device) ReadAltitude() (alt int32, err error) {
-	mPa, _ := d.ReadPressure()
+	mPa, err := d.ReadPressure()
+	if err != nil {
+		return
+	}
 	atmP := float32(mPa) / 100000
 	alt = int32(44330.0 * (1.0 - math.Pow(float64(atmP/SEALEVEL_PRESSURE), 0.1903)))
 	return
 }
 
+// ReadAll returns the temperature in celsius milli degrees (°C/1000), the
+// pressure in milli pascals (mPa) and the relative humidity in hundredths of
+// a percent, all from the same measurement read in a single burst.
+func (d *Device) ReadAll() (temperature, pressure, humidity int32, err error) {
+	data, err := d.readData()
+	if err != nil {
+		return
+	}
+	temperature, tFine := d.calculateTemp(data)
+	pressure = d.calculatePressure(data, tFine)
+	humidity = d.calculateHumidity(data, tFine)
+	return
+}
+
+// Update reads the selected measurements in a single burst read. Use
+// Temperature, Pressure and Humidity to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Pressure|drivers.Humidity) == 0 {
+		return nil
+	}
+	data, err := d.readData()
+	if err != nil {
+		return err
+	}
+	var tFine int32
+	d.temperature, tFine = d.calculateTemp(data)
+	if which&drivers.Pressure != 0 {
+		d.pressure = d.calculatePressure(data, tFine)
+	}
+	if which&drivers.Humidity != 0 {
+		d.humidity = d.calculateHumidity(data, tFine)
+	}
+	return nil
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// Pressure returns the pressure in milli pascals mPa read by the last call
+// to Update.
+func (d *Device) Pressure() int32 {
+	return d.pressure
+}
+
+// Humidity returns the relative humidity in hundredths of a percent read by
+// the last call to Update.
+func (d *Device) Humidity() int32 {
+	return d.humidity
+}
+
 // convert2Bytes converts two bytes to int32
 func convert2Bytes(msb byte, lsb byte) int32 {
 	return int32(readUint(msb, lsb))
@@ -185,15 +326,78 @@ func readIntLE(msb byte, lsb byte) int16 {
 
 // readData does a burst read from 0xF7 to 0xF0 according to the datasheet
 // resulting in an slice with 8 bytes 0-2 = pressure / 3-5 = temperature / 6-7 = humidity
+//
+// In forced mode, it first starts a measurement and waits for it to complete.
 func (d *Device) readData() (data [8]byte, err error) {
+	if d.Config.Mode == MODE_FORCED {
+		err = d.measure()
+		if err != nil {
+			return
+		}
+	}
 	err = d.bus.ReadRegister(uint8(d.Address), REG_PRESSURE, data[:])
 	if err != nil {
-		println(err)
-		return
+		err = drivers.NotResponding(err)
 	}
 	return
 }
 
+// measure starts a measurement in forced mode and waits until the device
+// returns to sleep mode.
+func (d *Device) measure() error {
+	err := d.writeRegister(CTRL_MEAS_ADDR, d.ctrlMeas(MODE_FORCED))
+	if err != nil {
+		return err
+	}
+	time.Sleep(d.measurementTime())
+
+	status := []byte{0}
+	for i := 0; i < 10; i++ {
+		err = d.bus.ReadRegister(uint8(d.Address), REG_STATUS, status)
+		if err != nil {
+			return drivers.NotResponding(err)
+		}
+		if status[0]&STATUS_MEASURING == 0 {
+			return nil
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return errMeasurementTimeout
+}
+
+// measurementTime returns the maximum duration of a measurement with the
+// configured oversampling, from section 9.1 of the datasheet.
+func (d *Device) measurementTime() time.Duration {
+	us := 1250 + 2300*d.Config.Temperature.factor()
+	if d.Config.Pressure != SAMPLING_SKIPPED {
+		us += 2300*d.Config.Pressure.factor() + 575
+	}
+	if d.Config.Humidity != SAMPLING_SKIPPED {
+		us += 2300*d.Config.Humidity.factor() + 575
+	}
+	return time.Duration(us) * time.Microsecond
+}
+
+// factor returns the number of samples taken by an oversampling setting.
+func (o Oversampling) factor() int {
+	if o == SAMPLING_SKIPPED {
+		return 0
+	}
+	if o > SAMPLING_16X {
+		o = SAMPLING_16X
+	}
+	return 1 << (o - 1)
+}
+
+// ctrlMeas returns the value of the ctrl_meas register for the given mode.
+func (d *Device) ctrlMeas(mode Mode) byte {
+	return byte(d.Config.Temperature)<<5 | byte(d.Config.Pressure)<<2 | byte(mode)
+}
+
+func (d *Device) writeRegister(reg uint8, value byte) error {
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, []byte{value}))
+}
+
 // calculateTemp uses the data slice and applies calibrations values on it to convert the value to milli degrees
 // it also calculates the variable tFine which is used by the pressure and humidity calculation
 func (d *Device) calculateTemp(data [8]byte) (int32, int32) {
@@ -0,0 +1,186 @@
+package bme280
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ interface {
+	drivers.Thermometer
+	drivers.Barometer
+	drivers.Hygrometer
+} = (*Device)(nil)
+
+// newFakeDevice returns a fake BME280 with the compensation parameters and
+// raw temperature and pressure from the example in section 8.2 of the
+// BMP280 datasheet, which uses the same formulas.
+func newFakeDevice(c *qt.C) *tester.I2CDevice8 {
+	fake := tester.NewI2CDevice8(c, Address)
+	copy(fake.Registers[REG_CALIBRATION:], []byte{
+		0x70, 0x6b, // T1 = 27504
+		0x43, 0x67, // T2 = 26435
+		0x18, 0xfc, // T3 = -1000
+		0x7d, 0x8e, // P1 = 36477
+		0x43, 0xd6, // P2 = -10685
+		0xd0, 0x0b, // P3 = 3024
+		0x27, 0x0b, // P4 = 2855
+		0x8c, 0x00, // P5 = 140
+		0xf9, 0xff, // P6 = -7
+		0x8c, 0x3c, // P7 = 15500
+		0xf8, 0xc6, // P8 = -14600
+		0x70, 0x17, // P9 = 6000
+	})
+	fake.Registers[REG_CALIBRATION_H1] = 75
+	copy(fake.Registers[REG_CALIBRATION_H2LSB:], []byte{0x6a, 0x01, 0x00, 0x13, 0x2b, 0x03, 0x1e})
+	fake.Registers[WHO_AM_I] = CHIP_ID
+	copy(fake.Registers[REG_PRESSURE:], []byte{
+		0x65, 0x5a, 0xc0, // adc_P = 415148
+		0x7e, 0xed, 0x00, // adc_T = 519888
+		0x6e, 0x8f, // adc_H = 28303
+	})
+	return fake
+}
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AddDevice(newFakeDevice(c))
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(dev.Connected(), qt.IsTrue)
+
+	trace := bus.Record()
+	err := dev.Update(drivers.AllMeasurements)
+	c.Assert(err, qt.IsNil)
+	c.Assert(trace.Transactions, qt.HasLen, 1)
+
+	c.Assert(dev.Temperature(), qt.Equals, int32(25080))
+	c.Assert(dev.Pressure(), qt.Equals, int32(100653000))
+	// The double precision formula of the datasheet gives 44.836 %RH.
+	c.Assert(dev.Humidity(), qt.Equals, int32(4483))
+	humidity, err := dev.ReadHumidity()
+	c.Assert(err, qt.IsNil)
+	c.Assert(humidity, qt.Equals, int32(4483))
+}
+
+func TestUpdateNothing(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AddDevice(newFakeDevice(c))
+
+	dev := New(bus)
+	trace := bus.Record()
+	c.Assert(dev.Update(drivers.Acceleration), qt.IsNil)
+	c.Assert(trace.Transactions, qt.HasLen, 0)
+}
+
+func TestConfigureWithSettings(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(), qt.IsNil)
+	c.Assert(fake.Registers[CTRL_HUMIDITY_ADDR], qt.Equals, uint8(0x05))
+	c.Assert(fake.Registers[CTRL_MEAS_ADDR], qt.Equals, uint8(0xB7))
+	c.Assert(fake.Registers[CTRL_CONFIG], qt.Equals, uint8(0x00))
+
+	err := dev.ConfigureWithSettings(Config{
+		Temperature: SAMPLING_2X,
+		Pressure:    SAMPLING_16X,
+		Humidity:    SAMPLING_1X,
+		Mode:        MODE_NORMAL,
+		Standby:     STANDBY_62_5MS,
+		Filter:      FILTER_16X,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(fake.Registers[CTRL_HUMIDITY_ADDR], qt.Equals, uint8(0x01))
+	c.Assert(fake.Registers[CTRL_MEAS_ADDR], qt.Equals, uint8(0x57))
+	c.Assert(fake.Registers[CTRL_CONFIG], qt.Equals, uint8(0x30))
+}
+
+func TestConfigureDefaults(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	// The zero oversampling settings select 16x instead of skipping the
+	// measurements.
+	dev := New(bus)
+	c.Assert(dev.ConfigureWithSettings(Config{Mode: MODE_FORCED, Filter: FILTER_4X}), qt.IsNil)
+	c.Assert(dev.Config, qt.Equals, Config{
+		Temperature: SAMPLING_16X,
+		Pressure:    SAMPLING_16X,
+		Humidity:    SAMPLING_16X,
+		Mode:        MODE_FORCED,
+		Filter:      FILTER_4X,
+	})
+	c.Assert(fake.Registers[CTRL_HUMIDITY_ADDR], qt.Equals, uint8(0x05))
+	temperature, pressure, humidity, err := dev.ReadAll()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temperature, qt.Equals, int32(25080))
+	c.Assert(pressure, qt.Equals, int32(100653000))
+	c.Assert(humidity, qt.Equals, int32(4483))
+
+	c.Assert(dev.ConfigureWithSettings(Config{Humidity: SAMPLING_1X}), qt.IsNil)
+	c.Assert(dev.Config.Mode, qt.Equals, MODE_NORMAL)
+	c.Assert(fake.Registers[CTRL_HUMIDITY_ADDR], qt.Equals, uint8(0x01))
+	c.Assert(fake.Registers[CTRL_MEAS_ADDR], qt.Equals, uint8(0xB7))
+
+	// Configure returns the bus errors.
+	fake.Err = errors.New("nack")
+	err = dev.Configure()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
+
+func TestForcedMode(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	err := dev.ConfigureWithSettings(Config{
+		Temperature: SAMPLING_1X,
+		Pressure:    SAMPLING_1X,
+		Humidity:    SAMPLING_1X,
+		Mode:        MODE_FORCED,
+	})
+	c.Assert(err, qt.IsNil)
+	// The device stays in sleep mode until a measurement is requested.
+	c.Assert(fake.Registers[CTRL_MEAS_ADDR], qt.Equals, uint8(MODE_SLEEP))
+	c.Assert(dev.measurementTime(), qt.Equals, 9300*time.Microsecond)
+
+	trace := bus.Record()
+	temperature, pressure, humidity, err := dev.ReadAll()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temperature, qt.Equals, int32(25080))
+	c.Assert(pressure, qt.Equals, int32(100653000))
+	c.Assert(humidity, qt.Equals, int32(4483))
+	c.Assert(trace.String(), qt.Equals, ""+
+		"i2c 0x76 w:f425\n"+
+		"i2c 0x76 w:f3 r:00\n"+
+		"i2c 0x76 w:f7 r:655ac07eed006e8f\n")
+}
+
+func TestForcedModeTimeout(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	err := dev.ConfigureWithSettings(Config{Temperature: SAMPLING_1X, Mode: MODE_FORCED})
+	c.Assert(err, qt.IsNil)
+	fake.Registers[REG_STATUS] = STATUS_MEASURING
+	_, _, _, err = dev.ReadAll()
+	c.Assert(err, qt.Equals, errMeasurementTimeout)
+}
@@ -10,6 +10,7 @@ const (
 	CTRL_MEAS_ADDR        = 0xF4
 	CTRL_HUMIDITY_ADDR    = 0xF2
 	CTRL_CONFIG           = 0xF5
+	REG_STATUS            = 0xF3
 	REG_PRESSURE          = 0xF7
 	REG_CALIBRATION       = 0x88
 	REG_CALIBRATION_H1    = 0xA1
@@ -18,6 +19,43 @@ const (
 
 	WHO_AM_I = 0xD0
 	CHIP_ID  = 0x60
+
+	// STATUS_MEASURING is set in REG_STATUS while a conversion is running.
+	STATUS_MEASURING = 0x08
+)
+
+const (
+	SAMPLING_SKIPPED Oversampling = iota
+	SAMPLING_1X
+	SAMPLING_2X
+	SAMPLING_4X
+	SAMPLING_8X
+	SAMPLING_16X
+)
+
+const (
+	MODE_SLEEP  Mode = 0x00
+	MODE_FORCED Mode = 0x01
+	MODE_NORMAL Mode = 0x03
+)
+
+const (
+	STANDBY_0_5MS Standby = iota
+	STANDBY_62_5MS
+	STANDBY_125MS
+	STANDBY_250MS
+	STANDBY_500MS
+	STANDBY_1000MS
+	STANDBY_10MS
+	STANDBY_20MS
+)
+
+const (
+	FILTER_OFF Filter = iota
+	FILTER_2X
+	FILTER_4X
+	FILTER_8X
+	FILTER_16X
 )
 
 const (
@@ -0,0 +1,629 @@
+// Package bme680 provides a driver for the BME680 and BME688 digital gas,
+// humidity, pressure and temperature sensors by Bosch.
+//
+// The gas sensor is a metal oxide layer whose resistance depends on the
+// volatile organic compounds in the air. It is read at a temperature set by
+// a small heater, which is turned on for every measurement.
+//
+// Datasheet:
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bme680-ds001.pdf
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bme688-ds000.pdf
+//
+// The compensation formulas are the integer versions of the BME68x Sensor
+// API: https://github.com/boschsensortec/BME68x-Sensor-API
+package bme680 // import "tinygo.org/x/drivers/bme680"
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+var (
+	errMeasurementTimeout = errors.New("bme680: measurement did not complete")
+	errHeaterProfile      = errors.New("bme680: heater profile index out of range")
+)
+
+// Oversampling is the oversampling ratio of a measurement.
+type Oversampling byte
+
+// Filter is the coefficient of the IIR filter applied to the temperature
+// and pressure measurements.
+type Filter byte
+
+// HeaterProfile is a heater set-point of the gas sensor.
+type HeaterProfile struct {
+	// Temperature is the target temperature of the heater in degrees
+	// celsius, up to MaxHeaterTemperature.
+	Temperature int32
+
+	// Duration is how long the heater is kept at the target temperature
+	// before the gas resistance is measured, up to 4032ms.
+	Duration time.Duration
+}
+
+// Config contains the measurement settings of the BME680. Each zero
+// oversampling field selects the setting of the Bosch examples, which
+// Configure uses: 2x oversampling of temperature, 1x of pressure and 16x of
+// humidity. As SAMPLING_SKIPPED is zero, measurements cannot be skipped:
+// the compensation of pressure and humidity needs the temperature anyway.
+type Config struct {
+	Temperature Oversampling
+	Pressure    Oversampling
+	Humidity    Oversampling
+	Filter      Filter
+
+	// Heater is the heater profile used for gas measurements. Gas
+	// measurements are disabled if its Duration is zero.
+	Heater HeaterProfile
+}
+
+var defaultConfig = Config{
+	Temperature: SAMPLING_2X,
+	Pressure:    SAMPLING_1X,
+	Humidity:    SAMPLING_16X,
+	Filter:      FILTER_OFF,
+	Heater:      HeaterProfile{Temperature: 300, Duration: 100 * time.Millisecond},
+}
+
+// calibrationCoefficients reads at startup and stores the calibration coefficients
+type calibrationCoefficients struct {
+	t1 uint16
+	t2 int16
+	t3 int8
+
+	p1  uint16
+	p2  int16
+	p3  int8
+	p4  int16
+	p5  int16
+	p6  int8
+	p7  int8
+	p8  int16
+	p9  int16
+	p10 uint8
+
+	h1 uint16
+	h2 uint16
+	h3 int8
+	h4 int8
+	h5 int8
+	h6 uint8
+	h7 int8
+
+	gh1 int8
+	gh2 int16
+	gh3 int8
+
+	resHeatRange uint8
+	resHeatVal   int8
+	rangeSwErr   int8
+}
+
+// Device wraps an I2C connection to a BME680 or BME688 device.
+type Device struct {
+	bus                     drivers.I2C
+	Address                 uint16
+	calibrationCoefficients calibrationCoefficients
+	Config                  Config
+	variant                 uint8
+
+	// heater profile selected for gas measurements, or -1 if disabled
+	profile  int
+	profiles [HeaterProfiles]HeaterProfile
+
+	// ambient temperature in degrees celsius used for the heater
+	ambient int32
+
+	// last values read by Update
+	temperature   int32
+	pressure      int32
+	humidity      int32
+	gasResistance uint32
+}
+
+// New creates a new BME680 connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) Device {
+	return Device{
+		bus:     bus,
+		Address: Address,
+		profile: -1,
+		ambient: 25,
+	}
+}
+
+// Connected returns whether a BME680 or BME688 has been found.
+// It does a "who am I" request and checks the response.
+func (d *Device) Connected() bool {
+	data := []byte{0}
+	d.bus.ReadRegister(uint8(d.Address), WHO_AM_I, data)
+	return data[0] == CHIP_ID
+}
+
+// Reset the device
+func (d *Device) Reset() {
+	d.bus.WriteRegister(uint8(d.Address), CMD_RESET, []byte{0xB6})
+}
+
+// Configure sets up the device for communication and reads the calibration
+// coefficients. It uses the settings of the Bosch examples: the default
+// oversampling, no filter and a gas measurement with the heater at 300°C
+// for 100ms.
+func (d *Device) Configure() error {
+	return d.ConfigureWithSettings(defaultConfig)
+}
+
+// ConfigureWithSettings reads the calibration coefficients and sets up the
+// device with the given measurement settings. Zero oversampling fields
+// select the same settings as Configure, while a zero Heater.Duration
+// disables gas measurements.
+//
+// The device sleeps between measurements. Every read starts a single
+// measurement and waits for it to complete, including the heater duration.
+func (d *Device) ConfigureWithSettings(config Config) error {
+	if config.Temperature == SAMPLING_SKIPPED {
+		config.Temperature = defaultConfig.Temperature
+	}
+	if config.Pressure == SAMPLING_SKIPPED {
+		config.Pressure = defaultConfig.Pressure
+	}
+	if config.Humidity == SAMPLING_SKIPPED {
+		config.Humidity = defaultConfig.Humidity
+	}
+	d.Config = config
+
+	variant := []byte{0}
+	err := d.bus.ReadRegister(uint8(d.Address), REG_VARIANT_ID, variant)
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
+	d.variant = variant[0]
+
+	var data [42]byte
+	err = d.bus.ReadRegister(uint8(d.Address), REG_CALIBRATION_1, data[0:23])
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
+	err = d.bus.ReadRegister(uint8(d.Address), REG_CALIBRATION_2, data[23:37])
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
+	err = d.bus.ReadRegister(uint8(d.Address), REG_CALIBRATION_3, data[37:42])
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
+	d.readCalibration(data)
+
+	err = d.writeRegister(REG_CTRL_MEAS, d.ctrlMeas(false))
+	if err != nil {
+		return err
+	}
+	err = d.writeRegister(REG_CTRL_HUM, byte(d.Config.Humidity))
+	if err != nil {
+		return err
+	}
+	err = d.writeRegister(REG_CONFIG, byte(d.Config.Filter)<<2)
+	if err != nil {
+		return err
+	}
+
+	if d.Config.Heater.Duration == 0 {
+		return d.DisableHeater()
+	}
+	err = d.SetHeaterProfile(0, d.Config.Heater)
+	if err != nil {
+		return err
+	}
+	return d.SelectHeaterProfile(0)
+}
+
+// readCalibration decodes the calibration coefficients from the three
+// calibration register blocks, read one after the other into data.
+func (d *Device) readCalibration(data [42]byte) {
+	c := &d.calibrationCoefficients
+	c.t1 = readUintLE(data[31], data[32])
+	c.t2 = readIntLE(data[0], data[1])
+	c.t3 = int8(data[2])
+
+	c.p1 = readUintLE(data[4], data[5])
+	c.p2 = readIntLE(data[6], data[7])
+	c.p3 = int8(data[8])
+	c.p4 = readIntLE(data[10], data[11])
+	c.p5 = readIntLE(data[12], data[13])
+	c.p7 = int8(data[14])
+	c.p6 = int8(data[15])
+	c.p8 = readIntLE(data[18], data[19])
+	c.p9 = readIntLE(data[20], data[21])
+	c.p10 = data[22]
+
+	// h1 and h2 share the nibbles of register 0xE2.
+	c.h2 = uint16(data[23])<<4 | uint16(data[24])>>4
+	c.h1 = uint16(data[25])<<4 | uint16(data[24]&0x0F)
+	c.h3 = int8(data[26])
+	c.h4 = int8(data[27])
+	c.h5 = int8(data[28])
+	c.h6 = data[29]
+	c.h7 = int8(data[30])
+
+	c.gh2 = readIntLE(data[33], data[34])
+	c.gh1 = int8(data[35])
+	c.gh3 = int8(data[36])
+
+	c.resHeatVal = int8(data[37])
+	c.resHeatRange = (data[39] & 0x30) >> 4
+	c.rangeSwErr = int8(data[41]) >> 4
+}
+
+// SetHeaterProfile stores a heater profile in one of the HeaterProfiles
+// slots of the device. The heater resistance needed to reach the target
+// temperature depends on the ambient temperature, for which the last
+// temperature read is used, or 25°C before the first reading.
+func (d *Device) SetHeaterProfile(index int, profile HeaterProfile) error {
+	if index < 0 || index >= HeaterProfiles {
+		return errHeaterProfile
+	}
+	err := d.writeRegister(REG_RES_HEAT_0+uint8(index), d.calculateHeaterResistance(profile.Temperature, d.ambient))
+	if err != nil {
+		return err
+	}
+	err = d.writeRegister(REG_GAS_WAIT_0+uint8(index), encodeGasWait(profile.Duration))
+	if err != nil {
+		return err
+	}
+	d.profiles[index] = profile
+	return nil
+}
+
+// SelectHeaterProfile enables gas measurements with a heater profile
+// previously stored with SetHeaterProfile.
+func (d *Device) SelectHeaterProfile(index int) error {
+	if index < 0 || index >= HeaterProfiles {
+		return errHeaterProfile
+	}
+	err := d.writeRegister(REG_CTRL_GAS_0, 0)
+	if err != nil {
+		return err
+	}
+	// run_gas is bit 4 on the BME680 and bit 5 on the BME688.
+	runGas := byte(0x10)
+	if d.variant == VARIANT_BME688 {
+		runGas = 0x20
+	}
+	err = d.writeRegister(REG_CTRL_GAS_1, runGas|byte(index))
+	if err != nil {
+		return err
+	}
+	d.profile = index
+	return nil
+}
+
+// DisableHeater turns off the heater and disables gas measurements.
+func (d *Device) DisableHeater() error {
+	err := d.writeRegister(REG_CTRL_GAS_1, 0)
+	if err != nil {
+		return err
+	}
+	err = d.writeRegister(REG_CTRL_GAS_0, HEAT_OFF)
+	if err != nil {
+		return err
+	}
+	d.profile = -1
+	return nil
+}
+
+// ReadTemperature returns the temperature in celsius milli degrees (°C/1000)
+func (d *Device) ReadTemperature() (int32, error) {
+	temperature, _, _, _, err := d.ReadAll()
+	return temperature, err
+}
+
+// ReadPressure returns the pressure in milli pascals mPa
+func (d *Device) ReadPressure() (int32, error) {
+	_, pressure, _, _, err := d.ReadAll()
+	return pressure, err
+}
+
+// ReadHumidity returns the relative humidity in hundredths of a percent
+func (d *Device) ReadHumidity() (int32, error) {
+	_, _, humidity, _, err := d.ReadAll()
+	return humidity, err
+}
+
+// ReadGasResistance returns the resistance of the gas sensor in ohms. See
+// ReadAll for when it is zero.
+func (d *Device) ReadGasResistance() (uint32, error) {
+	_, _, _, gasResistance, err := d.ReadAll()
+	return gasResistance, err
+}
+
+// ReadAll returns the temperature in celsius milli degrees (°C/1000), the
+// pressure in milli pascals (mPa), the relative humidity in hundredths of a
+// percent and the resistance of the gas sensor in ohms, all from the same
+// measurement read in a single burst.
+//
+// The gas resistance is zero if the heater is disabled or the measurement
+// is not valid, for example because the heater did not reach its target
+// temperature in the duration of the heater profile.
+func (d *Device) ReadAll() (temperature, pressure, humidity int32, gasResistance uint32, err error) {
+	data, err := d.readData()
+	if err != nil {
+		return
+	}
+	temperature, tFine := d.calculateTemp(data)
+	d.ambient = temperature / 1000
+	pressure = d.calculatePressure(data, tFine)
+	humidity = d.calculateHumidity(data, tFine)
+	gasResistance = d.calculateGasResistance(data)
+	return
+}
+
+// Update reads all measurements of the device, including the gas
+// resistance, in a single burst read. Use Temperature, Pressure, Humidity
+// and GasResistance to get the values.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&(drivers.Temperature|drivers.Pressure|drivers.Humidity) == 0 {
+		return nil
+	}
+	d.temperature, d.pressure, d.humidity, d.gasResistance, err = d.ReadAll()
+	return
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// Pressure returns the pressure in milli pascals mPa read by the last call
+// to Update.
+func (d *Device) Pressure() int32 {
+	return d.pressure
+}
+
+// Humidity returns the relative humidity in hundredths of a percent read by
+// the last call to Update.
+func (d *Device) Humidity() int32 {
+	return d.humidity
+}
+
+// GasResistance returns the resistance of the gas sensor in ohms read by
+// the last call to Update, or zero if it was not valid.
+func (d *Device) GasResistance() uint32 {
+	return d.gasResistance
+}
+
+// readData starts a measurement, waits for it to complete and does a burst
+// read of the 17 bytes of the data field:
+// 0 = status / 2-4 = pressure / 5-7 = temperature / 8-9 = humidity /
+// 13-14 = BME680 gas resistance / 15-16 = BME688 gas resistance
+func (d *Device) readData() (data [17]byte, err error) {
+	err = d.writeRegister(REG_CTRL_MEAS, d.ctrlMeas(true))
+	if err != nil {
+		return
+	}
+	time.Sleep(d.measurementTime())
+
+	for i := 0; i < 10; i++ {
+		err = d.bus.ReadRegister(uint8(d.Address), REG_MEAS_STATUS, data[:])
+		if err != nil {
+			err = drivers.NotResponding(err)
+			return
+		}
+		if data[0]&STATUS_NEW_DATA != 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	err = errMeasurementTimeout
+	return
+}
+
+// measurementTime returns the duration of a measurement with the
+// configured oversampling and heater profile, as computed by the BME68x
+// Sensor API.
+func (d *Device) measurementTime() time.Duration {
+	cycles := d.Config.Temperature.factor() + d.Config.Pressure.factor() + d.Config.Humidity.factor()
+	us := cycles*1963 + 477*4 + 477*5 + 1000
+	t := time.Duration(us) * time.Microsecond
+	if d.profile >= 0 {
+		t += d.profiles[d.profile].Duration
+	}
+	return t
+}
+
+// factor returns the number of samples taken by an oversampling setting.
+func (o Oversampling) factor() int {
+	if o == SAMPLING_SKIPPED {
+		return 0
+	}
+	if o > SAMPLING_16X {
+		o = SAMPLING_16X
+	}
+	return 1 << (o - 1)
+}
+
+// ctrlMeas returns the value of the ctrl_meas register, which starts a
+// measurement if forced is set.
+func (d *Device) ctrlMeas(forced bool) byte {
+	value := byte(d.Config.Temperature)<<5 | byte(d.Config.Pressure)<<2
+	if forced {
+		value |= 0x01
+	}
+	return value
+}
+
+func (d *Device) writeRegister(reg uint8, value byte) error {
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, []byte{value}))
+}
+
+// calculateTemp uses the data slice and applies calibrations values on it to convert the value to milli degrees
+// it also calculates the variable tFine which is used by the pressure and humidity calculation
+func (d *Device) calculateTemp(data [17]byte) (int32, int32) {
+	c := &d.calibrationCoefficients
+	rawTemp := convert3Bytes(data[5], data[6], data[7])
+
+	var1 := (rawTemp >> 3) - (int32(c.t1) << 1)
+	var2 := (var1 * int32(c.t2)) >> 11
+	var3 := ((var1 >> 1) * (var1 >> 1)) >> 12
+	var3 = (var3 * (int32(c.t3) << 4)) >> 14
+
+	tFine := var2 + var3
+	T := (tFine*5 + 128) >> 8
+	return 10 * T, tFine
+}
+
+// calculatePressure uses the data slice and applies calibrations values on it to convert the value to milli pascals mPa
+func (d *Device) calculatePressure(data [17]byte, tFine int32) int32 {
+	c := &d.calibrationCoefficients
+	rawPressure := convert3Bytes(data[2], data[3], data[4])
+
+	var1 := (tFine >> 1) - 64000
+	var2 := ((((var1 >> 2) * (var1 >> 2)) >> 11) * int32(c.p6)) >> 2
+	var2 = var2 + ((var1 * int32(c.p5)) << 1)
+	var2 = (var2 >> 2) + (int32(c.p4) << 16)
+	var1 = (((((var1 >> 2) * (var1 >> 2)) >> 13) * (int32(c.p3) << 5)) >> 3) + ((int32(c.p2) * var1) >> 1)
+	var1 = var1 >> 18
+	var1 = ((32768 + var1) * int32(c.p1)) >> 15
+	if var1 == 0 {
+		return 0 // avoid exception caused by division by zero
+	}
+
+	p := 1048576 - rawPressure
+	p = int32(uint32(p-(var2>>12)) * 3125)
+	if p >= 0x40000000 {
+		p = (p / var1) << 1
+	} else {
+		p = (p << 1) / var1
+	}
+	var1 = (int32(c.p9) * (((p >> 3) * (p >> 3)) >> 13)) >> 12
+	var2 = ((p >> 2) * int32(c.p8)) >> 13
+	var3 := ((p >> 8) * (p >> 8) * (p >> 8) * int32(c.p10)) >> 17
+	p = p + ((var1 + var2 + var3 + (int32(c.p7) << 7)) >> 4)
+	return 1000 * p
+}
+
+// calculateHumidity uses the data slice and applies calibrations values on it to convert the value to relative humidity in hundredths of a percent
+func (d *Device) calculateHumidity(data [17]byte, tFine int32) int32 {
+	c := &d.calibrationCoefficients
+	rawHumidity := int32(readUint(data[8], data[9]))
+
+	tempScaled := (tFine*5 + 128) >> 8
+	var1 := rawHumidity - int32(c.h1)*16 - ((tempScaled * int32(c.h3) / 100) >> 1)
+	var2 := (int32(c.h2) * (tempScaled*int32(c.h4)/100 +
+		((tempScaled*(tempScaled*int32(c.h5)/100))>>6)/100 +
+		(1 << 14))) >> 10
+	var3 := var1 * var2
+	var4 := int32(c.h6) << 7
+	var4 = (var4 + tempScaled*int32(c.h7)/100) >> 4
+	var5 := ((var3 >> 14) * (var3 >> 14)) >> 10
+	var6 := (var4 * var5) >> 1
+
+	// milli percent
+	h := (((var3 + var6) >> 10) * 1000) >> 12
+	if h > 100000 {
+		h = 100000
+	} else if h < 0 {
+		h = 0
+	}
+	return h / 10
+}
+
+// Lookup tables for the gas resistance of the BME680.
+var (
+	gasRangeTable1 = [16]uint32{
+		2147483647, 2147483647, 2147483647, 2147483647, 2147483647, 2126008810, 2147483647, 2130303777,
+		2147483647, 2147483647, 2143188679, 2136746228, 2147483647, 2126008810, 2147483647, 2147483647,
+	}
+	gasRangeTable2 = [16]uint32{
+		4096000000, 2048000000, 1024000000, 512000000, 255744255, 127110228, 64000000, 32258064,
+		16016016, 8000000, 4000000, 2000000, 1000000, 500000, 250000, 125000,
+	}
+)
+
+// calculateGasResistance uses the data slice and applies calibrations
+// values on it to convert the value to ohms. It returns zero if the gas
+// measurement is not valid.
+func (d *Device) calculateGasResistance(data [17]byte) uint32 {
+	if d.profile < 0 {
+		return 0
+	}
+	msb, lsb := data[13], data[14]
+	if d.variant == VARIANT_BME688 {
+		msb, lsb = data[15], data[16]
+	}
+	if lsb&(GAS_VALID|HEAT_STABLE) != GAS_VALID|HEAT_STABLE {
+		return 0
+	}
+	rawGas := int64(msb)<<2 | int64(lsb)>>6
+	gasRange := lsb & GAS_RANGE_MASK
+
+	if d.variant == VARIANT_BME688 {
+		var1 := uint32(262144) >> gasRange
+		var2 := int32(rawGas) - 512
+		var2 = 4096 + var2*3
+		return 10000 * var1 / uint32(var2) * 100
+	}
+
+	var1 := ((1340 + 5*int64(d.calibrationCoefficients.rangeSwErr)) * int64(gasRangeTable1[gasRange])) >> 16
+	var2 := rawGas<<15 - 16777216 + var1
+	var3 := (int64(gasRangeTable2[gasRange]) * var1) >> 9
+	return uint32((var3 + var2>>1) / var2)
+}
+
+// calculateHeaterResistance returns the value of the res_heat register
+// that heats the gas sensor to temperature, in degrees celsius, at the
+// ambient temperature, also in degrees celsius.
+func (d *Device) calculateHeaterResistance(temperature, ambient int32) uint8 {
+	c := &d.calibrationCoefficients
+	if temperature > MaxHeaterTemperature {
+		temperature = MaxHeaterTemperature
+	}
+
+	var1 := ambient * int32(c.gh3) / 1000 * 256
+	var2 := (int32(c.gh1) + 784) * (((int32(c.gh2)+154009)*temperature*5/100 + 3276800) / 10)
+	var3 := var1 + var2/2
+	var4 := var3 / (int32(c.resHeatRange) + 4)
+	var5 := 131*int32(c.resHeatVal) + 65536
+	resHeat := (var4/var5 - 250) * 34
+	return uint8((resHeat + 50) / 100)
+}
+
+// encodeGasWait encodes a heater duration in the format of the gas_wait
+// registers: 6 bits of milliseconds and a 2 bit multiplier of 1, 4, 16 or
+// 64.
+func encodeGasWait(duration time.Duration) uint8 {
+	ms := duration.Milliseconds()
+	if ms >= 0xFC0 {
+		return 0xFF
+	}
+	var factor uint8
+	for ms > 0x3F {
+		ms /= 4
+		factor++
+	}
+	return uint8(ms) + factor*64
+}
+
+// convert3Bytes converts three bytes to int32
+func convert3Bytes(msb byte, b1 byte, lsb byte) int32 {
+	return int32(((((uint32(msb) << 8) | uint32(b1)) << 8) | uint32(lsb)) >> 4)
+}
+
+// readUint converts two bytes to uint16
+func readUint(msb byte, lsb byte) uint16 {
+	return (uint16(msb) << 8) | uint16(lsb)
+}
+
+// readUintLE converts two little endian bytes to uint16
+func readUintLE(lsb byte, msb byte) uint16 {
+	return readUint(msb, lsb)
+}
+
+// readIntLE converts two little endian bytes to int16
+func readIntLE(lsb byte, msb byte) int16 {
+	return int16(readUintLE(lsb, msb))
+}
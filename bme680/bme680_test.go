@@ -0,0 +1,225 @@
+package bme680
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ interface {
+	drivers.Thermometer
+	drivers.Barometer
+	drivers.Hygrometer
+} = (*Device)(nil)
+
+// newFakeDevice returns a fake BME680 with typical calibration coefficients
+// and a measurement of 26°C, 970hPa and 48.5%RH. The expected values in the
+// tests were checked against the floating point formulas in section 3.3 of
+// the datasheet.
+func newFakeDevice(c *qt.C) *tester.I2CDevice8 {
+	fake := tester.NewI2CDevice8(c, Address)
+	fake.Registers[WHO_AM_I] = CHIP_ID
+	copy(fake.Registers[REG_CALIBRATION_1:], []byte{
+		0x15, 0x67, // T2 = 26389
+		0x03,       // T3 = 3
+		0x00,       //
+		0x2f, 0x91, // P1 = 37167
+		0xa8, 0xd7, // P2 = -10328
+		0x58,       // P3 = 88
+		0x00,       //
+		0xaa, 0x1b, // P4 = 7082
+		0x82, 0xff, // P5 = -126
+		0x34,       // P7 = 52
+		0x1e,       // P6 = 30
+		0x00, 0x00, //
+		0x34, 0xfa, // P8 = -1484
+		0xac, 0xf4, // P9 = -2900
+		0x1e, // P10 = 30
+	})
+	copy(fake.Registers[REG_CALIBRATION_2:], []byte{
+		0x3e, 0xc1, 0x31, // H2 = 1004, H1 = 785
+		0x00,       // H3 = 0
+		0x2d,       // H4 = 45
+		0x14,       // H5 = 20
+		0x78,       // H6 = 120
+		0x9c,       // H7 = -100
+		0xe5, 0x65, // T1 = 26085
+		0x14, 0xd3, // GH2 = -11500
+		0xdb, // GH1 = -37
+		0x12, // GH3 = 18
+	})
+	copy(fake.Registers[REG_CALIBRATION_3:], []byte{
+		0x2b, // res_heat_val = 43
+		0x00,
+		0x10, // res_heat_range = 1
+		0x00,
+		0xf0, // range_sw_err = -1
+	})
+	copy(fake.Registers[REG_MEAS_STATUS:], []byte{
+		STATUS_NEW_DATA,
+		0x00,
+		0x57, 0xe4, 0x00, // adc_P = 360000
+		0x7a, 0x12, 0x00, // adc_T = 500000
+		0x55, 0xf0, // adc_H = 22000
+		0x00, 0x00, 0x00,
+		0x96, 0x35, // BME680 adc_G = 600, range 5, valid, stable
+		0x96, 0x35, // BME688 adc_G = 600, range 5, valid, stable
+	})
+	return fake
+}
+
+func TestConfigure(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Connected(), qt.IsTrue)
+	c.Assert(dev.Configure(), qt.IsNil)
+	c.Assert(fake.Registers[REG_CTRL_HUM], qt.Equals, uint8(0x05))
+	c.Assert(fake.Registers[REG_CTRL_MEAS], qt.Equals, uint8(0x44))
+	c.Assert(fake.Registers[REG_CONFIG], qt.Equals, uint8(0x00))
+
+	// 300°C for 100ms at 25°C ambient (float: 109.3).
+	c.Assert(fake.Registers[REG_RES_HEAT_0], qt.Equals, uint8(108))
+	c.Assert(fake.Registers[REG_GAS_WAIT_0], qt.Equals, uint8(25+1*64))
+	c.Assert(fake.Registers[REG_CTRL_GAS_0], qt.Equals, uint8(0x00))
+	c.Assert(fake.Registers[REG_CTRL_GAS_1], qt.Equals, uint8(0x10))
+	c.Assert(dev.measurementTime(), qt.Equals, 42590*time.Microsecond+100*time.Millisecond)
+}
+
+func TestConfigureDefaults(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	// The zero oversampling fields select their defaults, and the zero
+	// heater duration disables gas measurements.
+	dev := New(bus)
+	c.Assert(dev.ConfigureWithSettings(Config{Pressure: SAMPLING_4X, Filter: FILTER_3X}), qt.IsNil)
+	c.Assert(fake.Registers[REG_CTRL_HUM], qt.Equals, uint8(0x05))
+	c.Assert(fake.Registers[REG_CTRL_MEAS], qt.Equals, uint8(0x4C))
+	c.Assert(fake.Registers[REG_CONFIG], qt.Equals, uint8(0x08))
+	c.Assert(fake.Registers[REG_CTRL_GAS_0], qt.Equals, uint8(HEAT_OFF))
+	c.Assert(fake.Registers[REG_CTRL_GAS_1], qt.Equals, uint8(0x00))
+
+	temperature, err := dev.ReadTemperature()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temperature, qt.Equals, int32(26000))
+
+	fake.Err = errors.New("bus error")
+	c.Assert(errors.Is(dev.Configure(), drivers.ErrNotResponding), qt.IsTrue)
+}
+
+func TestReadAll(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.ConfigureWithSettings(Config{
+		Temperature: SAMPLING_1X,
+		Pressure:    SAMPLING_1X,
+		Humidity:    SAMPLING_1X,
+		Heater:      HeaterProfile{Temperature: 320, Duration: 150 * time.Millisecond},
+	}), qt.IsNil)
+
+	trace := bus.Record()
+	temperature, pressure, humidity, gasResistance, err := dev.ReadAll()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temperature, qt.Equals, int32(26000))     // float: 26.0007°C
+	c.Assert(pressure, qt.Equals, int32(97015000))     // float: 97016.97Pa
+	c.Assert(humidity, qt.Equals, int32(4854))         // float: 48.554%
+	c.Assert(gasResistance, qt.Equals, uint32(232764)) // float: 232763.9Ω
+	c.Assert(trace.String(), qt.Equals, ""+
+		"i2c 0x76 w:7425\n"+
+		"i2c 0x76 w:1d r:800057e4007a120055f000000096359635\n")
+
+	// The heater resistance follows the measured temperature.
+	c.Assert(dev.SetHeaterProfile(1, HeaterProfile{Temperature: 320, Duration: time.Second}), qt.IsNil)
+	c.Assert(fake.Registers[REG_RES_HEAT_0+1], qt.Equals, uint8(113))
+	c.Assert(fake.Registers[REG_GAS_WAIT_0+1], qt.Equals, uint8(62+2*64))
+	c.Assert(dev.SelectHeaterProfile(1), qt.IsNil)
+	c.Assert(fake.Registers[REG_CTRL_GAS_1], qt.Equals, uint8(0x11))
+	c.Assert(dev.SetHeaterProfile(HeaterProfiles, HeaterProfile{}), qt.Equals, errHeaterProfile)
+	c.Assert(dev.SelectHeaterProfile(-1), qt.Equals, errHeaterProfile)
+}
+
+func TestGasInvalid(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(), qt.IsNil)
+
+	// The heater did not reach the target temperature.
+	fake.Registers[REG_MEAS_STATUS+14] = GAS_VALID | 5
+	gasResistance, err := dev.ReadGasResistance()
+	c.Assert(err, qt.IsNil)
+	c.Assert(gasResistance, qt.Equals, uint32(0))
+
+	// The heater is disabled.
+	fake.Registers[REG_MEAS_STATUS+14] = GAS_VALID | HEAT_STABLE | 5
+	c.Assert(dev.DisableHeater(), qt.IsNil)
+	c.Assert(fake.Registers[REG_CTRL_GAS_0], qt.Equals, uint8(HEAT_OFF))
+	c.Assert(fake.Registers[REG_CTRL_GAS_1], qt.Equals, uint8(0x00))
+	c.Assert(dev.Update(drivers.Temperature), qt.IsNil)
+	c.Assert(dev.Temperature(), qt.Equals, int32(26000))
+	c.Assert(dev.GasResistance(), qt.Equals, uint32(0))
+}
+
+func TestBME688(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	fake.Registers[REG_VARIANT_ID] = VARIANT_BME688
+	// Only the BME688 gas registers hold a valid reading.
+	fake.Registers[REG_MEAS_STATUS+14] = 0
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(), qt.IsNil)
+	c.Assert(fake.Registers[REG_CTRL_GAS_1], qt.Equals, uint8(0x20))
+
+	c.Assert(dev.Update(drivers.AllMeasurements), qt.IsNil)
+	c.Assert(dev.Pressure(), qt.Equals, int32(97015000))
+	c.Assert(dev.Humidity(), qt.Equals, int32(4854))
+	c.Assert(dev.GasResistance(), qt.Equals, uint32(1878800))
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(), qt.IsNil)
+
+	fake.Registers[REG_MEAS_STATUS] = 0
+	_, _, _, _, err := dev.ReadAll()
+	c.Assert(err, qt.Equals, errMeasurementTimeout)
+
+	busErr := errors.New("bus error")
+	fake.Err = busErr
+	err = dev.Update(drivers.AllMeasurements)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+}
+
+func TestEncodeGasWait(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(encodeGasWait(63*time.Millisecond), qt.Equals, uint8(63))
+	c.Assert(encodeGasWait(100*time.Millisecond), qt.Equals, uint8(0x59))
+	c.Assert(encodeGasWait(4031*time.Millisecond), qt.Equals, uint8(0xFE))
+	c.Assert(encodeGasWait(5*time.Second), qt.Equals, uint8(0xFF))
+}
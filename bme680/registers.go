@@ -0,0 +1,73 @@
+package bme680
+
+// Constants/addresses used for I2C.
+
+// The I2C address which this device listens to.
+const Address = 0x76
+
+// Registers. Names, addresses and comments copied from the datasheet.
+const (
+	REG_CALIBRATION_3 = 0x00 // res_heat_val, res_heat_range, range_sw_err
+	REG_MEAS_STATUS   = 0x1D // start of the data field
+	REG_IDAC_HEAT_0   = 0x50
+	REG_RES_HEAT_0    = 0x5A
+	REG_GAS_WAIT_0    = 0x64
+	REG_CTRL_GAS_0    = 0x70
+	REG_CTRL_GAS_1    = 0x71
+	REG_CTRL_HUM      = 0x72
+	REG_CTRL_MEAS     = 0x74
+	REG_CONFIG        = 0x75
+	REG_CALIBRATION_1 = 0x8A
+	REG_CALIBRATION_2 = 0xE1
+	REG_VARIANT_ID    = 0xF0
+
+	CMD_RESET = 0xE0
+
+	WHO_AM_I = 0xD0
+	CHIP_ID  = 0x61
+)
+
+// Values of the variant ID register.
+const (
+	VARIANT_BME680 = 0x00
+	VARIANT_BME688 = 0x01
+)
+
+// Bits of the data field.
+const (
+	STATUS_NEW_DATA = 0x80
+	GAS_VALID       = 0x20
+	HEAT_STABLE     = 0x10
+	GAS_RANGE_MASK  = 0x0F
+
+	HEAT_OFF = 0x08
+)
+
+const (
+	SAMPLING_SKIPPED Oversampling = iota
+	SAMPLING_1X
+	SAMPLING_2X
+	SAMPLING_4X
+	SAMPLING_8X
+	SAMPLING_16X
+)
+
+const (
+	FILTER_OFF Filter = iota
+	FILTER_1X
+	FILTER_3X
+	FILTER_7X
+	FILTER_15X
+	FILTER_31X
+	FILTER_63X
+	FILTER_127X
+)
+
+const (
+	// HeaterProfiles is the number of heater profiles the device can store.
+	HeaterProfiles = 10
+
+	// MaxHeaterTemperature is the highest supported heater temperature in
+	// degrees celsius.
+	MaxHeaterTemperature = 400
+)
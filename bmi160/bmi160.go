@@ -13,10 +13,15 @@ type DeviceSPI struct {
 	// Chip select pin
 	CSB machine.Pin
 
-	buf [7]byte
+	buf [13]byte
 
 	// SPI bus (requires chip select to be usable).
 	Bus drivers.SPI
+
+	// last values read by Update
+	accel       [3]int32
+	gyro        [3]int32
+	temperature int32
 }
 
 // NewSPI returns a new device driver. The pin and SPI interface are not
@@ -129,17 +134,9 @@ func (d *DeviceSPI) ReadAcceleration() (x int32, y int32, z int32, err error) {
 	if err != nil {
 		return
 	}
-	// Now do two things:
-	// 1. merge the two values to a 16-bit number (and cast to a 32-bit integer)
-	// 2. scale the value to bring it in the -1000000..1000000 range.
-	//    This is done with a trick. What we do here is essentially multiply by
-	//    1000000 and divide by 16384 to get the original scale, but to avoid
-	//    overflow we do it at 1/64 of the value:
-	//      1000000 / 64 = 15625
-	//      16384   / 64 = 256
-	x = int32(int16(uint16(data[1])|uint16(data[2])<<8)) * 15625 / 256
-	y = int32(int16(uint16(data[3])|uint16(data[4])<<8)) * 15625 / 256
-	z = int32(int16(uint16(data[5])|uint16(data[6])<<8)) * 15625 / 256
+	x = convertAcceleration(data[1], data[2])
+	y = convertAcceleration(data[3], data[4])
+	z = convertAcceleration(data[5], data[6])
 	return
 }
 
@@ -159,25 +156,87 @@ func (d *DeviceSPI) ReadRotation() (x int32, y int32, z int32, err error) {
 	if err != nil {
 		return
 	}
+	x = convertRotation(data[1], data[2])
+	y = convertRotation(data[3], data[4])
+	z = convertRotation(data[5], data[6])
+	return
+}
+
+// Update reads the selected measurements. Use Acceleration, AngularVelocity
+// and Temperature to get the values.
+func (d *DeviceSPI) Update(which drivers.Measurement) (err error) {
+	if which&(drivers.Acceleration|drivers.AngularVelocity) != 0 {
+		// The gyroscope registers are followed by the accelerometer
+		// registers, so read both in a single burst.
+		data := d.buf[:13]
+		data[0] = 0x80 | reg_GYR_XL
+		for i := 1; i < len(data); i++ {
+			data[i] = 0
+		}
+		d.CSB.Low()
+		err = d.Bus.Tx(data, data)
+		d.CSB.High()
+		if err != nil {
+			return
+		}
+		for i := 0; i < 3; i++ {
+			d.gyro[i] = convertRotation(data[1+i*2], data[2+i*2])
+			d.accel[i] = convertAcceleration(data[7+i*2], data[8+i*2])
+		}
+	}
+	if which&drivers.Temperature != 0 {
+		d.temperature, err = d.ReadTemperature()
+	}
+	return
+}
+
+// convertAcceleration converts a raw accelerometer sample to µg.
+func convertAcceleration(lo, hi uint8) int32 {
+	// Do two things:
+	// 1. merge the two values to a 16-bit number (and cast to a 32-bit integer)
+	// 2. scale the value to bring it in the -1000000..1000000 range.
+	//    This is done with a trick. What we do here is essentially multiply by
+	//    1000000 and divide by 16384 to get the original scale, but to avoid
+	//    overflow we do it at 1/64 of the value:
+	//      1000000 / 64 = 15625
+	//      16384   / 64 = 256
+	return int32(int16(uint16(lo)|uint16(hi)<<8)) * 15625 / 256
+}
+
+// convertRotation converts a raw gyroscope sample to µ°/s.
+func convertRotation(lo, hi uint8) int32 {
 	// First the value is converted from a pair of bytes to a signed 16-bit
 	// value and then to a signed 32-bit value to avoid integer overflow.
 	// Then the value is scaled to µ°/s (micro-degrees per second).
 	// The default is 2000°/s full scale range for -32768..32767.
-	// The formula works as follows (taking X as an example):
+	// The formula works as follows:
 	// 1. Scale from 32768 to 2000. This means that it is in °/s units.
-	//    rawX * 2000 / 32768
+	//    raw * 2000 / 32768
 	// 2. Scale to µ°/s by multiplying by 1e6.
-	//    rawX * 1e6 * 2000 / 32768
+	//    raw * 1e6 * 2000 / 32768
 	// 3. Simplify.
-	//    rawX * 2e9 / 32768
-	//    rawX * 1953125 / 32
-	rawX := int32(int16(uint16(data[1]) | uint16(data[2])<<8))
-	rawY := int32(int16(uint16(data[3]) | uint16(data[4])<<8))
-	rawZ := int32(int16(uint16(data[5]) | uint16(data[6])<<8))
-	x = int32(int64(rawX) * 1953125 / 32)
-	y = int32(int64(rawY) * 1953125 / 32)
-	z = int32(int64(rawZ) * 1953125 / 32)
-	return
+	//    raw * 2e9 / 32768
+	//    raw * 1953125 / 32
+	raw := int32(int16(uint16(lo) | uint16(hi)<<8))
+	return int32(int64(raw) * 1953125 / 32)
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *DeviceSPI) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
+
+// AngularVelocity returns the rotation in µ°/s (micro-degrees/sec) read by
+// the last call to Update.
+func (d *DeviceSPI) AngularVelocity() (x, y, z int32) {
+	return d.gyro[0], d.gyro[1], d.gyro[2]
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *DeviceSPI) Temperature() int32 {
+	return d.temperature
 }
 
 // runCommand runs a BMI160 command through the CMD register. It waits for the
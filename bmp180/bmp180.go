@@ -35,6 +35,10 @@ type Device struct {
 	Address                 uint16
 	mode                    OversamplingMode
 	calibrationCoefficients calibrationCoefficients
+
+	// last values read by Update
+	temperature int32
+	pressure    int32
 }
 
 // New creates a new BMP180 connection. The I2C bus must already be
@@ -85,9 +89,7 @@ func (d *Device) ReadTemperature() (temperature int32, err error) {
 	if err != nil {
 		return
 	}
-	b5 := d.calculateB5(rawTemp)
-	t := (b5 + 8) >> 4
-	return 100 * t, nil
+	return d.calculateTemp(rawTemp), nil
 }
 
 // ReadPressure returns the pressure in milli pascals (mPa).
@@ -100,6 +102,53 @@ func (d *Device) ReadPressure() (pressure int32, err error) {
 	if err != nil {
 		return
 	}
+	return d.calculatePressure(rawTemp, rawPressure), nil
+}
+
+// Update reads the selected measurements. The pressure calculation needs
+// the temperature, so both are always converted together. Use Temperature
+// and Pressure to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Pressure) == 0 {
+		return nil
+	}
+	rawTemp, err := d.rawTemp()
+	if err != nil {
+		return err
+	}
+	d.temperature = d.calculateTemp(rawTemp)
+	if which&drivers.Pressure != 0 {
+		rawPressure, err := d.rawPressure(d.mode)
+		if err != nil {
+			return err
+		}
+		d.pressure = d.calculatePressure(rawTemp, rawPressure)
+	}
+	return nil
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// Pressure returns the pressure in milli pascals (mPa) read by the last call
+// to Update.
+func (d *Device) Pressure() int32 {
+	return d.pressure
+}
+
+// calculateTemp converts a raw temperature to celsius milli degrees.
+func (d *Device) calculateTemp(rawTemp int32) int32 {
+	b5 := d.calculateB5(rawTemp)
+	t := (b5 + 8) >> 4
+	return 100 * t
+}
+
+// calculatePressure converts a raw pressure to milli pascals, compensated
+// with the raw temperature measured just before.
+func (d *Device) calculatePressure(rawTemp, rawPressure int32) int32 {
 	b5 := d.calculateB5(rawTemp)
 	b6 := b5 - 4000
 	x1 := (int32(d.calibrationCoefficients.b2) * (b6 * b6 >> 12)) >> 11
@@ -120,7 +169,7 @@ func (d *Device) ReadPressure() (pressure int32, err error) {
 	x1 = (p >> 8) * (p >> 8)
 	x1 = (x1 * 3038) >> 16
 	x2 = (-7357 * p) >> 16
-	return 1000 * (p + ((x1 + x2 + 3791) >> 4)), nil
+	return 1000 * (p + ((x1 + x2 + 3791) >> 4))
 }
 
 // rawTemp returns the sensor's raw values of the temperature
@@ -28,6 +28,10 @@ type Device struct {
 	Mode        Mode
 	Standby     Standby
 	Filter      Filter
+
+	// last values read by Update
+	temperature int32
+	pressure    int32
 }
 
 type calibrationCoefficients struct {
@@ -138,19 +142,7 @@ func (d *Device) ReadTemperature() (temperature int32, err error) {
 		return
 	}
 
-	rawTemp := convert3Bytes(data[0], data[1], data[2])
-
-	// Datasheet: 8.2 Compensation formula in 32 bit fixed point
-	// Temperature compensation
-	var1 := ((rawTemp >> 3) - int32(d.cali.t1<<1)) * int32(d.cali.t2) >> 11
-	var2 := (((rawTemp >> 4) - int32(d.cali.t1)) * ((rawTemp >> 4) - int32(d.cali.t1)) >> 12) *
-		int32(d.cali.t3) >> 14
-
-	tFine := var1 + var2
-
-	// Convert from degrees to milli degrees by multiplying by 10.
-	// Will output 30250 milli degrees celsius for 30.25 degrees celsius
-	temperature = 10 * ((tFine*5 + 128) >> 8)
+	temperature, _ = d.calculateTemp(convert3Bytes(data[0], data[1], data[2]))
 	return
 }
 
@@ -162,22 +154,78 @@ func (d *Device) ReadPressure() (pressure int32, err error) {
 		return
 	}
 
-	rawTemp := convert3Bytes(data[3], data[4], data[5])
+	_, tFine := d.calculateTemp(convert3Bytes(data[3], data[4], data[5]))
+	return d.calculatePressure(convert3Bytes(data[0], data[1], data[2]), tFine), nil
+}
+
+// Update reads the selected measurements in a single burst read. Use
+// Sensor to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Pressure) == 0 {
+		return nil
+	}
+	// First 3 bytes are Pressure, last 3 bytes are Temperature
+	data, err := d.readData(REG_PRES, 6)
+	if err != nil {
+		return err
+	}
+
+	var tFine int32
+	d.temperature, tFine = d.calculateTemp(convert3Bytes(data[3], data[4], data[5]))
+	if which&drivers.Pressure != 0 {
+		d.pressure = d.calculatePressure(convert3Bytes(data[0], data[1], data[2]), tFine)
+	}
+	return nil
+}
+
+// Sensor is a view of a Device that implements drivers.Thermometer and
+// drivers.Barometer. The Device itself cannot, as its Temperature and
+// Pressure fields hold the oversampling settings.
+type Sensor struct {
+	*Device
+}
+
+// Sensor returns a view of d that implements drivers.Thermometer and
+// drivers.Barometer.
+func (d *Device) Sensor() Sensor {
+	return Sensor{d}
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (s Sensor) Temperature() int32 {
+	return s.temperature
+}
+
+// Pressure returns the pressure in milli pascals (mPa) read by the last call
+// to Update.
+func (s Sensor) Pressure() int32 {
+	return s.pressure
+}
 
+// calculateTemp converts a raw temperature to celsius milli degrees. It also
+// returns tFine, which is used for the pressure compensation.
+func (d *Device) calculateTemp(rawTemp int32) (temperature, tFine int32) {
 	// Datasheet: 8.2 Compensation formula in 32 bit fixed point
-	// Calculate tFine (temperature), used for the Pressure compensation
+	// Temperature compensation
 	var1 := ((rawTemp >> 3) - int32(d.cali.t1<<1)) * int32(d.cali.t2) >> 11
 	var2 := (((rawTemp >> 4) - int32(d.cali.t1)) * ((rawTemp >> 4) - int32(d.cali.t1)) >> 12) *
 		int32(d.cali.t3) >> 14
 
-	tFine := var1 + var2
+	tFine = var1 + var2
 
-	rawPres := convert3Bytes(data[0], data[1], data[2])
+	// Convert from degrees to milli degrees by multiplying by 10.
+	// Will output 30250 milli degrees celsius for 30.25 degrees celsius
+	temperature = 10 * ((tFine*5 + 128) >> 8)
+	return
+}
 
+// calculatePressure converts a raw pressure to milli pascals (mPa).
+func (d *Device) calculatePressure(rawPres, tFine int32) int32 {
 	// Datasheet: 8.2 Compensation formula in 32 bit fixed point
 	// Pressure compensation
-	var1 = (tFine >> 1) - 64000
-	var2 = (((var1 >> 2) * (var1 >> 2)) >> 11) * int32(d.cali.p6)
+	var1 := (tFine >> 1) - 64000
+	var2 := (((var1 >> 2) * (var1 >> 2)) >> 11) * int32(d.cali.p6)
 	var2 = var2 + ((var1 * int32(d.cali.p5)) << 1)
 	var2 = (var2 >> 2) + (int32(d.cali.p4) << 16)
 	var1 = (((int32(d.cali.p3) * (((var1 >> 2) * (var1 >> 2)) >> 13)) >> 3) +
@@ -185,7 +233,7 @@ func (d *Device) ReadPressure() (pressure int32, err error) {
 	var1 = ((32768 + var1) * int32(d.cali.p1)) >> 15
 
 	if var1 == 0 {
-		return 0, nil
+		return 0
 	}
 
 	p := uint32(((1048576 - rawPres) - (var2 >> 12)) * 3125)
@@ -198,7 +246,7 @@ func (d *Device) ReadPressure() (pressure int32, err error) {
 	var1 = (int32(d.cali.p9) * int32(((p>>3)*(p>>3))>>13)) >> 12
 	var2 = (int32(p>>2) * int32(d.cali.p8)) >> 13
 
-	return 1000 * (int32(p) + ((var1 + var2 + int32(d.cali.p7)) >> 4)), nil
+	return 1000 * (int32(p) + ((var1 + var2 + int32(d.cali.p7)) >> 4))
 }
 
 // readData reads n number of bytes of the specified register
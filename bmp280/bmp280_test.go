@@ -0,0 +1,42 @@
+package bmp280
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var (
+	_ drivers.Thermometer = Sensor{}
+	_ drivers.Barometer   = Sensor{}
+)
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	bus.AddDevice(fake)
+
+	// Datasheet: 3.12 Calculating pressure and temperature, example
+	// trimming parameters and readings.
+	copy(fake.Registers[REG_CALI:], []uint8{
+		0x70, 0x6B, 0x43, 0x67, 0x18, 0xFC,
+		0x7D, 0x8E, 0x43, 0xD6, 0xD0, 0x0B, 0x27, 0x0B, 0x8C, 0x00,
+		0xF9, 0xFF, 0x8C, 0x3C, 0xF8, 0xC6, 0x70, 0x17,
+	})
+	copy(fake.Registers[REG_PRES:], []uint8{0x65, 0x5A, 0xC0, 0x7E, 0xED, 0x00})
+
+	dev := New(bus)
+	dev.Configure(STANDBY_125MS, FILTER_4X, SAMPLING_16X, SAMPLING_16X, MODE_FORCED)
+	c.Assert(dev.Temperature, qt.Equals, Oversampling(SAMPLING_16X))
+	c.Assert(fake.Registers[REG_CTRL_MEAS], qt.Equals, uint8(SAMPLING_16X<<5|SAMPLING_16X<<2)|uint8(MODE_FORCED))
+
+	sensor := dev.Sensor()
+	c.Assert(sensor.Update(drivers.Temperature|drivers.Pressure), qt.IsNil)
+	c.Assert(sensor.Temperature(), qt.Equals, int32(25080))
+	// The 32-bit compensation is 3 Pa above the 100653.27 Pa of the
+	// floating-point one.
+	c.Assert(sensor.Pressure(), qt.Equals, int32(100656000))
+}
@@ -34,6 +34,10 @@ type Device struct {
 	Address uint8
 	cali    calibrationCoefficients
 	Config  Config
+
+	// last values read by Update
+	temperature int32
+	pressure    int32
 }
 
 type calibrationCoefficients struct {
@@ -122,15 +126,18 @@ func (d *Device) tlinCompensate() (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	return d.tlin(rawTemp), nil
+}
 
+// tlin computes the temperature compensation value from a raw temperature.
+func (d *Device) tlin(rawTemp int64) int64 {
 	// pulled from C driver: https://github.com/BoschSensortec/BMP3-Sensor-API/blob/master/bmp3.c
 	partialData1 := rawTemp - (256 * int64(d.cali.t1))
 	partialData2 := int64(d.cali.t2) * partialData1
 	partialData3 := (partialData1 * partialData1)
 	partialData4 := partialData3 * int64(d.cali.t3)
 	partialData5 := (partialData2 * 262144) + partialData4
-	return partialData5 / 4294967296, nil
-
+	return partialData5 / 4294967296
 }
 
 // ReadTemperature returns the temperature in centicelsius, i.e 2426 / 100 = 24.26 C
@@ -156,7 +163,11 @@ func (d *Device) ReadPressure() (int32, error) {
 	if err != nil {
 		return 0, err
 	}
+	return d.compensatePressure(tlin, rawPress), nil
+}
 
+// compensatePressure converts a raw pressure to centipascals.
+func (d *Device) compensatePressure(tlin, rawPress int64) int32 {
 	// code pulled from bmp388 C driver: https://github.com/BoschSensortec/BMP3-Sensor-API/blob/master/bmp3.c
 	partialData1 := tlin * tlin
 	partialData2 := partialData1 / 64
@@ -183,7 +194,44 @@ func (d *Device) ReadPressure() (int32, error) {
 	partialData3 = (partialData2 * rawPress) / 128
 	partialData4 = (offset / 4) + partialData1 + partialData5 + partialData3
 	compPress := ((uint64(partialData4) * 25) / uint64(1099511627776))
-	return int32(compPress), nil
+	return int32(compPress)
+}
+
+// Update reads the selected measurements in a single burst read. Use
+// Temperature and Pressure to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Pressure) == 0 {
+		return nil
+	}
+	if err := d.startMeasurement(); err != nil {
+		return err
+	}
+	// Pressure data registers are directly followed by the temperature
+	bytes, err := d.readRegister(RegPress, 6)
+	if err != nil {
+		return err
+	}
+	rawPress := int64(bytes[2])<<16 | int64(bytes[1])<<8 | int64(bytes[0])
+	rawTemp := int64(bytes[5])<<16 | int64(bytes[4])<<8 | int64(bytes[3])
+
+	tlin := d.tlin(rawTemp)
+	d.temperature = int32((tlin*25)/16384) * 10
+	if which&drivers.Pressure != 0 {
+		d.pressure = d.compensatePressure(tlin, rawPress) * 10
+	}
+	return nil
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// Pressure returns the pressure in milli pascals (mPa) read by the last call
+// to Update.
+func (d *Device) Pressure() int32 {
+	return d.pressure
 }
 
 // SoftReset commands the BMP388 to reset of all user configuration settings
@@ -210,18 +258,9 @@ func (d *Device) SetMode(mode Mode) error {
 }
 
 func (d *Device) readSensorData(register byte) (data int64, err error) {
-
-	if !d.Connected() {
-		return 0, errNotConnected
-	}
-
-	// put the sensor back into forced mode to get a reading, the sensor goes back to sleep after taking one read in
-	// forced mode
-	if d.Config.Mode != Normal {
-		err = d.SetMode(Forced)
-		if err != nil {
-			return
-		}
+	err = d.startMeasurement()
+	if err != nil {
+		return
 	}
 
 	bytes, err := d.readRegister(register, 3)
@@ -232,6 +271,21 @@ func (d *Device) readSensorData(register byte) (data int64, err error) {
 	return
 }
 
+// startMeasurement checks the sensor is reachable and, unless it runs in
+// normal mode, triggers a new measurement.
+func (d *Device) startMeasurement() error {
+	if !d.Connected() {
+		return errNotConnected
+	}
+
+	// put the sensor back into forced mode to get a reading, the sensor goes back to sleep after taking one read in
+	// forced mode
+	if d.Config.Mode != Normal {
+		return d.SetMode(Forced)
+	}
+	return nil
+}
+
 // configurationError checks the register error for the configuration error bit. The bit is cleared on read by the bmp.
 func (d *Device) configurationError() bool {
 	data, err := d.readRegister(RegErr, 1)
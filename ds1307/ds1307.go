@@ -26,8 +26,16 @@ func New(bus drivers.I2C) Device {
 	}
 }
 
-// SetTime sets the time and date
+// SetTime sets the time and date. The DS1307 has no century bit and makes
+// every year divisible by 4 a leap year, so only the years 2000 to 2099 are
+// accepted; others return drivers.ErrTimeRange. The seconds are written with
+// the clock halt bit cleared, which starts the oscillator if it was stopped
+// and restarts the current second.
 func (d *Device) SetTime(t time.Time) error {
+	t = t.UTC()
+	if t.Year() < 2000 || t.Year() > 2099 {
+		return drivers.ErrTimeRange
+	}
 	data := make([]byte, 8)
 	data[0] = uint8(TimeDate)
 	data[1] = decToBcd(t.Second())
@@ -161,7 +169,8 @@ func bcdToDec(bcd uint8) int {
 // hoursBCDToInt converts the BCD hours to int
 func hoursBCDToInt(value uint8) (hour int) {
 	if value&0x40 != 0x00 {
-		hour = bcdToDec(value & 0x1F)
+		// 12-hour mode, from 12 AM to 11 PM.
+		hour = bcdToDec(value&0x1F) % 12
 		if (value & 0x20) != 0x00 {
 			hour += 12
 		}
@@ -0,0 +1,22 @@
+package ds1307
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestTime(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, I2CAddress)
+	bus.AddDevice(fake)
+	dev := New(bus)
+
+	tester.CheckBCDClock(c, &dev, tester.BCDClock{
+		Registers:   fake.Registers[TimeDate : TimeDate+7],
+		WeekdayBase: 1,
+		Hour12:      true,
+	})
+}
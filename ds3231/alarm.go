@@ -0,0 +1,206 @@
+package ds3231
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errAlarmMode = errors.New("ds3231: alarm mode not supported by this alarm")
+	errTimeout   = errors.New("ds3231: temperature conversion timeout")
+)
+
+// alarmMask is the bit that excludes a register of an alarm from the match.
+const alarmMask = 1 << 7
+
+// alarmWeekday is the bit of the day register of an alarm that selects the
+// day of the week instead of the day of the month.
+const alarmWeekday = 1 << 6
+
+// SetAlarm1 sets alarm 1 to go off when the time matches t as selected by
+// mode, and clears its flag. Alarm 1 has a resolution of one second, it does
+// not support ALARM_EVERY_MINUTE.
+func (d *Device) SetAlarm1(t time.Time, mode AlarmMode) error {
+	if mode == ALARM_EVERY_MINUTE {
+		return errAlarmMode
+	}
+	data := make([]uint8, REG_ALARMONE_SIZE)
+	data[0] = uint8ToBCD(uint8(t.Second()))
+	encodeAlarm(data[1:], t, mode)
+	if mode == ALARM_EVERY_SECOND {
+		data[0] |= alarmMask
+	}
+	err := d.bus.WriteRegister(uint8(d.Address), REG_ALARMONE, data)
+	if err != nil {
+		return err
+	}
+	return d.ClearAlarm(AlarmFlag_Alarm1)
+}
+
+// SetAlarm2 sets alarm 2 to go off when the time matches t as selected by
+// mode, and clears its flag. Alarm 2 has a resolution of one minute and goes
+// off at 00 seconds, it does not support ALARM_EVERY_SECOND and
+// ALARM_MATCH_SECONDS.
+func (d *Device) SetAlarm2(t time.Time, mode AlarmMode) error {
+	if mode == ALARM_EVERY_SECOND || mode == ALARM_MATCH_SECONDS {
+		return errAlarmMode
+	}
+	data := make([]uint8, REG_ALARMTWO_SIZE)
+	encodeAlarm(data, t, mode)
+	err := d.bus.WriteRegister(uint8(d.Address), REG_ALARMTWO, data)
+	if err != nil {
+		return err
+	}
+	return d.ClearAlarm(AlarmFlag_Alarm2)
+}
+
+// encodeAlarm stores the minutes, hours and day registers of an alarm.
+func encodeAlarm(data []uint8, t time.Time, mode AlarmMode) {
+	data[0] = uint8ToBCD(uint8(t.Minute()))
+	data[1] = uint8ToBCD(uint8(t.Hour()))
+	data[2] = uint8ToBCD(uint8(t.Day()))
+	if mode == ALARM_MATCH_WEEKDAY {
+		data[2] = alarmWeekday | uint8ToBCD(weekday(t))
+	}
+	// Each mode matches one register more than the previous one, except
+	// for the two day modes.
+	if mode < ALARM_MATCH_DATE {
+		data[2] |= alarmMask
+	}
+	if mode < ALARM_MATCH_HOURS {
+		data[1] |= alarmMask
+	}
+	if mode < ALARM_MATCH_MINUTES {
+		data[0] |= alarmMask
+	}
+}
+
+// ClearAlarm clears the flags of the given alarms, which releases the INT/SQW
+// pin.
+func (d *Device) ClearAlarm(alarm Alarm) error {
+	return d.updateStatus(uint8(alarm), 0)
+}
+
+// EnableAlarmInterrupt enables the interrupt of the given alarms. When
+// triggered, the INT/SQW pin goes low. This stops the square wave output.
+func (d *Device) EnableAlarmInterrupt(alarm Alarm) error {
+	return d.updateRegister(REG_CONTROL, 0, uint8(alarm)|1<<INTCN)
+}
+
+// DisableAlarmInterrupt disables the interrupt of the given alarms.
+func (d *Device) DisableAlarmInterrupt(alarm Alarm) error {
+	return d.updateRegister(REG_CONTROL, uint8(alarm), 0)
+}
+
+// AlarmTriggered returns whether or not one of the given alarms has been
+// triggered. The flags stay set until ClearAlarm is called.
+func (d *Device) AlarmTriggered(alarm Alarm) bool {
+	data := []uint8{0}
+	err := d.bus.ReadRegister(uint8(d.Address), REG_STATUS, data)
+	if err != nil {
+		return false
+	}
+	return data[0]&uint8(alarm) != 0
+}
+
+// SetOscillatorFrequency sets the output of the INT/SQW pin to a square wave
+// of the given frequency, or to the alarm interrupts with SQW_OFF.
+func (d *Device) SetOscillatorFrequency(freq SQWFrequency) error {
+	return d.updateRegister(REG_CONTROL, 1<<INTCN|1<<RS2|1<<RS1, uint8(freq))
+}
+
+// SetOutput32kHz enables or disables the 32kHz output pin.
+func (d *Device) SetOutput32kHz(enabled bool) error {
+	if enabled {
+		return d.updateStatus(0, 1<<EN32KHZ)
+	}
+	return d.updateStatus(1<<EN32KHZ, 0)
+}
+
+// ConvertTemperature starts a temperature conversion and waits until it
+// completes, so that ReadTemperature returns a fresh value instead of the
+// one of the automatic conversion every 64 seconds. It also updates the
+// compensation of the oscillator.
+func (d *Device) ConvertTemperature() error {
+	data := []uint8{0}
+	start := time.Now()
+	for {
+		// Wait for the end of an automatic conversion first.
+		err := d.bus.ReadRegister(uint8(d.Address), REG_STATUS, data)
+		if err != nil {
+			return err
+		}
+		if data[0]&(1<<BSY) == 0 {
+			break
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			return errTimeout
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err := d.updateRegister(REG_CONTROL, 0, 1<<CONV)
+	if err != nil {
+		return err
+	}
+	for {
+		err := d.bus.ReadRegister(uint8(d.Address), REG_CONTROL, data)
+		if err != nil {
+			return err
+		}
+		if data[0]&(1<<CONV) == 0 {
+			return nil
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			return errTimeout
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// SetAgingOffset sets the aging offset that trims the frequency of the
+// oscillator. Positive values slow the clock down and negative values
+// speed it up, by about 0.1ppm per step at 25°C. The new offset applies
+// from the next temperature conversion.
+func (d *Device) SetAgingOffset(offset int8) error {
+	return d.bus.WriteRegister(uint8(d.Address), REG_AGING, []uint8{uint8(offset)})
+}
+
+// ReadAgingOffset returns the aging offset.
+func (d *Device) ReadAgingOffset() (int8, error) {
+	data := []uint8{0}
+	err := d.bus.ReadRegister(uint8(d.Address), REG_AGING, data)
+	if err != nil {
+		return 0, err
+	}
+	return int8(data[0]), nil
+}
+
+// statusFlags are the bits of the status register that the DS3231 sets and
+// the host can only clear. Writing 1 to them leaves them unchanged.
+const statusFlags = 1<<OSF | 1<<A2F | 1<<A1F
+
+// updateStatus clears the bits of clear and sets the bits of set in the
+// status register. The flags not in clear are written as 1 rather than as
+// read, so that a flag set by the DS3231 between the read and the write is
+// not lost.
+func (d *Device) updateStatus(clear, set uint8) error {
+	data := []uint8{0}
+	err := d.bus.ReadRegister(uint8(d.Address), REG_STATUS, data)
+	if err != nil {
+		return err
+	}
+	data[0] = data[0]&^clear | set | statusFlags&^clear
+	return d.bus.WriteRegister(uint8(d.Address), REG_STATUS, data)
+}
+
+// updateRegister clears the bits of clear and sets the bits of set in reg.
+func (d *Device) updateRegister(reg uint8, clear, set uint8) error {
+	data := []uint8{0}
+	err := d.bus.ReadRegister(uint8(d.Address), reg, data)
+	if err != nil {
+		return err
+	}
+	data[0] = data[0]&^clear | set
+	return d.bus.WriteRegister(uint8(d.Address), reg, data)
+}
@@ -73,35 +73,31 @@ func (d *Device) SetRunning(isRunning bool) error {
 	return nil
 }
 
-// SetTime sets the date and time in the DS3231
+// SetTime sets the date and time in the DS3231. The DS3231 corrects for
+// leap years only up to 2100, which it would count as a leap year, so years
+// outside 2000 to 2099 return drivers.ErrTimeRange. The century bit is
+// cleared; the DS3231 sets it when the year wraps after 2099. SetTime also
+// clears the oscillator stop flag, and the write of the seconds restarts
+// the countdown chain, so the new time starts at the beginning of a second.
 func (d *Device) SetTime(dt time.Time) error {
-	data := []byte{0}
-	err := d.bus.ReadRegister(uint8(d.Address), REG_STATUS, data)
-	if err != nil {
-		return err
+	dt = dt.UTC()
+	if dt.Year() < 2000 || dt.Year() > 2099 {
+		return drivers.ErrTimeRange
 	}
-	data[0] &^= 1 << OSF
-	err = d.bus.WriteRegister(uint8(d.Address), REG_STATUS, data)
+	err := d.updateStatus(1<<OSF, 0)
 	if err != nil {
 		return err
 	}
 
-	data = make([]uint8, 7)
+	data := make([]uint8, 7)
 	data[0] = uint8ToBCD(uint8(dt.Second()))
 	data[1] = uint8ToBCD(uint8(dt.Minute()))
 	data[2] = uint8ToBCD(uint8(dt.Hour()))
 
-	year := uint8(dt.Year() - 2000)
-	centuryFlag := uint8(0)
-	if year >= 100 {
-		year -= 100
-		centuryFlag = 1 << 7
-	}
-
-	data[3] = uint8ToBCD(uint8(dt.Weekday()))
+	data[3] = uint8ToBCD(weekday(dt))
 	data[4] = uint8ToBCD(uint8(dt.Day()))
-	data[5] = uint8ToBCD(uint8(dt.Month()) | centuryFlag)
-	data[6] = uint8ToBCD(year)
+	data[5] = uint8ToBCD(uint8(dt.Month()))
+	data[6] = uint8ToBCD(uint8(dt.Year() - 2000))
 
 	err = d.bus.WriteRegister(uint8(d.Address), REG_TIMEDATE, data)
 	if err != nil {
@@ -140,7 +136,15 @@ func (d *Device) ReadTemperature() (int32, error) {
 	if err != nil {
 		return 0, err
 	}
-	return int32(data[0])*1000 + int32((data[1]>>6)*25)*10, nil
+	// 10-bit two's complement in 0.25°C.
+	raw := int16(uint16(data[0])<<8|uint16(data[1])) >> 6
+	return int32(raw) * 250, nil
+}
+
+// weekday returns the day of the week as stored by the DS3231, from 1 for
+// Sunday to 7 for Saturday.
+func weekday(t time.Time) uint8 {
+	return uint8(t.Weekday()) + 1
 }
 
 // uint8ToBCD converts a byte to BCD for the DS3231
@@ -156,7 +160,8 @@ func bcdToInt(value uint8) int {
 // hoursBCDToInt converts the BCD hours to int
 func hoursBCDToInt(value uint8) (hour int) {
 	if value&0x40 != 0x00 {
-		hour = bcdToInt(value & 0x1F)
+		// 12-hour mode, from 12 AM to 11 PM.
+		hour = bcdToInt(value&0x1F) % 12
 		if (value & 0x20) != 0x00 {
 			hour += 12
 		}
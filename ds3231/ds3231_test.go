@@ -0,0 +1,160 @@
+package ds3231
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers/tester"
+)
+
+func newDevice(c *qt.C) (*Device, *tester.I2CDevice8) {
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	bus.AddDevice(fake)
+	dev := New(bus)
+	return &dev, fake
+}
+
+func TestSetAlarm(t *testing.T) {
+	c := qt.New(t)
+	dev, fake := newDevice(c)
+	// Saturday 29 February 2020, 23:59:45.
+	at := time.Date(2020, 2, 29, 23, 59, 45, 0, time.UTC)
+
+	for _, test := range []struct {
+		mode AlarmMode
+		want []uint8
+	}{
+		{ALARM_EVERY_SECOND, []uint8{0xC5, 0xD9, 0xA3, 0xA9}},
+		{ALARM_MATCH_SECONDS, []uint8{0x45, 0xD9, 0xA3, 0xA9}},
+		{ALARM_MATCH_MINUTES, []uint8{0x45, 0x59, 0xA3, 0xA9}},
+		{ALARM_MATCH_HOURS, []uint8{0x45, 0x59, 0x23, 0xA9}},
+		{ALARM_MATCH_DATE, []uint8{0x45, 0x59, 0x23, 0x29}},
+		{ALARM_MATCH_WEEKDAY, []uint8{0x45, 0x59, 0x23, 0x47}},
+	} {
+		fake.Registers[REG_STATUS] = 1<<OSF | 1<<A2F | 1<<A1F
+		c.Assert(dev.SetAlarm1(at, test.mode), qt.IsNil)
+		c.Assert(fake.Registers[REG_ALARMONE:REG_ALARMONE+REG_ALARMONE_SIZE], qt.DeepEquals, test.want, qt.Commentf("mode %d", test.mode))
+		c.Assert(fake.Registers[REG_STATUS], qt.Equals, uint8(1<<OSF|1<<A2F))
+	}
+	c.Assert(dev.SetAlarm1(at, ALARM_EVERY_MINUTE), qt.Equals, errAlarmMode)
+
+	for _, test := range []struct {
+		mode AlarmMode
+		want []uint8
+	}{
+		{ALARM_EVERY_MINUTE, []uint8{0xD9, 0xA3, 0xA9}},
+		{ALARM_MATCH_MINUTES, []uint8{0x59, 0xA3, 0xA9}},
+		{ALARM_MATCH_HOURS, []uint8{0x59, 0x23, 0xA9}},
+		{ALARM_MATCH_DATE, []uint8{0x59, 0x23, 0x29}},
+		{ALARM_MATCH_WEEKDAY, []uint8{0x59, 0x23, 0x47}},
+	} {
+		fake.Registers[REG_STATUS] = 1<<A2F | 1<<A1F
+		c.Assert(dev.SetAlarm2(at, test.mode), qt.IsNil)
+		c.Assert(fake.Registers[REG_ALARMTWO:REG_ALARMTWO+REG_ALARMTWO_SIZE], qt.DeepEquals, test.want, qt.Commentf("mode %d", test.mode))
+		// The flags that stay are written as 1, which the DS3231 ignores.
+		c.Assert(fake.Registers[REG_STATUS], qt.Equals, uint8(1<<OSF|1<<A1F))
+	}
+	c.Assert(dev.SetAlarm2(at, ALARM_EVERY_SECOND), qt.Equals, errAlarmMode)
+	c.Assert(dev.SetAlarm2(at, ALARM_MATCH_SECONDS), qt.Equals, errAlarmMode)
+}
+
+func TestAlarmInterrupt(t *testing.T) {
+	c := qt.New(t)
+	dev, fake := newDevice(c)
+	fake.Registers[REG_CONTROL] = 1<<RS2 | 1<<RS1
+
+	c.Assert(dev.EnableAlarmInterrupt(AlarmFlag_AlarmBoth), qt.IsNil)
+	c.Assert(fake.Registers[REG_CONTROL], qt.Equals, uint8(1<<RS2|1<<RS1|1<<INTCN|1<<A2IE|1<<A1IE))
+	c.Assert(dev.DisableAlarmInterrupt(AlarmFlag_Alarm1), qt.IsNil)
+	c.Assert(fake.Registers[REG_CONTROL], qt.Equals, uint8(1<<RS2|1<<RS1|1<<INTCN|1<<A2IE))
+
+	c.Assert(dev.AlarmTriggered(AlarmFlag_AlarmBoth), qt.IsFalse)
+	fake.Registers[REG_STATUS] = 1 << A2F
+	c.Assert(dev.AlarmTriggered(AlarmFlag_Alarm1), qt.IsFalse)
+	c.Assert(dev.AlarmTriggered(AlarmFlag_Alarm2), qt.IsTrue)
+	c.Assert(dev.ClearAlarm(AlarmFlag_Alarm2), qt.IsNil)
+	c.Assert(dev.AlarmTriggered(AlarmFlag_Alarm2), qt.IsFalse)
+	// The flags that stay are written as 1, which the DS3231 ignores, so a
+	// flag set between the read and the write is not cleared.
+	c.Assert(fake.Registers[REG_STATUS], qt.Equals, uint8(1<<OSF|1<<A1F))
+
+	// A square wave replaces the interrupts, which stay enabled.
+	c.Assert(dev.SetOscillatorFrequency(SQW_1KHZ), qt.IsNil)
+	c.Assert(fake.Registers[REG_CONTROL], qt.Equals, uint8(1<<RS1|1<<A2IE))
+	c.Assert(dev.SetOscillatorFrequency(SQW_OFF), qt.IsNil)
+	c.Assert(fake.Registers[REG_CONTROL], qt.Equals, uint8(1<<INTCN|1<<A2IE))
+}
+
+func TestOutput32kHz(t *testing.T) {
+	c := qt.New(t)
+	dev, fake := newDevice(c)
+	fake.Registers[REG_STATUS] = 1<<OSF | 1<<A1F
+
+	c.Assert(dev.SetOutput32kHz(true), qt.IsNil)
+	// The flags are written as 1, which the DS3231 ignores.
+	c.Assert(fake.Registers[REG_STATUS], qt.Equals, uint8(1<<OSF|1<<EN32KHZ|1<<A2F|1<<A1F))
+	c.Assert(dev.SetOutput32kHz(false), qt.IsNil)
+	c.Assert(fake.Registers[REG_STATUS], qt.Equals, uint8(1<<OSF|1<<A2F|1<<A1F))
+}
+
+func TestTemperature(t *testing.T) {
+	c := qt.New(t)
+	dev, fake := newDevice(c)
+
+	// The mock clears CONV after it has been read once, as the end of the
+	// conversion would.
+	fake.Registers[REG_CONTROL] = 1 << INTCN
+	fake.Flags[REG_CONTROL] = tester.RegisterClearOnRead
+	c.Assert(dev.ConvertTemperature(), qt.IsNil)
+	c.Assert(fake.Registers[REG_CONTROL], qt.Equals, uint8(0))
+
+	fake.Flags[REG_CONTROL] = 0
+	fake.Registers[REG_CONTROL] = 1 << CONV
+	c.Assert(dev.ConvertTemperature(), qt.Equals, errTimeout)
+
+	for _, test := range []struct {
+		msb, lsb uint8
+		want     int32
+	}{
+		{0x19, 0x40, 25250},
+		{0x00, 0x00, 0},
+		{0xFF, 0xC0, -250},
+		{0xE7, 0x00, -25000},
+	} {
+		fake.Registers[REG_TEMP] = test.msb
+		fake.Registers[REG_TEMP+1] = test.lsb
+		temp, err := dev.ReadTemperature()
+		c.Assert(err, qt.IsNil)
+		c.Assert(temp, qt.Equals, test.want)
+	}
+}
+
+func TestAgingOffset(t *testing.T) {
+	c := qt.New(t)
+	dev, fake := newDevice(c)
+
+	c.Assert(dev.SetAgingOffset(-12), qt.IsNil)
+	c.Assert(fake.Registers[REG_AGING], qt.Equals, uint8(0xF4))
+	offset, err := dev.ReadAgingOffset()
+	c.Assert(err, qt.IsNil)
+	c.Assert(offset, qt.Equals, int8(-12))
+}
+
+func TestTime(t *testing.T) {
+	c := qt.New(t)
+	dev, fake := newDevice(c)
+
+	tester.CheckBCDClock(c, dev, tester.BCDClock{
+		Registers:   fake.Registers[REG_TIMEDATE : REG_TIMEDATE+7],
+		WeekdayBase: 1,
+		Hour12:      true,
+	})
+
+	// The century bit is set when the year wraps after 2099.
+	copy(fake.Registers[REG_TIMEDATE:], []uint8{0x00, 0x00, 0x00, 0x06, 0x01, 0x81, 0x00})
+	got, err := dev.ReadTime()
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+}
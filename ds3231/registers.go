@@ -46,3 +46,47 @@ const (
 	AlarmTwo      Mode = 4
 	ModeAlarmBoth Mode = 5
 )
+
+// Alarm selects one or both of the alarms of the DS3231: AlarmFlag_Alarm1,
+// AlarmFlag_Alarm2 or AlarmFlag_AlarmBoth.
+type Alarm uint8
+
+// AlarmMode selects which parts of the time an alarm matches.
+type AlarmMode uint8
+
+const (
+	// ALARM_EVERY_SECOND goes off every second. Alarm 1 only.
+	ALARM_EVERY_SECOND AlarmMode = iota
+	// ALARM_EVERY_MINUTE goes off every minute, at 00 seconds. Alarm 2
+	// only.
+	ALARM_EVERY_MINUTE
+	// ALARM_MATCH_SECONDS goes off when the seconds match, once a minute.
+	// Alarm 1 only.
+	ALARM_MATCH_SECONDS
+	// ALARM_MATCH_MINUTES goes off when the minutes (and seconds) match,
+	// once an hour.
+	ALARM_MATCH_MINUTES
+	// ALARM_MATCH_HOURS goes off when the hours, minutes (and seconds)
+	// match, once a day.
+	ALARM_MATCH_HOURS
+	// ALARM_MATCH_DATE goes off when the day of the month and the time
+	// match, once a month.
+	ALARM_MATCH_DATE
+	// ALARM_MATCH_WEEKDAY goes off when the day of the week and the time
+	// match, once a week.
+	ALARM_MATCH_WEEKDAY
+)
+
+// SQWFrequency is the output of the INT/SQW pin.
+type SQWFrequency uint8
+
+const (
+	SQW_1HZ  SQWFrequency = 0
+	SQW_1KHZ SQWFrequency = 1 << RS1        // 1.024kHz
+	SQW_4KHZ SQWFrequency = 1 << RS2        // 4.096kHz
+	SQW_8KHZ SQWFrequency = 1<<RS2 | 1<<RS1 // 8.192kHz
+
+	// SQW_OFF stops the square wave, so that the pin signals the alarms
+	// enabled with EnableAlarmInterrupt.
+	SQW_OFF SQWFrequency = 1 << INTCN
+)
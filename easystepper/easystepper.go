@@ -3,8 +3,9 @@ package easystepper // import "tinygo.org/x/drivers/easystepper"
 
 import (
 	"errors"
-	"machine"
 	"time"
+
+	"tinygo.org/x/drivers"
 )
 
 // StepMode determines the coil sequence used to perform a single step
@@ -33,7 +34,7 @@ func (sm StepMode) stepCount() uint {
 // DeviceConfig contains the configuration data for a single easystepper driver
 type DeviceConfig struct {
 	// Pin1 ... Pin4 determines the pins to configure and use for the device
-	Pin1, Pin2, Pin3, Pin4 machine.Pin
+	Pin1, Pin2, Pin3, Pin4 drivers.Pin
 	// StepCount is the number of steps required to perform a full revolution of the stepper motor
 	StepCount uint
 	// RPM determines the speed of the stepper motor in 'Revolutions per Minute'
@@ -46,12 +47,12 @@ type DeviceConfig struct {
 type DualDeviceConfig struct {
 	DeviceConfig
 	// Pin5 ... Pin8 determines the pins to configure and use for the second device
-	Pin5, Pin6, Pin7, Pin8 machine.Pin
+	Pin5, Pin6, Pin7, Pin8 drivers.Pin
 }
 
 // Device holds the pins and the delay between steps
 type Device struct {
-	pins       [4]machine.Pin
+	pins       [4]drivers.Pin
 	stepDelay  time.Duration
 	stepNumber uint8
 	stepMode   StepMode
@@ -68,7 +69,7 @@ func New(config DeviceConfig) (*Device, error) {
 		return nil, errors.New("config.StepCount and config.RPM must be > 0")
 	}
 	return &Device{
-		pins:      [4]machine.Pin{config.Pin1, config.Pin2, config.Pin3, config.Pin4},
+		pins:      [4]drivers.Pin{config.Pin1, config.Pin2, config.Pin3, config.Pin4},
 		stepDelay: time.Second * 60 / time.Duration((config.StepCount * config.RPM)),
 		stepMode:  config.Mode,
 	}, nil
@@ -77,7 +78,7 @@ func New(config DeviceConfig) (*Device, error) {
 // Configure configures the pins of the Device
 func (d *Device) Configure() {
 	for _, pin := range d.pins {
-		pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+		drivers.ConfigurePin(pin, drivers.PinOutput)
 	}
 }
 
@@ -126,7 +127,7 @@ func (d *Device) Move(steps int32) {
 // Off turns off all motor pins
 func (d *Device) Off() {
 	for _, pin := range d.pins {
-		pin.Low()
+		pin.Set(false)
 	}
 }
 
@@ -187,28 +188,28 @@ func (d *Device) stepMotor(step uint8) {
 func (d *Device) stepMotor4(step uint8) {
 	switch step {
 	case 0:
-		d.pins[0].High()
-		d.pins[1].Low()
-		d.pins[2].High()
-		d.pins[3].Low()
+		d.pins[0].Set(true)
+		d.pins[1].Set(false)
+		d.pins[2].Set(true)
+		d.pins[3].Set(false)
 		break
 	case 1:
-		d.pins[0].Low()
-		d.pins[1].High()
-		d.pins[2].High()
-		d.pins[3].Low()
+		d.pins[0].Set(false)
+		d.pins[1].Set(true)
+		d.pins[2].Set(true)
+		d.pins[3].Set(false)
 		break
 	case 2:
-		d.pins[0].Low()
-		d.pins[1].High()
-		d.pins[2].Low()
-		d.pins[3].High()
+		d.pins[0].Set(false)
+		d.pins[1].Set(true)
+		d.pins[2].Set(false)
+		d.pins[3].Set(true)
 		break
 	case 3:
-		d.pins[0].High()
-		d.pins[1].Low()
-		d.pins[2].Low()
-		d.pins[3].High()
+		d.pins[0].Set(true)
+		d.pins[1].Set(false)
+		d.pins[2].Set(false)
+		d.pins[3].Set(true)
 		break
 	}
 	d.stepNumber = step
@@ -218,45 +219,45 @@ func (d *Device) stepMotor4(step uint8) {
 func (d *Device) stepMotor8(step uint8) {
 	switch step {
 	case 0:
-		d.pins[0].High()
-		d.pins[2].Low()
-		d.pins[1].Low()
-		d.pins[3].Low()
+		d.pins[0].Set(true)
+		d.pins[2].Set(false)
+		d.pins[1].Set(false)
+		d.pins[3].Set(false)
 	case 1:
-		d.pins[0].High()
-		d.pins[2].High()
-		d.pins[1].Low()
-		d.pins[3].Low()
+		d.pins[0].Set(true)
+		d.pins[2].Set(true)
+		d.pins[1].Set(false)
+		d.pins[3].Set(false)
 	case 2:
-		d.pins[0].Low()
-		d.pins[2].High()
-		d.pins[1].Low()
-		d.pins[3].Low()
+		d.pins[0].Set(false)
+		d.pins[2].Set(true)
+		d.pins[1].Set(false)
+		d.pins[3].Set(false)
 	case 3:
-		d.pins[0].Low()
-		d.pins[2].High()
-		d.pins[1].High()
-		d.pins[3].Low()
+		d.pins[0].Set(false)
+		d.pins[2].Set(true)
+		d.pins[1].Set(true)
+		d.pins[3].Set(false)
 	case 4:
-		d.pins[0].Low()
-		d.pins[2].Low()
-		d.pins[1].High()
-		d.pins[3].Low()
+		d.pins[0].Set(false)
+		d.pins[2].Set(false)
+		d.pins[1].Set(true)
+		d.pins[3].Set(false)
 	case 5:
-		d.pins[0].Low()
-		d.pins[2].Low()
-		d.pins[1].High()
-		d.pins[3].High()
+		d.pins[0].Set(false)
+		d.pins[2].Set(false)
+		d.pins[1].Set(true)
+		d.pins[3].Set(true)
 	case 6:
-		d.pins[0].Low()
-		d.pins[2].Low()
-		d.pins[1].Low()
-		d.pins[3].High()
+		d.pins[0].Set(false)
+		d.pins[2].Set(false)
+		d.pins[1].Set(false)
+		d.pins[3].Set(true)
 	case 7:
-		d.pins[0].High()
-		d.pins[2].Low()
-		d.pins[1].Low()
-		d.pins[3].High()
+		d.pins[0].Set(true)
+		d.pins[2].Set(false)
+		d.pins[1].Set(false)
+		d.pins[3].Set(true)
 	}
 	d.stepNumber = step
 }
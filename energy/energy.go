@@ -0,0 +1,106 @@
+// Package energy integrates the current and power readings of a power
+// monitor, such as the INA219 or INA260, into charge and energy, for
+// example to measure the capacity of a battery.
+package energy // import "tinygo.org/x/drivers/energy"
+
+import "time"
+
+// Meter is a power monitor that measures current and power.
+type Meter interface {
+	// Current returns the current in µA.
+	Current() (int32, error)
+
+	// Power returns the power in µW.
+	Power() (int32, error)
+}
+
+// microHour is one hour in µs, doubled because the readings are
+// integrated with the trapezoidal rule, which halves the sum of two
+// readings.
+const microHour = 2 * 3600 * 1000000
+
+// Accumulator integrates the readings of a Meter over time.
+//
+// The time between readings is measured, not assumed, and the integrals
+// are kept exactly in integers, so neither irregular polling nor rounding
+// makes the totals drift, however long the accumulator runs.
+type Accumulator struct {
+	meter Meter
+
+	started        bool
+	start, last    time.Time
+	current, power int32
+
+	// charge and energy are the integrals in µAh and µWh; chargeRem and
+	// energyRem are the remainders in units of 1/microHour of those.
+	charge, chargeRem int64
+	energy, energyRem int64
+}
+
+// New returns an Accumulator that reads meter.
+func New(meter Meter) *Accumulator {
+	return &Accumulator{meter: meter}
+}
+
+// Update reads the current and power from the meter and adds them at the
+// current time. Call it regularly; the faster, the more accurate the
+// integrals are for changing loads.
+func (a *Accumulator) Update() error {
+	current, err := a.meter.Current()
+	if err != nil {
+		return err
+	}
+	power, err := a.meter.Power()
+	if err != nil {
+		return err
+	}
+	a.Add(current, power, time.Now())
+	return nil
+}
+
+// Add adds a reading of the current in µA and power in µW taken at time t.
+// The first reading starts the integration. Readings taken before the
+// previous one are ignored.
+func (a *Accumulator) Add(current, power int32, t time.Time) {
+	if !a.started {
+		a.started = true
+		a.start, a.last = t, t
+		a.current, a.power = current, power
+		return
+	}
+	dt := int64(t.Sub(a.last) / time.Microsecond)
+	if dt < 0 {
+		return
+	}
+	// Advance by whole µs only, so the rest is counted next time.
+	a.last = a.last.Add(time.Duration(dt) * time.Microsecond)
+
+	a.chargeRem += (int64(a.current) + int64(current)) * dt
+	a.charge += a.chargeRem / microHour
+	a.chargeRem %= microHour
+	a.energyRem += (int64(a.power) + int64(power)) * dt
+	a.energy += a.energyRem / microHour
+	a.energyRem %= microHour
+	a.current, a.power = current, power
+}
+
+// Charge returns the charge in µAh (1/1000 mAh) since the first reading.
+// It decreases while the current is negative.
+func (a *Accumulator) Charge() int64 {
+	return a.charge
+}
+
+// Energy returns the energy in µWh (1/1000 mWh) since the first reading.
+func (a *Accumulator) Energy() int64 {
+	return a.energy
+}
+
+// Elapsed returns the time between the first and the last reading.
+func (a *Accumulator) Elapsed() time.Duration {
+	return a.last.Sub(a.start)
+}
+
+// Reset clears the integrals. The next reading starts a new integration.
+func (a *Accumulator) Reset() {
+	*a = Accumulator{meter: a.meter}
+}
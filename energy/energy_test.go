@@ -0,0 +1,68 @@
+package energy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/ina260"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ Meter = (*ina260.Device)(nil)
+
+func TestAccumulator(t *testing.T) {
+	c := qt.New(t)
+	a := New(nil)
+	start := time.Unix(1000, 0)
+
+	// 500mA and 2.5W for an hour, read at irregular intervals of a few ms
+	// that do not divide a µs evenly.
+	now := start
+	a.Add(500000, 2500000, now)
+	for now.Sub(start) < time.Hour {
+		now = now.Add(time.Duration(1000000+(now.UnixNano()%7)*123457) * time.Nanosecond)
+		if now.Sub(start) > time.Hour {
+			now = start.Add(time.Hour)
+		}
+		a.Add(500000, 2500000, now)
+	}
+	c.Assert(a.Elapsed(), qt.Equals, time.Hour)
+	c.Assert(a.Charge(), qt.Equals, int64(500000))
+	c.Assert(a.Energy(), qt.Equals, int64(2500000))
+
+	// A linear ramp is integrated exactly.
+	a.Reset()
+	a.Add(0, 0, start)
+	a.Add(-1000000, 4000000, start.Add(30*time.Minute))
+	c.Assert(a.Charge(), qt.Equals, int64(-250000))
+	c.Assert(a.Energy(), qt.Equals, int64(1000000))
+
+	// Readings out of order are ignored.
+	a.Add(0, 0, start)
+	c.Assert(a.Elapsed(), qt.Equals, 30*time.Minute)
+}
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice16(c, ina260.Address)
+	fake.Registers = map[uint8]uint16{
+		ina260.REG_CURRENT: 0x0190, // 500mA
+		ina260.REG_POWER:   0x00FA, // 2.5W
+	}
+	bus.AddDevice(fake)
+	dev := ina260.New(bus)
+	a := New(&dev)
+
+	c.Assert(a.Update(), qt.IsNil)
+	c.Assert(a.current, qt.Equals, int32(500000))
+	c.Assert(a.power, qt.Equals, int32(2500000))
+
+	busErr := errors.New("nack")
+	fake.Err = busErr
+	err := a.Update()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
@@ -0,0 +1,50 @@
+package drivers
+
+import "errors"
+
+// ErrNotResponding is the error drivers report when a device does not
+// respond on its bus, for example because it was disconnected or lost
+// power. Check for it with errors.Is, since drivers return it wrapped
+// together with the error of the bus:
+//
+//	if errors.Is(err, drivers.ErrNotResponding) {
+//		// reset or re-initialize the device
+//	}
+var ErrNotResponding = errors.New("device not responding")
+
+// ErrNotReady is returned by Sensor.Update when the device has no new
+// measurement since the previous one, for example because it measures
+// periodically. The values stored by the previous Update are kept.
+var ErrNotReady = errors.New("no new measurement available")
+
+// NotResponding returns an error that matches ErrNotResponding and wraps
+// err, the error of a failed bus transaction. It returns nil if err is nil,
+// so it can wrap the result of a bus call directly:
+//
+//	return drivers.NotResponding(d.bus.ReadRegister(addr, reg, buf))
+func NotResponding(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*notRespondingError); ok {
+		return err
+	}
+	return &notRespondingError{err}
+}
+
+// notRespondingError is a bus error reported as ErrNotResponding.
+type notRespondingError struct {
+	err error
+}
+
+func (e *notRespondingError) Error() string {
+	return ErrNotResponding.Error() + ": " + e.err.Error()
+}
+
+func (e *notRespondingError) Unwrap() error {
+	return e.err
+}
+
+func (e *notRespondingError) Is(target error) bool {
+	return target == ErrNotResponding
+}
@@ -0,0 +1,23 @@
+package drivers_test
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+)
+
+func TestNotResponding(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(drivers.NotResponding(nil), qt.IsNil)
+
+	busErr := errors.New("i2c: nack")
+	err := drivers.NotResponding(busErr)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	c.Assert(err.Error(), qt.Equals, "device not responding: i2c: nack")
+
+	// Wrapping twice does not repeat the message.
+	c.Assert(drivers.NotResponding(err), qt.Equals, err)
+}
@@ -0,0 +1,81 @@
+// Tilt compensated compass with a MAG3110 magnetometer and an MMA8653
+// accelerometer, such as on the BBC micro:bit v1. The magnetometer
+// calibration is stored in an AT24Cx EEPROM on the same I2C bus. If no
+// calibration is stored, rotate the board through all orientations for 30
+// seconds after start.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/ahrs"
+	"tinygo.org/x/drivers/at24cx"
+	"tinygo.org/x/drivers/mag3110"
+	"tinygo.org/x/drivers/mma8653"
+)
+
+// calibrationAddress is the address of the calibration in the EEPROM.
+const calibrationAddress = 0
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+
+	accel := mma8653.New(machine.I2C0)
+	accel.Configure(mma8653.DataRate200Hz, mma8653.Sensitivity2G)
+	mag := mag3110.New(machine.I2C0)
+	mag.Configure()
+	eeprom := at24cx.New(machine.I2C0)
+	eeprom.Configure(at24cx.Config{})
+
+	var cal ahrs.MagCalibration
+	data := make([]byte, ahrs.MagCalibrationSize)
+	_, err := eeprom.ReadAt(data, calibrationAddress)
+	if err == nil {
+		err = cal.UnmarshalBinary(data)
+	}
+	if err != nil {
+		cal = calibrate(mag)
+		data, _ = cal.MarshalBinary()
+		if _, err := eeprom.WriteAt(data, calibrationAddress); err != nil {
+			println("could not store calibration:", err.Error())
+		}
+	}
+	println("offset:", cal.Offset[0], cal.Offset[1], cal.Offset[2], "field:", cal.Field)
+
+	for {
+		time.Sleep(200 * time.Millisecond)
+		if err := mag.Update(drivers.MagneticField); err != nil {
+			println("could not read MAG3110:", err.Error())
+			continue
+		}
+		ax, ay, az, err := accel.ReadAcceleration()
+		if err != nil {
+			println("could not read MMA8653:", err.Error())
+			continue
+		}
+		mx, my, mz := cal.Apply(mag.MagneticField())
+		println("heading:", ahrs.Heading(ax, ay, az, mx, my, mz)/1000000, "°")
+	}
+}
+
+// calibrate fits a calibration to the readings of 30 seconds.
+func calibrate(mag *mag3110.Device) ahrs.MagCalibration {
+	for {
+		println("rotate the board through all orientations")
+		var calibrator ahrs.MagCalibrator
+		for i := 0; i < 600; i++ {
+			time.Sleep(50 * time.Millisecond)
+			if err := mag.Update(drivers.MagneticField); err != nil {
+				continue
+			}
+			calibrator.Add(mag.MagneticField())
+		}
+		cal, err := calibrator.Fit()
+		if err == nil {
+			return cal
+		}
+		println("calibration failed:", err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+// Estimates the orientation of LSM6DSOX and LIS2MDL breakout boards on the
+// same I2C bus with a Madgwick filter. Mount the boards so that the axes of
+// both sensors match.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/ahrs"
+	"tinygo.org/x/drivers/lis2mdl"
+	"tinygo.org/x/drivers/lsm6dsox"
+)
+
+const period = 10 * time.Millisecond
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+
+	imu := lsm6dsox.New(machine.I2C0)
+	err := imu.Configure(lsm6dsox.Configuration{
+		AccelRange:      lsm6dsox.ACCEL_4G,
+		AccelSampleRate: lsm6dsox.ACCEL_SR_104,
+		GyroRange:       lsm6dsox.GYRO_500DPS,
+		GyroSampleRate:  lsm6dsox.GYRO_SR_104,
+	})
+	if err != nil {
+		println("could not configure LSM6DSOX:", err.Error())
+		return
+	}
+	compass := lis2mdl.New(machine.I2C0)
+	compass.Configure(lis2mdl.Configuration{})
+
+	filter := ahrs.NewMadgwick(100)
+	var bias ahrs.BiasEstimator
+	initialized := false
+
+	for i := 0; ; i++ {
+		gx, gy, gz, err := imu.ReadRotation()
+		if err != nil {
+			println("error:", err.Error())
+			continue
+		}
+		ax, ay, az, _ := imu.ReadAcceleration()
+		mx, my, mz, _ := compass.ReadMagneticField()
+
+		if !initialized {
+			filter.SetQuaternion(ahrs.FromAccelMag(ax, ay, az, mx, my, mz))
+			initialized = true
+		}
+		// Keep the device still for a second after start to estimate the
+		// gyroscope bias.
+		bias.Update(gx, gy, gz, ax, ay, az)
+		gx, gy, gz = bias.Correct(gx, gy, gz)
+		filter.Update(gx, gy, gz, ax, ay, az, mx, my, mz, period)
+
+		if i%10 == 0 {
+			roll, pitch, yaw := filter.Quaternion().Euler()
+			println("Roll:", roll/1000, "Pitch:", pitch/1000, "Yaw:", yaw/1000, "m°")
+		}
+		time.Sleep(period)
+	}
+}
@@ -0,0 +1,52 @@
+// Records a sensor trace from LSM6DSOX and LIS2MDL breakout boards on the
+// same I2C bus, in the format of the traces in ahrs/testdata. Mount the
+// boards so that the axes of both sensors match, and describe the motion
+// and the final orientation in a comment at the top of the trace. See
+// ahrs/testdata/recorded/README.md to add the trace to the tests.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/lis2mdl"
+	"tinygo.org/x/drivers/lsm6dsox"
+)
+
+const period = 10 * time.Millisecond
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+
+	imu := lsm6dsox.New(machine.I2C0)
+	err := imu.Configure(lsm6dsox.Configuration{
+		AccelRange:      lsm6dsox.ACCEL_4G,
+		AccelSampleRate: lsm6dsox.ACCEL_SR_104,
+		GyroRange:       lsm6dsox.GYRO_500DPS,
+		GyroSampleRate:  lsm6dsox.GYRO_SR_104,
+	})
+	if err != nil {
+		println("could not configure LSM6DSOX:", err.Error())
+		return
+	}
+	compass := lis2mdl.New(machine.I2C0)
+	compass.Configure(lis2mdl.Configuration{})
+
+	println("# dt (µs), gyroscope (µ°/s), accelerometer (µg), magnetometer (nT)")
+	last := time.Now()
+	for {
+		gx, gy, gz, err := imu.ReadRotation()
+		if err != nil {
+			println("# error:", err.Error())
+			continue
+		}
+		ax, ay, az, _ := imu.ReadAcceleration()
+		mx, my, mz, _ := compass.ReadMagneticField()
+		now := time.Now()
+		dt := now.Sub(last)
+		last = now
+
+		println(dt.Microseconds(), ",", gx, ",", gy, ",", gz, ",", ax, ",", ay, ",", az, ",", mx, ",", my, ",", mz)
+		time.Sleep(period)
+	}
+}
@@ -28,28 +28,31 @@ func main() {
 	camera := amg88xx.New(machine.I2C0)
 	camera.Configure(amg88xx.Config{})
 
-	var data [64]int16
-	var value int16
+	// show the image upscaled to 128x128 in the middle of the PyBadge's
+	// display, turned to face the user
+	renderer := amg88xx.Renderer{
+		ColorMap:      amg88xx.IRON,
+		Interpolation: amg88xx.BILINEAR,
+		X:             16,
+		Width:         128,
+		Height:        128,
+		FlipX:         true,
+		FlipY:         true,
+	}
+	var detector amg88xx.Detector
+
+	var frame amg88xx.Frame
 	for {
 		// get the values of the sensor in millicelsius
-		camera.ReadPixels(&data)
-
-		for j := int16(0); j < 8; j++ {
-			for i := int16(0); i < 8; i++ {
-				value = data[63-(i+j*8)]
-				// treat anything below 18°C as 18°C
-				if value < 18000 {
-					value = 0
-				} else {
-					value = (value - 18000) / 36
-					// our color array only have 433 values, avoid getting a value that doesn't exist
-					if value > 432 {
-						value = 432
-					}
-				}
-				// show the image on the PyBadge's display
-				display.FillRectangle(16+i*16, j*16, 16, 16, colors[value])
-			}
+		if err := camera.ReadFrame(&frame); err != nil {
+			println("error:", err.Error())
+			continue
+		}
+		renderer.Render(&display, &frame)
+
+		if n := detector.Update(&frame); n > 0 {
+			spot := detector.Hotspots()[0]
+			println("hotspots:", n, "largest at", spot.X, spot.Y, "max (m°C):", spot.Max)
 		}
 	}
 
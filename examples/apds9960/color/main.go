@@ -29,8 +29,8 @@ func main() {
 
 	for {
 
-		if sensor.ColorAvailable() {
-			r, g, b, c := sensor.ReadColor()
+		if ok, _ := sensor.ColorAvailable(); ok {
+			r, g, b, c, _ := sensor.ReadColor()
 			println("Red =", r, "\tGreen =", g, "\tBlue =", b, "\tClear =", c)
 		}
 		time.Sleep(time.Millisecond * 100)
@@ -30,7 +30,7 @@ func main() {
 	for {
 
 		// wave your hand (not too slow) about 10 cm above the sensor
-		if sensor.GestureAvailable() {
+		if ok, _ := sensor.GestureAvailable(); ok {
 
 			gesture := sensor.ReadGesture()
 			print("Detected gesture: ")
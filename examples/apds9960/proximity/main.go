@@ -30,8 +30,8 @@ func main() {
 
 	for {
 
-		if sensor.ProximityAvailable() {
-			p := sensor.ReadProximity()
+		if ok, _ := sensor.ProximityAvailable(); ok {
+			p, _ := sensor.ReadProximity()
 			println("Proximity:", p)
 		}
 		time.Sleep(time.Millisecond * 100)
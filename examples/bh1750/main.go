@@ -14,8 +14,12 @@ func main() {
 	sensor.Configure()
 
 	for {
-		lux := sensor.Illuminance()
-		println("Illuminance:", lux, "lx")
+		lux, err := sensor.ReadIlluminance()
+		if err != nil {
+			println("error:", err.Error())
+		} else {
+			println("Illuminance:", lux, "lx")
+		}
 
 		time.Sleep(500 * time.Millisecond)
 	}
@@ -12,7 +12,13 @@ func main() {
 
 	machine.I2C0.Configure(machine.I2CConfig{})
 	sensor := bme280.New(machine.I2C0)
-	sensor.Configure()
+	// Measure only when reading, and sleep in between.
+	sensor.ConfigureWithSettings(bme280.Config{
+		Temperature: bme280.SAMPLING_1X,
+		Pressure:    bme280.SAMPLING_1X,
+		Humidity:    bme280.SAMPLING_1X,
+		Mode:        bme280.MODE_FORCED,
+	})
 
 	connected := sensor.Connected()
 	if !connected {
@@ -21,11 +27,14 @@ func main() {
 	println("BME280 detected")
 
 	for {
-		temp, _ := sensor.ReadTemperature()
+		temp, press, hum, err := sensor.ReadAll()
+		if err != nil {
+			println("error:", err.Error())
+			time.Sleep(2 * time.Second)
+			continue
+		}
 		println("Temperature:", strconv.FormatFloat(float64(temp)/1000, 'f', 2, 64), "°C")
-		press, _ := sensor.ReadPressure()
 		println("Pressure:", strconv.FormatFloat(float64(press)/100000, 'f', 2, 64), "hPa")
-		hum, _ := sensor.ReadHumidity()
 		println("Humidity:", strconv.FormatFloat(float64(hum)/100, 'f', 2, 64), "%")
 		alt, _ := sensor.ReadAltitude()
 		println("Altitude:", alt, "m")
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"machine"
+	"strconv"
+	"time"
+
+	"tinygo.org/x/drivers/bme680"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	sensor := bme680.New(machine.I2C0)
+
+	if !sensor.Connected() {
+		println("BME680 not detected")
+		return
+	}
+	println("BME680 detected")
+
+	err := sensor.ConfigureWithSettings(bme680.Config{
+		Temperature: bme680.SAMPLING_2X,
+		Pressure:    bme680.SAMPLING_4X,
+		Humidity:    bme680.SAMPLING_2X,
+		Filter:      bme680.FILTER_3X,
+		Heater:      bme680.HeaterProfile{Temperature: 320, Duration: 150 * time.Millisecond},
+	})
+	if err != nil {
+		println("could not configure BME680:", err.Error())
+		return
+	}
+
+	for {
+		temp, press, hum, gas, err := sensor.ReadAll()
+		if err != nil {
+			println("error:", err.Error())
+		} else {
+			println("Temperature:", strconv.FormatFloat(float64(temp)/1000, 'f', 2, 64), "°C")
+			println("Pressure:", strconv.FormatFloat(float64(press)/100000, 'f', 2, 64), "hPa")
+			println("Humidity:", strconv.FormatFloat(float64(hum)/100, 'f', 2, 64), "%")
+			println("Gas resistance:", gas, "Ω")
+		}
+		time.Sleep(3 * time.Second)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/ds3231"
+)
+
+var (
+	i2c = machine.I2C0
+	rtc = ds3231.New(i2c)
+)
+
+func main() {
+	i2c.Configure(machine.I2CConfig{})
+
+	rtc.SetTime(time.Date(2006, 1, 2, 15, 4, 50, 0, time.UTC))
+
+	// alarm 1 goes off at 15:05:00, alarm 2 every minute
+	rtc.SetAlarm1(time.Date(2006, 1, 2, 15, 5, 0, 0, time.UTC), ds3231.ALARM_MATCH_HOURS)
+	rtc.SetAlarm2(time.Time{}, ds3231.ALARM_EVERY_MINUTE)
+	rtc.EnableAlarmInterrupt(ds3231.AlarmFlag_AlarmBoth)
+
+	prev := -1
+
+	for {
+		t, _ := rtc.ReadTime()
+		if prev != t.Second() {
+			fmt.Printf("%s\r\n", t.String())
+			prev = t.Second()
+
+			if rtc.AlarmTriggered(ds3231.AlarmFlag_Alarm1) {
+				fmt.Printf("alarm 1 triggered\r\n")
+				rtc.ClearAlarm(ds3231.AlarmFlag_Alarm1)
+			}
+			if rtc.AlarmTriggered(ds3231.AlarmFlag_Alarm2) {
+				fmt.Printf("alarm 2 triggered\r\n")
+				rtc.ClearAlarm(ds3231.AlarmFlag_Alarm2)
+			}
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+}
@@ -8,13 +8,15 @@ import (
 )
 
 func main() {
+	spi := &machine.SPI1
 	console_example.RunFor(
-		flash.NewSPI(
-			&machine.SPI1,
-			machine.SPI1_SDO_PIN,
-			machine.SPI1_SDI_PIN,
-			machine.SPI1_SCK_PIN,
-			machine.SPI1_CS_PIN,
-		),
+		flash.NewSPI(spi, machine.SPI1_CS_PIN, func(hz uint32) error {
+			return spi.Configure(machine.SPIConfig{
+				Frequency: hz,
+				SDO:       machine.SPI1_SDO_PIN,
+				SDI:       machine.SPI1_SDI_PIN,
+				SCK:       machine.SPI1_SCK_PIN,
+			})
+		}),
 	)
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/i2cscan"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+
+	for {
+		devices := i2cscan.ScanAndIdentify(machine.I2C0)
+		println("found", len(devices), "devices")
+		for _, dev := range devices {
+			print("0x", hex(dev.Address), ":")
+			for _, c := range dev.Candidates {
+				if c.Confidence == i2cscan.Unlikely {
+					continue
+				}
+				print(" ", c.Chip.Name, " (", c.Confidence.String(), ")")
+			}
+			println()
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func hex(b uint16) string {
+	const digits = "0123456789abcdef"
+	return string([]byte{digits[b>>4&0xf], digits[b&0xf]})
+}
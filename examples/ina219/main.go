@@ -0,0 +1,38 @@
+// Measures the charge and energy drawn through an INA219 breakout board,
+// for example to test the capacity of a battery.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/energy"
+	"tinygo.org/x/drivers/ina219"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+
+	dev := ina219.New(machine.I2C0)
+	err := dev.Configure(ina219.Config{
+		ShuntResistance: 100,
+		MaxCurrent:      2000000,
+		Averaging:       ina219.AVG_16,
+	})
+	if err != nil {
+		println("could not configure INA219:", err.Error())
+		return
+	}
+
+	acc := energy.New(&dev)
+	for i := 0; ; i++ {
+		if err := acc.Update(); err != nil {
+			println("error:", err.Error())
+		}
+		if i%100 == 0 {
+			voltage, _ := dev.Voltage()
+			println(voltage/1000, "mV,", acc.Charge()/1000, "mAh,", acc.Energy()/1000, "mWh in", acc.Elapsed().String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
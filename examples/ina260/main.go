@@ -26,9 +26,14 @@ func main() {
 	}
 
 	for {
-		microvolts := dev.Voltage()
-		microamps := dev.Current()
-		microwatts := dev.Power()
+		microvolts, err := dev.Voltage()
+		if err != nil {
+			println("error:", err.Error())
+			time.Sleep(time.Second)
+			continue
+		}
+		microamps, _ := dev.Current()
+		microwatts, _ := dev.Power()
 
 		println(fmtD(microvolts, 4, 3), "mV,", fmtD(microamps, 4, 3), "mA,", fmtD(microwatts, 4, 3), "mW")
 
@@ -4,6 +4,7 @@ import (
 	"machine"
 	"time"
 
+	"tinygo.org/x/drivers"
 	"tinygo.org/x/drivers/l3gd20"
 )
 
@@ -28,7 +29,7 @@ func main() {
 
 	var x, y, z int32
 	for {
-		err = gyro.Update()
+		err = gyro.Update(drivers.AngularVelocity)
 		if err != nil {
 			println(err.Error())
 		}
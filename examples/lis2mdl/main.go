@@ -21,8 +21,12 @@ func main() {
 	compass.Configure(lis2mdl.Configuration{}) //default settings
 
 	for {
-		heading := compass.ReadCompass()
-		println("Heading:", heading)
+		heading, err := compass.ReadCompass()
+		if err != nil {
+			println("error:", err.Error())
+		} else {
+			println("Heading:", heading)
+		}
 
 		time.Sleep(time.Millisecond * 100)
 	}
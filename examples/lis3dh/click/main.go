@@ -0,0 +1,47 @@
+// Detects single and double clicks with the LIS3DH accelerometer on the
+// Adafruit Circuit Playground Express.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/lis3dh"
+)
+
+var i2c = machine.I2C1
+
+func main() {
+	i2c.Configure(machine.I2CConfig{SCL: machine.SCL1_PIN, SDA: machine.SDA1_PIN})
+
+	accel := lis3dh.New(i2c)
+	accel.Address = lis3dh.Address1 // address on the Circuit Playground Express
+	accel.Configure()
+	accel.SetRange(lis3dh.RANGE_2_G)
+
+	err := accel.ConfigureClick(lis3dh.ClickConfig{
+		Axes:        lis3dh.AXIS_X | lis3dh.AXIS_Y | lis3dh.AXIS_Z,
+		Threshold:   1200000,
+		TimeLimit:   25 * time.Millisecond,
+		Latency:     50 * time.Millisecond,
+		Window:      250 * time.Millisecond,
+		DoubleClick: true,
+		Latch:       true,
+	})
+	if err != nil {
+		println("could not configure click detection:", err.Error())
+		return
+	}
+
+	for {
+		source, err := accel.ReadEventSource()
+		if err != nil {
+			println("error:", err.Error())
+		} else if source.Events&lis3dh.EVENT_DOUBLE_CLICK != 0 {
+			println("double click, axes:", source.ClickAxes)
+		} else if source.Events&lis3dh.EVENT_SINGLE_CLICK != 0 {
+			println("single click, axes:", source.ClickAxes)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,43 @@
+// Counts steps with the pedometer of an LSM6DS3TR, which keeps counting
+// while the microcontroller sleeps.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/lsm6ds3tr"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+
+	accel := lsm6ds3tr.New(machine.I2C0)
+	err := accel.Configure(lsm6ds3tr.Configuration{
+		AccelSampleRate:  lsm6ds3tr.ACCEL_SR_26,
+		IsPedometer:      true,
+		ResetStepCounter: true,
+	})
+	if err != nil {
+		for {
+			println("Failed to configure", err.Error())
+			time.Sleep(time.Second)
+		}
+	}
+
+	// Signal every step on INT1, for example to wake up the microcontroller.
+	err = accel.RouteInterrupts(lsm6ds3tr.INT1, lsm6ds3tr.EVENT_STEP)
+	if err != nil {
+		println("Failed to route interrupt", err.Error())
+	}
+
+	for {
+		steps, err := accel.ReadSteps()
+		if err != nil {
+			println("error:", err.Error())
+		} else {
+			println("Steps:", steps)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -15,7 +15,7 @@ func main() {
 	mag.Configure()
 
 	for {
-		x, y, z := mag.ReadMagnetic()
+		x, y, z, _ := mag.ReadMagnetic()
 		println("Magnetic readings:", x, y, z)
 
 		c, _ := mag.ReadTemperature()
@@ -12,10 +12,18 @@ func main() {
 	machine.I2C0.Configure(machine.I2CConfig{})
 
 	accel := mpu6050.New(machine.I2C0)
-	accel.Configure()
+	err := accel.ConfigureWithSettings(mpu6050.Config{
+		AccelRange: mpu6050.ACCEL_RANGE_4G,
+		GyroRange:  mpu6050.GYRO_RANGE_500,
+		DLPF:       mpu6050.DLPF_44HZ,
+	})
+	if err != nil {
+		println("could not configure MPU6050:", err.Error())
+		return
+	}
 
 	for {
-		x, y, z := accel.ReadAcceleration()
+		x, y, z, _ := accel.ReadAcceleration()
 		println(x, y, z)
 		time.Sleep(time.Millisecond * 100)
 	}
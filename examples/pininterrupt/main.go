@@ -0,0 +1,55 @@
+// Connect a push button between D2 and ground. Each press is reported by an
+// interrupt of a machine.Pin adapted to drivers.InterruptPin.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+// machine.Pin is a drivers.Pin, to which interruptPin adds the interrupts.
+var _ drivers.Pin = machine.D2
+
+// interruptPin adapts a machine.Pin to drivers.InterruptPin.
+func interruptPin(p machine.Pin) drivers.InterruptPin {
+	return drivers.NewInterruptPin(p, func(change drivers.PinChange, callback func()) error {
+		if callback == nil {
+			return p.SetInterrupt(0, nil)
+		}
+		var c machine.PinChange
+		switch change {
+		case drivers.PinRising:
+			c = machine.PinRising
+		case drivers.PinFalling:
+			c = machine.PinFalling
+		default:
+			c = machine.PinToggle
+		}
+		return p.SetInterrupt(c, func(machine.Pin) { callback() })
+	})
+}
+
+var presses = make(chan struct{}, 1)
+
+func main() {
+	button := interruptPin(machine.D2)
+	drivers.ConfigurePin(machine.D2, drivers.PinInputPullup)
+	err := button.SetInterrupt(drivers.PinFalling, func(drivers.Pin) {
+		select {
+		case presses <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		println("could not set interrupt:", err.Error())
+		return
+	}
+
+	for {
+		<-presses
+		println("pressed")
+		time.Sleep(50 * time.Millisecond) // debounce
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/bme280"
+	"tinygo.org/x/drivers/tca9548a"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	mux := tca9548a.New(machine.I2C0)
+	if !mux.Connected() {
+		println("TCA9548A not detected")
+		return
+	}
+
+	// Two BME280 sensors with the same address on channels 0 and 1.
+	sensors := []bme280.Device{
+		bme280.New(mux.Channel(0)),
+		bme280.New(mux.Channel(1)),
+	}
+	for i := range sensors {
+		sensors[i].Configure()
+	}
+
+	for {
+		for i := range sensors {
+			temp, _ := sensors[i].ReadTemperature()
+			println("Sensor", i, "temperature:", float32(temp)/1000, "°C")
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
@@ -19,15 +19,24 @@ func main() {
 		return
 	}
 	println("VL53L1X device found")
-	sensor.Configure(true)
+	if err := sensor.Configure(true); err != nil {
+		println("could not configure VL53L1X:", err.Error())
+		return
+	}
 	sensor.SetMeasurementTimingBudget(50000)
 	sensor.StartContinuous(50)
 	for {
-		sensor.Read(true)
-		println("Distance (mm):", sensor.Distance())
-		println("Status:", sensor.Status())
-		println("Peak signal rate (cps):", sensor.SignalRate())
-		println("Ambient rate (cps):", sensor.AmbientRate())
+		result, err := sensor.ReadResult(true)
+		if err != nil {
+			println("error:", err.Error())
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		println("Distance (mm):", result.Distance)
+		println("Status:", result.Status)
+		println("Peak signal rate (cps):", result.SignalRate)
+		println("Ambient rate (cps):", result.AmbientRate)
+		println("SPADs:", result.SPADCount)
 		println("---")
 		time.Sleep(100 * time.Millisecond)
 	}
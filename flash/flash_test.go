@@ -0,0 +1,59 @@
+package flash
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestSPI(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewSPIBus(c)
+	chip := bus.NewDevice()
+	cs := tester.NewPin()
+	cs.SetInterrupt(drivers.PinToggle, func(p drivers.Pin) {
+		if p.Get() {
+			chip.Deselect()
+		} else {
+			chip.Select()
+		}
+	})
+	var clocks []uint32
+	dev := NewSPI(bus, cs, func(hz uint32) error {
+		clocks = append(clocks, hz)
+		return nil
+	})
+
+	dev.trans.configure(&DeviceConfig{})
+	c.Assert(cs.Get(), qt.IsTrue)
+	c.Assert(dev.trans.setClockSpeed(104e6), qt.IsNil)
+	c.Assert(clocks, qt.DeepEquals, []uint32{5e6, 24e6})
+
+	chip.Expect([]byte{cmdReadJedecID}, nil)
+	chip.Expect([]byte{0xFF}, []byte{0xEF})
+	chip.Expect([]byte{0xFF}, []byte{0x40})
+	chip.Expect([]byte{0xFF}, []byte{0x15})
+	id, err := dev.ReadJEDEC()
+	c.Assert(err, qt.IsNil)
+	c.Assert(id, qt.Equals, JedecID{0xEF, 0x40, 0x15})
+	chip.AssertDone()
+	c.Assert(chip.Transactions[0].Frame, qt.Equals, chip.Transactions[3].Frame)
+	c.Assert(cs.Get(), qt.IsTrue)
+}
+
+func TestSharedSPI(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewSPIBus(c)
+	chip := bus.NewDevice()
+	shared := drivers.NewSharedSPI(bus, nil)
+	spi := shared.Device(tester.NewPin(), drivers.SPIConfig{Frequency: 24e6})
+	dev := NewSPI(spi, spi.CS(), nil)
+
+	dev.trans.configure(&DeviceConfig{})
+	c.Assert(dev.trans.setClockSpeed(104e6), qt.IsNil)
+	chip.Expect([]byte{cmdEnableReset}, nil)
+	c.Assert(dev.trans.runCommand(cmdEnableReset), qt.IsNil)
+	chip.AssertDone()
+}
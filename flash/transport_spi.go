@@ -1,7 +1,7 @@
 package flash
 
 import (
-	"machine"
+	"tinygo.org/x/drivers"
 )
 
 type transport interface {
@@ -16,26 +16,29 @@ type transport interface {
 	writeMemory(addr uint32, data []byte) (err error)
 }
 
-// NewSPI returns a pointer to a flash device that uses a SPI peripheral to
+// NewSPI returns a pointer to a flash device that uses a SPI bus to
 // communicate with a serial memory chip.
-func NewSPI(spi *machine.SPI, sdo, sdi, sck, cs machine.Pin) *Device {
+//
+// setClockSpeed is called to change the clock of the bus, first to 5MHz and
+// then to the maximum speed of the chip once it has been identified. It
+// usually wraps machine.SPI.Configure. If it is nil, the bus is used at the
+// speed it was configured with, as on a drivers.SharedSPI, where the device
+// should be created with the maximum speed of the chip and its CS pin be
+// passed as cs.
+func NewSPI(bus drivers.SPI, cs drivers.Pin, setClockSpeed func(hz uint32) error) *Device {
 	return &Device{
 		trans: &spiTransport{
-			spi: spi,
-			sdo: sdo,
-			sdi: sdi,
-			sck: sck,
-			ss:  cs,
+			spi:      bus,
+			ss:       cs,
+			setClock: setClockSpeed,
 		},
 	}
 }
 
 type spiTransport struct {
-	spi *machine.SPI
-	sdo machine.Pin
-	sdi machine.Pin
-	sck machine.Pin
-	ss  machine.Pin
+	spi      drivers.SPI
+	ss       drivers.Pin
+	setClock func(hz uint32) error
 }
 
 func (tr *spiTransport) configure(config *DeviceConfig) {
@@ -43,25 +46,20 @@ func (tr *spiTransport) configure(config *DeviceConfig) {
 	tr.setClockSpeed(5000000)
 
 	// Configure chip select pin
-	tr.ss.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	tr.ss.High()
+	drivers.ConfigurePin(tr.ss, drivers.PinOutput)
+	tr.ss.Set(true)
 }
 
 func (tr *spiTransport) setClockSpeed(hz uint32) error {
+	if tr.setClock == nil {
+		return nil
+	}
 	// TODO: un-hardcode this max speed; it is probably a sensible
 	//       default maximum for atsamd and nrf at least
 	if hz > 24*1e6 {
 		hz = 24 * 1e6
 	}
-	tr.spi.Configure(machine.SPIConfig{
-		Frequency: hz,
-		SDI:       tr.sdi,
-		SDO:       tr.sdo,
-		SCK:       tr.sck,
-		LSBFirst:  false,
-		Mode:      0,
-	})
-	return nil
+	return tr.setClock(hz)
 }
 
 func (tr *spiTransport) supportQuadMode() bool {
@@ -69,61 +67,61 @@ func (tr *spiTransport) supportQuadMode() bool {
 }
 
 func (tr *spiTransport) runCommand(cmd byte) (err error) {
-	tr.ss.Low()
+	tr.ss.Set(false)
 	_, err = tr.spi.Transfer(byte(cmd))
-	tr.ss.High()
+	tr.ss.Set(true)
 	return
 }
 
 func (tr *spiTransport) readCommand(cmd byte, rsp []byte) (err error) {
-	tr.ss.Low()
+	tr.ss.Set(false)
 	if _, err := tr.spi.Transfer(byte(cmd)); err == nil {
 		err = tr.readInto(rsp)
 	}
-	tr.ss.High()
+	tr.ss.Set(true)
 	return
 }
 
 func (tr *spiTransport) readCommandByte(cmd byte) (rsp byte, err error) {
-	tr.ss.Low()
+	tr.ss.Set(false)
 	if _, err := tr.spi.Transfer(byte(cmd)); err == nil {
 		rsp, err = tr.spi.Transfer(0xFF)
 	}
-	tr.ss.High()
+	tr.ss.Set(true)
 	return
 }
 
 func (tr *spiTransport) writeCommand(cmd byte, data []byte) (err error) {
-	tr.ss.Low()
+	tr.ss.Set(false)
 	if _, err := tr.spi.Transfer(byte(cmd)); err == nil {
 		err = tr.writeFrom(data)
 	}
-	tr.ss.High()
+	tr.ss.Set(true)
 	return
 }
 
 func (tr *spiTransport) eraseCommand(cmd byte, address uint32) (err error) {
-	tr.ss.Low()
+	tr.ss.Set(false)
 	err = tr.sendAddress(cmd, address)
-	tr.ss.High()
+	tr.ss.Set(true)
 	return
 }
 
 func (tr *spiTransport) readMemory(addr uint32, rsp []byte) (err error) {
-	tr.ss.Low()
+	tr.ss.Set(false)
 	if err = tr.sendAddress(cmdRead, addr); err == nil {
 		err = tr.readInto(rsp)
 	}
-	tr.ss.High()
+	tr.ss.Set(true)
 	return
 }
 
 func (tr *spiTransport) writeMemory(addr uint32, data []byte) (err error) {
-	tr.ss.Low()
+	tr.ss.Set(false)
 	if err = tr.sendAddress(cmdPageProgram, addr); err == nil {
 		err = tr.writeFrom(data)
 	}
-	tr.ss.High()
+	tr.ss.Set(true)
 	return
 }
 
@@ -3,29 +3,31 @@ package hd44780
 import (
 	"errors"
 
-	"machine"
+	"tinygo.org/x/drivers"
 )
 
 type GPIO struct {
-	dataPins []machine.Pin
-	en       machine.Pin
-	rw       machine.Pin
-	rs       machine.Pin
+	dataPins []drivers.Pin
+	en       drivers.Pin
+	rw       drivers.Pin
+	rs       drivers.Pin
 
 	write func(data byte)
 	read  func() byte
 }
 
-func newGPIO(dataPins []machine.Pin, en, rs, rw machine.Pin, mode byte) Device {
-	pins := make([]machine.Pin, len(dataPins))
+func newGPIO(dataPins []drivers.Pin, en, rs, rw drivers.Pin, mode byte) Device {
+	pins := make([]drivers.Pin, len(dataPins))
 	for i := 0; i < len(dataPins); i++ {
-		dataPins[i].Configure(machine.PinConfig{Mode: machine.PinOutput})
+		drivers.ConfigurePin(dataPins[i], drivers.PinOutput)
 		pins[i] = dataPins[i]
 	}
-	en.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	rs.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	rw.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	rw.Low()
+	drivers.ConfigurePin(en, drivers.PinOutput)
+	drivers.ConfigurePin(rs, drivers.PinOutput)
+	if !drivers.IsNoPin(rw) {
+		drivers.ConfigurePin(rw, drivers.PinOutput)
+		rw.Set(false)
+	}
 
 	gpio := GPIO{
 		dataPins: pins,
@@ -51,21 +53,21 @@ func newGPIO(dataPins []machine.Pin, en, rs, rw machine.Pin, mode byte) Device {
 // SetCommandMode sets command/instruction mode
 func (g *GPIO) SetCommandMode(set bool) {
 	if set {
-		g.rs.Low()
+		g.rs.Set(false)
 	} else {
-		g.rs.High()
+		g.rs.Set(true)
 	}
 }
 
-// WriteOnly is true if you passed rw in as machine.NoPin
+// WriteOnly is true if you passed rw in as machine.NoPin or nil
 func (g *GPIO) WriteOnly() bool {
-	return g.rw == machine.NoPin
+	return drivers.IsNoPin(g.rw)
 }
 
 // Write writes len(data) bytes from data to display driver
 func (g *GPIO) Write(data []byte) (n int, err error) {
 	if !g.WriteOnly() {
-		g.rw.Low()
+		g.rw.Set(false)
 	}
 	for _, d := range data {
 		g.write(d)
@@ -75,19 +77,19 @@ func (g *GPIO) Write(data []byte) (n int, err error) {
 }
 
 func (g *GPIO) write8BitMode(data byte) {
-	g.en.High()
+	g.en.Set(true)
 	g.setPins(data)
-	g.en.Low()
+	g.en.Set(false)
 }
 
 func (g *GPIO) write4BitMode(data byte) {
-	g.en.High()
+	g.en.Set(true)
 	g.setPins(data >> 4)
-	g.en.Low()
+	g.en.Set(false)
 
-	g.en.High()
+	g.en.Set(true)
 	g.setPins(data)
-	g.en.Low()
+	g.en.Set(false)
 }
 
 // Read reads len(data) bytes from display RAM to data starting from RAM address counter position
@@ -99,38 +101,38 @@ func (g *GPIO) Read(data []byte) (n int, err error) {
 	if g.WriteOnly() {
 		return 0, errors.New("Read not supported if RW not wired")
 	}
-	g.rw.High()
-	g.reconfigureGPIOMode(machine.PinInput)
+	g.rw.Set(true)
+	g.reconfigureGPIOMode(drivers.PinInput)
 	for i := 0; i < len(data); i++ {
 		data[i] = g.read()
 		n++
 	}
-	g.rw.Low()
-	g.reconfigureGPIOMode(machine.PinOutput)
+	g.rw.Set(false)
+	g.reconfigureGPIOMode(drivers.PinOutput)
 	return n, nil
 }
 
 func (g *GPIO) read4BitMode() byte {
-	g.en.High()
+	g.en.Set(true)
 	data := (g.pins() << 4 & 0xF0)
-	g.en.Low()
+	g.en.Set(false)
 
-	g.en.High()
+	g.en.Set(true)
 	data |= (g.pins() & 0x0F)
-	g.en.Low()
+	g.en.Set(false)
 	return data
 }
 
 func (g *GPIO) read8BitMode() byte {
-	g.en.High()
+	g.en.Set(true)
 	data := g.pins()
-	g.en.Low()
+	g.en.Set(false)
 	return data
 }
 
-func (g *GPIO) reconfigureGPIOMode(mode machine.PinMode) {
+func (g *GPIO) reconfigureGPIOMode(mode drivers.PinMode) {
 	for i := 0; i < len(g.dataPins); i++ {
-		g.dataPins[i].Configure(machine.PinConfig{Mode: mode})
+		drivers.ConfigurePin(g.dataPins[i], mode)
 	}
 }
 
@@ -138,11 +140,7 @@ func (g *GPIO) reconfigureGPIOMode(mode machine.PinMode) {
 func (g *GPIO) setPins(data byte) {
 	mask := byte(1)
 	for i := 0; i < len(g.dataPins); i++ {
-		if (data & mask) != 0 {
-			g.dataPins[i].High()
-		} else {
-			g.dataPins[i].Low()
-		}
+		g.dataPins[i].Set((data & mask) != 0)
 		mask = mask << 1
 	}
 }
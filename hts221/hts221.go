@@ -18,6 +18,10 @@ type Device struct {
 	humidityZero     float32
 	temperatureSlope float32
 	temperatureZero  float32
+
+	// last values read by Update
+	temperature int32
+	humidity    int32
 }
 
 // New creates a new HTS221 connection. The I2C bus must already be
@@ -54,10 +58,10 @@ func (d *Device) ReadHumidity() (humidity int32, err error) {
 	}
 
 	// read data and calibrate
-	data := []byte{0, 0}
-	d.bus.ReadRegister(d.Address, HTS221_HUMID_OUT_REG, data[:1])
-	d.bus.ReadRegister(d.Address, HTS221_HUMID_OUT_REG+1, data[1:])
-	hValue := readInt(data[1], data[0])
+	hValue, err := d.readOutput(HTS221_HUMID_OUT_REG)
+	if err != nil {
+		return
+	}
 	hValueCalib := float32(hValue)*d.humiditySlope + d.humidityZero
 
 	return int32(hValueCalib * 100), nil
@@ -72,15 +76,70 @@ func (d *Device) ReadTemperature() (temperature int32, err error) {
 	}
 
 	// read data and calibrate
-	data := []byte{0, 0}
-	d.bus.ReadRegister(d.Address, HTS221_TEMP_OUT_REG, data[:1])
-	d.bus.ReadRegister(d.Address, HTS221_TEMP_OUT_REG+1, data[1:])
-	tValue := readInt(data[1], data[0])
+	tValue, err := d.readOutput(HTS221_TEMP_OUT_REG)
+	if err != nil {
+		return
+	}
 	tValueCalib := float32(tValue)*d.temperatureSlope + d.temperatureZero
 
 	return int32(tValueCalib * 1000), nil
 }
 
+// Update triggers a single conversion and reads the selected measurements.
+// Use Temperature and Humidity to get the values. Returns an error if the
+// device is not turned on.
+func (d *Device) Update(which drivers.Measurement) error {
+	var filter uint8
+	if which&drivers.Temperature != 0 {
+		filter |= 0x01
+	}
+	if which&drivers.Humidity != 0 {
+		filter |= 0x02
+	}
+	if filter == 0 {
+		return nil
+	}
+	if err := d.waitForOneShot(filter); err != nil {
+		return err
+	}
+
+	// Read both before storing either, so that a failed read leaves the
+	// previous values untouched.
+	var hValue, tValue int16
+	var err error
+	if which&drivers.Humidity != 0 {
+		hValue, err = d.readOutput(HTS221_HUMID_OUT_REG)
+		if err != nil {
+			return err
+		}
+	}
+	if which&drivers.Temperature != 0 {
+		tValue, err = d.readOutput(HTS221_TEMP_OUT_REG)
+		if err != nil {
+			return err
+		}
+	}
+	if which&drivers.Humidity != 0 {
+		d.humidity = int32((float32(hValue)*d.humiditySlope + d.humidityZero) * 100)
+	}
+	if which&drivers.Temperature != 0 {
+		d.temperature = int32((float32(tValue)*d.temperatureSlope + d.temperatureZero) * 1000)
+	}
+	return nil
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// Humidity returns the relative humidity in percent * 100 read by the last
+// call to Update.
+func (d *Device) Humidity() int32 {
+	return d.humidity
+}
+
 // Resolution sets the HTS221's resolution mode.
 // The higher resolutions are more accurate but comsume more power (see datasheet).
 // The number of averaged samples will be (h + 2) ^ 2, (t + 1) ^ 2
@@ -138,27 +197,37 @@ func (d *Device) waitForOneShot(filter uint8) error {
 	data := []byte{0}
 
 	// check if the device is on
-	d.bus.ReadRegister(d.Address, HTS221_CTRL1_REG, data)
+	err := d.bus.ReadRegister(d.Address, HTS221_CTRL1_REG, data)
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
 	if data[0]&0x80 == 0 {
 		return errors.New("device is off, unable to query")
 	}
 
 	// wait until one shot (one conversion) is ready to go
-	data[0] = 1
 	for {
-		d.bus.ReadRegister(d.Address, HTS221_CTRL2_REG, data)
+		err = d.bus.ReadRegister(d.Address, HTS221_CTRL2_REG, data)
+		if err != nil {
+			return drivers.NotResponding(err)
+		}
 		if data[0]&0x01 == 0 {
 			break
 		}
 	}
 
 	// trigger one shot
-	d.bus.WriteRegister(d.Address, HTS221_CTRL2_REG, []byte{0x01})
+	err = d.bus.WriteRegister(d.Address, HTS221_CTRL2_REG, []byte{0x01})
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
 
 	// wait until conversion completed
-	data[0] = 0
 	for {
-		d.bus.ReadRegister(d.Address, HTS221_STATUS_REG, data)
+		err = d.bus.ReadRegister(d.Address, HTS221_STATUS_REG, data)
+		if err != nil {
+			return drivers.NotResponding(err)
+		}
 		if data[0]&filter == filter {
 			break
 		}
@@ -167,6 +236,22 @@ func (d *Device) waitForOneShot(filter uint8) error {
 	return nil
 }
 
+// readOutput reads the two bytes of an output register. The bytes are read
+// separately as the HTS221 only auto-increments with the MSB of the address
+// set.
+func (d *Device) readOutput(reg uint8) (int16, error) {
+	data := []byte{0, 0}
+	err := d.bus.ReadRegister(d.Address, reg, data[:1])
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	err = d.bus.ReadRegister(d.Address, reg+1, data[1:])
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return readInt(data[1], data[0]), nil
+}
+
 func readUint(msb byte, lsb byte) uint16 {
 	return uint16(msb)<<8 | uint16(lsb)
 }
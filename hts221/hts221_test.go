@@ -0,0 +1,42 @@
+package hts221
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var (
+	_ drivers.Thermometer = (*Device)(nil)
+	_ drivers.Hygrometer  = (*Device)(nil)
+)
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, HTS221_ADDRESS)
+	bus.AddDevice(fake)
+	fake.Registers[HTS221_CTRL1_REG] = 0x84
+	fake.Flags[HTS221_CTRL2_REG] = tester.RegisterClearOnRead
+	fake.Registers[HTS221_STATUS_REG] = 0x03
+	copy(fake.Registers[HTS221_HUMID_OUT_REG:], []uint8{100, 0, 200, 0})
+
+	dev := New(bus)
+	dev.humiditySlope = 0.5
+	dev.temperatureSlope = 0.125
+	c.Assert(dev.Update(drivers.Temperature|drivers.Humidity), qt.IsNil)
+	c.Assert(dev.Humidity(), qt.Equals, int32(5000))
+	c.Assert(dev.Temperature(), qt.Equals, int32(25000))
+
+	// A bus error is returned and keeps the previous values.
+	fake.Err = errors.New("nack")
+	err := dev.Update(drivers.Temperature | drivers.Humidity)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(dev.Humidity(), qt.Equals, int32(5000))
+	c.Assert(dev.Temperature(), qt.Equals, int32(25000))
+	_, err = dev.ReadTemperature()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
@@ -0,0 +1,152 @@
+// Package i2cscan finds the devices connected to an I2C bus and identifies
+// which driver packages they are likely to need.
+//
+// Scan probes every 7-bit address and returns those that acknowledge.
+// Identify then reads the chip ID, WHO_AM_I register or serial number of
+// each chip known to live at an address to tell apart chips that share
+// addresses, such as the MPU6050 and DS3231 at 0x68.
+//
+// Probing reads registers of devices that are not known in advance. This is
+// harmless for the sensors in this repository, but may have side effects on
+// other devices, such as clearing a latched status register.
+package i2cscan // import "tinygo.org/x/drivers/i2cscan"
+
+import (
+	"sort"
+
+	"tinygo.org/x/drivers"
+)
+
+// First and last addresses probed by Scan. Addresses outside this range are
+// reserved by the I2C specification.
+const (
+	FirstAddress = 0x08
+	LastAddress  = 0x77
+)
+
+// Confidence is how sure an identification is.
+type Confidence uint8
+
+const (
+	// Unlikely means the chip uses the address, but its identification did
+	// not match what the chip returns.
+	Unlikely Confidence = iota
+
+	// Possible means the chip uses the address and has no identification
+	// register, so the address is all that is known.
+	Possible
+
+	// Likely means the identification matched, but the same value is
+	// returned by other chips at the address.
+	Likely
+
+	// Certain means the identification matched and no other known chip at
+	// the address returns the same value.
+	Certain
+)
+
+// String returns the name of the confidence level.
+func (c Confidence) String() string {
+	switch c {
+	case Unlikely:
+		return "unlikely"
+	case Possible:
+		return "possible"
+	case Likely:
+		return "likely"
+	case Certain:
+		return "certain"
+	}
+	return "unknown"
+}
+
+// Candidate is a chip that may be present at an address.
+type Candidate struct {
+	Chip       *Chip
+	Confidence Confidence
+}
+
+// Device is a device found on the bus, with the chips it may be ordered
+// from most to least likely.
+type Device struct {
+	Address    uint16
+	Candidates []Candidate
+}
+
+// Scan returns the addresses of all devices that acknowledge on the bus.
+func Scan(bus drivers.I2C) []uint16 {
+	var found []uint16
+	for addr := uint16(FirstAddress); addr <= LastAddress; addr++ {
+		if Probe(bus, addr) {
+			found = append(found, addr)
+		}
+	}
+	return found
+}
+
+// Probe returns whether a device acknowledges at addr. It first sends the
+// address alone, and falls back to reading a single byte since not every
+// I2C implementation supports empty transactions.
+func Probe(bus drivers.I2C, addr uint16) bool {
+	if bus.Tx(addr, nil, nil) == nil {
+		return true
+	}
+	var buf [1]byte
+	return bus.Tx(addr, nil, buf[:]) == nil
+}
+
+// Identify returns the registered chips that use addr, ordered from most
+// to least likely to be the device at that address. Chips whose
+// identification does not match are included with confidence Unlikely.
+func Identify(bus drivers.I2C, addr uint16) []Candidate {
+	var candidates []Candidate
+	for i := range registry {
+		chip := &registry[i]
+		if !chip.uses(addr) {
+			continue
+		}
+		confidence := Possible
+		if chip.Identify != nil {
+			ok, err := chip.Identify(bus, addr)
+			if err != nil || !ok {
+				confidence = Unlikely
+			} else {
+				confidence = Certain
+			}
+		}
+		candidates = append(candidates, Candidate{Chip: chip, Confidence: confidence})
+	}
+
+	// Identifications that several chips return at this address only make
+	// each of them likely.
+	matched := 0
+	for _, c := range candidates {
+		if c.Confidence == Certain {
+			matched++
+		}
+	}
+	if matched > 1 {
+		for i := range candidates {
+			if candidates[i].Confidence == Certain {
+				candidates[i].Confidence = Likely
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+	return candidates
+}
+
+// ScanAndIdentify scans the bus and identifies every device found.
+func ScanAndIdentify(bus drivers.I2C) []Device {
+	var devices []Device
+	for _, addr := range Scan(bus) {
+		devices = append(devices, Device{
+			Address:    addr,
+			Candidates: Identify(bus, addr),
+		})
+	}
+	return devices
+}
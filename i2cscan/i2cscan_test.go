@@ -0,0 +1,154 @@
+package i2cscan
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestScan(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	bus.AddDevice(tester.NewI2CDevice8(c, 0x29))
+	bus.AddDevice(tester.NewI2CDevice8(c, 0x68))
+	bus.AddDevice(tester.NewI2CDeviceCmd(c, 0x62))
+
+	c.Assert(Scan(bus), qt.DeepEquals, []uint16{0x29, 0x62, 0x68})
+}
+
+func TestIdentifyRegister8(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	dev := tester.NewI2CDevice8(c, 0x68)
+	dev.Registers[0x75] = 0x68
+	bus.AddDevice(dev)
+
+	candidates := Identify(bus, 0x68)
+	c.Assert(names(candidates), qt.DeepEquals, []string{"MPU6050", "AMG88xx", "DS1307", "DS3231"})
+	c.Assert(candidates[0].Confidence, qt.Equals, Certain)
+	c.Assert(candidates[0].Chip.Package, qt.Equals, "mpu6050")
+	c.Assert(candidates[1].Confidence, qt.Equals, Possible)
+
+	// Without the ID, the chips without an ID register are more likely.
+	dev.Registers[0x75] = 0
+	candidates = Identify(bus, 0x68)
+	c.Assert(names(candidates), qt.DeepEquals, []string{"AMG88xx", "DS1307", "DS3231", "MPU6050"})
+	c.Assert(candidates[3].Confidence, qt.Equals, Unlikely)
+}
+
+func TestIdentifySharedID(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDevice8(c, 0x19)
+	dev.Registers[0x0F] = 0x33
+	bus.AddDevice(dev)
+
+	candidates := Identify(bus, 0x19)
+	c.Assert(names(candidates), qt.DeepEquals, []string{"LIS3DH", "LSM303AGR accelerometer"})
+	c.Assert(candidates[0].Confidence, qt.Equals, Likely)
+	c.Assert(candidates[1].Confidence, qt.Equals, Likely)
+}
+
+func TestIdentifyRegister16(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceAddr16(c, 0x29)
+	dev.Registers[0x0000] = 0x00
+	dev.Registers[0x010F] = 0xEA
+	dev.Registers[0x0110] = 0xCC
+	bus.AddDevice(dev)
+
+	candidates := Identify(bus, 0x29)
+	c.Assert(names(candidates), qt.DeepEquals, []string{"VL53L1X", "VL6180X"})
+	c.Assert(candidates[0].Confidence, qt.Equals, Certain)
+	c.Assert(candidates[1].Confidence, qt.Equals, Unlikely)
+}
+
+func TestIdentifyINA260(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDevice16(c, 0x40)
+	dev.Registers[0xFE] = 0x5449
+	dev.Registers[0xFF] = 0x2271
+	bus.AddDevice(dev)
+
+	candidates := Identify(bus, 0x40)
+	c.Assert(names(candidates), qt.DeepEquals, []string{"INA260", "INA219", "PCA9685"})
+	c.Assert(candidates[0].Confidence, qt.Equals, Certain)
+}
+
+func TestIdentifySensirion(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceCmd(c, 0x62)
+	dev.Commands = map[uint8]*tester.Cmd{
+		0x36: {
+			Command:  []byte{0x36, 0x82},
+			Mask:     []byte{0xFF, 0xFF},
+			Response: []byte{0xF8, 0x96, 0x31, 0x9F, 0x07, 0xC2, 0x3B, 0xBE, 0x89},
+		},
+	}
+	bus.AddDevice(dev)
+
+	candidates := Identify(bus, 0x62)
+	c.Assert(names(candidates), qt.DeepEquals, []string{"SCD4x"})
+	c.Assert(candidates[0].Confidence, qt.Equals, Certain)
+
+	// A corrupted serial number is not a match.
+	dev.Commands[0x36].Response[2] = 0
+	candidates = Identify(bus, 0x62)
+	c.Assert(candidates[0].Confidence, qt.Equals, Unlikely)
+}
+
+func TestIdentifyMultiplexer(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	// A device without commands fails the test on any write, which would
+	// change the channels of a TCA9548A.
+	bus.AddDevice(tester.NewI2CDeviceCmd(c, 0x70))
+
+	candidates := Identify(bus, 0x70)
+	c.Assert(names(candidates), qt.DeepEquals, []string{"SHTC3", "TCA9548A"})
+	c.Assert(candidates[0].Confidence, qt.Equals, Possible)
+	c.Assert(candidates[1].Confidence, qt.Equals, Possible)
+}
+
+func TestScanAndIdentify(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	bme := tester.NewI2CDevice8(c, 0x76)
+	bme.Registers[0xD0] = 0x60
+	bus.AddDevice(bme)
+
+	devices := ScanAndIdentify(bus)
+	c.Assert(devices, qt.HasLen, 1)
+	c.Assert(devices[0].Address, qt.Equals, uint16(0x76))
+	c.Assert(devices[0].Candidates[0].Chip.Package, qt.Equals, "bme280")
+	c.Assert(devices[0].Candidates[0].Confidence, qt.Equals, Certain)
+}
+
+func TestRegister(t *testing.T) {
+	c := qt.New(t)
+	saved := registry
+	defer func() { registry = saved }()
+
+	Register(Chip{Name: "Custom", Package: "example.com/custom", Addresses: []uint16{0x12}})
+	bus := tester.NewI2CBus(c)
+	bus.AddDevice(tester.NewI2CDevice8(c, 0x12))
+
+	candidates := Identify(bus, 0x12)
+	c.Assert(names(candidates), qt.DeepEquals, []string{"Custom"})
+	c.Assert(candidates[0].Confidence, qt.Equals, Possible)
+}
+
+func names(candidates []Candidate) []string {
+	var s []string
+	for _, c := range candidates {
+		s = append(s, c.Chip.Name)
+	}
+	return s
+}
@@ -0,0 +1,184 @@
+package i2cscan
+
+import (
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/sensirion"
+)
+
+// Chip describes a chip that can be found on an I2C bus.
+type Chip struct {
+	// Name is the name of the chip, for example "BME280".
+	Name string
+
+	// Package is the import path of the driver package, relative to
+	// tinygo.org/x/drivers.
+	Package string
+
+	// Addresses are the addresses the chip can be strapped to.
+	Addresses []uint16
+
+	// Identify reports whether the device at addr is this chip, usually by
+	// reading a chip ID register. It is nil for chips without any way to
+	// identify them, and for chips that can only be identified with a
+	// command that changes the state of another chip at the same address.
+	// For example, the wake-up command of the SHTC3 would connect channels
+	// of a TCA9548A multiplexer at 0x70 to the bus.
+	Identify func(bus drivers.I2C, addr uint16) (bool, error)
+}
+
+func (c *Chip) uses(addr uint16) bool {
+	for _, a := range c.Addresses {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Register adds a chip to the registry used by Identify, for example for a
+// driver that lives outside of this repository.
+func Register(chip Chip) {
+	registry = append(registry, chip)
+}
+
+// Chips returns all registered chips.
+func Chips() []Chip {
+	return append([]Chip(nil), registry...)
+}
+
+// registry holds the chips supported by the drivers in this repository,
+// sorted by name.
+var registry = []Chip{
+	{"ADT7410", "adt7410", addressRange(0x48, 0x4B), register8(0x0B, 0xF8, 0xC8)},
+	{"ADXL345", "adxl345", []uint16{0x1D, 0x53}, register8(0x00, 0xFF, 0xE5)},
+	{"AHT20", "aht20", []uint16{0x38}, nil},
+	{"AMG88xx", "amg88xx", []uint16{0x68, 0x69}, nil},
+	{"APDS9960", "apds9960", []uint16{0x39}, register8(0x92, 0xFF, 0xAB)},
+	{"AT24Cx", "at24cx", addressRange(0x50, 0x57), nil},
+	{"AXP192", "axp192", []uint16{0x34}, nil},
+	{"BH1750", "bh1750", []uint16{0x23, 0x5C}, nil},
+	{"BlinkM", "blinkm", []uint16{0x09}, nil},
+	{"BME280", "bme280", []uint16{0x76, 0x77}, register8(0xD0, 0xFF, 0x60)},
+	{"BME680", "bme680", []uint16{0x76, 0x77}, register8(0xD0, 0xFF, 0x61)},
+	{"BMP180", "bmp180", []uint16{0x77}, register8(0xD0, 0xFF, 0x55)},
+	{"BMP280", "bmp280", []uint16{0x76, 0x77}, register8(0xD0, 0xFF, 0x58)},
+	{"BMP388", "bmp388", []uint16{0x76, 0x77}, register8(0x00, 0xFF, 0x50)},
+	{"DS1307", "ds1307", []uint16{0x68}, nil},
+	{"DS3231", "ds3231", []uint16{0x68}, nil},
+	{"FT6336", "ft6336", []uint16{0x38}, register8(0xA8, 0xFF, 0x11)},
+	{"HD44780 I2C backpack", "hd44780i2c", addressRange(0x20, 0x27), nil},
+	{"HTS221", "hts221", []uint16{0x5F}, register8(0x0F, 0xFF, 0xBC)},
+	{"INA219", "ina219", addressRange(0x40, 0x4F), nil},
+	{"INA260", "ina260", addressRange(0x40, 0x4F), ina260ID},
+	{"IS31FL3731", "is31fl3731", addressRange(0x74, 0x77), nil},
+	{"L3GD20", "l3gd20", []uint16{0x6A, 0x6B}, register8(0x0F, 0xFF, 0xD4, 0xD7)},
+	{"LIS2MDL", "lis2mdl", []uint16{0x1E}, register8(0x4F, 0xFF, 0x40)},
+	{"LIS3DH", "lis3dh", []uint16{0x18, 0x19}, register8(0x0F, 0xFF, 0x33)},
+	{"LPS22HB", "lps22hb", []uint16{0x5C, 0x5D}, register8(0x0F, 0xFF, 0xB1)},
+	{"LSM303AGR accelerometer", "lsm303agr", []uint16{0x19}, register8(0x0F, 0xFF, 0x33)},
+	{"LSM303AGR magnetometer", "lsm303agr", []uint16{0x1E}, register8(0x4F, 0xFF, 0x40)},
+	{"LSM6DS3", "lsm6ds3", []uint16{0x6A, 0x6B}, register8(0x0F, 0xFF, 0x69)},
+	{"LSM6DS3TR", "lsm6ds3tr", []uint16{0x6A, 0x6B}, register8(0x0F, 0xFF, 0x6A)},
+	{"LSM6DSOX", "lsm6dsox", []uint16{0x6A, 0x6B}, register8(0x0F, 0xFF, 0x6C)},
+	{"LSM9DS1 accelerometer/gyroscope", "lsm9ds1", []uint16{0x6A, 0x6B}, register8(0x0F, 0xFF, 0x68)},
+	{"LSM9DS1 magnetometer", "lsm9ds1", []uint16{0x1C, 0x1E}, register8(0x0F, 0xFF, 0x3D)},
+	{"MAG3110", "mag3110", []uint16{0x0E}, register8(0x07, 0xFF, 0xC4)},
+	{"MCP23017", "mcp23017", addressRange(0x20, 0x27), nil},
+	{"MMA8653", "mma8653", []uint16{0x1D}, register8(0x0D, 0xFF, 0x5A)},
+	{"MPU6050", "mpu6050", []uint16{0x68, 0x69}, register8(0x75, 0xFF, 0x68)},
+	{"PCA9685", "pca9685", addressRange(0x40, 0x4F), nil},
+	{"PCF8563", "pcf8563", []uint16{0x51}, nil},
+	{"SCD4x", "scd4x", []uint16{0x62}, sensirionWords(0x3682, 3, time.Millisecond)},
+	{"SHT3x", "sht3x", []uint16{0x44, 0x45}, sensirionWords(0x3780, 2, time.Millisecond)},
+	{"SHTC3", "shtc3", []uint16{0x70}, nil},
+	{"SSD1306", "ssd1306", []uint16{0x3C, 0x3D}, nil},
+	{"TCA9548A", "tca9548a", addressRange(0x70, 0x77), nil},
+	{"TMP102", "tmp102", addressRange(0x48, 0x4B), nil},
+	{"VEML6070", "veml6070", []uint16{0x38, 0x39}, nil},
+	{"VL53L1X", "vl53l1x", []uint16{0x29}, register16(0x010F, 0xEACC)},
+	{"VL6180X", "vl6180x", []uint16{0x29}, register16(0x0000, 0xB4)},
+}
+
+func addressRange(first, last uint16) []uint16 {
+	addrs := make([]uint16, 0, last-first+1)
+	for addr := first; addr <= last; addr++ {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// register8 identifies chips by an ID register, matching when the register
+// masked with mask equals one of ids.
+func register8(reg, mask uint8, ids ...uint8) func(drivers.I2C, uint16) (bool, error) {
+	return func(bus drivers.I2C, addr uint16) (bool, error) {
+		var buf [1]byte
+		if err := bus.Tx(addr, []byte{reg}, buf[:]); err != nil {
+			return false, err
+		}
+		for _, id := range ids {
+			if buf[0]&mask == id {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// register16 identifies chips with 16-bit register addresses, such as the
+// ST time-of-flight sensors. IDs above 0xFF are read as 16-bit values.
+func register16(reg uint16, id uint16) func(drivers.I2C, uint16) (bool, error) {
+	return func(bus drivers.I2C, addr uint16) (bool, error) {
+		buf := make([]byte, 1, 2)
+		if id > 0xFF {
+			buf = buf[:2]
+		}
+		if err := bus.Tx(addr, []byte{byte(reg >> 8), byte(reg)}, buf); err != nil {
+			return false, err
+		}
+		got := uint16(buf[0])
+		if len(buf) == 2 {
+			got = got<<8 | uint16(buf[1])
+		}
+		return got == id, nil
+	}
+}
+
+// ina260ID checks the manufacturer ID ("TI") and the die ID, ignoring the
+// die revision.
+func ina260ID(bus drivers.I2C, addr uint16) (bool, error) {
+	var buf [2]byte
+	if err := bus.Tx(addr, []byte{0xFE}, buf[:]); err != nil {
+		return false, err
+	}
+	if buf[0] != 0x54 || buf[1] != 0x49 {
+		return false, nil
+	}
+	if err := bus.Tx(addr, []byte{0xFF}, buf[:]); err != nil {
+		return false, err
+	}
+	return (uint16(buf[0])<<8|uint16(buf[1]))&0xFFF0 == 0x2270, nil
+}
+
+// sensirionWords identifies Sensirion chips by sending a command that
+// returns a serial number of the given number of words, each followed by a
+// CRC. Only the CRCs are checked, since serial numbers are unique.
+func sensirionWords(cmd uint16, words int, delay time.Duration) func(drivers.I2C, uint16) (bool, error) {
+	return func(bus drivers.I2C, addr uint16) (bool, error) {
+		if err := bus.Tx(addr, []byte{byte(cmd >> 8), byte(cmd)}, nil); err != nil {
+			return false, err
+		}
+		time.Sleep(delay)
+		buf := make([]byte, words*3)
+		if err := bus.Tx(addr, nil, buf); err != nil {
+			return false, err
+		}
+		for i := 0; i < len(buf); i += 3 {
+			if sensirion.CRC8(buf[i:i+2]) != buf[i+2] {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
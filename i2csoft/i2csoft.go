@@ -2,15 +2,16 @@ package i2csoft
 
 import (
 	"errors"
-	"machine"
+
+	"tinygo.org/x/drivers"
 )
 
 // I2C is an I2C implementation by Software. Since it is implemented by
 // software, it can be used with microcontrollers that do not have I2C
 // function. This is not efficient but works around broken or missing drivers.
 type I2C struct {
-	scl      machine.Pin
-	sda      machine.Pin
+	scl      drivers.Pin
+	sda      drivers.Pin
 	nack     bool
 	baudrate uint32
 }
@@ -18,8 +19,8 @@ type I2C struct {
 // I2CConfig is used to store config info for I2C.
 type I2CConfig struct {
 	Frequency uint32
-	SCL       machine.Pin
-	SDA       machine.Pin
+	SCL       drivers.Pin
+	SDA       drivers.Pin
 }
 
 var (
@@ -29,7 +30,7 @@ var (
 // New returns the i2csoft driver. For the arguments, specify the pins to be
 // used as SCL and SDA. As I2C is implemented in software, any GPIO pin can be
 // specified.
-func New(sclPin, sdaPin machine.Pin) *I2C {
+func New(sclPin, sdaPin drivers.Pin) *I2C {
 	return &I2C{
 		scl:      sclPin,
 		sda:      sdaPin,
@@ -53,10 +54,10 @@ func (i2c *I2C) Configure(config I2CConfig) error {
 	}
 
 	// enable pins
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	i2c.sda.High()
-	i2c.scl.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	i2c.scl.High()
+	drivers.ConfigurePin(i2c.sda, drivers.PinOutput)
+	i2c.sda.Set(true)
+	drivers.ConfigurePin(i2c.scl, drivers.PinOutput)
+	i2c.scl.Set(true)
 
 	return nil
 }
@@ -127,30 +128,30 @@ func (i2c *I2C) Tx(addr uint16, w, r []byte) error {
 // writeByte writes a single byte to the I2C bus.
 func (i2c *I2C) writeByte(data byte) {
 	// Send data byte
-	i2c.scl.Low()
-	i2c.sda.High()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	i2c.scl.Set(false)
+	i2c.sda.Set(true)
+	drivers.ConfigurePin(i2c.sda, drivers.PinOutput)
 	i2c.wait()
 
 	for i := 0; i < 8; i++ {
-		i2c.scl.Low()
+		i2c.scl.Set(false)
 		if ((data >> (7 - i)) & 1) == 1 {
-			i2c.sda.High()
+			i2c.sda.Set(true)
 		} else {
-			i2c.sda.Low()
+			i2c.sda.Set(false)
 		}
 		i2c.wait()
 		i2c.wait()
-		i2c.scl.High()
+		i2c.scl.Set(true)
 		i2c.wait()
 		i2c.wait()
 	}
 
-	i2c.scl.Low()
+	i2c.scl.Set(false)
 	i2c.wait()
 	i2c.wait()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinInput})
-	i2c.scl.High()
+	drivers.ConfigurePin(i2c.sda, drivers.PinInput)
+	i2c.scl.Set(true)
 	i2c.wait()
 
 	i2c.nack = i2c.sda.Get()
@@ -167,29 +168,29 @@ func (i2c *I2C) sendAddress(address uint16, write bool) {
 		data |= 1 // set read flag
 	}
 
-	i2c.scl.High()
-	i2c.sda.Low()
+	i2c.scl.Set(true)
+	i2c.sda.Set(false)
 	i2c.wait()
 	i2c.wait()
 	for i := 0; i < 8; i++ {
-		i2c.scl.Low()
+		i2c.scl.Set(false)
 		if ((data >> (7 - i)) & 1) == 1 {
-			i2c.sda.High()
+			i2c.sda.Set(true)
 		} else {
-			i2c.sda.Low()
+			i2c.sda.Set(false)
 		}
 		i2c.wait()
 		i2c.wait()
-		i2c.scl.High()
+		i2c.scl.Set(true)
 		i2c.wait()
 		i2c.wait()
 	}
 
-	i2c.scl.Low()
+	i2c.scl.Set(false)
 	i2c.wait()
 	i2c.wait()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinInput})
-	i2c.scl.High()
+	drivers.ConfigurePin(i2c.sda, drivers.PinInput)
+	i2c.scl.Set(true)
 	i2c.wait()
 
 	i2c.nack = i2c.sda.Get()
@@ -200,15 +201,15 @@ func (i2c *I2C) sendAddress(address uint16, write bool) {
 }
 
 func (i2c *I2C) signalStop() {
-	i2c.scl.Low()
-	i2c.sda.Low()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	i2c.scl.Set(false)
+	i2c.sda.Set(false)
+	drivers.ConfigurePin(i2c.sda, drivers.PinOutput)
 	i2c.wait()
 	i2c.wait()
-	i2c.scl.High()
+	i2c.scl.Set(true)
 	i2c.wait()
 	i2c.wait()
-	i2c.sda.High()
+	i2c.sda.Set(true)
 	i2c.wait()
 	i2c.wait()
 }
@@ -216,12 +217,12 @@ func (i2c *I2C) signalStop() {
 func (i2c *I2C) signalRead() {
 	i2c.wait()
 	i2c.wait()
-	i2c.scl.Low()
-	i2c.sda.Low()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	i2c.scl.Set(false)
+	i2c.sda.Set(false)
+	drivers.ConfigurePin(i2c.sda, drivers.PinOutput)
 	i2c.wait()
 	i2c.wait()
-	i2c.scl.High()
+	i2c.scl.Set(true)
 	i2c.wait()
 	i2c.wait()
 }
@@ -229,11 +230,11 @@ func (i2c *I2C) signalRead() {
 func (i2c *I2C) readByte() byte {
 	var data byte
 	for i := 0; i < 8; i++ {
-		i2c.scl.Low()
-		i2c.sda.Configure(machine.PinConfig{Mode: machine.PinInput})
+		i2c.scl.Set(false)
+		drivers.ConfigurePin(i2c.sda, drivers.PinInput)
 		i2c.wait()
 		i2c.wait()
-		i2c.scl.High()
+		i2c.scl.Set(true)
 		if i2c.sda.Get() {
 			data |= 1 << (7 - i)
 		}
@@ -246,12 +247,12 @@ func (i2c *I2C) readByte() byte {
 func (i2c *I2C) sendNack() {
 	i2c.wait()
 	i2c.wait()
-	i2c.scl.Low()
-	i2c.sda.High()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	i2c.scl.Set(false)
+	i2c.sda.Set(true)
+	drivers.ConfigurePin(i2c.sda, drivers.PinOutput)
 	i2c.wait()
 	i2c.wait()
-	i2c.scl.High()
+	i2c.scl.Set(true)
 	i2c.wait()
 	i2c.wait()
 }
@@ -0,0 +1,180 @@
+// Package ina219 provides a driver for the INA219 current and power monitor
+// with an external shunt resistor.
+//
+// Datasheet: https://www.ti.com/lit/ds/symlink/ina219.pdf
+package ina219 // import "tinygo.org/x/drivers/ina219"
+
+import (
+	"errors"
+
+	"tinygo.org/x/drivers"
+)
+
+var (
+	errShunt    = errors.New("ina219: invalid shunt resistance")
+	errOverflow = errors.New("ina219: current or power out of range")
+)
+
+// Device wraps an I2C connection to an INA219 device.
+type Device struct {
+	bus     drivers.I2C
+	Address uint16
+
+	currentLSB int64 // nA
+}
+
+// Config holds the configuration of the INA219 device.
+type Config struct {
+	// ShuntResistance is the resistance of the shunt resistor in mΩ. Zero
+	// uses 100mΩ, the shunt of most breakout boards.
+	ShuntResistance uint32
+
+	// MaxCurrent is the largest expected current in µA, which sets the
+	// resolution of the current and power: MaxCurrent/32768. The shunt
+	// voltage range is the smallest that covers it. Zero uses 3.2A.
+	MaxCurrent int32
+
+	// LowVoltageRange measures bus voltages up to 16V instead of 32V.
+	LowVoltageRange bool
+
+	// Averaging is the number of samples averaged per conversion of both
+	// the bus and shunt voltage.
+	Averaging Averaging
+}
+
+// New creates a new INA219 connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) Device {
+	return Device{
+		bus:     bus,
+		Address: Address,
+	}
+}
+
+// Configure sets up the device and writes the calibration register, which
+// is needed to read the current and power. The device measures the bus and
+// shunt voltage continuously.
+func (d *Device) Configure(cfg Config) error {
+	shunt := int64(cfg.ShuntResistance)
+	if shunt == 0 {
+		shunt = 100
+	}
+	maxCurrent := int64(cfg.MaxCurrent)
+	if maxCurrent <= 0 {
+		maxCurrent = 3200000
+	}
+
+	// Choose the smallest shunt voltage range (40, 80, 160 or 320mV)
+	// covering the largest current.
+	shuntVoltage := maxCurrent * shunt / 1000 // µV
+	gain := uint16(0)
+	for gain < 3 && shuntVoltage > 40000<<gain {
+		gain++
+	}
+
+	// calibration = 0.04096 / (current LSB in A * shunt in Ω)
+	d.currentLSB = (maxCurrent*1000 + 32767) / 32768
+	calibration := 40960000000 / (d.currentLSB * shunt)
+	if calibration < 1 || calibration > 0xFFFE {
+		d.currentLSB = 0
+		return errShunt
+	}
+
+	val := gain<<11 | uint16(adcModes[cfg.Averaging&7])<<7 | uint16(adcModes[cfg.Averaging&7])<<3 |
+		MODE_CONTINUOUS | MODE_VOLTAGE | MODE_CURRENT
+	if !cfg.LowVoltageRange {
+		val |= CONFIG_BRNG
+	}
+	if err := d.WriteRegister(REG_CONFIG, val); err != nil {
+		return err
+	}
+	return d.WriteRegister(REG_CALIBRATION, uint16(calibration)&0xFFFE)
+}
+
+// adcModes are the ADC settings of the configuration register by
+// averaging: 12-bit resolution with the given number of samples.
+var adcModes = [8]uint8{0x3, 0x9, 0xA, 0xB, 0xC, 0xD, 0xE, 0xF}
+
+// Reset resets the device, setting all registers to default values. The
+// device must be configured again to read the current and power.
+func (d *Device) Reset() error {
+	d.currentLSB = 0
+	return d.WriteRegister(REG_CONFIG, CONFIG_RESET)
+}
+
+// ShuntVoltage returns the voltage across the shunt resistor in µV
+// (resolution 10µV).
+func (d *Device) ShuntVoltage() (int32, error) {
+	val, err := d.ReadRegister(REG_SHUNTVOLTAGE)
+	if err != nil {
+		return 0, err
+	}
+	return int32(int16(val)) * 10, nil
+}
+
+// Voltage returns the bus voltage in µV (resolution 4mV).
+func (d *Device) Voltage() (int32, error) {
+	val, err := d.ReadRegister(REG_BUSVOLTAGE)
+	if err != nil {
+		return 0, err
+	}
+	return int32(val>>3) * 4000, nil
+}
+
+// Current returns the current in µA. It returns an error if the device was
+// not configured or the current is out of range.
+func (d *Device) Current() (int32, error) {
+	val, err := d.readCalibrated(REG_CURRENT)
+	if err != nil {
+		return 0, err
+	}
+	return int32(int64(int16(val)) * d.currentLSB / 1000), nil
+}
+
+// Power returns the power in µW. It returns an error if the device was not
+// configured or the power is out of range.
+func (d *Device) Power() (int32, error) {
+	val, err := d.readCalibrated(REG_POWER)
+	if err != nil {
+		return 0, err
+	}
+	// The power LSB is 20 times the current LSB.
+	return int32(int64(val) * d.currentLSB * 20 / 1000), nil
+}
+
+// readCalibrated reads the current or power register, which are only valid
+// when the device is calibrated and the calculation did not overflow.
+func (d *Device) readCalibrated(reg uint8) (uint16, error) {
+	if d.currentLSB == 0 {
+		return 0, errShunt
+	}
+	bus, err := d.ReadRegister(REG_BUSVOLTAGE)
+	if err != nil {
+		return 0, err
+	}
+	if bus&BUSVOLTAGE_OVF != 0 {
+		return 0, errOverflow
+	}
+	return d.ReadRegister(reg)
+}
+
+// Read a register
+func (d *Device) ReadRegister(reg uint8) (uint16, error) {
+	data := []byte{0, 0}
+	err := d.bus.ReadRegister(uint8(d.Address), reg, data)
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return (uint16(data[0]) << 8) | uint16(data[1]), nil
+}
+
+// Write to a register
+func (d *Device) WriteRegister(reg uint8, v uint16) error {
+	data := []byte{0, 0}
+	data[0] = byte(v >> 8)
+	data[1] = byte(v & 0xff)
+
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, data))
+}
@@ -0,0 +1,109 @@
+package ina219
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/energy"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ energy.Meter = (*Device)(nil)
+
+func TestConfigure(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice16(c, Address)
+	fake.Registers = defaultRegisters()
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Config{}), qt.IsNil)
+	// The defaults of the driver match the power-on defaults.
+	c.Assert(fake.Registers[REG_CONFIG], qt.Equals, uint16(0x399F))
+	c.Assert(fake.Registers[REG_CALIBRATION], qt.Equals, uint16(4194))
+
+	// 16V, 40mV range (400mA with 100mΩ), 16 samples.
+	c.Assert(dev.Configure(Config{MaxCurrent: 400000, LowVoltageRange: true, Averaging: AVG_16}), qt.IsNil)
+	c.Assert(fake.Registers[REG_CONFIG], qt.Equals, uint16(0x0667))
+	c.Assert(fake.Registers[REG_CALIBRATION], qt.Equals, uint16(33550))
+
+	c.Assert(dev.Configure(Config{ShuntResistance: 1, MaxCurrent: 1}), qt.Equals, errShunt)
+}
+
+func TestRead(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice16(c, Address)
+	fake.Registers = defaultRegisters()
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	_, err := dev.Current()
+	c.Assert(err, qt.Equals, errShunt)
+	c.Assert(dev.Configure(Config{}), qt.IsNil)
+
+	// 40mV across 100mΩ is 400mA, at 12V bus voltage.
+	fake.Registers[REG_SHUNTVOLTAGE] = 4000
+	fake.Registers[REG_BUSVOLTAGE] = 3000<<3 | BUSVOLTAGE_CNVR
+	fake.Registers[REG_CURRENT] = 4095
+	fake.Registers[REG_POWER] = 4095 * 3000 / 5000
+
+	shunt, err := dev.ShuntVoltage()
+	c.Assert(err, qt.IsNil)
+	c.Assert(shunt, qt.Equals, int32(40000))
+	voltage, err := dev.Voltage()
+	c.Assert(err, qt.IsNil)
+	c.Assert(voltage, qt.Equals, int32(12000000))
+	current, err := dev.Current()
+	c.Assert(err, qt.IsNil)
+	c.Assert(current, qt.Equals, int32(399905))
+	power, err := dev.Power()
+	c.Assert(err, qt.IsNil)
+	c.Assert(power, qt.Equals, int32(4798864))
+
+	// Negative currents.
+	fake.Registers[REG_CURRENT] = uint16(0x10000 - 4095)
+	current, err = dev.Current()
+	c.Assert(err, qt.IsNil)
+	c.Assert(current, qt.Equals, int32(-399905))
+
+	fake.Registers[REG_BUSVOLTAGE] |= BUSVOLTAGE_OVF
+	_, err = dev.Power()
+	c.Assert(err, qt.Equals, errOverflow)
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice16(c, Address)
+	fake.Registers = defaultRegisters()
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Config{}), qt.IsNil)
+	busErr := errors.New("nack")
+	fake.Err = busErr
+
+	_, err := dev.Voltage()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	_, err = dev.Current()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.Power()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
+
+// defaultRegisters returns the power-on defaults of the device's registers.
+func defaultRegisters() map[uint8]uint16 {
+	return map[uint8]uint16{
+		REG_CONFIG:       0x399F,
+		REG_SHUNTVOLTAGE: 0x0000,
+		REG_BUSVOLTAGE:   0x0000,
+		REG_POWER:        0x0000,
+		REG_CURRENT:      0x0000,
+		REG_CALIBRATION:  0x0000,
+	}
+}
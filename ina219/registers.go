@@ -0,0 +1,48 @@
+package ina219
+
+// The default I2C address for this device.
+//
+// The actual address is configurable by connecting address pins.
+const Address = 0x40
+
+// Registers
+const (
+	REG_CONFIG       = 0x00
+	REG_SHUNTVOLTAGE = 0x01
+	REG_BUSVOLTAGE   = 0x02
+	REG_POWER        = 0x03
+	REG_CURRENT      = 0x04
+	REG_CALIBRATION  = 0x05
+)
+
+// Configuration register bits.
+const (
+	CONFIG_RESET = 1 << 15
+	CONFIG_BRNG  = 1 << 13 // 32V bus voltage range
+
+	MODE_CONTINUOUS = 0x4
+	MODE_TRIGGERED  = 0x0
+	MODE_VOLTAGE    = 0x2
+	MODE_CURRENT    = 0x1
+)
+
+// Bus voltage register bits.
+const (
+	BUSVOLTAGE_CNVR = 1 << 1 // conversion ready
+	BUSVOLTAGE_OVF  = 1 << 0 // math overflow
+)
+
+// Averaging is the number of samples the ADC averages per conversion. A
+// single sample takes 532µs; averaging multiplies the conversion time.
+type Averaging uint8
+
+const (
+	AVG_1 Averaging = iota
+	AVG_2
+	AVG_4
+	AVG_8
+	AVG_16
+	AVG_32
+	AVG_64
+	AVG_128
+)
@@ -0,0 +1,117 @@
+package ina260
+
+import "errors"
+
+var errAlertFunction = errors.New("ina260: only one alert limit function can be enabled")
+
+// AlertConfig configures the ALERT pin.
+type AlertConfig struct {
+	// Function is the alert function, one of the limit functions
+	// optionally combined with ALERT_CONVERSION_READY.
+	Function Alert
+
+	// Limit is the limit of the limit function, in µA for current
+	// functions, µV for voltage functions and µW for ALERT_OVER_POWER. The
+	// resolution is 1.25mA, 1.25mV and 10mW.
+	Limit int32
+
+	// ActiveHigh drives the ALERT pin high instead of low on an alert.
+	ActiveHigh bool
+
+	// Latch keeps the ALERT pin and FLAG_ALERT set after the condition
+	// cleared, until the flags are read with ReadFlags.
+	Latch bool
+}
+
+// ConfigureAlert configures the ALERT pin and writes the alert limit.
+func (d *Device) ConfigureAlert(cfg AlertConfig) error {
+	limit := cfg.Function & alertLimitMask
+	if limit&(limit-1) != 0 {
+		return errAlertFunction
+	}
+	if limit != 0 {
+		if err := d.WriteRegister(REG_ALERTLIMIT, toLimit(limit, cfg.Limit)); err != nil {
+			return err
+		}
+	}
+	val := uint16(cfg.Function & (alertLimitMask | ALERT_CONVERSION_READY))
+	if cfg.ActiveHigh {
+		val |= MASKENABLE_APOL
+	}
+	if cfg.Latch {
+		val |= MASKENABLE_LEN
+	}
+	return d.WriteRegister(REG_MASKENABLE, val)
+}
+
+// ReadAlertLimit returns the enabled alert limit function and its limit in
+// µA, µV or µW. The limit is zero if no limit function is enabled.
+func (d *Device) ReadAlertLimit() (Alert, int32, error) {
+	mask, err := d.ReadRegister(REG_MASKENABLE)
+	if err != nil {
+		return 0, 0, err
+	}
+	function := Alert(mask) & alertLimitMask
+	// The most significant function takes priority.
+	for bit := ALERT_OVER_CURRENT; bit > ALERT_CONVERSION_READY; bit >>= 1 {
+		if function&bit != 0 {
+			function = bit
+			break
+		}
+	}
+	if function == 0 {
+		return 0, 0, nil
+	}
+	val, err := d.ReadRegister(REG_ALERTLIMIT)
+	if err != nil {
+		return 0, 0, err
+	}
+	return function, fromLimit(function, val), nil
+}
+
+// ReadFlags reads and clears the flags. Reading the flags also clears a
+// latched alert.
+func (d *Device) ReadFlags() (Flag, error) {
+	mask, err := d.ReadRegister(REG_MASKENABLE)
+	if err != nil {
+		return 0, err
+	}
+	return Flag(mask) & flagMask, nil
+}
+
+// toLimit converts a limit in µA, µV or µW to the alert limit register,
+// which compares against the register of the limit function.
+func toLimit(function Alert, limit int32) uint16 {
+	lsb := int32(1250)
+	if function == ALERT_OVER_POWER {
+		lsb = 10000
+	}
+	v := limit / lsb
+	if function == ALERT_OVER_POWER || function == ALERT_OVER_VOLTAGE || function == ALERT_UNDER_VOLTAGE {
+		// Unsigned registers.
+		if v < 0 {
+			v = 0
+		} else if v > 0xFFFF {
+			v = 0xFFFF
+		}
+		return uint16(v)
+	}
+	if v < -0x8000 {
+		v = -0x8000
+	} else if v > 0x7FFF {
+		v = 0x7FFF
+	}
+	return uint16(int16(v))
+}
+
+// fromLimit converts the alert limit register to µA, µV or µW.
+func fromLimit(function Alert, val uint16) int32 {
+	switch function {
+	case ALERT_OVER_POWER:
+		return int32(val) * 10000
+	case ALERT_OVER_VOLTAGE, ALERT_UNDER_VOLTAGE:
+		return int32(val) * 1250
+	default:
+		return int32(int16(val)) * 1250
+	}
+}
@@ -61,51 +61,56 @@ func (d *Device) Reset() {
 
 // Connected returns whether an INA260 has been found.
 func (d *Device) Connected() bool {
-	return d.ReadRegister(REG_MANF_ID) == MANF_ID &&
-		(d.ReadRegister(REG_DIE_ID)&DEVICE_ID_MASK) == DEVICE_ID
+	manf, err := d.ReadRegister(REG_MANF_ID)
+	if err != nil || manf != MANF_ID {
+		return false
+	}
+	die, err := d.ReadRegister(REG_DIE_ID)
+	return err == nil && die&DEVICE_ID_MASK == DEVICE_ID
 }
 
 // Gets the measured current in µA (max resolution 1.25mA)
-func (d *Device) Current() int32 {
-	val := d.ReadRegister(REG_CURRENT)
-
-	if val&0x8000 == 0 {
-		return int32(val) * 1250
+func (d *Device) Current() (int32, error) {
+	val, err := d.ReadRegister(REG_CURRENT)
+	if err != nil {
+		return 0, err
 	}
-
-	// Two's complement, convert to signed int
-	return -(int32(^val) + 1) * 1250
+	return int32(int16(val)) * 1250, nil
 }
 
 // Gets the measured voltage in µV (max resolution 1.25mV)
-func (d *Device) Voltage() int32 {
-	val := d.ReadRegister(REG_BUSVOLTAGE)
-
-	if val&0x8000 == 0 {
-		return int32(val) * 1250
+func (d *Device) Voltage() (int32, error) {
+	val, err := d.ReadRegister(REG_BUSVOLTAGE)
+	if err != nil {
+		return 0, err
 	}
-
-	// Two's complement, convert to signed int
-	return -(int32(^val) + 1) * 1250
+	return int32(int16(val)) * 1250, nil
 }
 
 // Gets the measured power in µW (max resolution 10mW)
-func (d *Device) Power() int32 {
-	return int32(d.ReadRegister(REG_POWER)) * 10000
+func (d *Device) Power() (int32, error) {
+	val, err := d.ReadRegister(REG_POWER)
+	if err != nil {
+		return 0, err
+	}
+	return int32(val) * 10000, nil
 }
 
 // Read a register
-func (d *Device) ReadRegister(reg uint8) uint16 {
+func (d *Device) ReadRegister(reg uint8) (uint16, error) {
 	data := []byte{0, 0}
-	d.bus.ReadRegister(uint8(d.Address), reg, data)
-	return (uint16(data[0]) << 8) | uint16(data[1])
+	err := d.bus.ReadRegister(uint8(d.Address), reg, data)
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return (uint16(data[0]) << 8) | uint16(data[1]), nil
 }
 
 // Write to a register
-func (d *Device) WriteRegister(reg uint8, v uint16) {
+func (d *Device) WriteRegister(reg uint8, v uint16) error {
 	data := []byte{0, 0}
 	data[0] = byte(v >> 8)
 	data[1] = byte(v & 0xff)
 
-	d.bus.WriteRegister(uint8(d.Address), reg, data)
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, data))
 }
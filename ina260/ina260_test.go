@@ -1,9 +1,11 @@
 package ina260
 
 import (
+	"errors"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
 	"tinygo.org/x/drivers/tester"
 )
 
@@ -35,7 +37,9 @@ func TestVoltage(t *testing.T) {
 
 	dev := New(bus)
 	// Datasheet: 2570h = 11.98V = 11980mV = 11980000uV
-	c.Assert(dev.Voltage(), qt.Equals, int32(11980000))
+	voltage, err := dev.Voltage()
+	c.Assert(err, qt.IsNil)
+	c.Assert(voltage, qt.Equals, int32(11980000))
 }
 
 func TestCurrent(t *testing.T) {
@@ -48,7 +52,9 @@ func TestCurrent(t *testing.T) {
 
 	dev := New(bus)
 	// Datasheet: 2710h = 12.5A = 12500mA = 12500000uA
-	c.Assert(dev.Current(), qt.Equals, int32(12500000))
+	current, err := dev.Current()
+	c.Assert(err, qt.IsNil)
+	c.Assert(current, qt.Equals, int32(12500000))
 }
 
 func TestPower(t *testing.T) {
@@ -61,7 +67,78 @@ func TestPower(t *testing.T) {
 
 	dev := New(bus)
 	// 3A7Fh = 149.75W = 149750mW = 149750000uW
-	c.Assert(dev.Power(), qt.Equals, int32(149750000))
+	power, err := dev.Power()
+	c.Assert(err, qt.IsNil)
+	c.Assert(power, qt.Equals, int32(149750000))
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice16(c, Address)
+	fake.Registers = defaultRegisters()
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	busErr := errors.New("nack")
+	fake.Err = busErr
+
+	_, err := dev.Voltage()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	_, err = dev.Current()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.Power()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(dev.Connected(), qt.IsFalse)
+}
+
+func TestAlert(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice16(c, Address)
+	fake.Registers = defaultRegisters()
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	function, limit, err := dev.ReadAlertLimit()
+	c.Assert(err, qt.IsNil)
+	c.Assert(function, qt.Equals, ALERT_NONE)
+	c.Assert(limit, qt.Equals, int32(0))
+
+	err = dev.ConfigureAlert(AlertConfig{
+		Function: ALERT_OVER_CURRENT | ALERT_CONVERSION_READY,
+		Limit:    2500000,
+		Latch:    true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(fake.Registers[REG_MASKENABLE], qt.Equals, uint16(0x8401))
+	c.Assert(fake.Registers[REG_ALERTLIMIT], qt.Equals, uint16(2000))
+	function, limit, err = dev.ReadAlertLimit()
+	c.Assert(err, qt.IsNil)
+	c.Assert(function, qt.Equals, ALERT_OVER_CURRENT)
+	c.Assert(limit, qt.Equals, int32(2500000))
+
+	// Negative current limits.
+	err = dev.ConfigureAlert(AlertConfig{Function: ALERT_UNDER_CURRENT, Limit: -1250000, ActiveHigh: true})
+	c.Assert(err, qt.IsNil)
+	c.Assert(fake.Registers[REG_MASKENABLE], qt.Equals, uint16(0x4002))
+	c.Assert(fake.Registers[REG_ALERTLIMIT], qt.Equals, uint16(0xFC18))
+	_, limit, err = dev.ReadAlertLimit()
+	c.Assert(err, qt.IsNil)
+	c.Assert(limit, qt.Equals, int32(-1250000))
+
+	err = dev.ConfigureAlert(AlertConfig{Function: ALERT_OVER_POWER, Limit: 50000000})
+	c.Assert(err, qt.IsNil)
+	c.Assert(fake.Registers[REG_ALERTLIMIT], qt.Equals, uint16(5000))
+
+	err = dev.ConfigureAlert(AlertConfig{Function: ALERT_OVER_POWER | ALERT_UNDER_VOLTAGE})
+	c.Assert(err, qt.Equals, errAlertFunction)
+
+	fake.Registers[REG_MASKENABLE] |= uint16(FLAG_ALERT | FLAG_CONVERSION_READY)
+	flags, err := dev.ReadFlags()
+	c.Assert(err, qt.IsNil)
+	c.Assert(flags, qt.Equals, FLAG_ALERT|FLAG_CONVERSION_READY)
 }
 
 // defaultRegisters returns the default values for all of the device's registers.
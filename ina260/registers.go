@@ -48,3 +48,37 @@ const (
 	MODE_CURRENT    = 0x1
 	MODE_NO_CURRENT = 0x0
 )
+
+// Alert functions of the Mask/Enable register. Only one of the limit
+// functions can be enabled at a time; ALERT_CONVERSION_READY can be
+// combined with any of them.
+type Alert uint16
+
+const (
+	ALERT_NONE             Alert = 0
+	ALERT_OVER_CURRENT     Alert = 1 << 15
+	ALERT_UNDER_CURRENT    Alert = 1 << 14
+	ALERT_OVER_VOLTAGE     Alert = 1 << 13
+	ALERT_UNDER_VOLTAGE    Alert = 1 << 12
+	ALERT_OVER_POWER       Alert = 1 << 11
+	ALERT_CONVERSION_READY Alert = 1 << 10
+
+	alertLimitMask = ALERT_OVER_CURRENT | ALERT_UNDER_CURRENT | ALERT_OVER_VOLTAGE | ALERT_UNDER_VOLTAGE | ALERT_OVER_POWER
+)
+
+// Flags of the Mask/Enable register.
+type Flag uint16
+
+const (
+	FLAG_ALERT            Flag = 1 << 4 // an alert function triggered
+	FLAG_CONVERSION_READY Flag = 1 << 3 // a conversion completed
+	FLAG_OVERFLOW         Flag = 1 << 2 // the power calculation overflowed
+
+	flagMask = FLAG_ALERT | FLAG_CONVERSION_READY | FLAG_OVERFLOW
+)
+
+// Mask/Enable register bits.
+const (
+	MASKENABLE_APOL = 1 << 1 // alert pin active high
+	MASKENABLE_LEN  = 1 << 0 // latch alerts
+)
@@ -0,0 +1,190 @@
+// Package lsm6ds implements the embedded motion functions shared by the ST
+// LSM6DS3, LSM6DS3TR and LSM6DSOX IMUs. The chips place these functions in
+// different registers, but encode their thresholds and durations and report
+// their events with the same register fields. Scale is also used by the
+// L3GD20 gyroscope.
+package lsm6ds // import "tinygo.org/x/drivers/internal/lsm6ds"
+
+import (
+	"math"
+	"time"
+)
+
+// Events, with the values of the Event constants of the drivers.
+const (
+	EventAccelReady uint16 = 1 << iota
+	EventGyroReady
+	EventFIFOThreshold
+	EventFIFOOverrun
+	EventFIFOFull
+	EventStep
+	EventSingleTap
+	EventDoubleTap
+	EventFreeFall
+	EventWakeUp
+	EventOrientation
+)
+
+// sampleRates are the accelerometer output data rates in mHz, indexed by
+// the ODR_XL field minus one.
+var sampleRates = [...]int64{12500, 26000, 52000, 104000, 208000, 416000, 833000, 1666000, 3332000, 6667000}
+
+// freeFallThresholds are the FF_THS thresholds in µg.
+var freeFallThresholds = [...]int32{156000, 219000, 250000, 312000, 344000, 406000, 469000, 500000}
+
+// Samples returns the number of accelerometer samples in the duration at
+// the output data rate odr, the value of the ODR_XL field. It returns 0 if
+// the accelerometer is off.
+func Samples(duration time.Duration, odr uint8) int32 {
+	if odr == 0 || int(odr) > len(sampleRates) {
+		return 0
+	}
+	return int32(int64(duration/time.Microsecond) * sampleRates[odr-1] / 1e9)
+}
+
+// Scale converts a little-endian raw sample to µg or µ°/s at a sensitivity
+// in µg/LSB or µ°/s/LSB. At 2000 dps the samples near full scale exceed
+// the range of an int32 in µ°/s, so they saturate at about ±2147 °/s.
+func Scale(lo, hi uint8, sensitivity int32) int32 {
+	v := int64(int16(uint16(hi)<<8|uint16(lo))) * int64(sensitivity)
+	if v > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	if v < math.MinInt32 {
+		return math.MinInt32
+	}
+	return int32(v)
+}
+
+// TapThreshold returns the TAP_THS field for a threshold in µg, at an
+// accelerometer sensitivity in µg/LSB. A step of the field is 1/32 of the
+// full-scale range.
+func TapThreshold(threshold, sensitivity int32) uint8 {
+	return clamp(threshold/(sensitivity*1024), 1, 31)
+}
+
+// TapDurations returns the INT_DUR2 register for the maximum duration of a
+// tap, the quiet time after it and the maximum time between the taps of a
+// double tap.
+func TapDurations(shock, quiet, window time.Duration, odr uint8) uint8 {
+	s := clamp(Samples(shock, odr)/8, 0, 3)
+	q := clamp(Samples(quiet, odr)/4, 0, 3)
+	w := clamp(Samples(window, odr)/32, 0, 15)
+	return w<<4 | q<<2 | s
+}
+
+// FreeFall returns the FF_THS field for a threshold in µg, rounded down,
+// and the 6-bit FF_DUR field for a duration.
+func FreeFall(threshold int32, duration time.Duration, odr uint8) (ths, dur uint8) {
+	for i, t := range freeFallThresholds {
+		if threshold >= t {
+			ths = uint8(i)
+		}
+	}
+	return ths, clamp(Samples(duration, odr), 0, 63)
+}
+
+// WakeUp returns the WK_THS field for a threshold in µg, at an
+// accelerometer sensitivity in µg/LSB, and the WAKE_DUR field for a
+// duration. A step of WK_THS is 1/64 of the full-scale range.
+func WakeUp(threshold, sensitivity int32, duration time.Duration, odr uint8) (ths, dur uint8) {
+	return clamp(threshold/(sensitivity*512), 0, 63), clamp(Samples(duration, odr), 0, 3)
+}
+
+// SixDThreshold returns the SIXD_THS field, which selects 80, 70, 60 or 50
+// degrees, for the nearest angle in degrees.
+func SixDThreshold(degrees int32) uint8 {
+	return clamp((80-degrees+5)/10, 0, 3)
+}
+
+// InterruptBits returns the bits of the INTx_CTRL and MDx_CFG registers
+// that route the events to an interrupt pin. The routing of EventStep
+// differs between the chips and is left to the driver.
+func InterruptBits(events uint16) (ctrl, md uint8) {
+	return bits(events, ctrlBits), bits(events, mdBits)
+}
+
+// SourceEvents returns the events set in the WAKE_UP_SRC, TAP_SRC,
+// D6D_SRC, STATUS_REG and FIFO_STATUS2 registers.
+func SourceEvents(wakeUpSrc, tapSrc, d6dSrc, status, fifoStatus2 uint8) uint16 {
+	return flags(wakeUpSrc, wakeUpSrcBits) | flags(tapSrc, tapSrcBits) |
+		flags(d6dSrc, d6dSrcBits) | flags(status, statusBits) |
+		flags(fifoStatus2, fifoStatusBits)
+}
+
+type eventBit struct {
+	event uint16
+	bit   uint8
+}
+
+// Bits of the interrupt routing registers.
+var (
+	ctrlBits = []eventBit{
+		{EventAccelReady, 0x01},
+		{EventGyroReady, 0x02},
+		{EventFIFOThreshold, 0x08},
+		{EventFIFOOverrun, 0x10},
+		{EventFIFOFull, 0x20},
+	}
+	mdBits = []eventBit{
+		{EventOrientation, 0x04},
+		{EventDoubleTap, 0x08},
+		{EventFreeFall, 0x10},
+		{EventWakeUp, 0x20},
+		{EventSingleTap, 0x40},
+	}
+)
+
+// Bits of the event source registers.
+var (
+	wakeUpSrcBits = []eventBit{
+		{EventWakeUp, 0x08},
+		{EventFreeFall, 0x20},
+	}
+	tapSrcBits = []eventBit{
+		{EventDoubleTap, 0x10},
+		{EventSingleTap, 0x20},
+	}
+	d6dSrcBits = []eventBit{
+		{EventOrientation, 0x40},
+	}
+	statusBits = []eventBit{
+		{EventAccelReady, 0x01},
+		{EventGyroReady, 0x02},
+	}
+	fifoStatusBits = []eventBit{
+		{EventFIFOFull, 0x20},
+		{EventFIFOOverrun, 0x40},
+		{EventFIFOThreshold, 0x80},
+	}
+)
+
+// bits returns the register bits of the events.
+func bits(events uint16, bits []eventBit) (value uint8) {
+	for _, b := range bits {
+		if events&b.event != 0 {
+			value |= b.bit
+		}
+	}
+	return value
+}
+
+// flags returns the events of the bits set in a register value.
+func flags(value uint8, bits []eventBit) (events uint16) {
+	for _, b := range bits {
+		if value&b.bit != 0 {
+			events |= b.event
+		}
+	}
+	return events
+}
+
+func clamp(v, min, max int32) uint8 {
+	if v < min {
+		return uint8(min)
+	}
+	if v > max {
+		return uint8(max)
+	}
+	return uint8(v)
+}
@@ -0,0 +1,60 @@
+package lsm6ds
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestSamples(t *testing.T) {
+	c := qt.New(t)
+	// 104Hz.
+	c.Assert(Samples(100*time.Millisecond, 4), qt.Equals, int32(10))
+	c.Assert(Samples(time.Second, 10), qt.Equals, int32(6667))
+	// Off and out of range.
+	c.Assert(Samples(time.Second, 0), qt.Equals, int32(0))
+	c.Assert(Samples(time.Second, 11), qt.Equals, int32(0))
+}
+
+func TestScale(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(Scale(0xE8, 0x03, 70000), qt.Equals, int32(70000000))
+	c.Assert(Scale(0x18, 0xFC, 70000), qt.Equals, int32(-70000000))
+	// 2000 dps at full scale saturates.
+	c.Assert(Scale(0xFF, 0x7F, 70000), qt.Equals, int32(math.MaxInt32))
+	c.Assert(Scale(0x00, 0x80, 70000), qt.Equals, int32(math.MinInt32))
+	c.Assert(Scale(0x00, 0x80, 61), qt.Equals, int32(-1998848))
+}
+
+func TestFields(t *testing.T) {
+	c := qt.New(t)
+	// ±2g at 61µg/LSB: 1/32 of the range is 62464µg.
+	c.Assert(TapThreshold(500000, 61), qt.Equals, uint8(8))
+	c.Assert(TapThreshold(0, 61), qt.Equals, uint8(1))
+	c.Assert(TapDurations(80*time.Millisecond, 40*time.Millisecond, 320*time.Millisecond, 4), qt.Equals, uint8(0x15))
+
+	ths, dur := FreeFall(300000, 100*time.Millisecond, 4)
+	c.Assert([]uint8{ths, dur}, qt.DeepEquals, []uint8{2, 10})
+	ths, dur = FreeFall(100000, time.Second, 4)
+	c.Assert([]uint8{ths, dur}, qt.DeepEquals, []uint8{0, 63})
+
+	ths, dur = WakeUp(125000, 61, 20*time.Millisecond, 4)
+	c.Assert([]uint8{ths, dur}, qt.DeepEquals, []uint8{4, 2})
+
+	c.Assert(SixDThreshold(80), qt.Equals, uint8(0))
+	c.Assert(SixDThreshold(62), qt.Equals, uint8(2))
+	c.Assert(SixDThreshold(45), qt.Equals, uint8(3))
+}
+
+func TestEvents(t *testing.T) {
+	c := qt.New(t)
+	ctrl, md := InterruptBits(EventFIFOThreshold | EventSingleTap | EventOrientation | EventStep)
+	c.Assert(ctrl, qt.Equals, uint8(0x08))
+	c.Assert(md, qt.Equals, uint8(0x44))
+
+	events := SourceEvents(0x28, 0x10, 0x40, 0x01, 0xA0)
+	c.Assert(events, qt.Equals, EventWakeUp|EventFreeFall|EventDoubleTap|
+		EventOrientation|EventAccelReady|EventFIFOThreshold|EventFIFOFull)
+}
@@ -0,0 +1,43 @@
+// Package sensirion implements the framing of data shared by the I2C
+// sensors of Sensirion, such as the SHT3x, SHTC3 and SCD4x: 16-bit big
+// endian words, each followed by a CRC-8 of the word.
+package sensirion // import "tinygo.org/x/drivers/internal/sensirion"
+
+// CRC8 returns the CRC-8 of data used by Sensirion sensors: polynomial
+// 0x31, initial value 0xFF, no final XOR.
+func CRC8(data []byte) uint8 {
+	crc := uint8(0xFF)
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x31
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// PutWord writes the word v followed by its CRC to the first 3 bytes of
+// buf.
+func PutWord(buf []byte, v uint16) {
+	buf[0] = byte(v >> 8)
+	buf[1] = byte(v)
+	buf[2] = CRC8(buf[:2])
+}
+
+// DecodeWords decodes data, which holds len(words) words each followed by
+// its CRC, into words. It returns false if a CRC does not match, in which
+// case words is undefined.
+func DecodeWords(data []byte, words []uint16) bool {
+	for i := range words {
+		w := data[i*3 : i*3+3]
+		if CRC8(w[:2]) != w[2] {
+			return false
+		}
+		words[i] = uint16(w[0])<<8 | uint16(w[1])
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package sensirion
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestCRC8(t *testing.T) {
+	c := qt.New(t)
+	// The example of the datasheets.
+	c.Assert(CRC8([]byte{0xBE, 0xEF}), qt.Equals, uint8(0x92))
+}
+
+func TestWords(t *testing.T) {
+	c := qt.New(t)
+	data := make([]byte, 6)
+	PutWord(data, 0xBEEF)
+	PutWord(data[3:], 0x1234)
+	c.Assert(data[:3], qt.DeepEquals, []byte{0xBE, 0xEF, 0x92})
+
+	words := make([]uint16, 2)
+	c.Assert(DecodeWords(data, words), qt.IsTrue)
+	c.Assert(words, qt.DeepEquals, []uint16{0xBEEF, 0x1234})
+
+	data[4] ^= 1
+	c.Assert(DecodeWords(data, words), qt.IsFalse)
+}
@@ -1,7 +1,7 @@
 package keypad4x4
 
 import (
-	"machine"
+	"tinygo.org/x/drivers"
 )
 
 // NoKeyPressed is used, when no key was pressed
@@ -19,31 +19,29 @@ type device struct {
 	inputEnabled bool
 	lastColumn   int
 	lastRow      int
-	columns      [4]machine.Pin
-	rows         [4]machine.Pin
+	columns      [4]drivers.Pin
+	rows         [4]drivers.Pin
 	mapping      [4][4]uint8
 }
 
 // takes r4 -r1 pins and c4 - c1 pins
-func NewDevice(r4, r3, r2, r1, c4, c3, c2, c1 machine.Pin) Device {
+func NewDevice(r4, r3, r2, r1, c4, c3, c2, c1 drivers.Pin) Device {
 	result := &device{}
-	result.columns = [4]machine.Pin{c4, c3, c2, c1}
-	result.rows = [4]machine.Pin{r4, r3, r2, r1}
+	result.columns = [4]drivers.Pin{c4, c3, c2, c1}
+	result.rows = [4]drivers.Pin{r4, r3, r2, r1}
 
 	return result
 }
 
 // Configure sets the column pins as input and the row pins as output
 func (keypad *device) Configure() {
-	inputConfig := machine.PinConfig{Mode: machine.PinInputPullup}
 	for i := range keypad.columns {
-		keypad.columns[i].Configure(inputConfig)
+		drivers.ConfigurePin(keypad.columns[i], drivers.PinInputPullup)
 	}
 
-	outputConfig := machine.PinConfig{Mode: machine.PinOutput}
 	for i := range keypad.rows {
-		keypad.rows[i].Configure(outputConfig)
-		keypad.rows[i].High()
+		drivers.ConfigurePin(keypad.rows[i], drivers.PinOutput)
+		keypad.rows[i].Set(true)
 	}
 
 	keypad.mapping = [4][4]uint8{
@@ -78,7 +76,7 @@ func (keypad *device) GetKey() uint8 {
 // GetIndices returns the position of the pressed key
 func (keypad *device) GetIndices() (int, int) {
 	for rowIndex, rowPin := range keypad.rows {
-		rowPin.Low()
+		rowPin.Set(false)
 
 		for columnIndex := range keypad.columns {
 			columnPin := keypad.columns[columnIndex]
@@ -100,7 +98,7 @@ func (keypad *device) GetIndices() (int, int) {
 			}
 		}
 
-		rowPin.High()
+		rowPin.Set(true)
 	}
 
 	return -1, -1
@@ -5,19 +5,19 @@
 package l293x // import "tinygo.org/x/drivers/l293x"
 
 import (
-	"machine"
+	"tinygo.org/x/drivers"
 )
 
 // Device is a motor without speed control.
 // a1 and a2 are the directional pins.
 // en is the pin turns the motor on/off.
 type Device struct {
-	a1, a2 machine.Pin
-	en     machine.Pin
+	a1, a2 drivers.Pin
+	en     drivers.Pin
 }
 
 // New returns a new Motor driver for GPIO-only operation.
-func New(direction1, direction2, enablePin machine.Pin) Device {
+func New(direction1, direction2, enablePin drivers.Pin) Device {
 	return Device{
 		a1: direction1,
 		a2: direction2,
@@ -27,55 +27,54 @@ func New(direction1, direction2, enablePin machine.Pin) Device {
 
 // Configure configures the Device.
 func (d *Device) Configure() {
-	d.a1.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	d.a2.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	d.en.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	drivers.ConfigurePin(d.a1, drivers.PinOutput)
+	drivers.ConfigurePin(d.a2, drivers.PinOutput)
+	drivers.ConfigurePin(d.en, drivers.PinOutput)
 
 	d.Stop()
 }
 
 // Forward turns motor on in forward direction.
 func (d *Device) Forward() {
-	d.a1.High()
-	d.a2.Low()
-	d.en.High()
+	d.a1.Set(true)
+	d.a2.Set(false)
+	d.en.Set(true)
 }
 
 // Backward turns motor on in backward direction.
 func (d *Device) Backward() {
-	d.a1.Low()
-	d.a2.High()
-	d.en.High()
+	d.a1.Set(false)
+	d.a2.Set(true)
+	d.en.Set(true)
 }
 
 // Stop turns motor off.
 func (d *Device) Stop() {
-	d.a1.Low()
-	d.a2.Low()
-	d.en.Low()
+	d.a1.Set(false)
+	d.a2.Set(false)
+	d.en.Set(false)
 }
 
-// PWM is the interface necessary for controlling the motor driver.
+// PWM is the interface necessary for controlling the motor driver. It is
+// implemented by the machine PWM types, which must be configured before
+// use.
 type PWM interface {
-	Configure(config machine.PWMConfig) error
-	Channel(pin machine.Pin) (channel uint8, err error)
 	Top() uint32
 	Set(channel uint8, value uint32)
-	SetPeriod(period uint64) error
 }
 
 // PWMDevice is a motor with speed control.
 // a1 and a2 are the directional GPIO pins.
 // en is the PWM pin that controls the motor speed.
 type PWMDevice struct {
-	a1, a2 machine.Pin
+	a1, a2 drivers.Pin
 	spc    uint8
 	pwm    PWM
 }
 
 // NewWithSpeed returns a new PWMMotor driver that uses an already configured PWM channel
 // to control speed.
-func NewWithSpeed(direction1, direction2 machine.Pin, spc uint8, pwm PWM) PWMDevice {
+func NewWithSpeed(direction1, direction2 drivers.Pin, spc uint8, pwm PWM) PWMDevice {
 	return PWMDevice{
 		a1:  direction1,
 		a2:  direction2,
@@ -87,8 +86,8 @@ func NewWithSpeed(direction1, direction2 machine.Pin, spc uint8, pwm PWM) PWMDev
 // Configure configures the PWMDevice. Note that the PWM interface and
 // channel must already be configured, this function will not do it for you.
 func (d *PWMDevice) Configure() error {
-	d.a1.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	d.a2.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	drivers.ConfigurePin(d.a1, drivers.PinOutput)
+	drivers.ConfigurePin(d.a2, drivers.PinOutput)
 
 	d.Stop()
 
@@ -101,8 +100,8 @@ func (d *PWMDevice) Forward(speed uint32) {
 		speed = 100
 	}
 
-	d.a1.High()
-	d.a2.Low()
+	d.a1.Set(true)
+	d.a2.Set(false)
 	d.pwm.Set(d.spc, d.pwm.Top()*speed/100)
 }
 
@@ -112,14 +111,14 @@ func (d *PWMDevice) Backward(speed uint32) {
 		speed = 100
 	}
 
-	d.a1.Low()
-	d.a2.High()
+	d.a1.Set(false)
+	d.a2.Set(true)
 	d.pwm.Set(d.spc, d.pwm.Top()*speed/100)
 }
 
 // Stop turns motor off.
 func (d *PWMDevice) Stop() {
-	d.a1.Low()
-	d.a2.Low()
+	d.a1.Set(false)
+	d.a2.Set(false)
 	d.pwm.Set(d.spc, 0)
 }
@@ -1,10 +1,10 @@
 package l3gd20
 
 import (
-	"encoding/binary"
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/lsm6ds"
 )
 
 const (
@@ -86,7 +86,11 @@ func (d *DevI2C) Configure(cfg Config) error {
 	return nil
 }
 
-func (d *DevI2C) Update() error {
+// Update reads the angular velocity. Use AngularVelocity to get the value.
+func (d *DevI2C) Update(which drivers.Measurement) error {
+	if which&drivers.AngularVelocity == 0 {
+		return nil
+	}
 	err := d.bus.ReadRegister(d.addr, OUT_X_L, d.databuf[:2])
 	if err != nil {
 		return err
@@ -99,12 +103,9 @@ func (d *DevI2C) Update() error {
 	if err != nil {
 		return err
 	}
-	x := int16(binary.LittleEndian.Uint16(d.databuf[0:]))
-	y := int16(binary.LittleEndian.Uint16(d.databuf[2:]))
-	z := int16(binary.LittleEndian.Uint16(d.databuf[4:]))
-	d.data[0] = d.mul * int32(x)
-	d.data[1] = d.mul * int32(y)
-	d.data[2] = d.mul * int32(z)
+	d.data[0] = lsm6ds.Scale(d.databuf[0], d.databuf[1], d.mul)
+	d.data[1] = lsm6ds.Scale(d.databuf[2], d.databuf[3], d.mul)
+	d.data[2] = lsm6ds.Scale(d.databuf[4], d.databuf[5], d.mul)
 	return nil
 }
 
@@ -123,13 +124,13 @@ func (d *DevI2C) Reboot() error {
 	return d.write8(CTRL_REG5, reg5&^reg5RebootBit)
 }
 
-// AngularVelocity returns result in microradians per second.
+// AngularVelocity returns the rotation in µ°/s (micro-degrees/sec) read by
+// the last call to Update, as required by drivers.Gyroscope. Note that
+// previous versions of this driver returned µrad/s (micro-radians/sec).
 func (d *DevI2C) AngularVelocity() (x, y, z int32) {
 	return d.data[0], d.data[1], d.data[2]
 }
 
-// func (d DevI2C) Update(measurement)
-
 func (d DevI2C) read8(reg uint8) (byte, error) {
 	err := d.bus.ReadRegister(d.addr, reg, d.buf[:1])
 	return d.buf[0], err
@@ -28,11 +28,10 @@ const (
 	sens_500       = 7. / sensDiv500dps  // Sensitivity at 500 dps
 	sens_2000      = 7. / sensDiv2000dps // Sensitivity at 500 dp
 
-	// 1e6*Pi/180. = 17453.292519943298 (constant for Degree to micro radians conversion)
-	// sensitivities for radians
-	sensMul250  = 7 * 1745329 / 100 / sensDiv250dps
-	sensMul500  = 7 * 1745329 / 100 / sensDiv500dps
-	sensMul2000 = 7 * 1745329 / 100 / sensDiv2000dps
+	// sensitivities in micro degrees per second
+	sensMul250  = 7 * 1000000 / sensDiv250dps
+	sensMul500  = 7 * 1000000 / sensDiv500dps
+	sensMul2000 = 7 * 1000000 / sensDiv2000dps
 )
 
 type Config struct {
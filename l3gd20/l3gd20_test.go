@@ -0,0 +1,37 @@
+package l3gd20
+
+import (
+	"math"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestAngularVelocityFullScale(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, 105)
+	bus.AddDevice(fake)
+	fake.Registers[WHOAMI] = expectedWHOAMI
+	// X and Y at full scale, Z at 1000 LSB.
+	copy(fake.Registers[OUT_X_L:], []uint8{0xFF, 0x7F, 0x00, 0x80, 0xE8, 0x03})
+
+	dev := NewI2C(bus, 105)
+	var _ drivers.Gyroscope = dev
+
+	for _, test := range []struct {
+		rng     uint8
+		x, y, z int32
+	}{
+		{Range_250, 286711250, -286720000, 8750000},
+		{Range_500, 573422500, -573440000, 17500000},
+		{Range_2000, math.MaxInt32, math.MinInt32, 70000000},
+	} {
+		c.Assert(dev.Configure(Config{Range: test.rng}), qt.IsNil)
+		c.Assert(dev.Update(drivers.AngularVelocity), qt.IsNil)
+		x, y, z := dev.AngularVelocity()
+		c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{test.x, test.y, test.z}, qt.Commentf("range %#x", test.rng))
+	}
+}
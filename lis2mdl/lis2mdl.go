@@ -18,6 +18,9 @@ type Device struct {
 	PowerMode  uint8
 	SystemMode uint8
 	DataRate   uint8
+
+	// last values read by Update
+	mag [3]int32
 }
 
 // Configuration for LIS2MDL device.
@@ -89,15 +92,21 @@ func (d *Device) Configure(cfg Configuration) {
 
 // ReadMagneticField reads the current magnetic field from the device and returns
 // it in mG (milligauss). 1 mG = 0.1 µT (microtesla).
-func (d *Device) ReadMagneticField() (x int32, y int32, z int32) {
+func (d *Device) ReadMagneticField() (x int32, y int32, z int32, err error) {
 	// turn back on read mode, even though it is supposed to be continuous?
 	cmd := []byte{0}
 	cmd[0] = byte(0x80 | d.PowerMode<<4 | d.DataRate<<2 | d.SystemMode)
-	d.bus.WriteRegister(uint8(d.Address), CFG_REG_A, cmd)
+	err = d.bus.WriteRegister(uint8(d.Address), CFG_REG_A, cmd)
+	if err != nil {
+		return 0, 0, 0, drivers.NotResponding(err)
+	}
 	time.Sleep(10 * time.Millisecond)
 
 	data := make([]byte, 6)
-	d.bus.ReadRegister(uint8(d.Address), OUTX_L_REG, data)
+	err = d.bus.ReadRegister(uint8(d.Address), OUTX_L_REG, data)
+	if err != nil {
+		return 0, 0, 0, drivers.NotResponding(err)
+	}
 
 	x = int32(int16((uint16(data[0]) << 8) | uint16(data[1])))
 	y = int32(int16((uint16(data[2]) << 8) | uint16(data[3])))
@@ -112,8 +121,16 @@ func (d *Device) ReadMagneticField() (x int32, y int32, z int32) {
 //
 // However, the heading may be off due to electronic compasses would be effected
 // by strong magnetic fields and require constant calibration.
-func (d *Device) ReadCompass() (h int32) {
-	x, y, _ := d.ReadMagneticField()
+//
+// The LIS2MDL has no accelerometer, so ReadCompass cannot compensate tilt.
+// For a calibrated heading, fit an ahrs.MagCalibration to readings of
+// ReadMagneticField converted to nT (1 mG = 100 nT), and pass the corrected
+// field with the acceleration of a separate accelerometer to ahrs.Heading.
+func (d *Device) ReadCompass() (h int32, err error) {
+	x, y, _, err := d.ReadMagneticField()
+	if err != nil {
+		return 0, err
+	}
 	xf, yf := float64(x)*0.15, float64(y)*0.15
 
 	rh := (math.Atan2(yf, xf) * 180) / math.Pi
@@ -121,5 +138,24 @@ func (d *Device) ReadCompass() (h int32) {
 		rh = 360 + rh
 	}
 
-	return int32(rh)
+	return int32(rh), nil
+}
+
+// Update reads the magnetic field. Use MagneticField to get the value.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&drivers.MagneticField != 0 {
+		x, y, z, err := d.ReadMagneticField()
+		if err != nil {
+			return err
+		}
+		// 1.5 mG/LSB, 1 mG = 100 nT
+		d.mag = [3]int32{x * 150, y * 150, z * 150}
+	}
+	return nil
+}
+
+// MagneticField returns the magnetic field in nT (nanotesla) read by the
+// last call to Update.
+func (d *Device) MagneticField() (x, y, z int32) {
+	return d.mag[0], d.mag[1], d.mag[2]
 }
@@ -1,9 +1,11 @@
 package lis2mdl
 
 import (
+	"errors"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
 	"tinygo.org/x/drivers/tester"
 )
 
@@ -28,6 +30,21 @@ func TestWhoAmI(t *testing.T) {
 	c.Assert(dev.Connected(), qt.Equals, false)
 }
 
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	dev := New(bus)
+
+	_, _, _, err := dev.ReadMagneticField()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, tester.ErrNoDevice), qt.IsTrue)
+	_, err = dev.ReadCompass()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	err = dev.Update(drivers.MagneticField)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
+
 // defaultRegisters returns the default values for all of the device's registers.
 // see table 22 on page 27 of the datasheet.
 func defaultRegisters() []uint8 {
@@ -0,0 +1,250 @@
+package lis3dh
+
+import "time"
+
+// ClickConfig configures single and double click detection.
+type ClickConfig struct {
+	// Axes enables click detection on the given axes. Zero disables click
+	// detection.
+	Axes Axis
+
+	// Threshold is the acceleration of a click in µg. Its resolution
+	// depends on the range: 16mg at ±2g, 32mg at ±4g, 62mg at ±8g and
+	// 186mg at ±16g.
+	Threshold int32
+
+	// TimeLimit is the maximum duration of a click, up to 127 samples.
+	TimeLimit time.Duration
+
+	// Latency is the time after a click in which no other click is
+	// detected, up to 255 samples.
+	Latency time.Duration
+
+	// Window is the time after the latency in which the second click of a
+	// double click must start, up to 255 samples.
+	Window time.Duration
+
+	// DoubleClick enables double click detection in addition to single
+	// click detection.
+	DoubleClick bool
+
+	// Latch keeps the click event set until read by ReadEventSource.
+	Latch bool
+}
+
+// InterruptConfig configures an interrupt generator. For example, free-fall
+// is detected with INTERRUPT_AND of X_LOW, Y_LOW and Z_LOW and a threshold
+// of about 350mg, wake-up with INTERRUPT_OR of X_HIGH, Y_HIGH and Z_HIGH.
+type InterruptConfig struct {
+	Mode InterruptMode
+
+	// Events are the axis events combined by the mode. In the 6D modes the
+	// events select the orientations to detect. Zero disables the
+	// interrupt generator.
+	Events AxisEvent
+
+	// Threshold is the acceleration threshold of the axis events in µg,
+	// with the same resolution as ClickConfig.Threshold.
+	Threshold int32
+
+	// Duration is the minimum duration of the event, up to 127 samples.
+	Duration time.Duration
+
+	// Latch keeps the event set until read by ReadEventSource.
+	Latch bool
+}
+
+// EventSource holds the events decoded from the source registers of the
+// device.
+type EventSource struct {
+	// Events are the events that are set.
+	Events Event
+
+	// ClickAxes are the axes on which the click was detected, and
+	// ClickNegative whether it was in the negative direction.
+	ClickAxes     Axis
+	ClickNegative bool
+
+	// IA1 and IA2 are the axis events that triggered the interrupt
+	// generators.
+	IA1, IA2 AxisEvent
+}
+
+// sampleRates are the data rates of the device in mHz.
+var sampleRates = [...]int64{1000, 10000, 25000, 50000, 100000, 200000, 400000, 1600000, 1344000}
+
+// thresholdLSBs are the resolutions of the click and interrupt generator
+// thresholds in µg, for each range. They are not a fixed fraction of the
+// range: the datasheet gives 186mg rather than 125mg at ±16g.
+var thresholdLSBs = [...]int32{16000, 32000, 62000, 186000}
+
+// ConfigureClick sets up single and double click detection.
+func (d *Device) ConfigureClick(config ClickConfig) error {
+	var cfg uint8
+	for i, axis := range [...]Axis{AXIS_X, AXIS_Y, AXIS_Z} {
+		if config.Axes&axis == 0 {
+			continue
+		}
+		// Each axis has a single and a double click enable bit.
+		cfg |= 0x01 << (i * 2)
+		if config.DoubleClick {
+			cfg |= 0x02 << (i * 2)
+		}
+	}
+	ths := clamp(config.Threshold/thresholdLSBs[d.r&0x03], 0, 127)
+	if config.Latch {
+		ths |= LIR_CLICK
+	}
+	if err := d.writeRegister(REG_CLICKTHS, ths); err != nil {
+		return err
+	}
+	if err := d.writeRegister(REG_TIMELIMIT, clamp(d.samples(config.TimeLimit), 0, 127)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(REG_TIMELATEN, clamp(d.samples(config.Latency), 0, 255)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(REG_TIMEWINDO, clamp(d.samples(config.Window), 0, 255)); err != nil {
+		return err
+	}
+	return d.writeRegister(REG_CLICKCFG, cfg)
+}
+
+// ConfigureInterrupt sets up one of the two interrupt generators, which
+// detect free-fall, wake-up and orientation. Route EVENT_IA1 or EVENT_IA2
+// to an interrupt pin to use them.
+func (d *Device) ConfigureInterrupt(generator InterruptGenerator, config InterruptConfig) error {
+	cfgReg, thsReg, durReg, lir := uint8(REG_INT1CFG), uint8(REG_INT1THS), uint8(REG_INT1DUR), uint8(LIR_INT1)
+	if generator == IA2 {
+		cfgReg, thsReg, durReg, lir = REG_INT2CFG, REG_INT2THS, REG_INT2DUR, LIR_INT2
+	}
+	var latch uint8
+	if config.Latch {
+		latch = lir
+	}
+	ths := clamp(config.Threshold/thresholdLSBs[d.r&0x03], 0, 127)
+	if err := d.writeRegister(thsReg, ths); err != nil {
+		return err
+	}
+	if err := d.writeRegister(durReg, clamp(d.samples(config.Duration), 0, 127)); err != nil {
+		return err
+	}
+	if err := d.updateRegister(REG_CTRL5, lir, latch); err != nil {
+		return err
+	}
+	return d.writeRegister(cfgReg, uint8(config.Mode)&0xC0|uint8(config.Events)&0x3F)
+}
+
+// RouteInterrupts sets the events that drive the given interrupt pin,
+// replacing the events routed to it before. EVENT_SINGLE_CLICK and
+// EVENT_DOUBLE_CLICK share a routing bit. The data ready and FIFO events can
+// only be routed to INT1.
+func (d *Device) RouteInterrupts(pin InterruptPin, events Event) error {
+	if pin == INT2 {
+		if events&^(EVENT_SINGLE_CLICK|EVENT_DOUBLE_CLICK|EVENT_IA1|EVENT_IA2) != 0 {
+			return errInterruptPin
+		}
+		// REG_CTRL6 also holds the interrupt polarity.
+		return d.updateRegister(REG_CTRL6, 0xE0, eventBits(events, routingBits))
+	}
+	return d.writeRegister(REG_CTRL3, eventBits(events, routingBits))
+}
+
+// ReadEventSource returns the events that are currently set, with the axes
+// that caused them. Reading the event source clears latched events.
+func (d *Device) ReadEventSource() (source EventSource, err error) {
+	data := d.buf[:1]
+	if err := d.readRegister(REG_STATUS2, data); err != nil {
+		return source, err
+	}
+	if data[0]&ZYXDA != 0 {
+		source.Events |= EVENT_DATA_READY
+	}
+
+	if err := d.readRegister(REG_FIFOSRC, data); err != nil {
+		return source, err
+	}
+	if data[0]&FIFO_WTM != 0 {
+		source.Events |= EVENT_FIFO_WATERMARK
+	}
+	if data[0]&FIFO_OVRN != 0 {
+		source.Events |= EVENT_FIFO_OVERRUN
+	}
+
+	if err := d.readRegister(REG_INT1SRC, data); err != nil {
+		return source, err
+	}
+	if data[0]&INT_IA != 0 {
+		source.Events |= EVENT_IA1
+		source.IA1 = AxisEvent(data[0] & 0x3F)
+	}
+
+	if err := d.readRegister(REG_INT2SRC, data); err != nil {
+		return source, err
+	}
+	if data[0]&INT_IA != 0 {
+		source.Events |= EVENT_IA2
+		source.IA2 = AxisEvent(data[0] & 0x3F)
+	}
+
+	if err := d.readRegister(REG_CLICKSRC, data); err != nil {
+		return source, err
+	}
+	if data[0]&CLICK_IA != 0 {
+		if data[0]&CLICK_SCLICK != 0 {
+			source.Events |= EVENT_SINGLE_CLICK
+		}
+		if data[0]&CLICK_DCLICK != 0 {
+			source.Events |= EVENT_DOUBLE_CLICK
+		}
+		source.ClickAxes = Axis(data[0] & 0x07)
+		source.ClickNegative = data[0]&CLICK_SIGN != 0
+	}
+	return source, nil
+}
+
+type eventBit struct {
+	event Event
+	bit   uint8
+}
+
+// Bits of REG_CTRL3 (INT1) and REG_CTRL6 (INT2). Only the click and
+// interrupt generator bits exist in both.
+var routingBits = []eventBit{
+	{EVENT_SINGLE_CLICK | EVENT_DOUBLE_CLICK, 0x80},
+	{EVENT_IA1, 0x40},
+	{EVENT_IA2, 0x20},
+	{EVENT_DATA_READY, 0x10},
+	{EVENT_FIFO_WATERMARK, 0x04},
+	{EVENT_FIFO_OVERRUN, 0x02},
+}
+
+// eventBits returns the register bits of the events.
+func eventBits(events Event, bits []eventBit) (value uint8) {
+	for _, b := range bits {
+		if events&b.event != 0 {
+			value |= b.bit
+		}
+	}
+	return value
+}
+
+// samples returns the number of samples in the duration at the current
+// data rate.
+func (d *Device) samples(duration time.Duration) int32 {
+	n := int(d.rate)
+	if n == 0 || n > len(sampleRates) {
+		return 0
+	}
+	return int32(int64(duration/time.Microsecond) * sampleRates[n-1] / 1e9)
+}
+
+func clamp(v, min, max int32) uint8 {
+	if v < min {
+		return uint8(min)
+	}
+	if v > max {
+		return uint8(max)
+	}
+	return uint8(v)
+}
@@ -0,0 +1,63 @@
+package lis3dh
+
+// FIFOSample is an acceleration sample read from the FIFO, in µg.
+type FIFOSample struct {
+	X, Y, Z int32
+}
+
+// ConfigureFIFO empties the FIFO and starts collecting samples in the given
+// mode. EVENT_FIFO_WATERMARK is set when the FIFO holds more than watermark
+// samples, up to 31. FIFO_BYPASS disables the FIFO.
+func (d *Device) ConfigureFIFO(mode FIFOMode, watermark uint8) error {
+	// Switching to bypass mode empties the FIFO.
+	if err := d.writeRegister(REG_FIFOCTRL, uint8(FIFO_BYPASS)); err != nil {
+		return err
+	}
+	var enable uint8
+	if mode != FIFO_BYPASS {
+		enable = FIFO_EN
+	}
+	if err := d.updateRegister(REG_CTRL5, FIFO_EN, enable); err != nil {
+		return err
+	}
+	return d.writeRegister(REG_FIFOCTRL, uint8(mode)&0xC0|watermark&0x1F)
+}
+
+// FIFOCount returns the number of unread samples in the FIFO.
+func (d *Device) FIFOCount() (int, error) {
+	data := d.buf[:1]
+	if err := d.readRegister(REG_FIFOSRC, data); err != nil {
+		return 0, err
+	}
+	switch {
+	case data[0]&FIFO_EMPTY != 0:
+		return 0, nil
+	case data[0]&FIFO_OVRN != 0:
+		return FIFOSize, nil
+	}
+	return int(data[0] & 0x1F), nil
+}
+
+// ReadFIFO reads up to len(samples) samples from the FIFO, oldest first, and
+// returns the number of samples read.
+func (d *Device) ReadFIFO(samples []FIFOSample) (n int, err error) {
+	count, err := d.FIFOCount()
+	if err != nil {
+		return 0, err
+	}
+	if count > len(samples) {
+		count = len(samples)
+	}
+	data := d.buf[:6]
+	for n = 0; n < count; n++ {
+		// Each read of the output registers pops a sample from the FIFO.
+		if err := d.readRegister(REG_OUT_X_L|0x80, data); err != nil {
+			return n, err
+		}
+		s := &samples[n]
+		s.X = d.convert(int16(uint16(data[1])<<8 | uint16(data[0])))
+		s.Y = d.convert(int16(uint16(data[3])<<8 | uint16(data[2])))
+		s.Z = d.convert(int16(uint16(data[5])<<8 | uint16(data[4])))
+	}
+	return n, nil
+}
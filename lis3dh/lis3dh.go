@@ -3,13 +3,27 @@
 // Datasheet: https://www.st.com/resource/en/datasheet/lis3dh.pdf
 package lis3dh // import "tinygo.org/x/drivers/lis3dh"
 
-import "tinygo.org/x/drivers"
+import (
+	"errors"
+
+	"tinygo.org/x/drivers"
+)
+
+var (
+	errADCChannel   = errors.New("lis3dh: invalid ADC channel")
+	errInterruptPin = errors.New("lis3dh: event cannot be routed to this pin")
+)
 
 // Device wraps an I2C connection to a LIS3DH device.
 type Device struct {
 	bus     drivers.I2C
 	Address uint16
 	r       Range
+	rate    DataRate
+	buf     [6]byte
+
+	// last values read by Update
+	accel [3]int32
 }
 
 // New creates a new LIS3DH connection. The I2C bus must already be configured.
@@ -56,6 +70,9 @@ func (d *Device) SetDataRate(rate DataRate) {
 	ctl1[0] &^= 0xf0
 	ctl1[0] |= (byte(rate) << 4)
 	d.bus.WriteRegister(uint8(d.Address), REG_CTRL1, ctl1)
+
+	// store the new rate, used to convert durations to samples
+	d.rate = rate
 }
 
 // SetRange sets the G range for LIS3DH.
@@ -93,7 +110,15 @@ func (d *Device) ReadRange() (r Range) {
 // and the sensor is not moving the returned value will be around 1000000 or
 // -1000000.
 func (d *Device) ReadAcceleration() (int32, int32, int32, error) {
-	x, y, z := d.ReadRawAcceleration()
+	x, y, z, err := d.readRawAcceleration()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return d.convert(x), d.convert(y), d.convert(z), nil
+}
+
+// convert returns a raw acceleration value in µg.
+func (d *Device) convert(raw int16) int32 {
 	divider := float32(1)
 	switch d.r {
 	case RANGE_16_G:
@@ -105,16 +130,20 @@ func (d *Device) ReadAcceleration() (int32, int32, int32, error) {
 	case RANGE_2_G:
 		divider = 16380
 	}
-
-	return int32(float32(x) / divider * 1000000), int32(float32(y) / divider * 1000000), int32(float32(z) / divider * 1000000), nil
+	return int32(float32(raw) / divider * 1000000)
 }
 
 // ReadRawAcceleration returns the raw x, y and z axis from the LIS3DH
 func (d *Device) ReadRawAcceleration() (x int16, y int16, z int16) {
-	d.bus.WriteRegister(uint8(d.Address), REG_OUT_X_L|0x80, nil)
+	x, y, z, _ = d.readRawAcceleration()
+	return
+}
 
+func (d *Device) readRawAcceleration() (x, y, z int16, err error) {
 	data := []byte{0, 0, 0, 0, 0, 0}
-	d.bus.Tx(d.Address, nil, data)
+	if err := d.readRegister(REG_OUT_X_L|0x80, data); err != nil {
+		return 0, 0, 0, err
+	}
 
 	x = int16((uint16(data[1]) << 8) | uint16(data[0]))
 	y = int16((uint16(data[3]) << 8) | uint16(data[2]))
@@ -122,3 +151,60 @@ func (d *Device) ReadRawAcceleration() (x int16, y int16, z int16) {
 
 	return
 }
+
+// EnableADC enables or disables the auxiliary ADC. Block data update must be
+// enabled, which Configure does.
+func (d *Device) EnableADC(enable bool) error {
+	var value uint8
+	if enable {
+		value = ADC_EN
+	}
+	return d.updateRegister(REG_TEMPCFG, ADC_EN, value)
+}
+
+// ReadADC returns the value of auxiliary ADC channel 1, 2 or 3. The value is
+// a left aligned two's complement number: the resolution is 10 bits in normal
+// and high resolution mode and 8 bits in low power mode. The input range is
+// given in the datasheet and depends on the supply voltage.
+func (d *Device) ReadADC(channel int) (int16, error) {
+	if channel < 1 || channel > 3 {
+		return 0, errADCChannel
+	}
+	data := d.buf[:2]
+	if err := d.readRegister(REG_OUTADC1_L+uint8(channel-1)*2|0x80, data); err != nil {
+		return 0, err
+	}
+	return int16(uint16(data[1])<<8 | uint16(data[0])), nil
+}
+
+// Update reads the acceleration. Use Acceleration to get the value.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.Acceleration != 0 {
+		d.accel[0], d.accel[1], d.accel[2], err = d.ReadAcceleration()
+	}
+	return
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *Device) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
+
+func (d *Device) writeRegister(reg, value uint8) error {
+	d.buf[0] = value
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, d.buf[:1]))
+}
+
+func (d *Device) readRegister(reg uint8, data []byte) error {
+	return drivers.NotResponding(d.bus.ReadRegister(uint8(d.Address), reg, data))
+}
+
+// updateRegister replaces the bits in mask of a register with value.
+func (d *Device) updateRegister(reg, mask, value uint8) error {
+	data := d.buf[:1]
+	if err := d.readRegister(reg, data); err != nil {
+		return err
+	}
+	return d.writeRegister(reg, data[0]&^mask|value&mask)
+}
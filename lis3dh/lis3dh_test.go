@@ -0,0 +1,194 @@
+package lis3dh
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ drivers.Accelerometer = (*Device)(nil)
+
+func newFakeDevice(c *qt.C) *tester.I2CDevice8 {
+	fake := tester.NewI2CDevice8(c, Address0)
+	fake.Registers[WHO_AM_I] = 0x33
+	return fake
+}
+
+func TestConfigure(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Connected(), qt.IsTrue)
+	dev.Configure()
+	c.Assert(fake.Registers[REG_CTRL1], qt.Equals, uint8(0x77))
+	c.Assert(fake.Registers[REG_CTRL4], qt.Equals, uint8(0x88))
+	c.Assert(dev.rate, qt.Equals, DataRate(DATARATE_400_HZ))
+	c.Assert(dev.samples(50*time.Millisecond), qt.Equals, int32(20))
+}
+
+func TestClick(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(dev.ConfigureClick(ClickConfig{
+		Axes:        AXIS_Z,
+		Threshold:   1200000,
+		TimeLimit:   20 * time.Millisecond,
+		Latency:     50 * time.Millisecond,
+		Window:      300 * time.Millisecond,
+		DoubleClick: true,
+		Latch:       true,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[REG_CLICKCFG], qt.Equals, uint8(0x30))
+	c.Assert(fake.Registers[REG_CLICKTHS], qt.Equals, uint8(LIR_CLICK|75))
+	c.Assert(fake.Registers[REG_TIMELIMIT], qt.Equals, uint8(8))
+	c.Assert(fake.Registers[REG_TIMELATEN], qt.Equals, uint8(20))
+	c.Assert(fake.Registers[REG_TIMEWINDO], qt.Equals, uint8(120))
+
+	c.Assert(dev.RouteInterrupts(INT1, EVENT_DOUBLE_CLICK|EVENT_FIFO_WATERMARK), qt.IsNil)
+	c.Assert(fake.Registers[REG_CTRL3], qt.Equals, uint8(0x84))
+
+	fake.Registers[REG_CLICKSRC] = CLICK_IA | CLICK_DCLICK | CLICK_SIGN | 0x04
+	source, err := dev.ReadEventSource()
+	c.Assert(err, qt.IsNil)
+	c.Assert(source, qt.DeepEquals, EventSource{
+		Events:        EVENT_DOUBLE_CLICK,
+		ClickAxes:     AXIS_Z,
+		ClickNegative: true,
+	})
+}
+
+func TestInterruptGenerator(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	fake.Registers[REG_CTRL6] = 0x02 // INT_POLARITY
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.Configure()
+	// Free-fall on IA2.
+	c.Assert(dev.ConfigureInterrupt(IA2, InterruptConfig{
+		Mode:      INTERRUPT_AND,
+		Events:    X_LOW | Y_LOW | Z_LOW,
+		Threshold: 350000,
+		Duration:  30 * time.Millisecond,
+		Latch:     true,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[REG_INT2CFG], qt.Equals, uint8(0x95))
+	c.Assert(fake.Registers[REG_INT2THS], qt.Equals, uint8(21))
+	c.Assert(fake.Registers[REG_INT2DUR], qt.Equals, uint8(12))
+	c.Assert(fake.Registers[REG_CTRL5], qt.Equals, uint8(LIR_INT2))
+	c.Assert(fake.Registers[REG_INT1CFG], qt.Equals, uint8(0))
+
+	c.Assert(dev.RouteInterrupts(INT2, EVENT_IA2), qt.IsNil)
+	c.Assert(fake.Registers[REG_CTRL6], qt.Equals, uint8(0x22))
+	c.Assert(dev.RouteInterrupts(INT2, EVENT_DATA_READY), qt.Equals, errInterruptPin)
+
+	fake.Registers[REG_STATUS2] = ZYXDA
+	fake.Registers[REG_INT2SRC] = INT_IA | uint8(X_LOW|Y_LOW|Z_LOW)
+	fake.Flags[REG_INT2SRC] = tester.RegisterClearOnRead
+	source, err := dev.ReadEventSource()
+	c.Assert(err, qt.IsNil)
+	c.Assert(source, qt.DeepEquals, EventSource{
+		Events: EVENT_DATA_READY | EVENT_IA2,
+		IA2:    X_LOW | Y_LOW | Z_LOW,
+	})
+
+	// Reading the source cleared the latched event.
+	source, err = dev.ReadEventSource()
+	c.Assert(err, qt.IsNil)
+	c.Assert(source.Events, qt.Equals, EVENT_DATA_READY)
+}
+
+func TestFIFO(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(dev.ConfigureFIFO(FIFO_STREAM, 16), qt.IsNil)
+	c.Assert(fake.Registers[REG_FIFOCTRL], qt.Equals, uint8(0x90))
+	c.Assert(fake.Registers[REG_CTRL5], qt.Equals, uint8(FIFO_EN))
+
+	fake.Registers[REG_FIFOSRC] = FIFO_EMPTY
+	count, err := dev.FIFOCount()
+	c.Assert(err, qt.IsNil)
+	c.Assert(count, qt.Equals, 0)
+	fake.Registers[REG_FIFOSRC] = FIFO_WTM | FIFO_OVRN | 0x1F
+	count, err = dev.FIFOCount()
+	c.Assert(err, qt.IsNil)
+	c.Assert(count, qt.Equals, FIFOSize)
+
+	fake.Registers[REG_FIFOSRC] = 2
+	fake.FIFO[REG_OUT_X_L|0x80] = []byte{
+		0xfc, 0x3f, 0x00, 0x00, 0x04, 0xc0,
+		0xfe, 0x1f, 0x00, 0x00, 0x04, 0xc0,
+	}
+	samples := make([]FIFOSample, 4)
+	n, err := dev.ReadFIFO(samples)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 2)
+	c.Assert(samples[:n], qt.DeepEquals, []FIFOSample{
+		{1000000, 0, -1000000},
+		{500000, 0, -1000000},
+	})
+
+	c.Assert(dev.ConfigureFIFO(FIFO_BYPASS, 0), qt.IsNil)
+	c.Assert(fake.Registers[REG_CTRL5], qt.Equals, uint8(0))
+}
+
+func TestADC(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	fake.Registers[REG_OUTADC2_L|0x80] = 0x40
+	fake.Registers[REG_OUTADC2_H|0x80] = 0xff
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.Configure()
+	c.Assert(dev.EnableADC(true), qt.IsNil)
+	c.Assert(fake.Registers[REG_TEMPCFG], qt.Equals, uint8(ADC_EN))
+
+	value, err := dev.ReadADC(2)
+	c.Assert(err, qt.IsNil)
+	c.Assert(value, qt.Equals, int16(-192))
+	_, err = dev.ReadADC(4)
+	c.Assert(err, qt.Equals, errADCChannel)
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.Configure()
+
+	busErr := errors.New("bus error")
+	fake.Err = busErr
+	_, err := dev.ReadEventSource()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	_, err = dev.ReadFIFO(make([]FIFOSample, 1))
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	_, _, _, err = dev.ReadAcceleration()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	err = dev.Update(drivers.Acceleration)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+}
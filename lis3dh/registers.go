@@ -41,6 +41,10 @@ const (
 	REG_INT1SRC   = 0x31
 	REG_INT1THS   = 0x32
 	REG_INT1DUR   = 0x33
+	REG_INT2CFG   = 0x34
+	REG_INT2SRC   = 0x35
+	REG_INT2THS   = 0x36
+	REG_INT2DUR   = 0x37
 	REG_CLICKCFG  = 0x38
 	REG_CLICKSRC  = 0x39
 	REG_CLICKTHS  = 0x3A
@@ -75,3 +79,103 @@ const (
 	DATARATE_LOWPOWER_1K6HZ          = 8
 	DATARATE_LOWPOWER_5KHZ           = 9
 )
+
+// Register bits.
+const (
+	ADC_EN = 0x80 // REG_TEMPCFG
+
+	HPCLICK = 0x04 // REG_CTRL2
+
+	FIFO_EN  = 0x40 // REG_CTRL5
+	LIR_INT1 = 0x08 // REG_CTRL5
+	LIR_INT2 = 0x02 // REG_CTRL5
+
+	ZYXDA = 0x08 // REG_STATUS2
+
+	FIFO_WTM   = 0x80 // REG_FIFOSRC
+	FIFO_OVRN  = 0x40 // REG_FIFOSRC
+	FIFO_EMPTY = 0x20 // REG_FIFOSRC
+
+	INT_IA = 0x40 // REG_INT1SRC, REG_INT2SRC
+
+	LIR_CLICK    = 0x80 // REG_CLICKTHS
+	CLICK_IA     = 0x40 // REG_CLICKSRC
+	CLICK_DCLICK = 0x20 // REG_CLICKSRC
+	CLICK_SCLICK = 0x10 // REG_CLICKSRC
+	CLICK_SIGN   = 0x08 // REG_CLICKSRC
+)
+
+// Axis is a bitmask of accelerometer axes.
+type Axis uint8
+
+const (
+	AXIS_X Axis = 0x01
+	AXIS_Y Axis = 0x02
+	AXIS_Z Axis = 0x04
+)
+
+// AxisEvent is a bitmask of axes above or below the threshold of an
+// interrupt generator.
+type AxisEvent uint8
+
+const (
+	X_LOW AxisEvent = 1 << iota
+	X_HIGH
+	Y_LOW
+	Y_HIGH
+	Z_LOW
+	Z_HIGH
+)
+
+// InterruptMode selects how the axis events of an interrupt generator are
+// combined.
+type InterruptMode uint8
+
+const (
+	INTERRUPT_OR          InterruptMode = 0x00 // any of the events
+	INTERRUPT_AND         InterruptMode = 0x80 // all of the events
+	INTERRUPT_6D_MOVEMENT InterruptMode = 0x40 // the orientation changed
+	INTERRUPT_6D_POSITION InterruptMode = 0xC0 // the device is in the orientation
+)
+
+// InterruptGenerator selects one of the two interrupt generators.
+type InterruptGenerator uint8
+
+const (
+	IA1 InterruptGenerator = iota
+	IA2
+)
+
+// InterruptPin selects the INT1 or INT2 pin.
+type InterruptPin uint8
+
+const (
+	INT1 InterruptPin = iota
+	INT2
+)
+
+// Event is a bitmask of events detected by the device.
+type Event uint8
+
+const (
+	EVENT_SINGLE_CLICK Event = 1 << iota
+	EVENT_DOUBLE_CLICK
+	EVENT_IA1
+	EVENT_IA2
+	EVENT_DATA_READY
+	EVENT_FIFO_WATERMARK
+	EVENT_FIFO_OVERRUN
+)
+
+// FIFOMode selects how the FIFO collects samples.
+type FIFOMode uint8
+
+const (
+	FIFO_BYPASS         FIFOMode = 0x00
+	FIFO_FIFO           FIFOMode = 0x40 // stop collecting when full
+	FIFO_STREAM         FIFOMode = 0x80 // overwrite the oldest samples when full
+	FIFO_STREAM_TO_FIFO FIFOMode = 0xC0 // switch to FIFO mode on an IA1 event
+)
+
+// FIFOSize is the number of samples the FIFO holds.
+const FIFOSize = 32
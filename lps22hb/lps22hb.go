@@ -11,6 +11,10 @@ import (
 type Device struct {
 	bus     drivers.I2C
 	Address uint8
+
+	// last values read by Update
+	temperature int32
+	pressure    int32
 }
 
 // New creates a new LPS22HB connection. The I2C bus must already be
@@ -23,13 +27,17 @@ func New(bus drivers.I2C) Device {
 
 // ReadPressure returns the pressure in milli pascals (mPa).
 func (d *Device) ReadPressure() (pressure int32, err error) {
-	d.waitForOneShot()
+	err = d.waitForOneShot()
+	if err != nil {
+		return
+	}
 
 	// read data
 	data := []byte{0, 0, 0}
-	d.bus.ReadRegister(d.Address, LPS22HB_PRESS_OUT_REG, data[:1])
-	d.bus.ReadRegister(d.Address, LPS22HB_PRESS_OUT_REG+1, data[1:2])
-	d.bus.ReadRegister(d.Address, LPS22HB_PRESS_OUT_REG+2, data[2:])
+	err = d.readOutput(LPS22HB_PRESS_OUT_REG, data)
+	if err != nil {
+		return
+	}
 	pValue := float32(uint32(data[2])<<16|uint32(data[1])<<8|uint32(data[0])) / 4096.0
 
 	return int32(pValue * 1000), nil
@@ -45,30 +53,90 @@ func (d *Device) Connected() bool {
 
 // ReadTemperature returns the temperature in celsius milli degrees (°C/1000).
 func (d *Device) ReadTemperature() (temperature int32, err error) {
-	d.waitForOneShot()
+	err = d.waitForOneShot()
+	if err != nil {
+		return
+	}
 
 	// read data
 	data := []byte{0, 0}
-	d.bus.ReadRegister(d.Address, LPS22HB_TEMP_OUT_REG, data[:1])
-	d.bus.ReadRegister(d.Address, LPS22HB_TEMP_OUT_REG+1, data[1:])
+	err = d.readOutput(LPS22HB_TEMP_OUT_REG, data)
+	if err != nil {
+		return
+	}
 	tValue := float32(int16(uint16(data[1])<<8|uint16(data[0]))) / 100.0
 
 	return int32(tValue * 1000), nil
 }
 
+// Update triggers a single conversion and reads the selected measurements.
+// Use Temperature and Pressure to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Pressure) == 0 {
+		return nil
+	}
+	err := d.waitForOneShot()
+	if err != nil {
+		return err
+	}
+
+	// pressure and temperature output registers are contiguous
+	data := make([]byte, 5)
+	err = d.readOutput(LPS22HB_PRESS_OUT_REG, data)
+	if err != nil {
+		return err
+	}
+	// 4096 LSB/hPa, 1 hPa = 100000 mPa
+	rawPressure := int64(uint32(data[2])<<16 | uint32(data[1])<<8 | uint32(data[0]))
+	d.pressure = int32(rawPressure * 3125 / 128)
+	// 100 LSB/°C
+	d.temperature = int32(int16(uint16(data[4])<<8|uint16(data[3]))) * 10
+	return nil
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// Pressure returns the pressure in milli pascals (mPa) read by the last call
+// to Update.
+func (d *Device) Pressure() int32 {
+	return d.pressure
+}
+
 // private functions
 
 // wait and trigger one shot in block update
-func (d *Device) waitForOneShot() {
+func (d *Device) waitForOneShot() error {
 	// trigger one shot
-	d.bus.WriteRegister(d.Address, LPS22HB_CTRL2_REG, []byte{0x01})
+	err := d.bus.WriteRegister(d.Address, LPS22HB_CTRL2_REG, []byte{0x01})
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
 
 	// wait until one shot is cleared
 	data := []byte{1}
 	for {
-		d.bus.ReadRegister(d.Address, LPS22HB_CTRL2_REG, data)
+		err = d.bus.ReadRegister(d.Address, LPS22HB_CTRL2_REG, data)
+		if err != nil {
+			return drivers.NotResponding(err)
+		}
 		if data[0]&0x01 == 0 {
-			break
+			return nil
+		}
+	}
+}
+
+// readOutput reads the output registers from reg into data, one register at
+// a time.
+func (d *Device) readOutput(reg uint8, data []byte) error {
+	for i := range data {
+		err := d.bus.ReadRegister(d.Address, reg+uint8(i), data[i:i+1])
+		if err != nil {
+			return drivers.NotResponding(err)
 		}
 	}
+	return nil
 }
@@ -0,0 +1,40 @@
+package lps22hb
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var (
+	_ drivers.Thermometer = (*Device)(nil)
+	_ drivers.Barometer   = (*Device)(nil)
+)
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, LPS22HB_ADDRESS)
+	bus.AddDevice(fake)
+	// The mock clears ONE_SHOT after it has been read once, as the end of
+	// the conversion would.
+	fake.Flags[LPS22HB_CTRL2_REG] = tester.RegisterClearOnRead
+	// 1013.25 hPa and 21.5°C.
+	copy(fake.Registers[LPS22HB_PRESS_OUT_REG:], []uint8{0x00, 0x54, 0x3F, 0x66, 0x08})
+
+	dev := New(bus)
+	c.Assert(dev.Update(drivers.Temperature|drivers.Pressure), qt.IsNil)
+	c.Assert(dev.Pressure(), qt.Equals, int32(101325000))
+	c.Assert(dev.Temperature(), qt.Equals, int32(21500))
+
+	// A bus error is returned and keeps the previous values.
+	fake.Err = errors.New("nack")
+	err := dev.Update(drivers.Temperature | drivers.Pressure)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(dev.Pressure(), qt.Equals, int32(101325000))
+	_, err = dev.ReadPressure()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
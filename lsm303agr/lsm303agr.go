@@ -23,6 +23,11 @@ type Device struct {
 	MagSystemMode  uint8
 	MagDataRate    uint8
 	buf            [6]uint8
+
+	// last values read by Update
+	accel       [3]int32
+	mag         [3]int32
+	temperature int32
 }
 
 // Configuration for LSM303AGR device.
@@ -204,6 +209,12 @@ func (d *Device) ReadMagneticField() (x, y, z int32, err error) {
 //
 // However, the heading may be off due to electronic compasses would be effected
 // by strong magnetic fields and require constant calibration.
+//
+// ReadCompass uses neither a calibration nor the accelerometer of the
+// LSM303AGR, so it is only correct when the sensor is level. Pass the
+// readings of ReadMagneticField, converted to nT (1 mG = 100 nT) and
+// corrected by an ahrs.MagCalibration, and of ReadAcceleration to
+// ahrs.Heading for a tilt compensated heading.
 func (d *Device) ReadCompass() (h int32, err error) {
 
 	x, y, _, err := d.ReadMagneticField()
@@ -228,3 +239,47 @@ func (d *Device) ReadTemperature() (t int32, err error) {
 	t = 25000 + int32((float32(r)/8)*1000)
 	return
 }
+
+// Update reads the selected measurements. Use Acceleration, MagneticField
+// and Temperature to get the values.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.Acceleration != 0 {
+		d.accel[0], d.accel[1], d.accel[2], err = d.ReadAcceleration()
+		if err != nil {
+			return
+		}
+	}
+	if which&drivers.MagneticField != 0 {
+		x, y, z, err := d.ReadMagneticField()
+		if err != nil {
+			return err
+		}
+		// 1 mG = 100 nT
+		d.mag = [3]int32{x * 100, y * 100, z * 100}
+	}
+	if which&drivers.Temperature != 0 {
+		d.temperature, err = d.ReadTemperature()
+		if err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *Device) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
+
+// MagneticField returns the magnetic field in nT (nanotesla) read by the
+// last call to Update.
+func (d *Device) MagneticField() (x, y, z int32) {
+	return d.mag[0], d.mag[1], d.mag[2]
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
@@ -0,0 +1,165 @@
+package lsm6ds3
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ interface {
+	drivers.Accelerometer
+	drivers.Gyroscope
+	drivers.Thermometer
+} = (*Device)(nil)
+
+func newFakeDevice(c *qt.C) *tester.I2CDevice8 {
+	fake := tester.NewI2CDevice8(c, Address)
+	fake.Registers[WHO_AM_I] = 0x69
+	fake.Registers[CTRL10_C] = 0x38 // gyroscope axes enabled
+	return fake
+}
+
+func TestPedometer(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	fake.Registers[STEP_COUNTER_L] = 0x39
+	fake.Registers[STEP_COUNTER_H] = 0x85
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{}), qt.IsNil)
+	c.Assert(dev.EnablePedometer(), qt.IsNil)
+	c.Assert(fake.Registers[CTRL10_C], qt.Equals, uint8(0x3C))
+	c.Assert(fake.Registers[TAP_CFG], qt.Equals, uint8(PEDO_EN))
+
+	steps, err := dev.ReadSteps()
+	c.Assert(err, qt.IsNil)
+	c.Assert(steps, qt.Equals, int32(34105))
+
+	trace := bus.Record()
+	c.Assert(dev.ResetSteps(), qt.IsNil)
+	c.Assert(trace.String(), qt.Equals, ""+
+		"i2c 0x6a w:19 r:3c\n"+
+		"i2c 0x6a w:193e\n"+
+		"i2c 0x6a w:19 r:3e\n"+
+		"i2c 0x6a w:193c\n")
+
+	c.Assert(dev.RouteInterrupts(INT1, EVENT_STEP|EVENT_FIFO_THRESHOLD), qt.IsNil)
+	c.Assert(fake.Registers[INT1_CTRL], qt.Equals, uint8(0x88))
+	c.Assert(dev.RouteInterrupts(INT2, EVENT_STEP), qt.Equals, errInterruptPin)
+
+	fake.Registers[FUNC_SRC] = STEP_DETECTED
+	events, err := dev.ReadEvents()
+	c.Assert(err, qt.IsNil)
+	c.Assert(events, qt.Equals, EVENT_STEP)
+}
+
+func TestMotionFunctions(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{AccelRange: ACCEL_8G, AccelSampleRate: ACCEL_SR_208}), qt.IsNil)
+
+	c.Assert(dev.ConfigureTap(TapConfig{Axes: AXIS_Z, Threshold: 2000000}), qt.IsNil)
+	c.Assert(fake.Registers[TAP_THS_6D], qt.Equals, uint8(8))
+	c.Assert(fake.Registers[TAP_CFG], qt.Equals, uint8(0x02))
+	c.Assert(dev.ConfigureWakeUp(500000, 10*time.Millisecond), qt.IsNil)
+	c.Assert(fake.Registers[WAKE_UP_THS], qt.Equals, uint8(4))
+	c.Assert(fake.Registers[WAKE_UP_DUR], qt.Equals, uint8(2<<5))
+	c.Assert(dev.Configure6D(70), qt.IsNil)
+	c.Assert(fake.Registers[TAP_THS_6D], qt.Equals, uint8(1<<5|8))
+
+	c.Assert(dev.RouteInterrupts(INT2, EVENT_WAKE_UP|EVENT_ORIENTATION), qt.IsNil)
+	c.Assert(fake.Registers[INT2_CTRL], qt.Equals, uint8(0))
+	c.Assert(fake.Registers[MD2_CFG], qt.Equals, uint8(0x24))
+
+	fake.Registers[WAKE_UP_SRC] = 0x08
+	fake.Registers[D6D_SRC] = 0x40 | 0x04 // Y down
+	events, err := dev.ReadEvents()
+	c.Assert(err, qt.IsNil)
+	c.Assert(events, qt.Equals, EVENT_WAKE_UP|EVENT_ORIENTATION)
+	orientation, err := dev.ReadOrientation()
+	c.Assert(err, qt.IsNil)
+	c.Assert(orientation, qt.Equals, ORIENTATION_Y_DOWN)
+}
+
+func TestFIFO(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{AccelRange: ACCEL_2G, GyroRange: GYRO_1000DPS}), qt.IsNil)
+	c.Assert(dev.ConfigureFIFO(FIFOConfig{
+		Mode:      FIFO_MODE_CONTINUOUS,
+		Rate:      ACCEL_SR_104,
+		Accel:     true,
+		Gyro:      true,
+		Threshold: 100,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[FIFO_CTRL1], qt.Equals, uint8(300&0xFF))
+	c.Assert(fake.Registers[FIFO_CTRL2], qt.Equals, uint8(300>>8))
+	c.Assert(fake.Registers[FIFO_CTRL3], qt.Equals, uint8(0x09))
+	c.Assert(fake.Registers[FIFO_CTRL5], qt.Equals, uint8(0x26))
+
+	// The last word of a gyroscope sample, followed by an accelerometer
+	// and a gyroscope sample.
+	fake.Registers[FIFO_STATUS1] = 7
+	fake.Registers[FIFO_STATUS3] = 2
+	fake.FIFO[FIFO_DATA_OUT_L] = []byte{
+		0xff, 0xff,
+		0x00, 0x40, 0x00, 0x00, 0x00, 0xc0,
+		0x10, 0x00, 0x00, 0x00, 0xf0, 0xff,
+	}
+	samples := make([]FIFOSample, 4)
+	n, err := dev.ReadFIFO(samples)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 2)
+	c.Assert(samples[:n], qt.DeepEquals, []FIFOSample{
+		{FIFO_TAG_ACCEL, 999424, 0, -999424},
+		{FIFO_TAG_GYRO, 560000, 0, -560000},
+	})
+	c.Assert(fake.FIFO[FIFO_DATA_OUT_L], qt.HasLen, 0)
+}
+
+func TestRotationFullScale(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFakeDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{GyroRange: GYRO_2000DPS}), qt.IsNil)
+	// X and Y at full scale, Z at 1000 LSB.
+	copy(fake.Registers[OUTX_L_G:], []uint8{0xFF, 0x7F, 0x00, 0x80, 0xE8, 0x03})
+	x, y, z, err := dev.ReadRotation()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{math.MaxInt32, math.MinInt32, 70000000})
+
+	c.Assert(dev.Update(drivers.AngularVelocity), qt.IsNil)
+	x, y, z = dev.AngularVelocity()
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{math.MaxInt32, math.MinInt32, 70000000})
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	dev := New(bus)
+
+	_, err := dev.ReadEvents()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.FIFOCount()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(dev.EnablePedometer(), drivers.ErrNotResponding), qt.IsTrue)
+}
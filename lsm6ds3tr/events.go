@@ -0,0 +1,201 @@
+package lsm6ds3tr
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers/internal/lsm6ds"
+)
+
+// Event is a bitmask of events detected by the device.
+type Event uint16
+
+// Orientation is a bitmask of the axes detected pointing up or down by 6D
+// orientation detection.
+type Orientation uint8
+
+// InterruptPin selects the INT1 or INT2 pin.
+type InterruptPin uint8
+
+// Axis is a bitmask of the accelerometer axes.
+type Axis uint8
+
+// TapConfig configures single and double tap detection. The LSM6DS3TR-C
+// uses one threshold for all axes.
+type TapConfig struct {
+	// Axes enables tap detection on the given axes. Zero disables tap
+	// detection.
+	Axes Axis
+
+	// Threshold is the acceleration of a tap in µg, with a resolution of
+	// 1/32 of the full-scale range.
+	Threshold int32
+
+	// Shock is the maximum duration of a tap. Zero selects 4 samples,
+	// otherwise the resolution is 8 samples.
+	Shock time.Duration
+
+	// Quiet is the time after a tap in which no other tap is detected.
+	// Zero selects 2 samples, otherwise the resolution is 4 samples.
+	Quiet time.Duration
+
+	// Window is the maximum time between the taps of a double tap. Zero
+	// selects 16 samples, otherwise the resolution is 32 samples.
+	Window time.Duration
+
+	// DoubleTap enables double tap detection in addition to single tap
+	// detection.
+	DoubleTap bool
+}
+
+var errInterruptPin = errors.New("lsm6ds3tr: the step detector can only be routed to INT1")
+
+// EnablePedometer starts counting steps. The accelerometer must run at
+// 26Hz or more. The LSM6DS3TR-C keeps counting while the host sleeps; read
+// the count with ReadSteps or route EVENT_STEP to the INT1 pin.
+func (d *Device) EnablePedometer() error {
+	return d.updateRegister(CTRL10_C, FUNC_EN|PEDO_EN, FUNC_EN|PEDO_EN)
+}
+
+// DisablePedometer stops counting steps.
+func (d *Device) DisablePedometer() error {
+	return d.updateRegister(CTRL10_C, PEDO_EN, 0)
+}
+
+// ResetSteps sets the step count to zero.
+func (d *Device) ResetSteps() error {
+	if err := d.updateRegister(CTRL10_C, PEDO_RST_STEP, PEDO_RST_STEP); err != nil {
+		return err
+	}
+	return d.updateRegister(CTRL10_C, PEDO_RST_STEP, 0)
+}
+
+// ReadSteps returns the number of steps counted by the pedometer, which
+// wraps around after 65535.
+func (d *Device) ReadSteps() (int32, error) {
+	data := d.buf[:2]
+	if err := d.readRegister(STEP_COUNTER_L, data); err != nil {
+		return 0, err
+	}
+	return int32(uint16(data[1])<<8 | uint16(data[0])), nil
+}
+
+// ConfigureTap sets up single and double tap detection.
+func (d *Device) ConfigureTap(config TapConfig) error {
+	odr := uint8(d.accelSampleRate) >> 4
+	var doubleTap uint8
+	if config.DoubleTap {
+		doubleTap = SINGLE_DOUBLE_TAP
+	}
+	if err := d.updateRegister(TAP_THS_6D, 0x1F, lsm6ds.TapThreshold(config.Threshold, d.accelFactor())); err != nil {
+		return err
+	}
+	if err := d.writeRegister(INT_DUR2, lsm6ds.TapDurations(config.Shock, config.Quiet, config.Window, odr)); err != nil {
+		return err
+	}
+	if err := d.updateRegister(WAKE_UP_THS, SINGLE_DOUBLE_TAP, doubleTap); err != nil {
+		return err
+	}
+	return d.updateRegister(TAP_CFG, 0x0E, uint8(config.Axes&(AXIS_X|AXIS_Y|AXIS_Z))<<1)
+}
+
+// ConfigureFreeFall sets up free-fall detection, which is triggered when
+// the acceleration of all axes stays below threshold, in µg, for the given
+// duration of up to 63 samples. The threshold is rounded down to one of
+// 156, 219, 250, 312, 344, 406, 469 or 500mg.
+func (d *Device) ConfigureFreeFall(threshold int32, duration time.Duration) error {
+	ths, dur := lsm6ds.FreeFall(threshold, duration, uint8(d.accelSampleRate)>>4)
+	// The highest bit of the duration is in WAKE_UP_DUR.
+	if err := d.updateRegister(WAKE_UP_DUR, 0x80, dur>>5<<7); err != nil {
+		return err
+	}
+	return d.writeRegister(FREE_FALL, dur&0x1F<<3|ths)
+}
+
+// ConfigureWakeUp sets up wake-up detection, which is triggered when the
+// high-pass filtered acceleration of any axis exceeds threshold, in µg, for
+// the given duration. The threshold has a resolution of 1/64 of the
+// full-scale range, the duration is up to 3 samples.
+func (d *Device) ConfigureWakeUp(threshold int32, duration time.Duration) error {
+	ths, dur := lsm6ds.WakeUp(threshold, d.accelFactor(), duration, uint8(d.accelSampleRate)>>4)
+	if err := d.updateRegister(WAKE_UP_THS, 0x3F, ths); err != nil {
+		return err
+	}
+	return d.updateRegister(WAKE_UP_DUR, 0x60, dur<<5)
+}
+
+// Configure6D sets the threshold angle of 6D orientation detection in
+// degrees: 50, 60, 70 or 80. Other angles are rounded to the nearest one.
+func (d *Device) Configure6D(threshold int32) error {
+	return d.updateRegister(TAP_THS_6D, 0x60, lsm6ds.SixDThreshold(threshold)<<5)
+}
+
+// LatchInterrupts selects whether the tap, free-fall, wake-up and 6D
+// events stay set until read by ReadEvents, instead of being cleared
+// when the condition ends.
+func (d *Device) LatchInterrupts(latch bool) error {
+	var lir uint8
+	if latch {
+		lir = LIR
+	}
+	return d.updateRegister(TAP_CFG, LIR, lir)
+}
+
+// RouteInterrupts sets the events that drive the given interrupt pin,
+// replacing the events routed to it before. The LSM6DS3TR-C routes
+// EVENT_STEP only to INT1. Routing a tap, free-fall, wake-up or 6D event
+// also sets INTERRUPTS_ENABLE, without which the chip does not signal
+// them on either pin.
+func (d *Device) RouteInterrupts(pin InterruptPin, events Event) error {
+	ctrl, md := lsm6ds.InterruptBits(uint16(events))
+	ctrlReg, mdReg := uint8(INT1_CTRL), uint8(MD1_CFG)
+	if pin == INT2 {
+		if events&EVENT_STEP != 0 {
+			return errInterruptPin
+		}
+		ctrlReg, mdReg = INT2_CTRL, MD2_CFG
+	} else if events&EVENT_STEP != 0 {
+		ctrl |= INT1_STEP_DETECTOR
+	}
+	if err := d.writeRegister(ctrlReg, ctrl); err != nil {
+		return err
+	}
+	if err := d.writeRegister(mdReg, md); err != nil {
+		return err
+	}
+	if md == 0 {
+		return nil
+	}
+	return d.updateRegister(TAP_CFG, INTERRUPTS_ENABLE, INTERRUPTS_ENABLE)
+}
+
+// ReadEvents returns the events that are currently set. Reading the events
+// clears latched events.
+func (d *Device) ReadEvents() (Event, error) {
+	data := d.buf[:6]
+	// WAKE_UP_SRC, TAP_SRC, D6D_SRC and STATUS_REG.
+	if err := d.readRegister(WAKE_UP_SRC, data[:4]); err != nil {
+		return 0, err
+	}
+	if err := d.readRegister(FIFO_STATUS2, data[4:5]); err != nil {
+		return 0, err
+	}
+	if err := d.readRegister(FUNC_SRC1, data[5:6]); err != nil {
+		return 0, err
+	}
+	events := Event(lsm6ds.SourceEvents(data[0], data[1], data[2], data[3], data[4]))
+	if data[5]&STEP_DETECTED != 0 {
+		events |= EVENT_STEP
+	}
+	return events, nil
+}
+
+// ReadOrientation returns the axes pointing up and down, as detected by 6D
+// orientation detection.
+func (d *Device) ReadOrientation() (Orientation, error) {
+	data := d.buf[:1]
+	if err := d.readRegister(D6D_SRC, data); err != nil {
+		return 0, err
+	}
+	return Orientation(data[0] & 0x3F), nil
+}
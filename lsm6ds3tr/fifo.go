@@ -0,0 +1,133 @@
+package lsm6ds3tr
+
+import "tinygo.org/x/drivers/internal/lsm6ds"
+
+// FIFOMode selects how the FIFO collects samples.
+type FIFOMode uint8
+
+// FIFOTag identifies the sensor of a FIFO sample. The LSM6DS3TR-C stores no
+// tags; ReadFIFO sets them from the order of the batched sensors.
+type FIFOTag uint8
+
+// FIFOConfig configures batching of samples in the FIFO.
+type FIFOConfig struct {
+	Mode FIFOMode
+
+	// Rate is the rate at which samples are written to the FIFO. It should
+	// not exceed the sample rates of the batched sensors.
+	Rate AccelSampleRate
+
+	// Accel and Gyro select the sensors to batch.
+	Accel bool
+	Gyro  bool
+
+	// Threshold is the number of samples in the FIFO that sets
+	// EVENT_FIFO_THRESHOLD, up to 682.
+	Threshold uint16
+}
+
+// FIFOSample is a sample read from the FIFO, in µg for the accelerometer
+// and µ°/s for the gyroscope.
+type FIFOSample struct {
+	Tag     FIFOTag
+	X, Y, Z int32
+}
+
+// FIFOSize is the size of the FIFO in 16-bit words, three per sample.
+const FIFOSize = 2048
+
+// ConfigureFIFO empties the 4 KB FIFO of the LSM6DS3TR-C and starts batching
+// samples. Both sensors are batched at Rate.
+func (d *Device) ConfigureFIFO(config FIFOConfig) error {
+	// Switching to bypass mode empties the FIFO.
+	if err := d.writeRegister(FIFO_CTRL5, uint8(FIFO_MODE_BYPASS)); err != nil {
+		return err
+	}
+	// The FIFO has no tags, samples are stored in a pattern of gyroscope
+	// followed by accelerometer data.
+	d.fifoSets = 0
+	var decimation uint8
+	if config.Gyro {
+		d.fifoTags[d.fifoSets] = FIFO_TAG_GYRO
+		d.fifoSets++
+		decimation |= 0x01 << 3
+	}
+	if config.Accel {
+		d.fifoTags[d.fifoSets] = FIFO_TAG_ACCEL
+		d.fifoSets++
+		decimation |= 0x01
+	}
+	words := int(config.Threshold) * 3
+	if words > FIFOSize-1 {
+		words = FIFOSize - 1
+	}
+	if err := d.writeRegister(FIFO_CTRL1, uint8(words)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(FIFO_CTRL2, uint8(words>>8)&0x07); err != nil {
+		return err
+	}
+	if err := d.writeRegister(FIFO_CTRL3, decimation); err != nil {
+		return err
+	}
+	// The FIFO data rate uses the same encoding as the output data rates.
+	return d.writeRegister(FIFO_CTRL5, uint8(config.Rate)>>1&0x78|uint8(config.Mode))
+}
+
+// FIFOCount returns the number of unread samples in the FIFO.
+func (d *Device) FIFOCount() (int, error) {
+	data := d.buf[:2]
+	if err := d.readRegister(FIFO_STATUS1, data); err != nil {
+		return 0, err
+	}
+	return (int(data[1]&0x07)<<8 | int(data[0])) / 3, nil
+}
+
+// ReadFIFO reads up to len(samples) samples from the FIFO and returns the
+// number of samples read.
+func (d *Device) ReadFIFO(samples []FIFOSample) (n int, err error) {
+	if d.fifoSets == 0 {
+		return 0, nil
+	}
+	data := d.buf[:6]
+	if err := d.readRegister(FIFO_STATUS1, data[:4]); err != nil {
+		return 0, err
+	}
+	words := int(data[1]&0x07)<<8 | int(data[0])
+	pattern := int(data[3]&0x03)<<8 | int(data[2])
+
+	// Skip the rest of a partially read sample.
+	if skip := (3 - pattern%3) % 3; skip > 0 {
+		if skip > words {
+			return 0, nil
+		}
+		if err := d.readRegister(FIFO_DATA_OUT_L, data[:skip*2]); err != nil {
+			return 0, err
+		}
+		words -= skip
+		pattern += skip
+	}
+
+	count := words / 3
+	if count > len(samples) {
+		count = len(samples)
+	}
+	for n = 0; n < count; n++ {
+		// The address rolls back to FIFO_DATA_OUT_L after FIFO_DATA_OUT_H,
+		// so a burst reads consecutive words.
+		if err := d.readRegister(FIFO_DATA_OUT_L, data); err != nil {
+			return n, err
+		}
+		s := &samples[n]
+		s.Tag = d.fifoTags[pattern/3%d.fifoSets]
+		k := d.gyroFactor()
+		if s.Tag == FIFO_TAG_ACCEL {
+			k = d.accelFactor()
+		}
+		s.X = lsm6ds.Scale(data[0], data[1], k)
+		s.Y = lsm6ds.Scale(data[2], data[3], k)
+		s.Z = lsm6ds.Scale(data[4], data[5], k)
+		pattern += 3
+	}
+	return n, nil
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/lsm6ds"
 )
 
 type AccelRange uint8
@@ -25,7 +26,16 @@ type Device struct {
 	accelSampleRate AccelSampleRate
 	gyroRange       GyroRange
 	gyroSampleRate  GyroSampleRate
-	buf             [6]uint8
+	buf             [14]uint8
+
+	// sensors of the samples in the FIFO pattern
+	fifoTags [2]FIFOTag
+	fifoSets int
+
+	// last values read by Update
+	accel       [3]int32
+	gyro        [3]int32
+	temperature int32
 }
 
 // Configuration for LSM6DS3TR device.
@@ -111,6 +121,19 @@ func (d *Device) doConfigure(cfg Configuration) (err error) {
 		return
 	}
 
+	if cfg.IsPedometer {
+		err = d.EnablePedometer()
+		if err != nil {
+			return
+		}
+	}
+	if cfg.ResetStepCounter {
+		err = d.ResetSteps()
+		if err != nil {
+			return
+		}
+	}
+
 	return nil
 }
 
@@ -132,18 +155,10 @@ func (d *Device) ReadAcceleration() (x, y, z int32, err error) {
 	if err != nil {
 		return
 	}
-	// k comes from "Table 3. Mechanical characteristics" 3 of the datasheet * 1000
-	k := int32(61) // 2G
-	if d.accelRange == ACCEL_4G {
-		k = 122
-	} else if d.accelRange == ACCEL_8G {
-		k = 244
-	} else if d.accelRange == ACCEL_16G {
-		k = 488
-	}
-	x = int32(int16((uint16(data[1])<<8)|uint16(data[0]))) * k
-	y = int32(int16((uint16(data[3])<<8)|uint16(data[2]))) * k
-	z = int32(int16((uint16(data[5])<<8)|uint16(data[4]))) * k
+	k := d.accelFactor()
+	x = lsm6ds.Scale(data[0], data[1], k)
+	y = lsm6ds.Scale(data[2], data[3], k)
+	z = lsm6ds.Scale(data[4], data[5], k)
 	return
 }
 
@@ -157,6 +172,79 @@ func (d *Device) ReadRotation() (x, y, z int32, err error) {
 	if err != nil {
 		return
 	}
+	k := d.gyroFactor()
+	x = lsm6ds.Scale(data[0], data[1], k)
+	y = lsm6ds.Scale(data[2], data[3], k)
+	z = lsm6ds.Scale(data[4], data[5], k)
+	return
+}
+
+// ReadTemperature returns the temperature in celsius milli degrees (°C/1000)
+func (d *Device) ReadTemperature() (t int32, err error) {
+	data := d.buf[:2]
+	err = d.bus.ReadRegister(uint8(d.Address), OUT_TEMP_L, data)
+	if err != nil {
+		return
+	}
+	t = convertTemperature(data[0], data[1])
+	return
+}
+
+// Update reads the selected measurements in a single burst read of the
+// temperature, gyroscope and accelerometer output registers. Use
+// Acceleration, AngularVelocity and Temperature to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Acceleration|drivers.AngularVelocity|drivers.Temperature) == 0 {
+		return nil
+	}
+	data := d.buf[:14]
+	err := d.bus.ReadRegister(uint8(d.Address), OUT_TEMP_L, data)
+	if err != nil {
+		return err
+	}
+	d.temperature = convertTemperature(data[0], data[1])
+	gk, ak := d.gyroFactor(), d.accelFactor()
+	for i := 0; i < 3; i++ {
+		d.gyro[i] = lsm6ds.Scale(data[2+i*2], data[3+i*2], gk)
+		d.accel[i] = lsm6ds.Scale(data[8+i*2], data[9+i*2], ak)
+	}
+	return nil
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *Device) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
+
+// AngularVelocity returns the rotation in µ°/s (micro-degrees/sec) read by
+// the last call to Update.
+func (d *Device) AngularVelocity() (x, y, z int32) {
+	return d.gyro[0], d.gyro[1], d.gyro[2]
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// accelFactor returns the accelerometer sensitivity in µg/LSB.
+func (d *Device) accelFactor() int32 {
+	// k comes from "Table 3. Mechanical characteristics" 3 of the datasheet * 1000
+	k := int32(61) // 2G
+	if d.accelRange == ACCEL_4G {
+		k = 122
+	} else if d.accelRange == ACCEL_8G {
+		k = 244
+	} else if d.accelRange == ACCEL_16G {
+		k = 488
+	}
+	return k
+}
+
+// gyroFactor returns the gyroscope sensitivity in µ°/s/LSB.
+func (d *Device) gyroFactor() int32 {
 	// k comes from "Table 3. Mechanical characteristics" 3 of the datasheet * 1000
 	k := int32(4375) // 125DPS
 	if d.gyroRange == GYRO_245DPS {
@@ -168,21 +256,29 @@ func (d *Device) ReadRotation() (x, y, z int32, err error) {
 	} else if d.gyroRange == GYRO_2000DPS {
 		k = 70000
 	}
-	x = int32(int16((uint16(data[1])<<8)|uint16(data[0]))) * k
-	y = int32(int16((uint16(data[3])<<8)|uint16(data[2]))) * k
-	z = int32(int16((uint16(data[5])<<8)|uint16(data[4]))) * k
-	return
+	return k
 }
 
-// ReadTemperature returns the temperature in celsius milli degrees (°C/1000)
-func (d *Device) ReadTemperature() (t int32, err error) {
-	data := d.buf[:2]
-	err = d.bus.ReadRegister(uint8(d.Address), OUT_TEMP_L, data)
-	if err != nil {
-		return
-	}
+// convertTemperature converts a raw temperature to celsius milli degrees.
+func convertTemperature(lsb, msb byte) int32 {
 	// From "Table 5. Temperature sensor characteristics"
 	// temp = value/256 + 25
-	t = 25000 + (int32(int16((int16(data[1])<<8)|int16(data[0])))*125)/32
-	return
+	return 25000 + (int32(int16((int16(msb)<<8)|int16(lsb)))*125)/32
+}
+
+func (d *Device) readRegister(reg uint8, data []byte) error {
+	return drivers.NotResponding(d.bus.ReadRegister(uint8(d.Address), reg, data))
+}
+
+func (d *Device) writeRegister(reg, value uint8) error {
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, []byte{value}))
+}
+
+// updateRegister replaces the bits of mask in a register with value.
+func (d *Device) updateRegister(reg, mask, value uint8) error {
+	data := d.buf[:1]
+	if err := d.readRegister(reg, data); err != nil {
+		return err
+	}
+	return d.writeRegister(reg, data[0]&^mask|value&mask)
 }
@@ -0,0 +1,64 @@
+package lsm6ds3tr
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ interface {
+	drivers.Accelerometer
+	drivers.Gyroscope
+	drivers.Thermometer
+} = (*Device)(nil)
+
+func TestPedometer(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	fake.Registers[WHO_AM_I] = 0x6A
+	fake.Registers[STEP_COUNTER_L] = 0x0c
+	fake.Registers[STEP_COUNTER_H] = 0x01
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{AccelSampleRate: ACCEL_SR_26, IsPedometer: true}), qt.IsNil)
+	c.Assert(fake.Registers[CTRL10_C], qt.Equals, uint8(FUNC_EN|PEDO_EN))
+	steps, err := dev.ReadSteps()
+	c.Assert(err, qt.IsNil)
+	c.Assert(steps, qt.Equals, int32(268))
+
+	c.Assert(dev.RouteInterrupts(INT1, EVENT_STEP), qt.IsNil)
+	c.Assert(fake.Registers[INT1_CTRL], qt.Equals, uint8(INT1_STEP_DETECTOR))
+	c.Assert(fake.Registers[TAP_CFG], qt.Equals, uint8(0))
+
+	fake.Registers[FUNC_SRC1] = STEP_DETECTED
+	fake.Registers[FIFO_STATUS2] = FIFO_OVER_RUN
+	events, err := dev.ReadEvents()
+	c.Assert(err, qt.IsNil)
+	c.Assert(events, qt.Equals, EVENT_STEP|EVENT_FIFO_OVERRUN)
+
+	c.Assert(dev.DisablePedometer(), qt.IsNil)
+	c.Assert(fake.Registers[CTRL10_C], qt.Equals, uint8(FUNC_EN))
+}
+
+func TestInterruptsEnable(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	fake.Registers[WHO_AM_I] = 0x6A
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{}), qt.IsNil)
+	c.Assert(dev.ConfigureTap(TapConfig{Axes: AXIS_X | AXIS_Y | AXIS_Z, Threshold: 500000}), qt.IsNil)
+	c.Assert(dev.LatchInterrupts(true), qt.IsNil)
+	c.Assert(fake.Registers[TAP_CFG], qt.Equals, uint8(0x0F))
+
+	// Tap, free-fall, wake-up and 6D interrupts need INTERRUPTS_ENABLE.
+	c.Assert(dev.RouteInterrupts(INT2, EVENT_SINGLE_TAP), qt.IsNil)
+	c.Assert(fake.Registers[MD2_CFG], qt.Equals, uint8(0x40))
+	c.Assert(fake.Registers[TAP_CFG], qt.Equals, uint8(INTERRUPTS_ENABLE|0x0F))
+}
@@ -1,11 +1,36 @@
 package lsm6ds3tr
 
+import "tinygo.org/x/drivers/internal/lsm6ds"
+
 // Constants/addresses used for I2C.
 
 // The I2C address which this device listens to.
 const Address = 0x6A
 
 const (
+	FIFO_CTRL1           = 0x06
+	FIFO_CTRL2           = 0x07
+	FIFO_CTRL3           = 0x08
+	FIFO_CTRL4           = 0x09
+	FIFO_CTRL5           = 0x0A
+	INT2_CTRL            = 0x0E
+	WAKE_UP_SRC          = 0x1B
+	TAP_SRC              = 0x1C
+	D6D_SRC              = 0x1D
+	FIFO_STATUS1         = 0x3A
+	FIFO_STATUS2         = 0x3B
+	FIFO_STATUS3         = 0x3C
+	FIFO_STATUS4         = 0x3D
+	FIFO_DATA_OUT_L      = 0x3E
+	FIFO_DATA_OUT_H      = 0x3F
+	FUNC_SRC1            = 0x53
+	TAP_THS_6D           = 0x59
+	INT_DUR2             = 0x5A
+	WAKE_UP_THS          = 0x5B
+	WAKE_UP_DUR          = 0x5C
+	FREE_FALL            = 0x5D
+	MD1_CFG              = 0x5E
+	MD2_CFG              = 0x5F
 	WHO_AM_I             = 0x0F
 	STATUS               = 0x1E
 	CTRL1_XL             = 0x10
@@ -77,3 +102,70 @@ const (
 	GYRO_SR_3332 GyroSampleRate = 0x90
 	GYRO_SR_6664 GyroSampleRate = 0xA0
 )
+
+// Register bits.
+const (
+	PEDO_EN       = 0x10 // CTRL10_C
+	FUNC_EN       = 0x04 // CTRL10_C
+	PEDO_RST_STEP = 0x02 // CTRL10_C
+
+	INTERRUPTS_ENABLE = 0x80 // TAP_CFG
+	LIR               = 0x01 // TAP_CFG
+
+	SINGLE_DOUBLE_TAP  = 0x80 // WAKE_UP_THS
+	INT1_STEP_DETECTOR = 0x80 // INT1_CTRL
+	STEP_DETECTED      = 0x10 // FUNC_SRC1
+
+	FIFO_WATERM     = 0x80 // FIFO_STATUS2
+	FIFO_OVER_RUN   = 0x40 // FIFO_STATUS2
+	FIFO_FULL_SMART = 0x20 // FIFO_STATUS2
+)
+
+const (
+	FIFO_MODE_BYPASS               FIFOMode = 0x00
+	FIFO_MODE_FIFO                 FIFOMode = 0x01 // stop collecting when full
+	FIFO_MODE_CONTINUOUS_TO_FIFO   FIFOMode = 0x03
+	FIFO_MODE_BYPASS_TO_CONTINUOUS FIFOMode = 0x04
+	FIFO_MODE_CONTINUOUS           FIFOMode = 0x06 // overwrite the oldest samples when full
+)
+
+// Sensors of FIFO samples. The values match the FIFO tags of newer devices.
+const (
+	FIFO_TAG_GYRO  FIFOTag = 0x01
+	FIFO_TAG_ACCEL FIFOTag = 0x02
+)
+
+const (
+	AXIS_X Axis = 0x04
+	AXIS_Y Axis = 0x02
+	AXIS_Z Axis = 0x01
+)
+
+const (
+	INT1 InterruptPin = iota
+	INT2
+)
+
+const (
+	EVENT_ACCEL_READY    = Event(lsm6ds.EventAccelReady)
+	EVENT_GYRO_READY     = Event(lsm6ds.EventGyroReady)
+	EVENT_FIFO_THRESHOLD = Event(lsm6ds.EventFIFOThreshold)
+	EVENT_FIFO_OVERRUN   = Event(lsm6ds.EventFIFOOverrun)
+	EVENT_FIFO_FULL      = Event(lsm6ds.EventFIFOFull)
+	EVENT_STEP           = Event(lsm6ds.EventStep)
+	EVENT_SINGLE_TAP     = Event(lsm6ds.EventSingleTap)
+	EVENT_DOUBLE_TAP     = Event(lsm6ds.EventDoubleTap)
+	EVENT_FREE_FALL      = Event(lsm6ds.EventFreeFall)
+	EVENT_WAKE_UP        = Event(lsm6ds.EventWakeUp)
+	EVENT_ORIENTATION    = Event(lsm6ds.EventOrientation) // 6D orientation change
+)
+
+// Orientations reported by 6D detection, as the axes pointing up or down.
+const (
+	ORIENTATION_X_DOWN Orientation = 1 << iota
+	ORIENTATION_X_UP
+	ORIENTATION_Y_DOWN
+	ORIENTATION_Y_UP
+	ORIENTATION_Z_DOWN
+	ORIENTATION_Z_UP
+)
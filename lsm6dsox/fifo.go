@@ -0,0 +1,96 @@
+package lsm6dsox
+
+import "tinygo.org/x/drivers/internal/lsm6ds"
+
+// FIFOMode selects how the FIFO collects samples.
+type FIFOMode uint8
+
+// FIFOTag identifies the sensor of a FIFO word.
+type FIFOTag uint8
+
+// FIFOConfig configures batching of samples in the FIFO.
+type FIFOConfig struct {
+	Mode FIFOMode
+
+	// AccelRate and GyroRate are the rates at which samples are written to
+	// the FIFO. They should not exceed the sample rates of the sensors. Use
+	// ACCEL_SR_OFF and GYRO_SR_OFF to not batch a sensor.
+	AccelRate AccelSampleRate
+	GyroRate  GyroSampleRate
+
+	// Threshold is the number of words in the FIFO that sets
+	// EVENT_FIFO_THRESHOLD, up to 511.
+	Threshold uint16
+}
+
+// FIFOSample is a word read from the FIFO. Accelerometer samples are in µg
+// and gyroscope samples in µ°/s, words of other sensors hold the raw values
+// of their axes.
+type FIFOSample struct {
+	Tag     FIFOTag
+	X, Y, Z int32
+}
+
+// FIFOSize is the size of the FIFO in words.
+const FIFOSize = 512
+
+// ConfigureFIFO empties the FIFO and starts batching samples.
+func (d *Device) ConfigureFIFO(config FIFOConfig) error {
+	// Switching to bypass mode empties the FIFO.
+	if err := d.writeRegister(FIFO_CTRL4, uint8(FIFO_MODE_BYPASS)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(FIFO_CTRL1, uint8(config.Threshold)); err != nil {
+		return err
+	}
+	if err := d.writeRegister(FIFO_CTRL2, uint8(config.Threshold>>8)&0x01); err != nil {
+		return err
+	}
+	// The batch data rates use the same encoding as the output data rates.
+	if err := d.writeRegister(FIFO_CTRL3, uint8(config.GyroRate)&0xF0|uint8(config.AccelRate)>>4); err != nil {
+		return err
+	}
+	return d.writeRegister(FIFO_CTRL4, uint8(config.Mode))
+}
+
+// FIFOCount returns the number of unread words in the FIFO.
+func (d *Device) FIFOCount() (int, error) {
+	data := d.buf[:2]
+	if err := d.readRegister(FIFO_STATUS1, data); err != nil {
+		return 0, err
+	}
+	return int(data[1]&0x03)<<8 | int(data[0]), nil
+}
+
+// ReadFIFO reads up to len(samples) words from the FIFO and returns the
+// number of words read. Each word is tagged with its sensor, so samples of
+// sensors batched at different rates can be told apart.
+func (d *Device) ReadFIFO(samples []FIFOSample) (n int, err error) {
+	count, err := d.FIFOCount()
+	if err != nil {
+		return 0, err
+	}
+	if count > len(samples) {
+		count = len(samples)
+	}
+	data := d.buf[:7]
+	for n = 0; n < count; n++ {
+		// Read the tag and the data of a word in one burst.
+		if err := d.readRegister(FIFO_DATA_OUT_TAG, data); err != nil {
+			return n, err
+		}
+		s := &samples[n]
+		s.Tag = FIFOTag(data[0] >> 3)
+		var k int32 = 1
+		switch s.Tag {
+		case FIFO_TAG_ACCEL:
+			k = d.accelMultiplier
+		case FIFO_TAG_GYRO:
+			k = d.gyroMultiplier
+		}
+		s.X = lsm6ds.Scale(data[1], data[2], k)
+		s.Y = lsm6ds.Scale(data[3], data[4], k)
+		s.Z = lsm6ds.Scale(data[5], data[6], k)
+	}
+	return n, nil
+}
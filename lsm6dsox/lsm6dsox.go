@@ -8,6 +8,7 @@ import (
 	"errors"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/lsm6ds"
 )
 
 type AccelRange uint8
@@ -22,7 +23,13 @@ type Device struct {
 	Address         uint16
 	accelMultiplier int32
 	gyroMultiplier  int32
-	buf             [6]uint8
+	accelSampleRate AccelSampleRate
+	buf             [14]uint8
+
+	// last values read by Update
+	accel       [3]int32
+	gyro        [3]int32
+	temperature int32
 }
 
 // Configuration for LSM6DSOX device.
@@ -75,6 +82,8 @@ func (d *Device) Configure(cfg Configuration) (err error) {
 		d.gyroMultiplier = 70000
 	}
 
+	d.accelSampleRate = cfg.AccelSampleRate
+
 	data := d.buf[:1]
 	// Configure accelerometer
 	data[0] = uint8(cfg.AccelRange) | uint8(cfg.AccelSampleRate)
@@ -110,9 +119,9 @@ func (d *Device) ReadAcceleration() (x, y, z int32, err error) {
 	if err != nil {
 		return
 	}
-	x = int32(int16((uint16(data[1])<<8)|uint16(data[0]))) * d.accelMultiplier
-	y = int32(int16((uint16(data[3])<<8)|uint16(data[2]))) * d.accelMultiplier
-	z = int32(int16((uint16(data[5])<<8)|uint16(data[4]))) * d.accelMultiplier
+	x = lsm6ds.Scale(data[0], data[1], d.accelMultiplier)
+	y = lsm6ds.Scale(data[2], data[3], d.accelMultiplier)
+	z = lsm6ds.Scale(data[4], data[5], d.accelMultiplier)
 	return
 }
 
@@ -126,9 +135,9 @@ func (d *Device) ReadRotation() (x, y, z int32, err error) {
 	if err != nil {
 		return
 	}
-	x = int32(int16((uint16(data[1])<<8)|uint16(data[0]))) * d.gyroMultiplier
-	y = int32(int16((uint16(data[3])<<8)|uint16(data[2]))) * d.gyroMultiplier
-	z = int32(int16((uint16(data[5])<<8)|uint16(data[4]))) * d.gyroMultiplier
+	x = lsm6ds.Scale(data[0], data[1], d.gyroMultiplier)
+	y = lsm6ds.Scale(data[2], data[3], d.gyroMultiplier)
+	z = lsm6ds.Scale(data[4], data[5], d.gyroMultiplier)
 	return
 }
 
@@ -139,8 +148,94 @@ func (d *Device) ReadTemperature() (t int32, err error) {
 	if err != nil {
 		return
 	}
+	t = convertTemperature(data[0], data[1])
+	return
+}
+
+// Update reads the selected measurements in a single burst read of the
+// temperature, gyroscope and accelerometer output registers. Use
+// Acceleration, AngularVelocity and Temperature to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Acceleration|drivers.AngularVelocity|drivers.Temperature) == 0 {
+		return nil
+	}
+	data := d.buf[:14]
+	err := d.bus.ReadRegister(uint8(d.Address), OUT_TEMP_L, data)
+	if err != nil {
+		return err
+	}
+	d.temperature = convertTemperature(data[0], data[1])
+	gk, ak := d.gyroMultiplier, d.accelMultiplier
+	for i := 0; i < 3; i++ {
+		d.gyro[i] = lsm6ds.Scale(data[2+i*2], data[3+i*2], gk)
+		d.accel[i] = lsm6ds.Scale(data[8+i*2], data[9+i*2], ak)
+	}
+	return nil
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *Device) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
+
+// AngularVelocity returns the rotation in µ°/s (micro-degrees/sec) read by
+// the last call to Update.
+func (d *Device) AngularVelocity() (x, y, z int32) {
+	return d.gyro[0], d.gyro[1], d.gyro[2]
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// convertTemperature converts a raw temperature to celsius milli degrees.
+func convertTemperature(lsb, msb byte) int32 {
 	// From "Table 4. Temperature sensor characteristics"
 	// temp = value/256 + 25
-	t = 25000 + (int32(int16((int16(data[1])<<8)|int16(data[0])))*125)/32
-	return
+	return 25000 + (int32(int16((int16(msb)<<8)|int16(lsb)))*125)/32
+}
+
+func (d *Device) readRegister(reg uint8, data []byte) error {
+	return drivers.NotResponding(d.bus.ReadRegister(uint8(d.Address), reg, data))
+}
+
+func (d *Device) writeRegister(reg, value uint8) error {
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, []byte{value}))
+}
+
+// updateRegister replaces the bits of mask in a register with value.
+func (d *Device) updateRegister(reg, mask, value uint8) error {
+	data := d.buf[:1]
+	if err := d.readRegister(reg, data); err != nil {
+		return err
+	}
+	return d.writeRegister(reg, data[0]&^mask|value&mask)
+}
+
+// readEmbedded reads registers of the embedded function bank.
+func (d *Device) readEmbedded(reg uint8, data []byte) error {
+	if err := d.writeRegister(FUNC_CFG_ACCESS, EMB_FUNC_REG_ACCESS); err != nil {
+		return err
+	}
+	err := d.readRegister(reg, data)
+	if err2 := d.writeRegister(FUNC_CFG_ACCESS, 0); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// updateEmbedded replaces the bits of mask in a register of the embedded
+// function bank with value.
+func (d *Device) updateEmbedded(reg, mask, value uint8) error {
+	if err := d.writeRegister(FUNC_CFG_ACCESS, EMB_FUNC_REG_ACCESS); err != nil {
+		return err
+	}
+	err := d.updateRegister(reg, mask, value)
+	if err2 := d.writeRegister(FUNC_CFG_ACCESS, 0); err == nil {
+		err = err2
+	}
+	return err
 }
@@ -0,0 +1,228 @@
+package lsm6dsox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ interface {
+	drivers.Accelerometer
+	drivers.Gyroscope
+	drivers.Thermometer
+} = (*Device)(nil)
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	fake.Registers[WHO_AM_I] = 0x6C
+	copy(fake.Registers[OUT_TEMP_L:], []byte{
+		0x00, 0x02, // 2 °C above 25 °C
+		0x00, 0x00, 0x10, 0x00, 0xf0, 0xff, // gyro 0, 16, -16
+		0x00, 0x40, 0x00, 0x00, 0x00, 0xc0, // accel 16384, 0, -16384
+	})
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{AccelRange: ACCEL_2G, GyroRange: GYRO_250DPS}), qt.IsNil)
+
+	trace := bus.Record()
+	c.Assert(dev.Update(drivers.AllMeasurements), qt.IsNil)
+	c.Assert(trace.Transactions, qt.HasLen, 1)
+
+	x, y, z := dev.Acceleration()
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{999424, 0, -999424})
+	x, y, z = dev.AngularVelocity()
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{0, 140000, -140000})
+	c.Assert(dev.Temperature(), qt.Equals, int32(27000))
+
+	// The accessors match the individual reads.
+	ax, ay, az, err := dev.ReadAcceleration()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int32{ax, ay, az}, qt.DeepEquals, []int32{999424, 0, -999424})
+	temp, err := dev.ReadTemperature()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temp, qt.Equals, dev.Temperature())
+}
+
+// bankedDevice is a fake LSM6DSOX that switches to the embedded function
+// registers while FUNC_CFG_ACCESS is set.
+type bankedDevice struct {
+	*tester.I2CDevice8
+	embedded *tester.I2CDevice8
+}
+
+func newBankedDevice(c *qt.C) *bankedDevice {
+	fake := tester.NewI2CDevice8(c, Address)
+	fake.Registers[WHO_AM_I] = 0x6C
+	return &bankedDevice{fake, tester.NewI2CDevice8(c, Address)}
+}
+
+func (d *bankedDevice) bank(reg uint8) tester.I2CDevice {
+	if reg != FUNC_CFG_ACCESS && d.Registers[FUNC_CFG_ACCESS]&EMB_FUNC_REG_ACCESS != 0 {
+		return d.embedded
+	}
+	return d.I2CDevice8
+}
+
+func (d *bankedDevice) ReadRegister(r uint8, buf []byte) error {
+	return d.bank(r).ReadRegister(r, buf)
+}
+
+func (d *bankedDevice) WriteRegister(r uint8, buf []byte) error {
+	return d.bank(r).WriteRegister(r, buf)
+}
+
+func TestPedometer(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newBankedDevice(c)
+	fake.embedded.Registers[STEP_COUNTER_L] = 0x39
+	fake.embedded.Registers[STEP_COUNTER_H] = 0x85
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{AccelRange: ACCEL_2G, AccelSampleRate: ACCEL_SR_26}), qt.IsNil)
+	c.Assert(dev.EnablePedometer(), qt.IsNil)
+	c.Assert(fake.embedded.Registers[EMB_FUNC_EN_A], qt.Equals, uint8(PEDO_EN))
+	c.Assert(fake.Registers[FUNC_CFG_ACCESS], qt.Equals, uint8(0))
+
+	steps, err := dev.ReadSteps()
+	c.Assert(err, qt.IsNil)
+	c.Assert(steps, qt.Equals, int32(34105))
+
+	c.Assert(dev.ResetSteps(), qt.IsNil)
+	c.Assert(fake.embedded.Registers[EMB_FUNC_SRC], qt.Equals, uint8(PEDO_RST_STEP))
+
+	c.Assert(dev.RouteInterrupts(INT2, EVENT_STEP), qt.IsNil)
+	c.Assert(fake.embedded.Registers[EMB_FUNC_INT2], qt.Equals, uint8(INT_STEP_DETECTOR))
+	c.Assert(fake.Registers[MD2_CFG], qt.Equals, uint8(0x02))
+	c.Assert(fake.Registers[TAP_CFG2], qt.Equals, uint8(0))
+
+	fake.Registers[EMB_FUNC_STATUS_MAINPAGE] = IS_STEP_DET
+	events, err := dev.ReadEvents()
+	c.Assert(err, qt.IsNil)
+	c.Assert(events, qt.Equals, EVENT_STEP)
+
+	c.Assert(dev.DisablePedometer(), qt.IsNil)
+	c.Assert(fake.embedded.Registers[EMB_FUNC_EN_A], qt.Equals, uint8(0))
+}
+
+func TestMotionFunctions(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newBankedDevice(c)
+	fake.Registers[TAP_CFG1] = 0x60 // TAP_PRIORITY
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{AccelRange: ACCEL_4G, AccelSampleRate: ACCEL_SR_416}), qt.IsNil)
+
+	c.Assert(dev.ConfigureTap(TapConfig{
+		Axes:      AXIS_X | AXIS_Z,
+		Threshold: 1000000, // 8/32 of 4g
+		Shock:     40 * time.Millisecond,
+		Quiet:     20 * time.Millisecond,
+		Window:    500 * time.Millisecond,
+		DoubleTap: true,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[TAP_CFG1], qt.Equals, uint8(0x68))
+	c.Assert(fake.Registers[TAP_CFG2], qt.Equals, uint8(0x08))
+	c.Assert(fake.Registers[TAP_THS_6D], qt.Equals, uint8(0x08))
+	c.Assert(fake.Registers[INT_DUR2], qt.Equals, uint8(6<<4|2<<2|2))
+	c.Assert(fake.Registers[WAKE_UP_THS], qt.Equals, uint8(SINGLE_DOUBLE_TAP))
+	c.Assert(fake.Registers[TAP_CFG0], qt.Equals, uint8(0x0A))
+
+	c.Assert(dev.ConfigureWakeUp(250000, 5*time.Millisecond), qt.IsNil)
+	c.Assert(fake.Registers[WAKE_UP_THS], qt.Equals, uint8(SINGLE_DOUBLE_TAP|4))
+	c.Assert(fake.Registers[WAKE_UP_DUR], qt.Equals, uint8(2<<5))
+
+	c.Assert(dev.ConfigureFreeFall(320000, 100*time.Millisecond), qt.IsNil)
+	c.Assert(fake.Registers[FREE_FALL], qt.Equals, uint8(9<<3|3))
+	c.Assert(fake.Registers[WAKE_UP_DUR], qt.Equals, uint8(0x80|2<<5))
+
+	c.Assert(dev.Configure6D(60), qt.IsNil)
+	c.Assert(fake.Registers[TAP_THS_6D], qt.Equals, uint8(2<<5|0x08))
+	c.Assert(dev.LatchInterrupts(true), qt.IsNil)
+	c.Assert(fake.Registers[TAP_CFG0], qt.Equals, uint8(0x0B))
+
+	c.Assert(dev.RouteInterrupts(INT1, EVENT_SINGLE_TAP|EVENT_DOUBLE_TAP|EVENT_FREE_FALL|EVENT_ACCEL_READY), qt.IsNil)
+	c.Assert(fake.Registers[INT1_CTRL], qt.Equals, uint8(0x01))
+	c.Assert(fake.Registers[MD1_CFG], qt.Equals, uint8(0x58))
+	c.Assert(fake.Registers[TAP_CFG2]&INTERRUPTS_ENABLE, qt.Not(qt.Equals), uint8(0))
+
+	fake.Registers[WAKE_UP_SRC] = 0x20
+	fake.Registers[TAP_SRC] = 0x10
+	fake.Registers[D6D_SRC] = 0x40 | 0x20 // Z up
+	fake.Registers[STATUS_REG] = 0x03
+	events, err := dev.ReadEvents()
+	c.Assert(err, qt.IsNil)
+	c.Assert(events, qt.Equals, EVENT_FREE_FALL|EVENT_DOUBLE_TAP|EVENT_ORIENTATION|EVENT_ACCEL_READY|EVENT_GYRO_READY)
+	orientation, err := dev.ReadOrientation()
+	c.Assert(err, qt.IsNil)
+	c.Assert(orientation, qt.Equals, ORIENTATION_Z_UP)
+}
+
+func TestFIFO(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newBankedDevice(c)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(Configuration{AccelRange: ACCEL_2G, GyroRange: GYRO_250DPS}), qt.IsNil)
+	c.Assert(dev.ConfigureFIFO(FIFOConfig{
+		Mode:      FIFO_MODE_CONTINUOUS,
+		AccelRate: ACCEL_SR_104,
+		GyroRate:  GYRO_SR_52,
+		Threshold: 300,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[FIFO_CTRL1], qt.Equals, uint8(44))
+	c.Assert(fake.Registers[FIFO_CTRL2], qt.Equals, uint8(1))
+	c.Assert(fake.Registers[FIFO_CTRL3], qt.Equals, uint8(0x34))
+	c.Assert(fake.Registers[FIFO_CTRL4], qt.Equals, uint8(FIFO_MODE_CONTINUOUS))
+
+	fake.Registers[FIFO_STATUS1] = 2
+	fake.Registers[FIFO_STATUS2] = FIFO_WTM_IA
+	copy(fake.Registers[FIFO_DATA_OUT_TAG:], []byte{
+		uint8(FIFO_TAG_ACCEL)<<3 | 0x02, 0x00, 0x40, 0x00, 0x00, 0x00, 0xc0,
+	})
+	samples := make([]FIFOSample, 4)
+	trace := bus.Record()
+	n, err := dev.ReadFIFO(samples)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 2)
+	c.Assert(trace.Transactions, qt.HasLen, 3)
+	c.Assert(samples[0], qt.DeepEquals, FIFOSample{FIFO_TAG_ACCEL, 999424, 0, -999424})
+
+	fake.Registers[FIFO_DATA_OUT_TAG] = uint8(FIFO_TAG_GYRO) << 3
+	fake.Registers[FIFO_STATUS1] = 1
+	n, err = dev.ReadFIFO(samples)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 1)
+	c.Assert(samples[0], qt.DeepEquals, FIFOSample{FIFO_TAG_GYRO, 16384 * 8750, 0, -16384 * 8750})
+
+	events, err := dev.ReadEvents()
+	c.Assert(err, qt.IsNil)
+	c.Assert(events, qt.Equals, EVENT_FIFO_THRESHOLD)
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	dev := New(bus)
+
+	_, err := dev.ReadSteps()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.ReadEvents()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.ReadFIFO(make([]FIFOSample, 1))
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(dev.RouteInterrupts(INT1, EVENT_WAKE_UP), drivers.ErrNotResponding), qt.IsTrue)
+}
@@ -1,39 +1,68 @@
 package lsm6dsox
 
+import "tinygo.org/x/drivers/internal/lsm6ds"
+
 // Constants/addresses used for I2C.
 
 // The I2C address which this device listens to.
 const Address = 0x6A
 
 const (
-	INT1_CTRL  = 0x0D
-	INT2_CTRL  = 0x0E
-	WHO_AM_I   = 0x0F
-	CTRL1_XL   = 0x10 // Accelerometer control register 1 (r/w)
-	CTRL2_G    = 0x11 // Gyroscope control register 2 (r/w)
-	CTRL3_C    = 0x12
-	CTRL4_C    = 0x13
-	CTRL5_C    = 0x14
-	CTRL6_C    = 0x15
-	CTRL7_G    = 0x16
-	CTRL8_XL   = 0x17
-	CTRL9_XL   = 0x18
-	CTRL10_C   = 0x19
-	STATUS_REG = 0x1E
-	OUT_TEMP_L = 0x20
-	OUT_TEMP_H = 0x21
-	OUTX_L_G   = 0x22
-	OUTX_H_G   = 0x23
-	OUTY_L_G   = 0x24
-	OUTY_H_G   = 0x25
-	OUTZ_L_G   = 0x26
-	OUTZ_H_G   = 0x27
-	OUTX_L_A   = 0x28
-	OUTX_H_A   = 0x29
-	OUTY_L_A   = 0x2A
-	OUTY_H_A   = 0x2B
-	OUTZ_L_A   = 0x2C
-	OUTZ_H_A   = 0x2D
+	FUNC_CFG_ACCESS = 0x01
+	FIFO_CTRL1      = 0x07
+	FIFO_CTRL2      = 0x08
+	FIFO_CTRL3      = 0x09
+	FIFO_CTRL4      = 0x0A
+
+	INT1_CTRL   = 0x0D
+	INT2_CTRL   = 0x0E
+	WHO_AM_I    = 0x0F
+	CTRL1_XL    = 0x10 // Accelerometer control register 1 (r/w)
+	CTRL2_G     = 0x11 // Gyroscope control register 2 (r/w)
+	CTRL3_C     = 0x12
+	CTRL4_C     = 0x13
+	CTRL5_C     = 0x14
+	CTRL6_C     = 0x15
+	CTRL7_G     = 0x16
+	CTRL8_XL    = 0x17
+	CTRL9_XL    = 0x18
+	CTRL10_C    = 0x19
+	WAKE_UP_SRC = 0x1B
+	TAP_SRC     = 0x1C
+	D6D_SRC     = 0x1D
+	STATUS_REG  = 0x1E
+	OUT_TEMP_L  = 0x20
+	OUT_TEMP_H  = 0x21
+	OUTX_L_G    = 0x22
+	OUTX_H_G    = 0x23
+	OUTY_L_G    = 0x24
+	OUTY_H_G    = 0x25
+	OUTZ_L_G    = 0x26
+	OUTZ_H_G    = 0x27
+	OUTX_L_A    = 0x28
+	OUTX_H_A    = 0x29
+	OUTY_L_A    = 0x2A
+	OUTY_H_A    = 0x2B
+	OUTZ_L_A    = 0x2C
+	OUTZ_H_A    = 0x2D
+
+	EMB_FUNC_STATUS_MAINPAGE = 0x35
+	FIFO_STATUS1             = 0x3A
+	FIFO_STATUS2             = 0x3B
+
+	TAP_CFG0    = 0x56
+	TAP_CFG1    = 0x57
+	TAP_CFG2    = 0x58
+	TAP_THS_6D  = 0x59
+	INT_DUR2    = 0x5A
+	WAKE_UP_THS = 0x5B
+	WAKE_UP_DUR = 0x5C
+	FREE_FALL   = 0x5D
+	MD1_CFG     = 0x5E
+	MD2_CFG     = 0x5F
+
+	FIFO_DATA_OUT_TAG = 0x78
+	FIFO_DATA_OUT_X_L = 0x79
 
 	ACCEL_2G  AccelRange = 0x00
 	ACCEL_4G  AccelRange = 0x08
@@ -69,3 +98,87 @@ const (
 	GYRO_SR_3332 GyroSampleRate = 0x90
 	GYRO_SR_6664 GyroSampleRate = 0xA0
 )
+
+// Embedded function registers, accessible while FUNC_CFG_ACCESS is set to
+// EMB_FUNC_REG_ACCESS.
+const (
+	EMB_FUNC_EN_A   = 0x04
+	EMB_FUNC_INT1   = 0x0A
+	EMB_FUNC_INT2   = 0x0E
+	EMB_FUNC_STATUS = 0x12
+	STEP_COUNTER_L  = 0x62
+	STEP_COUNTER_H  = 0x63
+	EMB_FUNC_SRC    = 0x64
+)
+
+// Register bits.
+const (
+	EMB_FUNC_REG_ACCESS = 0x80 // FUNC_CFG_ACCESS
+
+	PEDO_EN           = 0x08 // EMB_FUNC_EN_A
+	PEDO_RST_STEP     = 0x80 // EMB_FUNC_SRC
+	INT_STEP_DETECTOR = 0x08 // EMB_FUNC_INT1, EMB_FUNC_INT2
+	IS_STEP_DET       = 0x08 // EMB_FUNC_STATUS_MAINPAGE
+
+	INTERRUPTS_ENABLE = 0x80 // TAP_CFG2
+	LIR               = 0x01 // TAP_CFG0
+	SINGLE_DOUBLE_TAP = 0x80 // WAKE_UP_THS
+
+	FIFO_WTM_IA  = 0x80 // FIFO_STATUS2
+	FIFO_OVR_IA  = 0x40 // FIFO_STATUS2
+	FIFO_FULL_IA = 0x20 // FIFO_STATUS2
+)
+
+const (
+	FIFO_MODE_BYPASS               FIFOMode = 0x00
+	FIFO_MODE_FIFO                 FIFOMode = 0x01 // stop collecting when full
+	FIFO_MODE_CONTINUOUS_TO_FIFO   FIFOMode = 0x03
+	FIFO_MODE_BYPASS_TO_CONTINUOUS FIFOMode = 0x04
+	FIFO_MODE_CONTINUOUS           FIFOMode = 0x06 // overwrite the oldest samples when full
+	FIFO_MODE_BYPASS_TO_FIFO       FIFOMode = 0x07
+)
+
+// Sensors identified by the tag of a FIFO word.
+const (
+	FIFO_TAG_GYRO         FIFOTag = 0x01
+	FIFO_TAG_ACCEL        FIFOTag = 0x02
+	FIFO_TAG_TEMPERATURE  FIFOTag = 0x03
+	FIFO_TAG_TIMESTAMP    FIFOTag = 0x04
+	FIFO_TAG_CONFIG       FIFOTag = 0x05
+	FIFO_TAG_STEP_COUNTER FIFOTag = 0x12
+)
+
+const (
+	AXIS_X Axis = 0x04
+	AXIS_Y Axis = 0x02
+	AXIS_Z Axis = 0x01
+)
+
+const (
+	INT1 InterruptPin = iota
+	INT2
+)
+
+const (
+	EVENT_ACCEL_READY    = Event(lsm6ds.EventAccelReady)
+	EVENT_GYRO_READY     = Event(lsm6ds.EventGyroReady)
+	EVENT_FIFO_THRESHOLD = Event(lsm6ds.EventFIFOThreshold)
+	EVENT_FIFO_OVERRUN   = Event(lsm6ds.EventFIFOOverrun)
+	EVENT_FIFO_FULL      = Event(lsm6ds.EventFIFOFull)
+	EVENT_STEP           = Event(lsm6ds.EventStep)
+	EVENT_SINGLE_TAP     = Event(lsm6ds.EventSingleTap)
+	EVENT_DOUBLE_TAP     = Event(lsm6ds.EventDoubleTap)
+	EVENT_FREE_FALL      = Event(lsm6ds.EventFreeFall)
+	EVENT_WAKE_UP        = Event(lsm6ds.EventWakeUp)
+	EVENT_ORIENTATION    = Event(lsm6ds.EventOrientation) // 6D orientation change
+)
+
+// Orientations reported by 6D detection, as the axes pointing up or down.
+const (
+	ORIENTATION_X_DOWN Orientation = 1 << iota
+	ORIENTATION_X_UP
+	ORIENTATION_Y_DOWN
+	ORIENTATION_Y_UP
+	ORIENTATION_Z_DOWN
+	ORIENTATION_Z_UP
+)
@@ -28,6 +28,12 @@ type Device struct {
 	gyroMultiplier  int32
 	magMultiplier   int32
 	buf             [6]uint8
+
+	// last values read by Update
+	accel       [3]int32
+	gyro        [3]int32
+	mag         [3]int32
+	temperature int32
 }
 
 // Configuration for LSM9DS1 device.
@@ -125,6 +131,60 @@ func (d *Device) ReadTemperature() (t int32, err error) {
 	return
 }
 
+// Update reads the selected measurements. Use Acceleration,
+// AngularVelocity, MagneticField and Temperature to get the values.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.Acceleration != 0 {
+		d.accel[0], d.accel[1], d.accel[2], err = d.ReadAcceleration()
+		if err != nil {
+			return
+		}
+	}
+	if which&drivers.AngularVelocity != 0 {
+		d.gyro[0], d.gyro[1], d.gyro[2], err = d.ReadRotation()
+		if err != nil {
+			return
+		}
+	}
+	if which&drivers.MagneticField != 0 {
+		d.mag[0], d.mag[1], d.mag[2], err = d.ReadMagneticField()
+		if err != nil {
+			return
+		}
+	}
+	if which&drivers.Temperature != 0 {
+		d.temperature, err = d.ReadTemperature()
+		if err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *Device) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
+
+// AngularVelocity returns the rotation in µ°/s (micro-degrees/sec) read by
+// the last call to Update.
+func (d *Device) AngularVelocity() (x, y, z int32) {
+	return d.gyro[0], d.gyro[1], d.gyro[2]
+}
+
+// MagneticField returns the magnetic field in nT (nanotesla) read by the
+// last call to Update.
+func (d *Device) MagneticField() (x, y, z int32) {
+	return d.mag[0], d.mag[1], d.mag[2]
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
 // --- end of public methods --------------------------------------------------
 
 // doConfigure is called by public Configure methods after all
@@ -10,46 +10,124 @@ import "tinygo.org/x/drivers"
 type Device struct {
 	bus     drivers.I2C
 	Address uint16
+
+	// last values read by Update
+	mag         [3]int32
+	temperature int32
 }
 
 // New creates a new MAG3110 connection. The I2C bus must already be
 // configured.
 //
 // This function only creates the Device object, it does not touch the device.
-func New(bus drivers.I2C) Device {
-	return Device{bus, Address}
+func New(bus drivers.I2C) *Device {
+	return &Device{bus: bus, Address: Address}
 }
 
 // Connected returns whether a MAG3110 has been found.
 // It does a "who am I" request and checks the response.
-func (d Device) Connected() bool {
+func (d *Device) Connected() bool {
 	data := []byte{0}
 	d.bus.ReadRegister(uint8(d.Address), WHO_AM_I, data)
 	return data[0] == 0xC4
 }
 
 // Configure sets up the device for communication.
-func (d Device) Configure() {
+func (d *Device) Configure() {
 	d.bus.WriteRegister(uint8(d.Address), CTRL_REG2, []uint8{0x80}) // Power down when not used
 }
 
 // ReadMagnetic reads the vectors of the magnetic field of the device and
 // returns it.
-func (d Device) ReadMagnetic() (x int16, y int16, z int16) {
-	d.bus.WriteRegister(uint8(d.Address), CTRL_REG1, []uint8{0x1a}) // Request a measurement
+func (d *Device) ReadMagnetic() (x int16, y int16, z int16, err error) {
+	// Request a measurement
+	err = d.bus.WriteRegister(uint8(d.Address), CTRL_REG1, []uint8{0x1a})
+	if err != nil {
+		return 0, 0, 0, drivers.NotResponding(err)
+	}
 
 	data := make([]byte, 6)
-	d.bus.ReadRegister(uint8(d.Address), OUT_X_MSB, data)
+	err = d.bus.ReadRegister(uint8(d.Address), OUT_X_MSB, data)
+	if err != nil {
+		return 0, 0, 0, drivers.NotResponding(err)
+	}
 	x = int16((uint16(data[0]) << 8) | uint16(data[1]))
 	y = int16((uint16(data[2]) << 8) | uint16(data[3]))
 	z = int16((uint16(data[4]) << 8) | uint16(data[5]))
 	return
 }
 
+// SetOffset sets the hard-iron offset in nT (nanotesla), which the MAG3110
+// subtracts from every reading before ReadMagnetic and Update return it.
+// The offset is stored with a resolution of 0.1 µT, between -1638.4 µT and
+// 1638.3 µT; larger offsets are clamped.
+//
+// To calibrate, set a zero offset, add readings to an ahrs.MagCalibrator
+// while rotating the device and store the Offset of the fitted calibration
+// with SetOffset. The MAG3110 cannot correct soft-iron distortion: apply the
+// Matrix of the calibration to the readings, with its Offset set to zero as
+// the readings are already corrected.
+func (d *Device) SetOffset(x, y, z int32) error {
+	data := make([]byte, 6)
+	for i, v := range [3]int32{x, y, z} {
+		// The offset registers hold bits 14 to 0 of the offset in 0.1 µT,
+		// shifted left by one.
+		if v < 0 {
+			v = (v - 50) / 100
+		} else {
+			v = (v + 50) / 100
+		}
+		if v < -1<<14 {
+			v = -1 << 14
+		} else if v > 1<<14-1 {
+			v = 1<<14 - 1
+		}
+		data[2*i] = uint8(v >> 7)
+		data[2*i+1] = uint8(v << 1)
+	}
+	err := d.bus.WriteRegister(uint8(d.Address), OFF_X_MSB, data)
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
+	return nil
+}
+
 // ReadTemperature reads and returns the current die temperature in
 // celsius milli degrees (°C/1000).
-func (d Device) ReadTemperature() (int32, error) {
+func (d *Device) ReadTemperature() (int32, error) {
 	data := make([]byte, 1)
-	d.bus.ReadRegister(uint8(d.Address), DIE_TEMP, data)
+	err := d.bus.ReadRegister(uint8(d.Address), DIE_TEMP, data)
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
 	return int32(data[0]) * 1000, nil
 }
+
+// Update reads the selected measurements. Use MagneticField and Temperature
+// to get the values.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.MagneticField != 0 {
+		x, y, z, err := d.ReadMagnetic()
+		if err != nil {
+			return err
+		}
+		// 0.1 µT/LSB
+		d.mag = [3]int32{int32(x) * 100, int32(y) * 100, int32(z) * 100}
+	}
+	if which&drivers.Temperature != 0 {
+		d.temperature, err = d.ReadTemperature()
+	}
+	return
+}
+
+// MagneticField returns the magnetic field in nT (nanotesla) read by the
+// last call to Update.
+func (d *Device) MagneticField() (x, y, z int32) {
+	return d.mag[0], d.mag[1], d.mag[2]
+}
+
+// Temperature returns the die temperature in celsius milli degrees
+// (°C/1000) read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
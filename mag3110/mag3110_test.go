@@ -0,0 +1,73 @@
+package mag3110
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ interface {
+	drivers.Magnetometer
+	drivers.Thermometer
+} = (*Device)(nil)
+
+func TestReadMagnetic(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	copy(fake.Registers[OUT_X_MSB:], []uint8{0x00, 0x64, 0xFF, 0x9C, 0x01, 0xF4})
+	fake.Registers[DIE_TEMP] = 25
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	x, y, z, err := dev.ReadMagnetic()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int16{x, y, z}, qt.DeepEquals, []int16{100, -100, 500})
+
+	c.Assert(dev.Update(drivers.MagneticField|drivers.Temperature), qt.IsNil)
+	mx, my, mz := dev.MagneticField()
+	c.Assert([]int32{mx, my, mz}, qt.DeepEquals, []int32{10000, -10000, 50000})
+	c.Assert(dev.Temperature(), qt.Equals, int32(25000))
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	busErr := errors.New("bus error")
+	fake.Err = busErr
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	_, _, _, err := dev.ReadMagnetic()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	_, err = dev.ReadTemperature()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	err = dev.Update(drivers.MagneticField)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
+
+func TestSetOffset(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.SetOffset(12345, -2050, 0), qt.IsNil)
+	// 123 and -21 in 0.1 µT, shifted left by one.
+	c.Assert(fake.Registers[OFF_X_MSB:OFF_Z_LSB+1], qt.DeepEquals, []uint8{0x00, 0xF6, 0xFF, 0xD6, 0x00, 0x00})
+
+	c.Assert(dev.SetOffset(1638300, -1638400, 2000000), qt.IsNil)
+	c.Assert(fake.Registers[OFF_X_MSB:OFF_Z_LSB+1], qt.DeepEquals, []uint8{0x7F, 0xFE, 0x80, 0x00, 0x7F, 0xFE})
+
+	busErr := errors.New("bus error")
+	fake.Err = busErr
+	err := dev.SetOffset(0, 0, 0)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+}
@@ -3,18 +3,18 @@
 package max72xx
 
 import (
-	"machine"
+	"tinygo.org/x/drivers"
 )
 
 type Device struct {
-	bus machine.SPI
-	cs  machine.Pin
+	bus drivers.SPI
+	cs  drivers.Pin
 }
 
 // NewDriver creates a new max7219 connection. The SPI wire must already be configured
 // The SPI frequency must not be higher than 10MHz.
 // parameter cs: the datasheet also refers to this pin as "load" pin.
-func NewDevice(bus machine.SPI, cs machine.Pin) *Device {
+func NewDevice(bus drivers.SPI, cs drivers.Pin) *Device {
 	return &Device{
 		bus: bus,
 		cs:  cs,
@@ -23,9 +23,7 @@ func NewDevice(bus machine.SPI, cs machine.Pin) *Device {
 
 // Configure setups the pins.
 func (driver *Device) Configure() {
-	outPutConfig := machine.PinConfig{Mode: machine.PinOutput}
-
-	driver.cs.Configure(outPutConfig)
+	drivers.ConfigurePin(driver.cs, drivers.PinOutput)
 }
 
 // SetScanLimit sets the scan limit. Maximum is 8.
@@ -87,8 +85,8 @@ func (driver *Device) writeByte(data byte) {
 
 // WriteCommand write data to a given register.
 func (driver *Device) WriteCommand(register, data byte) {
-	driver.cs.Low()
+	driver.cs.Set(false)
 	driver.writeByte(register)
 	driver.writeByte(data)
-	driver.cs.High()
+	driver.cs.Set(true)
 }
@@ -8,7 +8,6 @@ package mcp2515 // import "tinygo.org/x/drivers/mcp2515"
 import (
 	"errors"
 	"fmt"
-	"machine"
 	"time"
 
 	"tinygo.org/x/drivers"
@@ -17,7 +16,7 @@ import (
 // Device wraps MCP2515 SPI CAN Module.
 type Device struct {
 	spi     SPI
-	cs      machine.Pin
+	cs      drivers.Pin
 	msg     *CANMsg
 	mcpMode byte
 }
@@ -36,7 +35,7 @@ const (
 )
 
 // New returns a new MCP2515 driver. Pass in a fully configured SPI bus.
-func New(b drivers.SPI, csPin machine.Pin) *Device {
+func New(b drivers.SPI, csPin drivers.Pin) *Device {
 	d := &Device{
 		spi: SPI{
 			bus: b,
@@ -52,7 +51,7 @@ func New(b drivers.SPI, csPin machine.Pin) *Device {
 
 // Configure sets up the device for communication.
 func (d *Device) Configure() {
-	d.cs.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	drivers.ConfigurePin(d.cs, drivers.PinOutput)
 }
 
 const beginTimeoutValue int = 10
@@ -159,9 +158,9 @@ func (d *Device) init(speed, clock byte) error {
 
 // Reset resets mcp2515.
 func (d *Device) Reset() error {
-	d.cs.Low()
+	d.cs.Set(false)
 	_, err := d.spi.readWrite(mcpReset)
-	d.cs.High()
+	d.cs.Set(true)
 	// time.Sleep(time.Microsecond * 4)
 	if err != nil {
 		return err
@@ -338,8 +337,8 @@ func (d *Device) readMsg() error {
 
 func (d *Device) readRxBuffer(loadAddr uint8) error {
 	msg := d.msg
-	d.cs.Low()
-	defer d.cs.High()
+	d.cs.Set(false)
+	defer d.cs.Set(true)
 	_, err := d.spi.readWrite(loadAddr)
 	if err != nil {
 		return err
@@ -406,8 +405,8 @@ func (d *Device) getNextFreeTxBuf() (uint8, uint8, error) {
 }
 
 func (d *Device) writeCANMsg(bufNum uint8, canid uint32, ext, rtrBit, dlc uint8, data []byte) error {
-	d.cs.Low()
-	defer d.cs.High()
+	d.cs.Set(false)
+	defer d.cs.Set(true)
 	_, err := d.spi.readWrite(txSidhToLoad(bufNum))
 	if err != nil {
 		return err
@@ -426,7 +425,7 @@ func (d *Device) writeCANMsg(bufNum uint8, canid uint32, ext, rtrBit, dlc uint8,
 	}
 	// Since cs.Low and cs.High are executed in d.startTransmission,
 	// it is necessary to set cs.High once to separate the instruction of mcp2515.
-	d.cs.High()
+	d.cs.Set(true)
 
 	err = d.startTransmission(bufNum)
 	if err != nil {
@@ -494,9 +493,9 @@ func (s *SPI) setTxBufData(canid uint32, ext, rtrBit, dlc uint8, data []byte) er
 }
 
 func (d *Device) startTransmission(bufNum uint8) error {
-	d.cs.Low()
+	d.cs.Set(false)
 	_, err := d.spi.readWrite(txSidhToRTS(bufNum))
-	d.cs.High()
+	d.cs.Set(true)
 	if err != nil {
 		return err
 	}
@@ -590,8 +589,8 @@ func txSidhToLoad(i uint8) uint8 {
 }
 
 func (d *Device) setRegister(addr, value byte) error {
-	d.cs.Low()
-	defer d.cs.High()
+	d.cs.Set(false)
+	defer d.cs.Set(true)
 	_, err := d.spi.readWrite(mcpWrite)
 	if err != nil {
 		return err
@@ -610,8 +609,8 @@ func (d *Device) setRegister(addr, value byte) error {
 }
 
 func (d *Device) readRegister(addr byte) (byte, error) {
-	d.cs.Low()
-	defer d.cs.High()
+	d.cs.Set(false)
+	defer d.cs.Set(true)
 	_, err := d.spi.readWrite(mcpRead)
 	if err != nil {
 		return 0, err
@@ -629,8 +628,8 @@ func (d *Device) readRegister(addr byte) (byte, error) {
 }
 
 func (d *Device) modifyRegister(addr, mask, data byte) error {
-	d.cs.Low()
-	defer d.cs.High()
+	d.cs.Set(false)
+	defer d.cs.Set(true)
 	_, err := d.spi.readWrite(mcpBitMod)
 	if err != nil {
 		return err
@@ -672,8 +671,8 @@ func (d *Device) requestNewMode(newMode byte) error {
 }
 
 func (d *Device) readStatus() (byte, error) {
-	d.cs.Low()
-	defer d.cs.High()
+	d.cs.Set(false)
+	defer d.cs.Set(true)
 	_, err := d.spi.readWrite(mcpReadStatus)
 	if err != nil {
 		return 0, err
@@ -12,6 +12,9 @@ type Device struct {
 	bus         drivers.I2C
 	Address     uint16
 	sensitivity Sensitivity
+
+	// last values read by Update
+	accel [3]int32
 }
 
 // New creates a new MMA8653 connection. The I2C bus must already be
@@ -19,7 +22,7 @@ type Device struct {
 //
 // This function only creates the Device object, it does not touch the device.
 func New(bus drivers.I2C) Device {
-	return Device{bus, Address, Sensitivity2G}
+	return Device{bus: bus, Address: Address, sensitivity: Sensitivity2G}
 }
 
 // Connected returns whether a MMA8653 has been found.
@@ -72,3 +75,17 @@ func (d Device) ReadAcceleration() (x int32, y int32, z int32, err error) {
 	z = int32(int16((uint16(data[4])<<8)|uint16(data[5]))) * 15625 >> shift
 	return
 }
+
+// Update reads the acceleration. Use Acceleration to get the value.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.Acceleration != 0 {
+		d.accel[0], d.accel[1], d.accel[2], err = d.ReadAcceleration()
+	}
+	return
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *Device) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
@@ -6,42 +6,417 @@
 // https://www.invensense.com/wp-content/uploads/2015/02/MPU-6000-Register-Map1.pdf
 package mpu6050 // import "tinygo.org/x/drivers/mpu6050"
 
-import "tinygo.org/x/drivers"
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+// AccelRange is the full-scale range of the accelerometer.
+type AccelRange uint8
+
+// GyroRange is the full-scale range of the gyroscope in °/s.
+type GyroRange uint8
+
+// DLPF is a setting of the digital low-pass filter.
+type DLPF uint8
+
+// FIFOSource is a bitmask of measurements written to the FIFO.
+type FIFOSource uint8
+
+// Interrupt is a bitmask of interrupt sources.
+type Interrupt uint8
+
+// Config holds the measurement settings of the device. The zero value
+// selects the power-on defaults: ±2g, ±250°/s, no low-pass filter and a
+// sample rate of 8kHz.
+type Config struct {
+	AccelRange AccelRange
+	GyroRange  GyroRange
+	DLPF       DLPF
+
+	// SampleRateDivider sets the sample rate of the output registers, the
+	// FIFO and the data ready interrupt to the gyroscope output rate
+	// divided by 1 + SampleRateDivider. The gyroscope output rate is 8kHz
+	// with DLPF_260HZ and 1kHz otherwise.
+	SampleRateDivider uint8
+}
+
+// InterruptPinConfig configures the electrical behaviour of the INT pin.
+type InterruptPinConfig struct {
+	ActiveLow bool
+	OpenDrain bool
+
+	// Latched holds the pin active until the interrupt is cleared, instead
+	// of emitting a 50µs pulse.
+	Latched bool
+
+	// ClearOnRead clears the interrupt on any register read instead of
+	// only on reads of INT_STATUS.
+	ClearOnRead bool
+}
+
+// Sample is a set of measurements read from the FIFO. Measurements that are
+// not written to the FIFO are zero.
+type Sample struct {
+	Acceleration    [3]int32 // µg
+	Temperature     int32    // milli°C
+	AngularVelocity [3]int32 // µ°/s
+}
+
+var (
+	errConfig       = errors.New("mpu6050: invalid configuration")
+	errFIFOOverflow = errors.New("mpu6050: FIFO overflow")
+	errFIFOBuffer   = errors.New("mpu6050: buffer too small for a FIFO sample")
+)
 
 // Device wraps an I2C connection to a MPU6050 device.
 type Device struct {
 	bus     drivers.I2C
 	Address uint16
+
+	accelRange  AccelRange
+	gyroRange   GyroRange
+	fifoSources FIFOSource
+
+	// last values read by Update
+	accel       [3]int32
+	gyro        [3]int32
+	temperature int32
 }
 
 // New creates a new MPU6050 connection. The I2C bus must already be
 // configured.
 //
 // This function only creates the Device object, it does not touch the device.
-func New(bus drivers.I2C) Device {
-	return Device{bus, Address}
+func New(bus drivers.I2C) *Device {
+	return &Device{bus: bus, Address: Address}
 }
 
 // Connected returns whether a MPU6050 has been found.
 // It does a "who am I" request and checks the response.
-func (d Device) Connected() bool {
+func (d *Device) Connected() bool {
 	data := []byte{0}
 	d.bus.ReadRegister(uint8(d.Address), WHO_AM_I, data)
 	return data[0] == 0x68
 }
 
-// Configure sets up the device for communication.
-func (d Device) Configure() {
-	d.bus.WriteRegister(uint8(d.Address), PWR_MGMT_1, []uint8{0})
+// Configure wakes up the device with the power-on default settings.
+func (d *Device) Configure() error {
+	return d.ConfigureWithSettings(Config{})
+}
+
+// ConfigureWithSettings wakes up the device and sets the full-scale ranges,
+// the low-pass filter and the sample rate. The device is clocked from the
+// gyroscope PLL, which is more stable than the internal oscillator.
+func (d *Device) ConfigureWithSettings(config Config) error {
+	if config.AccelRange > ACCEL_RANGE_16G || config.GyroRange > GYRO_RANGE_2000 || config.DLPF > DLPF_5HZ {
+		return errConfig
+	}
+	d.accelRange = config.AccelRange
+	d.gyroRange = config.GyroRange
+
+	for _, w := range [...][2]uint8{
+		{PWR_MGMT_1, CLKSEL_PLL_XGYRO},
+		{SMPLRT_DIV, config.SampleRateDivider},
+		{CONFIG, uint8(config.DLPF)},
+		{GYRO_CONFIG, uint8(config.GyroRange) << 3},
+		// The high-pass filter only applies to motion detection.
+		{ACCEL_CONFIG, uint8(config.AccelRange)<<3 | ACCEL_HPF_5HZ},
+	} {
+		if err := d.writeRegister(w[0], w[1]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ReadAcceleration reads the current acceleration from the device and returns
 // it in µg (micro-gravity). When one of the axes is pointing straight to Earth
 // and the sensor is not moving the returned value will be around 1000000 or
-// -1000000.
-func (d Device) ReadAcceleration() (x int32, y int32, z int32) {
+// -1000000, independent of the configured range.
+func (d *Device) ReadAcceleration() (x int32, y int32, z int32, err error) {
 	data := make([]byte, 6)
-	d.bus.ReadRegister(uint8(d.Address), ACCEL_XOUT_H, data)
+	err = d.bus.ReadRegister(uint8(d.Address), ACCEL_XOUT_H, data)
+	if err != nil {
+		return 0, 0, 0, drivers.NotResponding(err)
+	}
+	x = d.convertAcceleration(data[0], data[1])
+	y = d.convertAcceleration(data[2], data[3])
+	z = d.convertAcceleration(data[4], data[5])
+	return
+}
+
+// ReadRotation reads the current rotation from the device and returns it in
+// µ°/s (micro-degrees/sec). This means that if you were to do a complete
+// rotation along one axis and while doing so integrate all values over time,
+// you would get a value close to 360000000.
+func (d *Device) ReadRotation() (x int32, y int32, z int32, err error) {
+	data := make([]byte, 6)
+	err = d.bus.ReadRegister(uint8(d.Address), GYRO_XOUT_H, data)
+	if err != nil {
+		return 0, 0, 0, drivers.NotResponding(err)
+	}
+	x = d.convertRotation(data[0], data[1])
+	y = d.convertRotation(data[2], data[3])
+	z = d.convertRotation(data[4], data[5])
+	return
+}
+
+// ReadTemperature returns the die temperature in celsius milli degrees
+// (°C/1000).
+func (d *Device) ReadTemperature() (int32, error) {
+	var data [2]byte
+	err := d.bus.ReadRegister(uint8(d.Address), TEMP_OUT_H, data[:])
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return convertTemperature(data[0], data[1]), nil
+}
+
+// Update reads the selected measurements in a single burst read of the
+// accelerometer, temperature and gyroscope output registers. Use
+// Acceleration, AngularVelocity and Temperature to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Acceleration|drivers.AngularVelocity|drivers.Temperature) == 0 {
+		return nil
+	}
+	var data [14]byte
+	if err := d.bus.ReadRegister(uint8(d.Address), ACCEL_XOUT_H, data[:]); err != nil {
+		return drivers.NotResponding(err)
+	}
+	for i := 0; i < 3; i++ {
+		d.accel[i] = d.convertAcceleration(data[i*2], data[i*2+1])
+		d.gyro[i] = d.convertRotation(data[8+i*2], data[8+i*2+1])
+	}
+	d.temperature = convertTemperature(data[6], data[7])
+	return nil
+}
+
+// Acceleration returns the acceleration in µg (micro-gravity) read by the
+// last call to Update.
+func (d *Device) Acceleration() (x, y, z int32) {
+	return d.accel[0], d.accel[1], d.accel[2]
+}
+
+// AngularVelocity returns the rotation in µ°/s (micro-degrees/sec) read by
+// the last call to Update.
+func (d *Device) AngularVelocity() (x, y, z int32) {
+	return d.gyro[0], d.gyro[1], d.gyro[2]
+}
+
+// Temperature returns the die temperature in celsius milli degrees
+// (°C/1000) read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// EnableFIFO resets the FIFO and starts writing the given measurements to
+// it at the configured sample rate.
+func (d *Device) EnableFIFO(sources FIFOSource) error {
+	if err := d.writeRegister(USER_CTRL, 0); err != nil {
+		return err
+	}
+	if err := d.writeRegister(FIFO_EN, uint8(sources)); err != nil {
+		return err
+	}
+	d.fifoSources = sources
+	return d.writeRegister(USER_CTRL, USER_CTRL_FIFO_EN|USER_CTRL_FIFO_RESET)
+}
+
+// DisableFIFO stops writing measurements to the FIFO.
+func (d *Device) DisableFIFO() error {
+	if err := d.writeRegister(FIFO_EN, 0); err != nil {
+		return err
+	}
+	d.fifoSources = 0
+	return d.writeRegister(USER_CTRL, 0)
+}
+
+// ResetFIFO discards the contents of the FIFO.
+func (d *Device) ResetFIFO() error {
+	var ctrl uint8 = USER_CTRL_FIFO_RESET
+	if d.fifoSources != 0 {
+		ctrl |= USER_CTRL_FIFO_EN
+	}
+	return d.writeRegister(USER_CTRL, ctrl)
+}
+
+// FIFOCount returns the number of bytes in the FIFO.
+func (d *Device) FIFOCount() (int, error) {
+	var data [2]byte
+	err := d.bus.ReadRegister(uint8(d.Address), FIFO_COUNTH, data[:])
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return int(data[0])<<8 | int(data[1]), nil
+}
+
+// FIFOSampleSize returns the size in bytes of a FIFO sample with the
+// measurements enabled by EnableFIFO.
+func (d *Device) FIFOSampleSize() int {
+	n := 0
+	for s := d.fifoSources; s != 0; s &= s - 1 {
+		n += 2
+	}
+	if d.fifoSources&FIFO_ACCEL != 0 {
+		n += 4 // three axes
+	}
+	return n
+}
+
+// ReadFIFO drains as many complete samples from the FIFO as fit in buf in a
+// single burst read, and returns the number of bytes read. Decode the
+// samples with DecodeSample.
+//
+// When the FIFO has overflowed, samples are no longer aligned: the FIFO is
+// reset and an error is returned.
+func (d *Device) ReadFIFO(buf []byte) (n int, err error) {
+	size := d.FIFOSampleSize()
+	if size == 0 {
+		return 0, nil
+	}
+	if len(buf) < size {
+		return 0, errFIFOBuffer
+	}
+	count, err := d.FIFOCount()
+	if err != nil {
+		return 0, err
+	}
+	if count >= FIFOSize {
+		if err := d.ResetFIFO(); err != nil {
+			return 0, err
+		}
+		return 0, errFIFOOverflow
+	}
+	if count > len(buf) {
+		count = len(buf)
+	}
+	n = count - count%size
+	if n == 0 {
+		return 0, nil
+	}
+	err = d.bus.ReadRegister(uint8(d.Address), FIFO_R_W, buf[:n])
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return n, nil
+}
+
+// DecodeSample converts a sample read by ReadFIFO. The data must hold at
+// least FIFOSampleSize bytes.
+func (d *Device) DecodeSample(data []byte) (s Sample) {
+	if d.fifoSources&FIFO_ACCEL != 0 {
+		for i := range s.Acceleration {
+			s.Acceleration[i] = d.convertAcceleration(data[0], data[1])
+			data = data[2:]
+		}
+	}
+	if d.fifoSources&FIFO_TEMP != 0 {
+		s.Temperature = convertTemperature(data[0], data[1])
+		data = data[2:]
+	}
+	for i, source := range [...]FIFOSource{FIFO_GYRO_X, FIFO_GYRO_Y, FIFO_GYRO_Z} {
+		if d.fifoSources&source != 0 {
+			s.AngularVelocity[i] = d.convertRotation(data[0], data[1])
+			data = data[2:]
+		}
+	}
+	return s
+}
+
+// ConfigureInterruptPin sets the electrical behaviour of the INT pin.
+func (d *Device) ConfigureInterruptPin(config InterruptPinConfig) error {
+	var data [1]byte
+	err := d.bus.ReadRegister(uint8(d.Address), INT_PIN_CFG, data[:])
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
+	// Keep the I2C bypass and FSYNC settings.
+	cfg := data[0] &^ (INT_LEVEL | INT_OPEN | LATCH_INT_EN | INT_RD_CLEAR)
+	if config.ActiveLow {
+		cfg |= INT_LEVEL
+	}
+	if config.OpenDrain {
+		cfg |= INT_OPEN
+	}
+	if config.Latched {
+		cfg |= LATCH_INT_EN
+	}
+	if config.ClearOnRead {
+		cfg |= INT_RD_CLEAR
+	}
+	return d.writeRegister(INT_PIN_CFG, cfg)
+}
+
+// EnableInterrupts sets the interrupt sources that drive the INT pin. All
+// other sources are disabled.
+func (d *Device) EnableInterrupts(which Interrupt) error {
+	return d.writeRegister(INT_ENABLE, uint8(which))
+}
+
+// InterruptStatus returns the interrupts that occurred since the last call.
+// Reading the status clears it.
+func (d *Device) InterruptStatus() (Interrupt, error) {
+	var data [1]byte
+	err := d.bus.ReadRegister(uint8(d.Address), INT_STATUS, data[:])
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return Interrupt(data[0]), nil
+}
+
+// SetMotionDetection configures the INT_MOTION interrupt, which fires when
+// the high-pass filtered acceleration of any axis exceeds threshold, in µg,
+// for at least the given duration. The threshold has a resolution of 2mg
+// and the duration of 1ms, up to 510mg and 255ms.
+func (d *Device) SetMotionDetection(threshold int32, duration time.Duration) error {
+	if err := d.writeRegister(MOT_THR, clampUint8(threshold/2000)); err != nil {
+		return err
+	}
+	return d.writeRegister(MOT_DUR, clampUint8(int32(duration/time.Millisecond)))
+}
+
+// SetZeroMotionDetection configures the INT_ZERO_MOTION interrupt, which
+// fires when the high-pass filtered acceleration of all axes stays below
+// threshold, in µg, for at least the given duration, and again when motion
+// resumes. The threshold has a resolution of 2mg and the duration of 64ms,
+// up to 510mg and 16.3s.
+func (d *Device) SetZeroMotionDetection(threshold int32, duration time.Duration) error {
+	if err := d.writeRegister(ZRMOT_THR, clampUint8(threshold/2000)); err != nil {
+		return err
+	}
+	return d.writeRegister(ZRMOT_DUR, clampUint8(int32(duration/(64*time.Millisecond))))
+}
+
+// ZeroMotionDetected returns whether the device is at rest, as detected by
+// zero motion detection.
+func (d *Device) ZeroMotionDetected() (bool, error) {
+	var data [1]byte
+	err := d.bus.ReadRegister(uint8(d.Address), MOT_DETECT_STATUS, data[:])
+	if err != nil {
+		return false, drivers.NotResponding(err)
+	}
+	return data[0]&MOT_DETECT_ZRMOT != 0, nil
+}
+
+func (d *Device) writeRegister(reg, value uint8) error {
+	return drivers.NotResponding(d.bus.WriteRegister(uint8(d.Address), reg, []uint8{value}))
+}
+
+func clampUint8(v int32) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+// convertAcceleration converts a raw accelerometer value to µg.
+func (d *Device) convertAcceleration(msb, lsb byte) int32 {
 	// Now do two things:
 	// 1. merge the two values to a 16-bit number (and cast to a 32-bit integer)
 	// 2. scale the value to bring it in the -1000000..1000000 range.
@@ -50,19 +425,12 @@ func (d Device) ReadAcceleration() (x int32, y int32, z int32) {
 	//    overflow we do it at 1/64 of the value:
 	//      1000000 / 64 = 15625
 	//      16384   / 64 = 256
-	x = int32(int16((uint16(data[0])<<8)|uint16(data[1]))) * 15625 / 256
-	y = int32(int16((uint16(data[2])<<8)|uint16(data[3]))) * 15625 / 256
-	z = int32(int16((uint16(data[4])<<8)|uint16(data[5]))) * 15625 / 256
-	return
+	//    Every doubling of the range halves the sensitivity.
+	return int32(int16((uint16(msb)<<8)|uint16(lsb))) * 15625 / (256 >> d.accelRange)
 }
 
-// ReadRotation reads the current rotation from the device and returns it in
-// µ°/s (micro-degrees/sec). This means that if you were to do a complete
-// rotation along one axis and while doing so integrate all values over time,
-// you would get a value close to 360000000.
-func (d Device) ReadRotation() (x int32, y int32, z int32) {
-	data := make([]byte, 6)
-	d.bus.ReadRegister(uint8(d.Address), GYRO_XOUT_H, data)
+// convertRotation converts a raw gyroscope value to µ°/s.
+func (d *Device) convertRotation(msb, lsb byte) int32 {
 	// First the value is converted from a pair of bytes to a signed 16-bit
 	// value and then to a signed 32-bit value to avoid integer overflow.
 	// Then the value is scaled to µ°/s (micro-degrees per second).
@@ -72,9 +440,13 @@ func (d Device) ReadRotation() (x int32, y int32, z int32) {
 	// The following calculation (x * 15625 / 2048 * 1000) is essentially the
 	// same but avoids overflow. First both operations are divided by 16 leading
 	// to multiply by 15625000 and divide by 2048, and then part of the multiply
-	// is done after the divide instead of before.
-	x = int32(int16((uint16(data[0])<<8)|uint16(data[1]))) * 15625 / 2048 * 1000
-	y = int32(int16((uint16(data[2])<<8)|uint16(data[3]))) * 15625 / 2048 * 1000
-	z = int32(int16((uint16(data[4])<<8)|uint16(data[5]))) * 15625 / 2048 * 1000
-	return
+	// is done after the divide instead of before. Larger ranges multiply the
+	// 250°/s by a power of two, which is done by shifting the divisor.
+	return int32(int16((uint16(msb)<<8)|uint16(lsb))) * 15625 / (2048 >> d.gyroRange) * 1000
+}
+
+// convertTemperature converts a raw temperature value to milli°C.
+func convertTemperature(msb, lsb byte) int32 {
+	// Temperature in °C is raw / 340 + 36.53.
+	return int32(int16(uint16(msb)<<8|uint16(lsb)))*1000/340 + 36530
 }
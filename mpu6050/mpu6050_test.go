@@ -0,0 +1,199 @@
+package mpu6050
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+var _ interface {
+	drivers.Accelerometer
+	drivers.Gyroscope
+	drivers.Thermometer
+} = (*Device)(nil)
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	copy(fake.Registers[ACCEL_XOUT_H:], []byte{
+		0x40, 0x00, 0x00, 0x00, 0xc0, 0x00, // accel 16384, 0, -16384
+		0xfd, 0xf3, // temperature -525
+		0x00, 0x83, 0x00, 0x00, 0xff, 0x7d, // gyro 131, 0, -131
+	})
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	trace := bus.Record()
+	c.Assert(dev.Update(drivers.Acceleration|drivers.AngularVelocity), qt.IsNil)
+	c.Assert(trace.Transactions, qt.HasLen, 1)
+
+	x, y, z := dev.Acceleration()
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{1000000, 0, -1000000})
+	x, y, z = dev.AngularVelocity()
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{999000, 0, -999000})
+	c.Assert(dev.Temperature(), qt.Equals, int32(34986))
+
+	rx, ry, rz, err := dev.ReadRotation()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int32{rx, ry, rz}, qt.DeepEquals, []int32{999000, 0, -999000})
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	dev := New(bus)
+
+	_, _, _, err := dev.ReadAcceleration()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(err, tester.ErrNoDevice), qt.IsTrue)
+	_, _, _, err = dev.ReadRotation()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	err = dev.Update(drivers.AllMeasurements)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.ReadTemperature()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(dev.Configure(), drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(dev.ConfigureWithSettings(Config{}), drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(dev.EnableFIFO(FIFO_ACCEL), drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.FIFOCount()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.InterruptStatus()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
+
+func TestConfigureWithSettings(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	fake.Registers[PWR_MGMT_1] = 0x40 // sleep
+	copy(fake.Registers[ACCEL_XOUT_H:], []byte{
+		0x40, 0x00, 0x00, 0x00, 0xc0, 0x00, // accel 16384, 0, -16384
+		0xfd, 0xf3, // temperature -525
+		0x00, 0x83, 0x00, 0x00, 0xff, 0x7d, // gyro 131, 0, -131
+	})
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.ConfigureWithSettings(Config{
+		AccelRange:        ACCEL_RANGE_8G,
+		GyroRange:         GYRO_RANGE_2000,
+		DLPF:              DLPF_44HZ,
+		SampleRateDivider: 9,
+	}), qt.IsNil)
+	c.Assert(fake.Registers[PWR_MGMT_1], qt.Equals, uint8(0x01))
+	c.Assert(fake.Registers[SMPLRT_DIV], qt.Equals, uint8(9))
+	c.Assert(fake.Registers[CONFIG], qt.Equals, uint8(0x03))
+	c.Assert(fake.Registers[GYRO_CONFIG], qt.Equals, uint8(0x18))
+	c.Assert(fake.Registers[ACCEL_CONFIG], qt.Equals, uint8(0x11))
+
+	x, y, z, err := dev.ReadAcceleration()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{4000000, 0, -4000000})
+	x, y, z, err = dev.ReadRotation()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int32{x, y, z}, qt.DeepEquals, []int32{7995000, 0, -7995000})
+	temperature, err := dev.ReadTemperature()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temperature, qt.Equals, int32(34986))
+
+	c.Assert(dev.ConfigureWithSettings(Config{DLPF: DLPF_5HZ + 1}), qt.Equals, errConfig)
+}
+
+func TestFIFO(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(), qt.IsNil)
+	c.Assert(dev.EnableFIFO(FIFO_ACCEL|FIFO_GYRO_Z), qt.IsNil)
+	c.Assert(fake.Registers[FIFO_EN], qt.Equals, uint8(0x18))
+	c.Assert(fake.Registers[USER_CTRL], qt.Equals, uint8(0x44))
+	c.Assert(dev.FIFOSampleSize(), qt.Equals, 8)
+
+	// Two and a half samples.
+	fake.Registers[FIFO_COUNTH] = 0
+	fake.Registers[FIFO_COUNTL] = 20
+	fake.FIFO[FIFO_R_W] = []byte{
+		0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x83,
+		0x00, 0x00, 0x20, 0x00, 0x00, 0x00, 0xff, 0x7d,
+		0x00, 0x00, 0x40, 0x00,
+	}
+	buf := make([]byte, 32)
+	trace := bus.Record()
+	n, err := dev.ReadFIFO(buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 16)
+	c.Assert(trace.String(), qt.Equals, ""+
+		"i2c 0x68 w:72 r:0014\n"+
+		"i2c 0x68 w:74 r:4000000000000083000020000000ff7d\n")
+	c.Assert(dev.DecodeSample(buf[0:]), qt.DeepEquals, Sample{
+		Acceleration:    [3]int32{1000000, 0, 0},
+		AngularVelocity: [3]int32{0, 0, 999000},
+	})
+	c.Assert(dev.DecodeSample(buf[8:]), qt.DeepEquals, Sample{
+		Acceleration:    [3]int32{0, 500000, 0},
+		AngularVelocity: [3]int32{0, 0, -999000},
+	})
+
+	// Only complete samples that fit are read.
+	fake.Registers[FIFO_COUNTL] = 4
+	n, err = dev.ReadFIFO(buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 0)
+	_, err = dev.ReadFIFO(buf[:7])
+	c.Assert(err, qt.Equals, errFIFOBuffer)
+
+	fake.Registers[FIFO_COUNTH] = 0x04
+	fake.Registers[FIFO_COUNTL] = 0x00
+	_, err = dev.ReadFIFO(buf)
+	c.Assert(err, qt.Equals, errFIFOOverflow)
+	c.Assert(fake.Registers[USER_CTRL], qt.Equals, uint8(0x44))
+
+	c.Assert(dev.DisableFIFO(), qt.IsNil)
+	c.Assert(fake.Registers[FIFO_EN], qt.Equals, uint8(0))
+	c.Assert(fake.Registers[USER_CTRL], qt.Equals, uint8(0))
+	c.Assert(dev.FIFOSampleSize(), qt.Equals, 0)
+}
+
+func TestInterrupts(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, Address)
+	fake.Registers[INT_PIN_CFG] = 0x02 // I2C bypass
+	fake.Flags[INT_STATUS] = tester.RegisterClearOnRead
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.ConfigureInterruptPin(InterruptPinConfig{ActiveLow: true, Latched: true}), qt.IsNil)
+	c.Assert(fake.Registers[INT_PIN_CFG], qt.Equals, uint8(0xa2))
+
+	c.Assert(dev.SetMotionDetection(40000, 5*time.Millisecond), qt.IsNil)
+	c.Assert(fake.Registers[MOT_THR], qt.Equals, uint8(20))
+	c.Assert(fake.Registers[MOT_DUR], qt.Equals, uint8(5))
+	c.Assert(dev.SetZeroMotionDetection(1000000, 2*time.Second), qt.IsNil)
+	c.Assert(fake.Registers[ZRMOT_THR], qt.Equals, uint8(255))
+	c.Assert(fake.Registers[ZRMOT_DUR], qt.Equals, uint8(31))
+
+	c.Assert(dev.EnableInterrupts(INT_MOTION|INT_ZERO_MOTION|INT_DATA_READY), qt.IsNil)
+	c.Assert(fake.Registers[INT_ENABLE], qt.Equals, uint8(0x61))
+
+	fake.Registers[INT_STATUS] = 0x21
+	fake.Registers[MOT_DETECT_STATUS] = 0x01
+	status, err := dev.InterruptStatus()
+	c.Assert(err, qt.IsNil)
+	c.Assert(status, qt.Equals, INT_ZERO_MOTION|INT_DATA_READY)
+	status, err = dev.InterruptStatus()
+	c.Assert(err, qt.IsNil)
+	c.Assert(status, qt.Equals, Interrupt(0))
+	still, err := dev.ZeroMotionDetected()
+	c.Assert(err, qt.IsNil)
+	c.Assert(still, qt.IsTrue)
+}
@@ -17,7 +17,15 @@ const (
 	CONFIG       = 0x1A // Configuration
 	GYRO_CONFIG  = 0x1B // Gyroscope configuration
 	ACCEL_CONFIG = 0x1C // Accelerometer configuration
-	FIFO_EN      = 0x23 // FIFO enable
+
+	// Motion detection configuration. The zero motion and duration
+	// registers are only described in revision 3 of the register map.
+	MOT_THR   = 0x1F // Motion detection threshold
+	MOT_DUR   = 0x20 // Motion detection duration
+	ZRMOT_THR = 0x21 // Zero motion detection threshold
+	ZRMOT_DUR = 0x22 // Zero motion detection duration
+
+	FIFO_EN = 0x23 // FIFO enable
 
 	// I2C pass-through configuration
 	I2C_MST_CTRL   = 0x24
@@ -91,6 +99,8 @@ const (
 	EXT_SENS_DATA_22 = 0x5F
 	EXT_SENS_DATA_23 = 0x60
 
+	MOT_DETECT_STATUS = 0x61 // Motion detection status
+
 	// I2C peripheral data out
 	I2C_PER0_DO      = 0x63
 	I2C_PER1_DO      = 0x64
@@ -99,6 +109,7 @@ const (
 	I2C_MST_DELAY_CT = 0x67
 
 	SIGNAL_PATH_RES = 0x68 // Signal path reset
+	MOT_DETECT_CTRL = 0x69 // Motion detection control
 	USER_CTRL       = 0x6A // User control
 	PWR_MGMT_1      = 0x6B // Power Management 1
 	PWR_MGMT_2      = 0x6C // Power Management 2
@@ -107,3 +118,71 @@ const (
 	FIFO_R_W        = 0x74 // FIFO read/write
 	WHO_AM_I        = 0x75 // Who am I
 )
+
+// Register bits.
+const (
+	CLKSEL_PLL_XGYRO = 0x01 // PWR_MGMT_1: PLL with X axis gyroscope reference
+
+	ACCEL_HPF_5HZ = 0x01 // ACCEL_CONFIG: 5Hz high-pass filter for motion detection
+
+	INT_LEVEL    = 0x80 // INT_PIN_CFG: active low
+	INT_OPEN     = 0x40 // INT_PIN_CFG: open drain
+	LATCH_INT_EN = 0x20 // INT_PIN_CFG: hold the pin until the interrupt is cleared
+	INT_RD_CLEAR = 0x10 // INT_PIN_CFG: clear the interrupt on any read
+
+	USER_CTRL_FIFO_EN    = 0x40
+	USER_CTRL_FIFO_RESET = 0x04
+
+	MOT_DETECT_ZRMOT = 0x01 // MOT_DETECT_STATUS: zero motion detected
+)
+
+// Full-scale ranges of the accelerometer.
+const (
+	ACCEL_RANGE_2G AccelRange = iota
+	ACCEL_RANGE_4G
+	ACCEL_RANGE_8G
+	ACCEL_RANGE_16G
+)
+
+// Full-scale ranges of the gyroscope.
+const (
+	GYRO_RANGE_250 GyroRange = iota
+	GYRO_RANGE_500
+	GYRO_RANGE_1000
+	GYRO_RANGE_2000
+)
+
+// Bandwidths of the digital low-pass filter for the accelerometer. The
+// gyroscope bandwidth is about the same.
+const (
+	DLPF_260HZ DLPF = iota // filter disabled, gyroscope output rate 8kHz
+	DLPF_184HZ
+	DLPF_94HZ
+	DLPF_44HZ
+	DLPF_21HZ
+	DLPF_10HZ
+	DLPF_5HZ
+)
+
+// Measurements that can be written to the FIFO. Samples hold the enabled
+// measurements in the order of the output registers.
+const (
+	FIFO_TEMP   FIFOSource = 0x80
+	FIFO_GYRO_X FIFOSource = 0x40
+	FIFO_GYRO_Y FIFOSource = 0x20
+	FIFO_GYRO_Z FIFOSource = 0x10
+	FIFO_ACCEL  FIFOSource = 0x08
+
+	FIFO_GYRO = FIFO_GYRO_X | FIFO_GYRO_Y | FIFO_GYRO_Z
+)
+
+// Interrupt sources, as used by INT_ENABLE and INT_STATUS.
+const (
+	INT_MOTION        Interrupt = 0x40
+	INT_ZERO_MOTION   Interrupt = 0x20
+	INT_FIFO_OVERFLOW Interrupt = 0x10
+	INT_DATA_READY    Interrupt = 0x01
+)
+
+// FIFOSize is the size of the FIFO in bytes.
+const FIFOSize = 1024
@@ -33,15 +33,24 @@ func (d *Device) Reset() (err error) {
 	return d.bus.Tx(d.Address, []byte{0x00, 0x00, 0x00}, nil)
 }
 
-// SetTime sets the time and date
+// SetTime sets the time and date. The PCF8563 adds February 29 to every
+// year divisible by 4, including year 00, so it keeps the calendar only from
+// 2000 to 2099 and other years return drivers.ErrTimeRange. The century bit,
+// which the PCF8563 toggles when the year wraps after 2099, is cleared.
+// Writing the seconds also clears the voltage low flag. The weekday is
+// stored as 0 (Sunday) to 6 (Saturday).
 func (d *Device) SetTime(t time.Time) error {
+	t = t.UTC()
+	if t.Year() < 2000 || t.Year() > 2099 {
+		return drivers.ErrTimeRange
+	}
 	var buf [9]byte
 	buf[0] = 0x02
 	buf[1] = decToBcd(t.Second())
 	buf[2] = decToBcd(t.Minute())
 	buf[3] = decToBcd(t.Hour())
 	buf[4] = decToBcd(t.Day())
-	buf[5] = decToBcd(int(t.Weekday() + 1))
+	buf[5] = decToBcd(int(t.Weekday()))
 	buf[6] = decToBcd(int(t.Month()))
 	buf[7] = decToBcd(t.Year() - 2000)
 	err := d.bus.Tx(d.Address, buf[:], nil)
@@ -57,11 +66,14 @@ func (d *Device) ReadTime() (time.Time, error) {
 	}
 
 	seconds := bcdToDec(buf[2] & 0x7F)
-	minute := bcdToDec(buf[3] % 0x7F)
+	minute := bcdToDec(buf[3] & 0x7F)
 	hour := bcdToDec(buf[4] & 0x3F)
 	day := bcdToDec(buf[5] & 0x3F)
-	month := time.Month(bcdToDec(buf[7] & 0x0F))
+	month := time.Month(bcdToDec(buf[7] & 0x1F))
 	year := int(bcdToDec(buf[8])) + 2000
+	if buf[7]&RTC_MONTH_CENTURY != 0 {
+		year += 100
+	}
 
 	t := time.Date(year, month, day, hour, minute, seconds, 0, time.UTC)
 	return t, nil
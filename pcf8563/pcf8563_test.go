@@ -0,0 +1,37 @@
+package pcf8563
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers/tester"
+)
+
+// regTime is the first register of the time and date.
+const regTime = 0x02
+
+func TestTime(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice8(c, PCF8563_ADDR)
+	bus.AddDevice(fake)
+	dev := New(bus)
+
+	tester.CheckBCDClock(c, &dev, tester.BCDClock{
+		Registers: fake.Registers[regTime : regTime+7],
+		DayFirst:  true,
+	})
+
+	// The voltage low flag and the unused bits are ignored.
+	copy(fake.Registers[regTime:], []uint8{0xD9, 0xD9, 0xE3, 0xE9, 0xF8, 0x62, 0x24})
+	got, err := dev.ReadTime()
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC))
+
+	// The century bit is set when the year wraps after 2099.
+	copy(fake.Registers[regTime:], []uint8{0x00, 0x00, 0x00, 0x01, 0x05, 0x81, 0x00})
+	got, err = dev.ReadTime()
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+}
@@ -28,4 +28,6 @@ const (
 
 	RTC_ALARM_DISABLE = 0x80
 	RTC_ALARM_ENABLE  = 0x00
+
+	RTC_MONTH_CENTURY = 0x80
 )
@@ -0,0 +1,91 @@
+package drivers
+
+// Pin is a single GPIO pin. It is notably implemented by the machine.Pin
+// type.
+//
+// Configuring a pin depends on the machine package, so it is not part of
+// this interface: use ConfigurePin instead, which configures machine.Pin
+// values as well as any pin implementing ConfigurablePin.
+type Pin interface {
+	// Set drives the pin high or low.
+	Set(high bool)
+
+	// Get returns the current level of the pin.
+	Get() bool
+}
+
+// PinMode is the mode a pin is configured in by ConfigurePin.
+type PinMode uint8
+
+const (
+	PinOutput PinMode = iota
+	PinInput
+	PinInputPullup
+)
+
+// PinChange is the kind of level change that triggers a pin interrupt.
+type PinChange uint8
+
+const (
+	PinRising PinChange = 1 << iota
+	PinFalling
+	PinToggle = PinRising | PinFalling
+)
+
+// ConfigurablePin is a Pin that can be configured without the machine
+// package, such as a mock pin.
+type ConfigurablePin interface {
+	Pin
+	Configure(mode PinMode)
+}
+
+// InterruptPin is a Pin that can call back when its level changes. It is
+// an optional interface: drivers that need interrupts should check for it
+// and fall back to polling when it is not implemented.
+type InterruptPin interface {
+	Pin
+
+	// SetInterrupt sets callback to be called on the given level change.
+	// A nil callback disables the interrupt.
+	SetInterrupt(change PinChange, callback func(Pin)) error
+}
+
+// NewInterruptPin returns an InterruptPin that drives and reads p, and sets
+// interrupts with setInterrupt. It adapts pins whose SetInterrupt method
+// takes their own types, such as machine.Pin, whose PinChange values differ
+// between targets:
+//
+//	pin := drivers.NewInterruptPin(machine.D2, func(change drivers.PinChange, callback func()) error {
+//		if callback == nil {
+//			return machine.D2.SetInterrupt(0, nil)
+//		}
+//		var c machine.PinChange
+//		switch change {
+//		case drivers.PinRising:
+//			c = machine.PinRising
+//		case drivers.PinFalling:
+//			c = machine.PinFalling
+//		default:
+//			c = machine.PinToggle
+//		}
+//		return machine.D2.SetInterrupt(c, func(machine.Pin) { callback() })
+//	})
+//
+// The callback given to SetInterrupt of the returned pin receives the
+// returned pin.
+func NewInterruptPin(p Pin, setInterrupt func(change PinChange, callback func()) error) InterruptPin {
+	return &interruptPin{Pin: p, setInterrupt: setInterrupt}
+}
+
+// interruptPin is the InterruptPin returned by NewInterruptPin.
+type interruptPin struct {
+	Pin
+	setInterrupt func(change PinChange, callback func()) error
+}
+
+func (p *interruptPin) SetInterrupt(change PinChange, callback func(Pin)) error {
+	if callback == nil {
+		return p.setInterrupt(change, nil)
+	}
+	return p.setInterrupt(change, func() { callback(p) })
+}
@@ -0,0 +1,18 @@
+//go:build !tinygo
+// +build !tinygo
+
+package drivers
+
+// ConfigurePin configures p in the given mode. Pins that do not implement
+// ConfigurablePin are left unchanged.
+func ConfigurePin(p Pin, mode PinMode) {
+	if p, ok := p.(ConfigurablePin); ok {
+		p.Configure(mode)
+	}
+}
+
+// IsNoPin returns whether p is nil, which drivers use to mark optional
+// pins that are not connected.
+func IsNoPin(p Pin) bool {
+	return p == nil
+}
@@ -0,0 +1,33 @@
+package drivers_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestNewInterruptPin(t *testing.T) {
+	c := qt.New(t)
+	line := tester.NewPin()
+	var change drivers.PinChange
+	var trigger func()
+	pin := drivers.NewInterruptPin(line, func(ch drivers.PinChange, callback func()) error {
+		change, trigger = ch, callback
+		return nil
+	})
+
+	pin.Set(true)
+	c.Assert(line.Get(), qt.IsTrue)
+	c.Assert(pin.Get(), qt.IsTrue)
+
+	var got drivers.Pin
+	c.Assert(pin.SetInterrupt(drivers.PinFalling, func(p drivers.Pin) { got = p }), qt.IsNil)
+	c.Assert(change, qt.Equals, drivers.PinFalling)
+	trigger()
+	c.Assert(got, qt.Equals, drivers.Pin(pin))
+
+	c.Assert(pin.SetInterrupt(0, nil), qt.IsNil)
+	c.Assert(trigger, qt.IsNil)
+}
@@ -0,0 +1,32 @@
+//go:build tinygo
+// +build tinygo
+
+package drivers
+
+import "machine"
+
+// ConfigurePin configures p in the given mode. Pins that are neither a
+// machine.Pin nor a ConfigurablePin are left unchanged.
+func ConfigurePin(p Pin, mode PinMode) {
+	switch p := p.(type) {
+	case machine.Pin:
+		var m machine.PinMode
+		switch mode {
+		case PinInput:
+			m = machine.PinInput
+		case PinInputPullup:
+			m = machine.PinInputPullup
+		default:
+			m = machine.PinOutput
+		}
+		p.Configure(machine.PinConfig{Mode: m})
+	case ConfigurablePin:
+		p.Configure(mode)
+	}
+}
+
+// IsNoPin returns whether p is nil or machine.NoPin, which drivers use to
+// mark optional pins that are not connected.
+func IsNoPin(p Pin) bool {
+	return p == nil || p == Pin(machine.NoPin)
+}
@@ -0,0 +1,23 @@
+package drivers
+
+import (
+	"errors"
+	"time"
+)
+
+// RTC is a real-time clock, which keeps the date and time with a resolution
+// of one second, usually on a backup battery.
+//
+// The clocks do not store a time zone: SetTime stores t converted to UTC,
+// and ReadTime returns a time in UTC.
+type RTC interface {
+	// SetTime sets the date and time of the clock. It returns
+	// ErrTimeRange if t is outside of the years the clock can store.
+	SetTime(t time.Time) error
+
+	// ReadTime returns the date and time of the clock.
+	ReadTime() (time.Time, error)
+}
+
+// ErrTimeRange is returned by RTC.SetTime for a time the clock cannot store.
+var ErrTimeRange = errors.New("time out of range of the clock")
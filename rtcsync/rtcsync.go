@@ -0,0 +1,197 @@
+// Package rtcsync keeps a software clock aligned with a real-time clock, and
+// measures and corrects the drift of the real-time clock against an accurate
+// time source such as GPS or SNTP.
+//
+// The clock of a microcontroller may drift by a few hundred ppm, a crystal
+// real-time clock by a few tens of ppm, and a temperature compensated one
+// like the DS3231 by a few ppm. A Clock reads the RTC every time Sync is
+// called to realign the software clock, and corrects the time of the RTC by
+// the drift measured between the last two references.
+package rtcsync // import "tinygo.org/x/drivers/rtcsync"
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+var errStopped = errors.New("rtcsync: RTC is not running")
+
+const (
+	// pollInterval is the time between reads of the RTC while waiting for
+	// the next second, which limits the precision of the alignment.
+	pollInterval = 5 * time.Millisecond
+
+	// minDriftInterval is the shortest time between two references to
+	// measure the drift, so that the precision of the alignment does not
+	// spoil the measurement.
+	minDriftInterval = time.Hour
+)
+
+// Clock is a software clock that follows an RTC.
+type Clock struct {
+	rtc drivers.RTC
+
+	// base is the corrected time of the RTC at the monotonic time baseMono.
+	base     time.Time
+	baseMono time.Time
+	synced   bool
+
+	drift      int32 // ppb
+	reference  time.Time
+	referenced bool
+
+	// now and sleep are the monotonic clock, replaced in tests.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// New returns a Clock that follows rtc. Sync or SetReference must be called
+// before the clock is used.
+func New(rtc drivers.RTC) *Clock {
+	return &Clock{
+		rtc:   rtc,
+		now:   time.Now,
+		sleep: time.Sleep,
+	}
+}
+
+// Now returns the current time: the time of the RTC at the last Sync or
+// SetReference plus the time elapsed since. It returns the zero time before
+// the clock has been synced.
+func (c *Clock) Now() time.Time {
+	if !c.synced {
+		return time.Time{}
+	}
+	return c.base.Add(c.now().Sub(c.baseMono))
+}
+
+// Sync aligns the software clock with the RTC, corrected by the measured
+// drift. It waits for the next second of the RTC, so it blocks for up to
+// one second. It should be called regularly, for example every few minutes,
+// as the clock of the microcontroller drifts much more than the RTC.
+func (c *Clock) Sync() error {
+	t, mono, err := c.tick()
+	if err != nil {
+		return err
+	}
+	c.base = c.correct(t)
+	c.baseMono = mono
+	c.synced = true
+	return nil
+}
+
+// SetReference aligns the software clock with ref, the current time from an
+// accurate source such as GPS or SNTP. The first reference, and every
+// reference at least an hour after the one the RTC was last set to, also
+// measures the drift of the RTC since that reference, which corrects the RTC
+// until the next one, and sets the RTC to ref. References less than an hour
+// apart only realign the software clock, so that sources called more often
+// still measure the drift over at least an hour. It should be called
+// regularly, for example every day, to follow the changes of the drift with
+// temperature and age.
+//
+// SetReference blocks for up to two seconds to set the RTC at the start of a
+// second, which is precise on the RTCs that reset the fraction of the second
+// when their time is set, such as the DS1307 and DS3231.
+func (c *Clock) SetReference(ref time.Time) error {
+	called := c.now()
+	t, mono, err := c.tick()
+	if err != nil {
+		return err
+	}
+	// The reference time at the tick of the RTC.
+	ref = ref.Add(mono.Sub(called))
+
+	if c.referenced {
+		elapsed := ref.Sub(c.reference)
+		if elapsed < minDriftInterval {
+			// Keep the RTC and the reference to measure the drift later.
+			c.base = ref
+			c.baseMono = mono
+			c.synced = true
+			return nil
+		}
+		// The RTC was set to the previous reference, so the time it
+		// gained since is its drift.
+		gained := t.Sub(c.reference) - elapsed
+		drift := int64(gained) / int64(elapsed/time.Second)
+		if drift > math.MaxInt32 {
+			drift = math.MaxInt32
+		} else if drift < math.MinInt32 {
+			drift = math.MinInt32
+		}
+		c.drift = int32(drift)
+	}
+
+	next := ref.Truncate(time.Second).Add(time.Second)
+	nextMono := mono.Add(next.Sub(ref))
+	if wait := nextMono.Sub(c.now()); wait > 0 {
+		c.sleep(wait)
+	}
+	err = c.rtc.SetTime(next)
+	if err != nil {
+		return err
+	}
+	c.reference = next
+	c.referenced = true
+	c.base = next
+	c.baseMono = nextMono
+	c.synced = true
+	return nil
+}
+
+// Correction returns the measured drift of the RTC in ppb (parts per
+// billion), positive when the RTC runs fast, and the time of the reference
+// since which it is corrected. Both can be stored, for example in an EEPROM,
+// and restored with SetCorrection after a restart.
+func (c *Clock) Correction() (drift int32, reference time.Time) {
+	return c.drift, c.reference
+}
+
+// SetCorrection restores a correction returned by Correction.
+func (c *Clock) SetCorrection(drift int32, reference time.Time) {
+	c.drift = drift
+	c.reference = reference
+	c.referenced = !reference.IsZero()
+}
+
+// correct removes the drift since the last reference from t, a time of the
+// RTC.
+func (c *Clock) correct(t time.Time) time.Time {
+	if !c.referenced {
+		return t
+	}
+	// A drift in ppb is a correction in ns per second.
+	elapsed := int64(t.Sub(c.reference) / time.Second)
+	return t.Add(-time.Duration(elapsed * int64(c.drift)))
+}
+
+// tick waits for the next second of the RTC, and returns the new time of the
+// RTC and the monotonic time at which it changed.
+func (c *Clock) tick() (time.Time, time.Time, error) {
+	first, err := c.rtc.ReadTime()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start := c.now()
+	prev := start
+	for {
+		c.sleep(pollInterval)
+		t, err := c.rtc.ReadTime()
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		at := c.now()
+		if !t.Equal(first) {
+			// The second changed between the last two reads.
+			return t, prev.Add(at.Sub(prev) / 2), nil
+		}
+		if at.Sub(start) > 1500*time.Millisecond {
+			return time.Time{}, time.Time{}, errStopped
+		}
+		prev = at
+	}
+}
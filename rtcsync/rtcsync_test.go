@@ -0,0 +1,160 @@
+package rtcsync
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+)
+
+// fakeRTC is an RTC that runs fast by drift ppb on a simulated monotonic
+// clock, which advances when the Clock sleeps.
+type fakeRTC struct {
+	mono    time.Time
+	drift   int64
+	set     time.Time
+	setMono time.Time
+	stopped bool
+}
+
+var _ drivers.RTC = (*fakeRTC)(nil)
+
+func (r *fakeRTC) SetTime(t time.Time) error {
+	r.set, r.setMono = t, r.mono
+	return nil
+}
+
+func (r *fakeRTC) ReadTime() (time.Time, error) {
+	if r.stopped {
+		return r.set, nil
+	}
+	return r.exact().Truncate(time.Second), nil
+}
+
+// exact returns the time of the RTC including the fraction of the second.
+func (r *fakeRTC) exact() time.Time {
+	elapsed := r.mono.Sub(r.setMono)
+	return r.set.Add(elapsed + time.Duration(int64(elapsed)/1000*r.drift/1000000))
+}
+
+// advance moves the simulated monotonic clock forward.
+func (r *fakeRTC) advance(d time.Duration) {
+	r.mono = r.mono.Add(d)
+}
+
+func newClock(rtc *fakeRTC) *Clock {
+	c := New(rtc)
+	c.now = func() time.Time { return rtc.mono }
+	c.sleep = rtc.advance
+	return c
+}
+
+func within(c *qt.C, got, want, tolerance time.Duration) {
+	c.Helper()
+	diff := got - want
+	if diff < 0 {
+		diff = -diff
+	}
+	c.Assert(diff <= tolerance, qt.IsTrue, qt.Commentf("got %v, want %v ± %v", got, want, tolerance))
+}
+
+func TestSync(t *testing.T) {
+	c := qt.New(t)
+	epoch := time.Date(2024, 2, 28, 23, 0, 0, 0, time.UTC)
+	rtc := &fakeRTC{mono: time.Unix(1000, 0), drift: 20000}
+	rtc.set, rtc.setMono = epoch, rtc.mono.Add(-300*time.Millisecond)
+	clock := newClock(rtc)
+	c.Assert(clock.Now().IsZero(), qt.IsTrue)
+
+	// The software clock follows the RTC, with the precision of the polling.
+	c.Assert(clock.Sync(), qt.IsNil)
+	within(c, clock.Now().Sub(rtc.exact()), 0, 5*time.Millisecond)
+	rtc.advance(3 * time.Hour)
+	c.Assert(clock.Sync(), qt.IsNil)
+	within(c, clock.Now().Sub(rtc.exact()), 0, 5*time.Millisecond)
+
+	// The first reference sets the RTC, but there is no drift to correct
+	// yet.
+	truth := func() time.Time { return epoch.Add(rtc.mono.Sub(time.Unix(1000, 0))) }
+	rtc.advance(1234 * time.Millisecond)
+	c.Assert(clock.SetReference(truth()), qt.IsNil)
+	within(c, clock.Now().Sub(truth()), 0, 10*time.Millisecond)
+	drift, reference := clock.Correction()
+	c.Assert(drift, qt.Equals, int32(0))
+	c.Assert(reference.Nanosecond(), qt.Equals, 0)
+	within(c, rtc.set.Sub(truth()), 0, 0)
+
+	// The second reference a day later measures the drift.
+	rtc.advance(24 * time.Hour)
+	c.Assert(clock.Sync(), qt.IsNil)
+	within(c, clock.Now().Sub(truth()), 1728*time.Millisecond, 10*time.Millisecond)
+	c.Assert(clock.SetReference(truth()), qt.IsNil)
+	drift, _ = clock.Correction()
+	c.Assert(drift > 19900 && drift < 20100, qt.IsTrue, qt.Commentf("drift %d", drift))
+
+	// The drift is corrected from then on, across the leap day.
+	rtc.advance(24 * time.Hour)
+	c.Assert(clock.Sync(), qt.IsNil)
+	within(c, clock.Now().Sub(truth()), 0, 20*time.Millisecond)
+	c.Assert(clock.Now().Month(), qt.Equals, time.March)
+
+	// A correction restored after a restart.
+	drift, reference = clock.Correction()
+	clock = newClock(rtc)
+	clock.SetCorrection(drift, reference)
+	rtc.advance(24 * time.Hour)
+	c.Assert(clock.Sync(), qt.IsNil)
+	within(c, clock.Now().Sub(truth()), 0, 20*time.Millisecond)
+
+	// References closer than an hour keep the drift, the reference and
+	// the RTC, and only realign the software clock.
+	rtc.advance(10 * time.Minute)
+	c.Assert(clock.SetReference(truth()), qt.IsNil)
+	drift, reference = clock.Correction()
+	c.Assert(drift > 19900 && drift < 20100, qt.IsTrue, qt.Commentf("drift %d", drift))
+	set := rtc.set
+	c.Assert(clock.SetReference(truth().Add(time.Second)), qt.IsNil)
+	within(c, clock.Now().Sub(truth()), time.Second, 10*time.Millisecond)
+	got, gotReference := clock.Correction()
+	c.Assert(got, qt.Equals, drift)
+	c.Assert(gotReference, qt.Equals, reference)
+	c.Assert(rtc.set, qt.Equals, set)
+}
+
+func TestSyncFrequentReferences(t *testing.T) {
+	c := qt.New(t)
+	epoch := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rtc := &fakeRTC{mono: time.Unix(1000, 0), drift: -15000, set: epoch}
+	rtc.setMono = rtc.mono
+	clock := newClock(rtc)
+	truth := func() time.Time { return epoch.Add(rtc.mono.Sub(time.Unix(1000, 0))) }
+
+	// A source called every 10 minutes measures the drift once the first
+	// reference is an hour old.
+	c.Assert(clock.SetReference(truth()), qt.IsNil)
+	_, first := clock.Correction()
+	for i := 0; i < 5; i++ {
+		rtc.advance(10 * time.Minute)
+		c.Assert(clock.SetReference(truth()), qt.IsNil)
+		within(c, clock.Now().Sub(truth()), 0, 10*time.Millisecond)
+		drift, reference := clock.Correction()
+		c.Assert(drift, qt.Equals, int32(0))
+		c.Assert(reference, qt.Equals, first)
+	}
+	rtc.advance(10 * time.Minute)
+	c.Assert(clock.SetReference(truth()), qt.IsNil)
+	drift, reference := clock.Correction()
+	// An hour measures the drift less precisely than a day.
+	c.Assert(drift > -16000 && drift < -14000, qt.IsTrue, qt.Commentf("drift %d", drift))
+	c.Assert(reference.After(first), qt.IsTrue)
+}
+
+func TestSyncStopped(t *testing.T) {
+	c := qt.New(t)
+	rtc := &fakeRTC{mono: time.Unix(1000, 0), stopped: true}
+	clock := newClock(rtc)
+	c.Assert(clock.Sync(), qt.Equals, errStopped)
+	c.Assert(clock.SetReference(time.Now()), qt.Equals, errStopped)
+	c.Assert(clock.Now().IsZero(), qt.IsTrue)
+}
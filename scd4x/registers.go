@@ -9,8 +9,12 @@ const (
 	CmdForcedRecal                      = 0x362F
 	CmdGetAltitude                      = 0x2322
 	CmdGetASCE                          = 0x2313
+	CmdGetPressure                      = 0xE000
 	CmdGetTempOffset                    = 0x2318
+	CmdMeasureSingleShot                = 0x219D
+	CmdMeasureSingleShotRHTOnly         = 0x2196
 	CmdPersistSettings                  = 0x3615
+	CmdPowerDown                        = 0x36E0
 	CmdReadMeasurement                  = 0xEC05
 	CmdReinit                           = 0x3646
 	CmdSelfTest                         = 0x3639
@@ -22,4 +26,5 @@ const (
 	CmdStartLowPowerPeriodicMeasurement = 0x21AC
 	CmdStartPeriodicMeasurement         = 0x21B1
 	CmdStopPeriodicMeasurement          = 0x3F86
+	CmdWakeUp                           = 0x36F6
 )
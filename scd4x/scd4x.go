@@ -9,9 +9,20 @@ package scd4x // import "tinygo.org/x/drivers/scd4x"
 
 import (
 	"encoding/binary"
+	"errors"
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/sensirion"
+)
+
+// ErrChecksum is returned when the CRC of data read from the sensor does
+// not match, usually because of noise on the bus.
+var ErrChecksum = errors.New("scd4x: checksum mismatch")
+
+var (
+	errRecalibration = errors.New("scd4x: forced recalibration failed")
+	errSelfTest      = errors.New("scd4x: self test detected a malfunction")
 )
 
 type Device struct {
@@ -44,12 +55,7 @@ func (d *Device) Configure() (err error) {
 	time.Sleep(500 * time.Millisecond)
 
 	// reset the chip
-	if err := d.sendCommand(CmdReinit); err != nil {
-		return err
-	}
-
-	time.Sleep(20 * time.Millisecond)
-	return
+	return d.Reinit()
 }
 
 // Connected returns whether sensor has been found.
@@ -60,10 +66,11 @@ func (d *Device) Connected() bool {
 
 // DataReady checks the sensor to see if new data is available.
 func (d *Device) DataReady() (bool, error) {
-	if err := d.sendCommandWithResult(CmdDataReady, d.rx[0:3]); err != nil {
+	var result [1]uint16
+	if err := d.sendCommandWithWords(CmdDataReady, result[:]); err != nil {
 		return false, err
 	}
-	return !(d.rx[0]&0x07 == 0 && d.rx[1] == 0), nil
+	return result[0]&0x07FF != 0, nil
 }
 
 // StartPeriodicMeasurement puts the sensor into working mode, about 5s per measurement.
@@ -84,12 +91,11 @@ func (d *Device) StartLowPowerPeriodicMeasurement() error {
 
 // ReadData reads the data from the sensor and caches it.
 func (d *Device) ReadData() error {
-	if err := d.sendCommandWithResult(CmdReadMeasurement, d.rx[0:9]); err != nil {
+	var result [3]uint16
+	if err := d.sendCommandWithWords(CmdReadMeasurement, result[:]); err != nil {
 		return err
 	}
-	d.co2 = binary.BigEndian.Uint16(d.rx[0:2])
-	d.temperature = binary.BigEndian.Uint16(d.rx[3:5])
-	d.humidity = binary.BigEndian.Uint16(d.rx[6:8])
+	d.co2, d.temperature, d.humidity = result[0], result[1], result[2]
 	return nil
 }
 
@@ -142,6 +148,258 @@ func (d *Device) ReadHumidity() (humidity int32, err error) {
 	return (25 * int32(d.humidity)) / 16384, err
 }
 
+// Update reads the latest measurement if the sensor has a new one, and
+// returns drivers.ErrNotReady otherwise. The sensor must be in periodic
+// measurement mode. Use CO2, Temperature and Humidity to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Humidity) == 0 {
+		return nil
+	}
+	ok, err := d.DataReady()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return drivers.ErrNotReady
+	}
+	return d.ReadData()
+}
+
+// CO2 returns the CO2 concentration in PPM (parts per million) read by the
+// last call to Update or ReadData.
+func (d *Device) CO2() int32 {
+	return int32(d.co2)
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update or ReadData.
+func (d *Device) Temperature() int32 {
+	return (-1 * 45000) + (21875 * (int32(d.temperature)) / 8192)
+}
+
+// Humidity returns the relative humidity in hundredths of a percent read by
+// the last call to Update or ReadData.
+func (d *Device) Humidity() int32 {
+	// humidity = 10000 * value / 2¹⁶
+	return (625 * int32(d.humidity)) / 4096
+}
+
+// Reinit reloads the settings stored with PersistSettings. The sensor must
+// not be measuring.
+func (d *Device) Reinit() error {
+	if err := d.sendCommand(CmdReinit); err != nil {
+		return err
+	}
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+// MeasureSingleShot starts a single measurement of CO2, temperature and
+// humidity and waits 5s for it to complete. Use ReadData to read it. The
+// sensor must not be measuring. Only the SCD41 supports single shot
+// measurements.
+func (d *Device) MeasureSingleShot() error {
+	if err := d.sendCommand(CmdMeasureSingleShot); err != nil {
+		return err
+	}
+	time.Sleep(5000 * time.Millisecond)
+	return nil
+}
+
+// MeasureSingleShotRHTOnly starts a single measurement of temperature and
+// humidity and waits 50ms for it to complete. Use ReadData to read it; the
+// CO2 concentration read is zero. Only the SCD41 supports single shot
+// measurements.
+func (d *Device) MeasureSingleShotRHTOnly() error {
+	if err := d.sendCommand(CmdMeasureSingleShotRHTOnly); err != nil {
+		return err
+	}
+	time.Sleep(50 * time.Millisecond)
+	return nil
+}
+
+// PowerDown puts the sensor into sleep mode, in which it draws less than
+// 1µA. The sensor must not be measuring. Only the SCD41 supports sleep mode.
+func (d *Device) PowerDown() error {
+	if err := d.sendCommand(CmdPowerDown); err != nil {
+		return err
+	}
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+// WakeUp wakes the sensor up from sleep mode. The sensor does not
+// acknowledge the command, so it cannot report whether it woke up; read
+// the serial number to check. Only the SCD41 supports sleep mode.
+func (d *Device) WakeUp() error {
+	d.sendCommand(CmdWakeUp)
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+// PerformForcedRecalibration recalibrates the sensor to the given CO2
+// concentration in PPM, and returns the correction it applied in PPM. The
+// sensor must have been measuring for at least 3 minutes in an environment
+// of a homogeneous and constant CO2 concentration, and must be stopped with
+// StopPeriodicMeasurement before recalibration.
+func (d *Device) PerformForcedRecalibration(ppm uint16) (correction int16, err error) {
+	if err := d.sendCommandWithValue(CmdForcedRecal, ppm); err != nil {
+		return 0, err
+	}
+	time.Sleep(400 * time.Millisecond)
+	var result [1]uint16
+	if err := d.readWords(result[:]); err != nil {
+		return 0, err
+	}
+	if result[0] == 0xFFFF {
+		return 0, errRecalibration
+	}
+	return int16(int32(result[0]) - 0x8000), nil
+}
+
+// SetAutomaticSelfCalibration enables or disables the automatic self
+// calibration, which assumes the sensor is exposed to fresh air of 400 PPM
+// at least once a week. It is enabled by default. The sensor must not be
+// measuring. Use PersistSettings to keep the setting after a power cycle.
+func (d *Device) SetAutomaticSelfCalibration(enabled bool) error {
+	var value uint16
+	if enabled {
+		value = 1
+	}
+	return d.sendCommandWithValue(CmdSetASCE, value)
+}
+
+// GetAutomaticSelfCalibration returns whether automatic self calibration
+// is enabled. The sensor must not be measuring.
+func (d *Device) GetAutomaticSelfCalibration() (bool, error) {
+	var result [1]uint16
+	if err := d.sendCommandWithWords(CmdGetASCE, result[:]); err != nil {
+		return false, err
+	}
+	return result[0] != 0, nil
+}
+
+// SetTemperatureOffset sets the offset in celsius milli degrees (°C/1000)
+// by which the sensor is warmer than the ambient air, to correct the
+// temperature and humidity readings. Offsets below zero are treated as
+// zero. The default offset is 4°C. The sensor must not be measuring. Use
+// PersistSettings to keep the setting after a power cycle.
+func (d *Device) SetTemperatureOffset(offset int32) error {
+	if offset < 0 {
+		offset = 0
+	}
+	// value = offset * 2¹⁶ / 175
+	value := (int64(offset)*65536 + 87500) / 175000
+	if value > 0xFFFF {
+		value = 0xFFFF
+	}
+	return d.sendCommandWithValue(CmdSetTempOffset, uint16(value))
+}
+
+// GetTemperatureOffset returns the temperature offset in celsius milli
+// degrees (°C/1000). The sensor must not be measuring.
+func (d *Device) GetTemperatureOffset() (int32, error) {
+	var result [1]uint16
+	if err := d.sendCommandWithWords(CmdGetTempOffset, result[:]); err != nil {
+		return 0, err
+	}
+	// offset = 175 * value / 2¹⁶
+	return int32((int64(result[0])*175000 + 32768) / 65536), nil
+}
+
+// SetSensorAltitude sets the altitude of the sensor in meters above sea
+// level, which is used to compensate the CO2 readings for the ambient
+// pressure. The default altitude is 0m. The sensor must not be measuring.
+// Use PersistSettings to keep the setting after a power cycle.
+func (d *Device) SetSensorAltitude(altitude uint16) error {
+	return d.sendCommandWithValue(CmdSetAltitude, altitude)
+}
+
+// GetSensorAltitude returns the altitude of the sensor in meters above sea
+// level. The sensor must not be measuring.
+func (d *Device) GetSensorAltitude() (uint16, error) {
+	var result [1]uint16
+	if err := d.sendCommandWithWords(CmdGetAltitude, result[:]); err != nil {
+		return 0, err
+	}
+	return result[0], nil
+}
+
+// SetAmbientPressure sets the ambient pressure in milli pascal, which is
+// used to compensate the CO2 readings instead of the sensor altitude. The
+// sensor may be measuring, so the pressure can be updated continuously,
+// for example from a barometer. The pressure is rounded to whole hPa.
+func (d *Device) SetAmbientPressure(pressure int32) error {
+	// The sensor takes the pressure in hPa.
+	value := (pressure + 50000) / 100000
+	if value < 0 {
+		value = 0
+	} else if value > 0xFFFF {
+		value = 0xFFFF
+	}
+	return d.sendCommandWithValue(CmdSetPressure, uint16(value))
+}
+
+// GetAmbientPressure returns the ambient pressure in milli pascal used to
+// compensate the CO2 readings.
+func (d *Device) GetAmbientPressure() (int32, error) {
+	var result [1]uint16
+	if err := d.sendCommandWithWords(CmdGetPressure, result[:]); err != nil {
+		return 0, err
+	}
+	return int32(result[0]) * 100000, nil
+}
+
+// PersistSettings stores the temperature offset, sensor altitude,
+// automatic self calibration setting and the forced recalibration in the
+// EEPROM of the sensor, which can be written at least 2000 times. The
+// sensor must not be measuring.
+func (d *Device) PersistSettings() error {
+	if err := d.sendCommand(CmdPersistSettings); err != nil {
+		return err
+	}
+	time.Sleep(800 * time.Millisecond)
+	return nil
+}
+
+// ReadSerialNumber returns the 48-bit serial number of the sensor. The
+// sensor must not be measuring.
+func (d *Device) ReadSerialNumber() (uint64, error) {
+	var result [3]uint16
+	if err := d.sendCommandWithWords(CmdSerialNumber, result[:]); err != nil {
+		return 0, err
+	}
+	return uint64(result[0])<<32 | uint64(result[1])<<16 | uint64(result[2]), nil
+}
+
+// SelfTest checks the sensor and returns an error if it detects a
+// malfunction. It takes 10s. The sensor must not be measuring.
+func (d *Device) SelfTest() error {
+	if err := d.sendCommand(CmdSelfTest); err != nil {
+		return err
+	}
+	time.Sleep(10 * time.Second)
+	var result [1]uint16
+	if err := d.readWords(result[:]); err != nil {
+		return err
+	}
+	if result[0] != 0 {
+		return errSelfTest
+	}
+	return nil
+}
+
+// FactoryReset resets all settings to their defaults, and erases the forced
+// recalibration and the automatic self calibration history. The sensor must
+// not be measuring.
+func (d *Device) FactoryReset() error {
+	if err := d.sendCommand(CmdFactoryReset); err != nil {
+		return err
+	}
+	time.Sleep(1200 * time.Millisecond)
+	return nil
+}
+
 func (d *Device) sendCommand(command uint16) error {
 	binary.BigEndian.PutUint16(d.tx[0:], command)
 	return d.bus.Tx(uint16(d.Address), d.tx[0:2], nil)
@@ -149,31 +407,30 @@ func (d *Device) sendCommand(command uint16) error {
 
 func (d *Device) sendCommandWithValue(command, value uint16) error {
 	binary.BigEndian.PutUint16(d.tx[0:], command)
-	binary.BigEndian.PutUint16(d.tx[2:], value)
-	d.tx[4] = crc8(d.tx[2:4])
+	sensirion.PutWord(d.tx[2:], value)
 	return d.bus.Tx(uint16(d.Address), d.tx[0:5], nil)
 }
 
-func (d *Device) sendCommandWithResult(command uint16, result []byte) error {
+// sendCommandWithWords sends a command and reads its result of len(words)
+// words.
+func (d *Device) sendCommandWithWords(command uint16, words []uint16) error {
 	binary.BigEndian.PutUint16(d.tx[0:], command)
 	if err := d.bus.Tx(uint16(d.Address), d.tx[0:2], nil); err != nil {
 		return err
 	}
 	time.Sleep(time.Millisecond)
-	return d.bus.Tx(uint16(d.Address), nil, result)
-}
-
-func crc8(buf []byte) uint8 {
-	var crc uint8 = 0xff
-	for _, b := range buf {
-		crc ^= b
-		for i := 0; i < 8; i++ {
-			if crc&0x80 != 0 {
-				crc = (crc << 1) ^ 0x31
-			} else {
-				crc <<= 1
-			}
-		}
-	}
-	return crc & 0xff
+	return d.readWords(words)
+}
+
+// readWords reads len(words) words, each followed by its CRC, of the result
+// of the last command.
+func (d *Device) readWords(words []uint16) error {
+	data := d.rx[:len(words)*3]
+	if err := d.bus.Tx(uint16(d.Address), nil, data); err != nil {
+		return err
+	}
+	if !sensirion.DecodeWords(data, words) {
+		return ErrChecksum
+	}
+	return nil
 }
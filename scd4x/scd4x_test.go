@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/sensirion"
 	"tinygo.org/x/drivers/tester"
 )
 
@@ -13,3 +15,186 @@ func TestDefaultI2CAddress(t *testing.T) {
 	dev := New(bus)
 	c.Assert(dev.Address, qt.Equals, uint8(Address))
 }
+
+// command returns a command with the given arguments, each followed by its
+// CRC, that responds with the given words, each followed by its CRC.
+func command(cmd uint16, args []uint16, response ...uint16) *tester.Cmd {
+	c := &tester.Cmd{
+		Command:  append([]byte{byte(cmd >> 8), byte(cmd)}, words(args...)...),
+		Response: words(response...),
+	}
+	c.Mask = make([]byte, len(c.Command))
+	for i := range c.Mask {
+		c.Mask[i] = 0xFF
+	}
+	return c
+}
+
+func words(values ...uint16) []byte {
+	data := make([]byte, len(values)*3)
+	for i, v := range values {
+		sensirion.PutWord(data[i*3:], v)
+	}
+	return data
+}
+
+func newDevice(c *qt.C, commands ...*tester.Cmd) (*Device, *tester.I2CDeviceCmd) {
+	bus := tester.NewI2CBus(c)
+	fdev := tester.NewI2CDeviceCmd(c, Address)
+	fdev.Commands = map[uint8]*tester.Cmd{}
+	for i, cmd := range commands {
+		fdev.Commands[uint8(i)] = cmd
+	}
+	bus.AddDevice(fdev)
+	return New(bus), fdev
+}
+
+func TestReadData(t *testing.T) {
+	c := qt.New(t)
+	ready := command(CmdDataReady, nil, 0x8006)
+	measurement := command(CmdReadMeasurement, nil, 0x01F4, 0x6666, 0x5EB9)
+	dev, _ := newDevice(c, ready, measurement)
+
+	co2, err := dev.ReadCO2()
+	c.Assert(err, qt.IsNil)
+	c.Assert(co2, qt.Equals, int32(500))
+	c.Assert(dev.Temperature(), qt.Equals, int32(24998))
+	c.Assert(dev.Humidity(), qt.Equals, int32(3700))
+	c.Assert(measurement.Invocations, qt.Equals, 1)
+}
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	ready := command(CmdDataReady, nil, 0x8000)
+	measurement := command(CmdReadMeasurement, nil, 0x01F4, 0x6666, 0x5EB9)
+	dev, _ := newDevice(c, ready, measurement)
+
+	err := dev.Update(drivers.Temperature | drivers.Humidity)
+	c.Assert(err, qt.Equals, drivers.ErrNotReady)
+	c.Assert(measurement.Invocations, qt.Equals, 0)
+
+	ready.Response = words(0x8006)
+	c.Assert(dev.Update(drivers.Temperature|drivers.Humidity), qt.IsNil)
+	c.Assert(dev.Temperature(), qt.Equals, int32(24998))
+	c.Assert(dev.Humidity(), qt.Equals, int32(3700))
+}
+
+func TestForcedRecalibration(t *testing.T) {
+	c := qt.New(t)
+	frc := command(CmdForcedRecal, []uint16{420}, 0x8000-35)
+	dev, _ := newDevice(c, frc)
+	correction, err := dev.PerformForcedRecalibration(420)
+	c.Assert(err, qt.IsNil)
+	c.Assert(correction, qt.Equals, int16(-35))
+
+	frc.Response = words(0xFFFF)
+	_, err = dev.PerformForcedRecalibration(420)
+	c.Assert(err, qt.Equals, errRecalibration)
+}
+
+func TestSettings(t *testing.T) {
+	c := qt.New(t)
+	setASC := command(CmdSetASCE, []uint16{0})
+	setOffset := command(CmdSetTempOffset, []uint16{0x0925})
+	setAltitude := command(CmdSetAltitude, []uint16{1600})
+	persist := command(CmdPersistSettings, nil)
+	dev, _ := newDevice(c,
+		setASC,
+		command(CmdGetASCE, nil, 0),
+		setOffset,
+		command(CmdGetTempOffset, nil, 0x0925),
+		setAltitude,
+		command(CmdGetAltitude, nil, 1600),
+		persist,
+	)
+
+	c.Assert(dev.SetAutomaticSelfCalibration(false), qt.IsNil)
+	c.Assert(setASC.Invocations, qt.Equals, 1)
+	enabled, err := dev.GetAutomaticSelfCalibration()
+	c.Assert(err, qt.IsNil)
+	c.Assert(enabled, qt.IsFalse)
+
+	// 6.25°C is 0x0925 (2340.57).
+	c.Assert(dev.SetTemperatureOffset(6250), qt.IsNil)
+	c.Assert(setOffset.Invocations, qt.Equals, 1)
+	offset, err := dev.GetTemperatureOffset()
+	c.Assert(err, qt.IsNil)
+	c.Assert(offset, qt.Equals, int32(6251))
+
+	c.Assert(dev.SetSensorAltitude(1600), qt.IsNil)
+	c.Assert(setAltitude.Invocations, qt.Equals, 1)
+	altitude, err := dev.GetSensorAltitude()
+	c.Assert(err, qt.IsNil)
+	c.Assert(altitude, qt.Equals, uint16(1600))
+
+	c.Assert(dev.PersistSettings(), qt.IsNil)
+	c.Assert(persist.Invocations, qt.Equals, 1)
+}
+
+func TestAmbientPressure(t *testing.T) {
+	c := qt.New(t)
+	set := command(CmdSetPressure, []uint16{835})
+	dev, fdev := newDevice(c, set)
+	c.Assert(dev.SetAmbientPressure(83_460_000), qt.IsNil)
+	c.Assert(set.Invocations, qt.Equals, 1)
+
+	// Reading the pressure sends the command without an argument.
+	fdev.Commands[0] = command(CmdGetPressure, nil, 835)
+	pressure, err := dev.GetAmbientPressure()
+	c.Assert(err, qt.IsNil)
+	c.Assert(pressure, qt.Equals, int32(83_500_000))
+}
+
+func TestSerialNumber(t *testing.T) {
+	c := qt.New(t)
+	serial := command(CmdSerialNumber, nil, 0xF896, 0x9F07, 0x3BBE)
+	dev, _ := newDevice(c, serial)
+	n, err := dev.ReadSerialNumber()
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, uint64(0xF8969F073BBE))
+
+	serial.Response[5] ^= 1
+	_, err = dev.ReadSerialNumber()
+	c.Assert(err, qt.Equals, ErrChecksum)
+}
+
+func TestSingleShot(t *testing.T) {
+	c := qt.New(t)
+	rht := command(CmdMeasureSingleShotRHTOnly, nil)
+	powerDown := command(CmdPowerDown, nil)
+	wakeUp := command(CmdWakeUp, nil)
+	dev, _ := newDevice(c,
+		rht,
+		command(CmdReadMeasurement, nil, 0, 0x6666, 0x5EB9),
+		powerDown,
+		wakeUp,
+	)
+	c.Assert(dev.MeasureSingleShotRHTOnly(), qt.IsNil)
+	c.Assert(dev.ReadData(), qt.IsNil)
+	c.Assert(rht.Invocations, qt.Equals, 1)
+	c.Assert(dev.CO2(), qt.Equals, int32(0))
+	c.Assert(dev.Temperature(), qt.Equals, int32(24998))
+
+	c.Assert(dev.PowerDown(), qt.IsNil)
+	c.Assert(powerDown.Invocations, qt.Equals, 1)
+	c.Assert(dev.WakeUp(), qt.IsNil)
+	c.Assert(wakeUp.Invocations, qt.Equals, 1)
+}
+
+func TestFactoryReset(t *testing.T) {
+	c := qt.New(t)
+	reset := command(CmdFactoryReset, nil)
+	dev, _ := newDevice(c, reset)
+	c.Assert(dev.FactoryReset(), qt.IsNil)
+	c.Assert(reset.Invocations, qt.Equals, 1)
+}
+
+func TestSelfTest(t *testing.T) {
+	c := qt.New(t)
+	if testing.Short() {
+		c.Skip("the self test takes 10s")
+	}
+	selfTest := command(CmdSelfTest, nil, 0x0001)
+	dev, _ := newDevice(c, selfTest)
+	c.Assert(dev.SelfTest(), qt.Equals, errSelfTest)
+}
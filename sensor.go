@@ -0,0 +1,91 @@
+package drivers
+
+// Measurement is a set of physical quantities, used to select what a
+// Sensor reads in Update. Values can be combined with the | operator.
+type Measurement uint32
+
+const (
+	Temperature Measurement = 1 << iota
+	Humidity
+	Pressure
+	Acceleration
+	AngularVelocity
+	MagneticField
+	Illuminance
+
+	// AllMeasurements selects every quantity the sensor supports.
+	AllMeasurements Measurement = 1<<iota - 1
+)
+
+// Sensor is a device that measures one or more physical quantities.
+//
+// Update reads the quantities selected by which from the device, in as few
+// bus transactions as the device allows, and stores them in the driver.
+// Quantities the sensor does not measure are ignored. The stored values are
+// returned by accessor methods, such as Temperature, which do not access the
+// bus, so all values returned after an Update belong to the same reading.
+// Devices that measure on their own schedule return ErrNotReady from Update
+// when they have no new reading.
+type Sensor interface {
+	Update(which Measurement) error
+}
+
+// Thermometer is a Sensor that measures temperature.
+type Thermometer interface {
+	Sensor
+
+	// Temperature returns the temperature in milli-degrees Celsius.
+	Temperature() int32
+}
+
+// Hygrometer is a Sensor that measures relative humidity.
+type Hygrometer interface {
+	Sensor
+
+	// Humidity returns the relative humidity in hundredths of a percent.
+	Humidity() int32
+}
+
+// Barometer is a Sensor that measures air pressure.
+type Barometer interface {
+	Sensor
+
+	// Pressure returns the pressure in milli-pascals.
+	Pressure() int32
+}
+
+// Accelerometer is a Sensor that measures acceleration.
+type Accelerometer interface {
+	Sensor
+
+	// Acceleration returns the acceleration along each axis in µg
+	// (micro-gravity). An axis pointing straight to Earth on a sensor that
+	// is not moving reads around 1000000 or -1000000.
+	Acceleration() (x, y, z int32)
+}
+
+// Gyroscope is a Sensor that measures angular velocity.
+type Gyroscope interface {
+	Sensor
+
+	// AngularVelocity returns the rotation around each axis in µ°/s
+	// (micro-degrees per second).
+	AngularVelocity() (x, y, z int32)
+}
+
+// Magnetometer is a Sensor that measures the magnetic field.
+type Magnetometer interface {
+	Sensor
+
+	// MagneticField returns the magnetic field along each axis in nT
+	// (nanotesla). 1 G (gauss) is 100000 nT.
+	MagneticField() (x, y, z int32)
+}
+
+// LightSensor is a Sensor that measures illuminance.
+type LightSensor interface {
+	Sensor
+
+	// LightLevel returns the illuminance in mlx (milli-lux).
+	LightLevel() int32
+}
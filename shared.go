@@ -0,0 +1,199 @@
+package drivers
+
+import "sync"
+
+// SharedI2C is an I2C bus that can be used by drivers running in different
+// goroutines. Every transaction holds a lock on the underlying bus, so
+// transactions of different devices are never interleaved.
+type SharedI2C struct {
+	mu  sync.Mutex
+	bus I2C
+}
+
+// NewSharedI2C returns a SharedI2C that serializes access to bus. All
+// drivers on the bus must use the returned value instead of bus.
+func NewSharedI2C(bus I2C) *SharedI2C {
+	return &SharedI2C{bus: bus}
+}
+
+// ReadRegister implements I2C.ReadRegister.
+func (s *SharedI2C) ReadRegister(addr uint8, r uint8, buf []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bus.ReadRegister(addr, r, buf)
+}
+
+// WriteRegister implements I2C.WriteRegister.
+func (s *SharedI2C) WriteRegister(addr uint8, r uint8, buf []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bus.WriteRegister(addr, r, buf)
+}
+
+// Tx implements I2C.Tx.
+func (s *SharedI2C) Tx(addr uint16, w, r []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bus.Tx(addr, w, r)
+}
+
+// SPIConfig holds the bus settings of a device on a SharedSPI.
+type SPIConfig struct {
+	Frequency uint32
+	Mode      uint8
+	LSBFirst  bool
+}
+
+// SharedSPI is an SPI bus that can be used by several devices, each with
+// its own chip select pin and bus settings, from different goroutines.
+type SharedSPI struct {
+	mu        sync.Mutex
+	bus       SPI
+	configure func(SPIConfig) error
+	current   *SPIDevice
+}
+
+// NewSharedSPI returns a SharedSPI that serializes access to bus.
+//
+// configure is called to apply the settings of a device whenever a device
+// other than the previous one starts a transaction. It usually wraps
+// machine.SPI.Configure. If it is nil, device settings are ignored.
+func NewSharedSPI(bus SPI, configure func(SPIConfig) error) *SharedSPI {
+	return &SharedSPI{
+		bus:       bus,
+		configure: configure,
+	}
+}
+
+// Device returns a handle for a device on the bus. The handle implements
+// SPI and can be passed to any driver. cs may be nil if the device has no
+// chip select or the driver controls it itself.
+func (s *SharedSPI) Device(cs Pin, config SPIConfig) *SPIDevice {
+	d := &SPIDevice{
+		shared: s,
+		cs:     cs,
+		config: config,
+	}
+	if !IsNoPin(cs) {
+		ConfigurePin(cs, PinOutput)
+		cs.Set(true)
+	}
+	return d
+}
+
+// SPIDevice is a device on a SharedSPI.
+//
+// Every Tx or Transfer outside of a transaction locks the bus, applies the
+// device settings and asserts chip select for the duration of the call.
+// Drivers that send several buffers within one chip select frame should
+// either call Begin and End around them, or control the pin returned by CS
+// as their chip select pin.
+//
+// A SPIDevice must only be used from one goroutine at a time.
+type SPIDevice struct {
+	shared *SharedSPI
+	cs     Pin
+	config SPIConfig
+	active bool
+
+	// err is the error of the Begin started by the CS pin, returned by Tx
+	// and Transfer until the pin is released.
+	err error
+}
+
+// Begin starts a transaction: it locks the bus, applies the device
+// settings and asserts chip select. Calling Begin during a transaction
+// has no effect.
+func (d *SPIDevice) Begin() error {
+	if d.active {
+		return nil
+	}
+	s := d.shared
+	s.mu.Lock()
+	if s.current != d && s.configure != nil {
+		if err := s.configure(d.config); err != nil {
+			s.current = nil
+			s.mu.Unlock()
+			return err
+		}
+	}
+	s.current = d
+	d.active = true
+	if !IsNoPin(d.cs) {
+		d.cs.Set(false)
+	}
+	return nil
+}
+
+// End ends a transaction: it releases chip select and unlocks the bus.
+// Calling End outside of a transaction has no effect.
+func (d *SPIDevice) End() {
+	if !d.active {
+		return
+	}
+	if !IsNoPin(d.cs) {
+		d.cs.Set(true)
+	}
+	d.active = false
+	d.shared.mu.Unlock()
+}
+
+// Tx implements SPI.Tx.
+func (d *SPIDevice) Tx(w, r []byte) error {
+	if d.err != nil {
+		return d.err
+	}
+	if !d.active {
+		if err := d.Begin(); err != nil {
+			return err
+		}
+		defer d.End()
+	}
+	return d.shared.bus.Tx(w, r)
+}
+
+// Transfer implements SPI.Transfer.
+func (d *SPIDevice) Transfer(b byte) (byte, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+	if !d.active {
+		if err := d.Begin(); err != nil {
+			return 0, err
+		}
+		defer d.End()
+	}
+	return d.shared.bus.Transfer(b)
+}
+
+// CS returns a pin that drivers can use as their chip select pin: driving
+// it low begins a transaction and driving it high ends it. If the device
+// settings cannot be applied, every Tx and Transfer until the pin is driven
+// high returns the error.
+func (d *SPIDevice) CS() Pin {
+	return spiDeviceCS{d}
+}
+
+// spiDeviceCS is the chip select pin of a SPIDevice.
+type spiDeviceCS struct {
+	d *SPIDevice
+}
+
+func (p spiDeviceCS) Set(high bool) {
+	if high {
+		p.d.err = nil
+		p.d.End()
+	} else if !p.d.active {
+		// Set cannot return the error, so the transfers of the frame do.
+		p.d.err = p.d.Begin()
+	}
+}
+
+func (p spiDeviceCS) Get() bool {
+	return !p.d.active
+}
+
+// Configure implements ConfigurablePin. The chip select is always an
+// output, so it does nothing.
+func (p spiDeviceCS) Configure(mode PinMode) {
+}
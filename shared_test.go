@@ -0,0 +1,127 @@
+package drivers_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestSharedI2C(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	a := bus.NewDevice(0x44)
+	b := bus.NewDevice(0x76)
+	a.Registers[0] = 0x11
+	b.Registers[0] = 0x22
+	shared := drivers.NewSharedI2C(bus)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(addr uint8, want byte) {
+			defer wg.Done()
+			buf := []byte{0}
+			for j := 0; j < 100; j++ {
+				shared.Tx(uint16(addr), []byte{0}, buf)
+				if buf[0] != want {
+					t.Errorf("read %#x from %#x, want %#x", buf[0], addr, want)
+					return
+				}
+			}
+		}([]uint8{0x44, 0x76}[i%2], []byte{0x11, 0x22}[i%2])
+	}
+	wg.Wait()
+}
+
+// selectOn makes the scripted device follow the level of its chip select
+// pin.
+func selectOn(cs *tester.Pin, d *tester.SPIDeviceScript) {
+	cs.SetInterrupt(drivers.PinToggle, func(p drivers.Pin) {
+		if p.Get() {
+			d.Deselect()
+		} else {
+			d.Select()
+		}
+	})
+}
+
+func TestSharedSPI(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewSPIBus(c)
+	flash := bus.NewDevice()
+	can := bus.NewDevice()
+	csFlash := tester.NewPin()
+	csCAN := tester.NewPin()
+	selectOn(csFlash, flash)
+	selectOn(csCAN, can)
+
+	var configs []drivers.SPIConfig
+	shared := drivers.NewSharedSPI(bus, func(cfg drivers.SPIConfig) error {
+		configs = append(configs, cfg)
+		return nil
+	})
+	flashCfg := drivers.SPIConfig{Frequency: 8000000}
+	canCfg := drivers.SPIConfig{Frequency: 1000000, Mode: 0}
+	f := shared.Device(csFlash, flashCfg)
+	m := shared.Device(csCAN, canCfg)
+	c.Assert(csFlash.Get(), qt.IsTrue)
+
+	flash.Expect([]byte{0x9f}, nil)
+	flash.Expect([]byte{0x03}, nil)
+	can.Expect([]byte{0xc0}, nil)
+	can.Expect([]byte{0x03, 0x0e}, []byte{0, 0x80})
+
+	f.Transfer(0x9f)
+	f.Transfer(0x03)
+	m.Transfer(0xc0)
+
+	// A driver controlling chip select through CS keeps both writes in
+	// one frame.
+	cs := m.CS()
+	cs.Set(false)
+	buf := []byte{0x03, 0x0e}
+	m.Tx(buf, buf)
+	cs.Set(true)
+	c.Assert(buf[1], qt.Equals, byte(0x80))
+
+	flash.AssertDone()
+	can.AssertDone()
+	c.Assert(configs, qt.DeepEquals, []drivers.SPIConfig{flashCfg, canCfg})
+	c.Assert(flash.Transactions[1].Frame, qt.Equals, 2)
+	c.Assert(can.Transactions[1].Frame, qt.Equals, 2)
+	c.Assert(csCAN.Get(), qt.IsTrue)
+}
+
+func TestSharedSPIConfigureError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewSPIBus(c)
+	dev := bus.NewDevice()
+	configErr := errors.New("bad frequency")
+	shared := drivers.NewSharedSPI(bus, func(cfg drivers.SPIConfig) error {
+		return configErr
+	})
+	m := shared.Device(nil, drivers.SPIConfig{Frequency: 100e6})
+
+	// The error of the frame started by CS is returned by its transfers.
+	cs := m.CS()
+	cs.Set(false)
+	_, err := m.Transfer(0x03)
+	c.Assert(err, qt.Equals, configErr)
+	c.Assert(m.Tx([]byte{0x0e}, nil), qt.Equals, configErr)
+	cs.Set(true)
+
+	shared = drivers.NewSharedSPI(bus, nil)
+	m = shared.Device(nil, drivers.SPIConfig{})
+	dev.Expect([]byte{0x03}, nil)
+	dev.Expect([]byte{0x0e}, nil)
+	cs = m.CS()
+	cs.Set(false)
+	m.Transfer(0x03)
+	c.Assert(m.Tx([]byte{0x0e}, nil), qt.IsNil)
+	cs.Set(true)
+	dev.AssertDone()
+}
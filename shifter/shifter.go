@@ -3,7 +3,8 @@ package shifter // import "tinygo.org/x/drivers/shifter"
 
 import (
 	"errors"
-	"machine"
+
+	"tinygo.org/x/drivers"
 )
 
 const (
@@ -16,22 +17,22 @@ type NumberBit int8
 
 // Device holds the Pins.
 type Device struct {
-	latch machine.Pin
-	clk   machine.Pin
-	out   machine.Pin
+	latch drivers.Pin
+	clk   drivers.Pin
+	out   drivers.Pin
 	Pins  []ShiftPin
 	bits  NumberBit
 }
 
 // ShiftPin is the implementation of the ShiftPin interface.
 type ShiftPin struct {
-	pin     machine.Pin
+	pin     int
 	d       *Device
 	pressed bool
 }
 
 // New returns a new shifter driver given the correct pins.
-func New(numBits NumberBit, latch, clk, out machine.Pin) Device {
+func New(numBits NumberBit, latch, clk, out drivers.Pin) Device {
 	return Device{
 		latch: latch,
 		clk:   clk,
@@ -43,9 +44,9 @@ func New(numBits NumberBit, latch, clk, out machine.Pin) Device {
 
 // Configure here just for interface compatibility.
 func (d *Device) Configure() {
-	d.latch.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	d.clk.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	d.out.Configure(machine.PinConfig{Mode: machine.PinInput})
+	drivers.ConfigurePin(d.latch, drivers.PinOutput)
+	drivers.ConfigurePin(d.clk, drivers.PinOutput)
+	drivers.ConfigurePin(d.out, drivers.PinInput)
 	for i := 0; i < int(d.bits); i++ {
 		d.Pins[i] = d.GetShiftPin(i)
 	}
@@ -53,7 +54,7 @@ func (d *Device) Configure() {
 
 // GetShiftPin returns an ShiftPin for a specific input.
 func (d *Device) GetShiftPin(input int) ShiftPin {
-	return ShiftPin{pin: machine.Pin(input), d: d}
+	return ShiftPin{pin: input, d: d}
 }
 
 // Read8Input updates the internal pins' states and returns it as an uint8.
@@ -93,18 +94,18 @@ func (p ShiftPin) Configure() {
 
 // readInput reads howMany bits from the shift register and updates the internal pins' states.
 func (d *Device) readInput(howMany NumberBit) uint32 {
-	d.latch.High()
+	d.latch.Set(true)
 	var data uint32
 	for i := howMany - 1; i >= 0; i-- {
-		d.clk.Low()
+		d.clk.Set(false)
 		if d.out.Get() {
 			data |= 1 << i
 			d.Pins[i].pressed = true
 		} else {
 			d.Pins[i].pressed = false
 		}
-		d.clk.High()
+		d.clk.Set(true)
 	}
-	d.latch.Low()
+	d.latch.Set(false)
 	return data
 }
@@ -13,3 +13,77 @@ const (
 	MEASUREMENT_COMMAND_MSB = 0x24
 	MEASUREMENT_COMMAND_LSB = 0x00
 )
+
+// Commands.
+const (
+	CMD_SINGLE_SHOT_STRETCH = 0x2C00 // + repeatability: high 0x06, medium 0x0D, low 0x10
+	CMD_SINGLE_SHOT         = 0x2400 // + repeatability: high 0x00, medium 0x0B, low 0x16
+	CMD_PERIODIC_ART        = 0x2B32
+	CMD_FETCH_DATA          = 0xE000
+	CMD_BREAK               = 0x3093
+	CMD_SOFT_RESET          = 0x30A2
+	CMD_HEATER_ENABLE       = 0x306D
+	CMD_HEATER_DISABLE      = 0x3066
+	CMD_READ_STATUS         = 0xF32D
+	CMD_CLEAR_STATUS        = 0x3041
+	CMD_READ_SERIAL_NUMBER  = 0x3780
+
+	CMD_READ_ALERT_HIGH_SET    = 0xE11F
+	CMD_READ_ALERT_HIGH_CLEAR  = 0xE114
+	CMD_READ_ALERT_LOW_CLEAR   = 0xE109
+	CMD_READ_ALERT_LOW_SET     = 0xE102
+	CMD_WRITE_ALERT_HIGH_SET   = 0x611D
+	CMD_WRITE_ALERT_HIGH_CLEAR = 0x6116
+	CMD_WRITE_ALERT_LOW_CLEAR  = 0x610B
+	CMD_WRITE_ALERT_LOW_SET    = 0x6100
+)
+
+// Repeatability is the repeatability of a measurement. Higher
+// repeatability measurements take longer and use more energy.
+type Repeatability uint8
+
+const (
+	REPEATABILITY_HIGH Repeatability = iota
+	REPEATABILITY_MEDIUM
+	REPEATABILITY_LOW
+)
+
+// Rate is the number of measurements per second in periodic mode.
+type Rate uint8
+
+const (
+	RATE_0_5HZ Rate = iota
+	RATE_1HZ
+	RATE_2HZ
+	RATE_4HZ
+	RATE_10HZ
+
+	// RATE_ART is the accelerated response time mode, which measures at 4Hz.
+	RATE_ART
+)
+
+// Status is the status register.
+type Status uint16
+
+const (
+	STATUS_ALERT_PENDING     Status = 1 << 15
+	STATUS_HEATER            Status = 1 << 13
+	STATUS_HUMIDITY_ALERT    Status = 1 << 11
+	STATUS_TEMPERATURE_ALERT Status = 1 << 10
+	STATUS_RESET             Status = 1 << 4
+	STATUS_COMMAND_FAILED    Status = 1 << 1
+	STATUS_WRITE_CHECKSUM    Status = 1 << 0
+)
+
+// AlertLimit selects one of the limits of the ALERT pin. The pin is set
+// when the temperature or humidity rises above the high set limit or falls
+// below the low set limit, and is cleared when both are back between the
+// clear limits.
+type AlertLimit uint8
+
+const (
+	ALERT_HIGH_SET AlertLimit = iota
+	ALERT_HIGH_CLEAR
+	ALERT_LOW_CLEAR
+	ALERT_LOW_SET
+)
@@ -6,15 +6,67 @@
 package sht3x // import "tinygo.org/x/drivers/sht3x"
 
 import (
+	"errors"
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/sensirion"
 )
 
+// ErrChecksum is returned when the CRC of data read from the sensor does
+// not match, usually because of noise on the bus.
+var ErrChecksum = errors.New("sht3x: checksum mismatch")
+
 // Device wraps an I2C connection to a SHT31 device.
 type Device struct {
 	bus     drivers.I2C
 	Address uint16
+
+	repeatability   Repeatability
+	clockStretching bool
+	periodic        bool
+	buf             [6]byte
+
+	// last values read by Update
+	temperature int32
+	humidity    int16
+}
+
+// Config holds the measurement settings.
+type Config struct {
+	// Repeatability of single shot and periodic measurements. The default
+	// is REPEATABILITY_HIGH.
+	Repeatability Repeatability
+
+	// ClockStretching makes the sensor hold the clock low during a single
+	// shot measurement until it is done, instead of not acknowledging
+	// reads. The I2C controller must support clock stretching.
+	ClockStretching bool
+}
+
+// singleShotCommands are the single shot measurement commands without and
+// with clock stretching, by repeatability.
+var singleShotCommands = [2][3]uint16{
+	{0x2400, 0x240B, 0x2416},
+	{0x2C06, 0x2C0D, 0x2C10},
+}
+
+// periodicCommands are the periodic measurement commands by rate and
+// repeatability.
+var periodicCommands = [5][3]uint16{
+	{0x2032, 0x2024, 0x202F},
+	{0x2130, 0x2126, 0x212D},
+	{0x2236, 0x2220, 0x222B},
+	{0x2334, 0x2322, 0x2329},
+	{0x2737, 0x2721, 0x272A},
+}
+
+// measurementDurations are the longest durations of a measurement by
+// repeatability.
+var measurementDurations = [3]time.Duration{
+	17 * time.Millisecond,
+	7 * time.Millisecond,
+	5 * time.Millisecond,
 }
 
 // New creates a new SHT31 connection. The I2C bus must already be
@@ -29,6 +81,16 @@ func New(bus drivers.I2C) Device {
 	}
 }
 
+// Configure sets the measurement settings. It does not communicate with the
+// sensor.
+func (d *Device) Configure(config Config) {
+	if config.Repeatability > REPEATABILITY_LOW {
+		config.Repeatability = REPEATABILITY_HIGH
+	}
+	d.repeatability = config.Repeatability
+	d.clockStretching = config.ClockStretching
+}
+
 // Read returns the temperature in celsius milli degrees (°C/1000).
 func (d *Device) ReadTemperature() (tempMilliCelsius int32, err error) {
 	tempMilliCelsius, _, err = d.ReadTemperatureHumidity()
@@ -53,20 +115,202 @@ func (d *Device) ReadTemperatureHumidity() (tempMilliCelsius int32, relativeHumi
 	return tempMilliCelsius, relativeHumidity, err
 }
 
+// Update reads the temperature and humidity, which are always measured
+// together. Use Temperature and Humidity to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Humidity) == 0 {
+		return nil
+	}
+	temperature, humidity, err := d.ReadTemperatureHumidity()
+	if err != nil {
+		return err
+	}
+	d.temperature, d.humidity = temperature, humidity
+	return nil
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// Humidity returns the relative humidity in hundredths of a percent read by
+// the last call to Update.
+func (d *Device) Humidity() int32 {
+	return int32(d.humidity)
+}
+
+// StartPeriodicMeasurement starts measuring periodically at the given rate
+// with the configured repeatability. While measuring periodically, reads
+// return the latest measurement, or an error if there was no new
+// measurement since the previous read.
+func (d *Device) StartPeriodicMeasurement(rate Rate) error {
+	command := uint16(CMD_PERIODIC_ART)
+	if rate < RATE_ART {
+		command = periodicCommands[rate][d.repeatability]
+	}
+	if err := d.sendCommand(command); err != nil {
+		return err
+	}
+	d.periodic = true
+	return nil
+}
+
+// StopPeriodicMeasurement stops measuring periodically and returns to
+// single shot mode.
+func (d *Device) StopPeriodicMeasurement() error {
+	if err := d.sendCommand(CMD_BREAK); err != nil {
+		return err
+	}
+	d.periodic = false
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+// Reset performs a soft reset, which stops periodic measurements and
+// resets the heater and alert limits.
+func (d *Device) Reset() error {
+	if err := d.sendCommand(CMD_SOFT_RESET); err != nil {
+		return err
+	}
+	d.periodic = false
+	time.Sleep(2 * time.Millisecond)
+	return nil
+}
+
+// SetHeater turns the internal heater on or off. The heater is meant for
+// plausibility checks and to evaporate condensation; it raises the
+// temperature by a few degrees.
+func (d *Device) SetHeater(enabled bool) error {
+	if enabled {
+		return d.sendCommand(CMD_HEATER_ENABLE)
+	}
+	return d.sendCommand(CMD_HEATER_DISABLE)
+}
+
+// ReadStatus reads the status register.
+func (d *Device) ReadStatus() (Status, error) {
+	var words [1]uint16
+	if err := d.sendCommand(CMD_READ_STATUS); err != nil {
+		return 0, err
+	}
+	if err := d.readWords(words[:]); err != nil {
+		return 0, err
+	}
+	return Status(words[0]), nil
+}
+
+// ClearStatus clears the alert and reset flags of the status register.
+func (d *Device) ClearStatus() error {
+	return d.sendCommand(CMD_CLEAR_STATUS)
+}
+
+// ReadSerialNumber reads the 32-bit serial number of the sensor. The sensor
+// must not be measuring periodically.
+func (d *Device) ReadSerialNumber() (uint32, error) {
+	var words [2]uint16
+	if err := d.sendCommand(CMD_READ_SERIAL_NUMBER); err != nil {
+		return 0, err
+	}
+	time.Sleep(time.Millisecond)
+	if err := d.readWords(words[:]); err != nil {
+		return 0, err
+	}
+	return uint32(words[0])<<16 | uint32(words[1]), nil
+}
+
+// alertCommands are the commands to read and write the alert limits.
+var alertCommands = [4][2]uint16{
+	{CMD_READ_ALERT_HIGH_SET, CMD_WRITE_ALERT_HIGH_SET},
+	{CMD_READ_ALERT_HIGH_CLEAR, CMD_WRITE_ALERT_HIGH_CLEAR},
+	{CMD_READ_ALERT_LOW_CLEAR, CMD_WRITE_ALERT_LOW_CLEAR},
+	{CMD_READ_ALERT_LOW_SET, CMD_WRITE_ALERT_LOW_SET},
+}
+
+// SetAlertLimit sets an alert limit of the ALERT pin to a temperature in
+// celsius milli degrees (°C/1000) and a relative humidity in hundredths of
+// a percent. The sensor stores the 9 most significant bits of the
+// temperature and the 7 most significant bits of the humidity, a
+// resolution of about 0.35°C and 0.8%. Alerts are only checked while
+// measuring periodically.
+func (d *Device) SetAlertLimit(limit AlertLimit, temperature, humidity int32) error {
+	// temperature = -45 + 175 * value / (2¹⁶ - 1)
+	rawTemp := clamp((int64(temperature) + 45000) * 65535 / 175000)
+	// humidity = 100 * value / (2¹⁶ - 1)
+	rawHum := clamp(int64(humidity) * 65535 / 10000)
+	d.buf[0] = byte(alertCommands[limit][1] >> 8)
+	d.buf[1] = byte(alertCommands[limit][1])
+	sensirion.PutWord(d.buf[2:], rawHum&0xFE00|rawTemp>>7)
+	return drivers.NotResponding(d.bus.Tx(d.Address, d.buf[:5], nil))
+}
+
+// ReadAlertLimit reads an alert limit of the ALERT pin, as a temperature in
+// celsius milli degrees (°C/1000) and a relative humidity in hundredths of
+// a percent.
+func (d *Device) ReadAlertLimit(limit AlertLimit) (temperature, humidity int32, err error) {
+	var words [1]uint16
+	if err := d.sendCommand(alertCommands[limit][0]); err != nil {
+		return 0, 0, err
+	}
+	if err := d.readWords(words[:]); err != nil {
+		return 0, 0, err
+	}
+	rawTemp := (words[0] & 0x01FF) << 7
+	rawHum := words[0] & 0xFE00
+	return (35000 * int32(rawTemp) / 13107) - 45000, 2000 * int32(rawHum) / 13107, nil
+}
+
 // rawReadings returns the sensor's raw values of the temperature and humidity
 func (d *Device) rawReadings() (uint16, uint16, error) {
-	d.bus.Tx(d.Address, []byte{MEASUREMENT_COMMAND_MSB, MEASUREMENT_COMMAND_LSB}, nil)
+	if d.periodic {
+		if err := d.sendCommand(CMD_FETCH_DATA); err != nil {
+			return 0, 0, err
+		}
+	} else {
+		stretch := 0
+		if d.clockStretching {
+			stretch = 1
+		}
+		if err := d.sendCommand(singleShotCommands[stretch][d.repeatability]); err != nil {
+			return 0, 0, err
+		}
+		time.Sleep(measurementDurations[d.repeatability])
+	}
 
-	time.Sleep(17 * time.Millisecond)
+	var words [2]uint16
+	if err := d.readWords(words[:]); err != nil {
+		return 0, 0, err
+	}
+	return words[0], words[1], nil
+}
 
-	var data [5]byte
-	d.bus.Tx(d.Address, []byte{}, data[:])
-	// ignore crc for now
+// sendCommand sends a 16-bit command.
+func (d *Device) sendCommand(command uint16) error {
+	d.buf[0] = byte(command >> 8)
+	d.buf[1] = byte(command)
+	return drivers.NotResponding(d.bus.Tx(d.Address, d.buf[:2], nil))
+}
 
-	return readUint(data[0], data[1]), readUint(data[3], data[4]), nil
+// readWords reads len(words) words, each followed by its CRC.
+func (d *Device) readWords(words []uint16) error {
+	data := d.buf[:len(words)*3]
+	if err := d.bus.Tx(d.Address, nil, data); err != nil {
+		return drivers.NotResponding(err)
+	}
+	if !sensirion.DecodeWords(data, words) {
+		return ErrChecksum
+	}
+	return nil
 }
 
-// readUint converts two bytes to uint16
-func readUint(msb byte, lsb byte) uint16 {
-	return (uint16(msb) << 8) | uint16(lsb)
+// clamp limits a raw value to 16 bits.
+func clamp(v int64) uint16 {
+	if v < 0 {
+		return 0
+	}
+	if v > 0xFFFF {
+		return 0xFFFF
+	}
+	return uint16(v)
 }
@@ -0,0 +1,161 @@
+package sht3x
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/sensirion"
+	"tinygo.org/x/drivers/tester"
+)
+
+var (
+	_ drivers.Thermometer = (*Device)(nil)
+	_ drivers.Hygrometer  = (*Device)(nil)
+)
+
+// command returns a command followed by the given words that responds
+// with the given words, all followed by their CRC.
+func command(cmd uint16, args []uint16, response ...uint16) *tester.Cmd {
+	c := &tester.Cmd{
+		Command:  append([]byte{byte(cmd >> 8), byte(cmd)}, words(args...)...),
+		Response: words(response...),
+	}
+	c.Mask = make([]byte, len(c.Command))
+	for i := range c.Mask {
+		c.Mask[i] = 0xFF
+	}
+	return c
+}
+
+func words(values ...uint16) []byte {
+	data := make([]byte, len(values)*3)
+	for i, v := range values {
+		sensirion.PutWord(data[i*3:], v)
+	}
+	return data
+}
+
+func newDevice(c *qt.C, commands ...*tester.Cmd) (*Device, *tester.I2CDeviceCmd) {
+	bus := tester.NewI2CBus(c)
+	fdev := tester.NewI2CDeviceCmd(c, AddressA)
+	fdev.Commands = map[uint8]*tester.Cmd{}
+	for i, cmd := range commands {
+		fdev.Commands[uint8(i)] = cmd
+	}
+	bus.AddDevice(fdev)
+	dev := New(bus)
+	return &dev, fdev
+}
+
+func TestReadTemperatureHumidity(t *testing.T) {
+	c := qt.New(t)
+	// 25°C and 50%.
+	measure := command(0x2400, nil, 0x6666, 0x8000)
+	dev, _ := newDevice(c, measure)
+	temperature, humidity, err := dev.ReadTemperatureHumidity()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temperature, qt.Equals, int32(25000))
+	c.Assert(humidity, qt.Equals, int16(5000))
+
+	measure.Response[4] ^= 1
+	_, _, err = dev.ReadTemperatureHumidity()
+	c.Assert(err, qt.Equals, ErrChecksum)
+}
+
+func TestRepeatability(t *testing.T) {
+	c := qt.New(t)
+	low := command(0x2416, nil, 0x6666, 0x8000)
+	stretch := command(0x2C0D, nil, 0x6666, 0x8000)
+	dev, _ := newDevice(c, low, stretch)
+
+	dev.Configure(Config{Repeatability: REPEATABILITY_LOW})
+	c.Assert(dev.Update(drivers.Temperature), qt.IsNil)
+	c.Assert(low.Invocations, qt.Equals, 1)
+	c.Assert(dev.Temperature(), qt.Equals, int32(25000))
+
+	dev.Configure(Config{Repeatability: REPEATABILITY_MEDIUM, ClockStretching: true})
+	c.Assert(dev.Update(drivers.Humidity), qt.IsNil)
+	c.Assert(stretch.Invocations, qt.Equals, 1)
+	c.Assert(dev.Humidity(), qt.Equals, int32(5000))
+}
+
+func TestPeriodic(t *testing.T) {
+	c := qt.New(t)
+	start := command(0x2126, nil)
+	fetch := command(CMD_FETCH_DATA, nil, 0x6666, 0x8000)
+	stop := command(CMD_BREAK, nil)
+	art := command(CMD_PERIODIC_ART, nil)
+	dev, _ := newDevice(c, start, fetch, stop, art)
+	dev.Configure(Config{Repeatability: REPEATABILITY_MEDIUM})
+
+	c.Assert(dev.StartPeriodicMeasurement(RATE_1HZ), qt.IsNil)
+	c.Assert(start.Invocations, qt.Equals, 1)
+	for i := 0; i < 2; i++ {
+		temperature, err := dev.ReadTemperature()
+		c.Assert(err, qt.IsNil)
+		c.Assert(temperature, qt.Equals, int32(25000))
+	}
+	c.Assert(fetch.Invocations, qt.Equals, 2)
+	c.Assert(dev.StopPeriodicMeasurement(), qt.IsNil)
+	c.Assert(stop.Invocations, qt.Equals, 1)
+
+	c.Assert(dev.StartPeriodicMeasurement(RATE_ART), qt.IsNil)
+	c.Assert(art.Invocations, qt.Equals, 1)
+}
+
+func TestHeaterAndStatus(t *testing.T) {
+	c := qt.New(t)
+	on := command(CMD_HEATER_ENABLE, nil)
+	off := command(CMD_HEATER_DISABLE, nil)
+	status := command(CMD_READ_STATUS, nil, 0xA010)
+	clear := command(CMD_CLEAR_STATUS, nil)
+	dev, _ := newDevice(c, on, off, status, clear)
+
+	c.Assert(dev.SetHeater(true), qt.IsNil)
+	c.Assert(dev.SetHeater(false), qt.IsNil)
+	c.Assert(on.Invocations, qt.Equals, 1)
+	c.Assert(off.Invocations, qt.Equals, 1)
+
+	s, err := dev.ReadStatus()
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, STATUS_ALERT_PENDING|STATUS_HEATER|STATUS_RESET)
+	c.Assert(dev.ClearStatus(), qt.IsNil)
+	c.Assert(clear.Invocations, qt.Equals, 1)
+}
+
+func TestAlertLimit(t *testing.T) {
+	c := qt.New(t)
+	// 60°C and 80% are the default high set limit.
+	write := command(CMD_WRITE_ALERT_HIGH_SET, []uint16{0xCD33})
+	dev, fdev := newDevice(c, write)
+	c.Assert(dev.SetAlertLimit(ALERT_HIGH_SET, 60000, 8000), qt.IsNil)
+	c.Assert(write.Invocations, qt.Equals, 1)
+
+	fdev.Commands[0] = command(CMD_READ_ALERT_HIGH_SET, nil, 0xCD33)
+	temperature, humidity, err := dev.ReadAlertLimit(ALERT_HIGH_SET)
+	c.Assert(err, qt.IsNil)
+	c.Assert(temperature, qt.Equals, int32(59933))
+	c.Assert(humidity, qt.Equals, int32(7968))
+}
+
+func TestSerialNumber(t *testing.T) {
+	c := qt.New(t)
+	dev, _ := newDevice(c, command(CMD_READ_SERIAL_NUMBER, nil, 0x1234, 0x5678))
+	serial, err := dev.ReadSerialNumber()
+	c.Assert(err, qt.IsNil)
+	c.Assert(serial, qt.Equals, uint32(0x12345678))
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	dev := New(bus)
+
+	_, _, err := dev.ReadTemperatureHumidity()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	err = dev.Update(drivers.Temperature)
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
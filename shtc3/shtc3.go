@@ -6,14 +6,24 @@
 package shtc3 // import "tinygo.org/x/drivers/shtc3"
 
 import (
+	"errors"
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/internal/sensirion"
 )
 
+// ErrChecksum is returned when the CRC of data read from the sensor does
+// not match, usually because of noise on the bus.
+var ErrChecksum = errors.New("shtc3: checksum mismatch")
+
 // Device wraps an I2C connection to a SHT31 device.
 type Device struct {
 	bus drivers.I2C
+
+	// last values read by Update
+	temperature int32
+	humidity    int16
 }
 
 // New creates a new SHTC3 connection. The I2C bus must already be
@@ -51,28 +61,55 @@ func (d *Device) ReadTemperatureHumidity() (tempMilliCelsius int32, relativeHumi
 	return tempMilliCelsius, relativeHumidity, err
 }
 
+// Update reads the temperature and humidity, which are always measured
+// together. Use Temperature and Humidity to get the values.
+func (d *Device) Update(which drivers.Measurement) error {
+	if which&(drivers.Temperature|drivers.Humidity) == 0 {
+		return nil
+	}
+	temperature, humidity, err := d.ReadTemperatureHumidity()
+	if err != nil {
+		return err
+	}
+	d.temperature, d.humidity = temperature, humidity
+	return nil
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
+
+// Humidity returns the relative humidity in hundredths of a percent read by
+// the last call to Update.
+func (d *Device) Humidity() int32 {
+	return int32(d.humidity)
+}
+
 // rawReadings returns the sensor's raw values of the temperature and humidity
 func (d *Device) rawReadings() (uint16, uint16, error) {
 	var data [6]byte
-	d.bus.Tx(SHTC3_ADDRESS, []byte(SHTC3_CMD_MEASURE_HP), data[:])
-	// ignore crc for now
-	return readUint(data[0], data[1]), readUint(data[3], data[4]), nil
+	if err := d.bus.Tx(SHTC3_ADDRESS, []byte(SHTC3_CMD_MEASURE_HP), data[:]); err != nil {
+		return 0, 0, drivers.NotResponding(err)
+	}
+	var words [2]uint16
+	if !sensirion.DecodeWords(data[:], words[:]) {
+		return 0, 0, ErrChecksum
+	}
+	return words[0], words[1], nil
 }
 
 // WakeUp makes device leave sleep mode
 func (d *Device) WakeUp() error {
-	d.bus.Tx(SHTC3_ADDRESS, []byte(SHTC3_CMD_WAKEUP), nil)
+	if err := d.bus.Tx(SHTC3_ADDRESS, []byte(SHTC3_CMD_WAKEUP), nil); err != nil {
+		return drivers.NotResponding(err)
+	}
 	time.Sleep(1 * time.Millisecond)
 	return nil
 }
 
 // Sleep makes device go to sleep
 func (d *Device) Sleep() error {
-	d.bus.Tx(SHTC3_ADDRESS, []byte(SHTC3_CMD_SLEEP), nil)
-	return nil
-}
-
-// readUint converts two bytes to uint16
-func readUint(msb byte, lsb byte) uint16 {
-	return (uint16(msb) << 8) | uint16(lsb)
+	return drivers.NotResponding(d.bus.Tx(SHTC3_ADDRESS, []byte(SHTC3_CMD_SLEEP), nil))
 }
@@ -0,0 +1,45 @@
+package shtc3
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestReadTemperatureHumidity(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDeviceCmd(c, SHTC3_ADDRESS)
+	// 25°C and 50%.
+	measure := &tester.Cmd{
+		Command:  []byte(SHTC3_CMD_MEASURE_HP),
+		Mask:     []byte{0xFF, 0xFF},
+		Response: []byte{0x66, 0x66, 0x93, 0x80, 0x00, 0xA2},
+	}
+	fake.Commands = map[uint8]*tester.Cmd{0: measure}
+	bus.AddDevice(fake)
+	dev := New(bus)
+
+	temperature, humidity, err := dev.ReadTemperatureHumidity()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temperature, qt.Equals, int32(24998))
+	c.Assert(humidity, qt.Equals, int16(5000))
+
+	measure.Response[2] ^= 1
+	_, _, err = dev.ReadTemperatureHumidity()
+	c.Assert(err, qt.Equals, ErrChecksum)
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AllowMissing = true
+	dev := New(bus)
+
+	_, _, err := dev.ReadTemperatureHumidity()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	c.Assert(errors.Is(dev.WakeUp(), drivers.ErrNotResponding), qt.IsTrue)
+}
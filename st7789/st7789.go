@@ -7,7 +7,6 @@ package st7789 // import "tinygo.org/x/drivers/st7789"
 
 import (
 	"image/color"
-	"machine"
 	"math"
 	"time"
 
@@ -25,10 +24,10 @@ type FrameRate uint8
 // Device wraps an SPI connection.
 type Device struct {
 	bus             drivers.SPI
-	dcPin           machine.Pin
-	resetPin        machine.Pin
-	csPin           machine.Pin
-	blPin           machine.Pin
+	dcPin           drivers.Pin
+	resetPin        drivers.Pin
+	csPin           drivers.Pin
+	blPin           drivers.Pin
 	width           int16
 	height          int16
 	columnOffsetCfg int16
@@ -54,11 +53,11 @@ type Config struct {
 }
 
 // New creates a new ST7789 connection. The SPI wire must already be configured.
-func New(bus drivers.SPI, resetPin, dcPin, csPin, blPin machine.Pin) Device {
-	dcPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	resetPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	csPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	blPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+func New(bus drivers.SPI, resetPin, dcPin, csPin, blPin drivers.Pin) Device {
+	drivers.ConfigurePin(dcPin, drivers.PinOutput)
+	drivers.ConfigurePin(resetPin, drivers.PinOutput)
+	drivers.ConfigurePin(csPin, drivers.PinOutput)
+	drivers.ConfigurePin(blPin, drivers.PinOutput)
 	return Device{
 		bus:      bus,
 		dcPin:    dcPin,
@@ -104,11 +103,11 @@ func (d *Device) Configure(cfg Config) {
 	d.batchLength += d.batchLength & 1
 
 	// Reset the device
-	d.resetPin.High()
+	d.resetPin.Set(true)
 	time.Sleep(50 * time.Millisecond)
-	d.resetPin.Low()
+	d.resetPin.Set(false)
 	time.Sleep(50 * time.Millisecond)
-	d.resetPin.High()
+	d.resetPin.Set(true)
 	time.Sleep(50 * time.Millisecond)
 
 	// Common initialization
@@ -157,7 +156,7 @@ func (d *Device) Configure(cfg Config) {
 	d.Command(DISPON)                 // Screen ON
 	time.Sleep(10 * time.Millisecond) //
 
-	d.blPin.High() // Backlight ON
+	d.blPin.Set(true) // Backlight ON
 }
 
 // Sync waits for the display to hit the next VSYNC pause
@@ -384,25 +383,25 @@ func (d *Device) Data(data uint8) {
 // Tx sends data to the display
 func (d *Device) Tx(data []byte, isCommand bool) {
 	if isCommand {
-		d.dcPin.Low()
+		d.dcPin.Set(false)
 	} else {
-		d.dcPin.High()
+		d.dcPin.Set(true)
 	}
-	d.csPin.Low()
+	d.csPin.Set(false)
 	d.bus.Tx(data, nil)
-	d.csPin.High()
+	d.csPin.Set(true)
 }
 
 // Rx reads data from the display
 func (d *Device) Rx(command uint8, data []byte) {
-	d.dcPin.Low()
-	d.csPin.Low()
+	d.dcPin.Set(false)
+	d.csPin.Set(false)
 	d.bus.Transfer(command)
-	d.dcPin.High()
+	d.dcPin.Set(true)
 	for i := range data {
 		data[i], _ = d.bus.Transfer(0xFF)
 	}
-	d.csPin.High()
+	d.csPin.Set(true)
 }
 
 // Size returns the current size of the display.
@@ -416,9 +415,9 @@ func (d *Device) Size() (w, h int16) {
 // EnableBacklight enables or disables the backlight
 func (d *Device) EnableBacklight(enable bool) {
 	if enable {
-		d.blPin.High()
+		d.blPin.Set(true)
 	} else {
-		d.blPin.Low()
+		d.blPin.Set(false)
 	}
 }
 
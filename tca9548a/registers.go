@@ -0,0 +1,9 @@
+package tca9548a
+
+const (
+	// Default I2C address. The A0-A2 pins select addresses up to 0x77.
+	Address = 0x70
+
+	// Number of downstream channels.
+	NumChannels = 8
+)
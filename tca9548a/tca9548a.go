@@ -0,0 +1,136 @@
+// Package tca9548a implements a driver for the TCA9548A and PCA9548A 8-channel
+// I2C multiplexers.
+//
+// Datasheet: https://www.ti.com/lit/ds/symlink/tca9548a.pdf
+package tca9548a // import "tinygo.org/x/drivers/tca9548a"
+
+import (
+	"errors"
+
+	"tinygo.org/x/drivers"
+)
+
+var errInvalidChannel = errors.New("tca9548a: invalid channel")
+
+// Device wraps an I2C connection to a TCA9548A device.
+//
+// The driver caches the enabled channels to avoid rewriting the control
+// register before every transaction, so the multiplexer must not be
+// reconfigured behind its back. It is not safe for concurrent use.
+type Device struct {
+	bus     drivers.I2C
+	Address uint16
+
+	// channels is the cached value of the control register.
+	channels uint8
+	// valid is whether channels reflects the device.
+	valid bool
+}
+
+// New creates a new TCA9548A connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) *Device {
+	return &Device{
+		bus:     bus,
+		Address: Address,
+	}
+}
+
+// Connected returns whether a multiplexer has been found.
+func (d *Device) Connected() bool {
+	_, err := d.ReadChannels()
+	return err == nil
+}
+
+// ReadChannels returns the enabled channels as a bit mask, bit n being
+// channel n, and refreshes the cache.
+func (d *Device) ReadChannels() (uint8, error) {
+	data := []byte{0}
+	err := d.bus.Tx(d.Address, nil, data)
+	if err != nil {
+		d.valid = false
+		return 0, err
+	}
+	d.channels = data[0]
+	d.valid = true
+	return d.channels, nil
+}
+
+// SetChannels enables the channels in the bit mask, bit n being channel
+// n, and disables all others. Enabling several channels at once connects
+// them to each other, so that devices on them share the same bus.
+func (d *Device) SetChannels(mask uint8) error {
+	if d.valid && d.channels == mask {
+		return nil
+	}
+	err := d.bus.Tx(d.Address, []byte{mask}, nil)
+	if err != nil {
+		d.valid = false
+		return err
+	}
+	d.channels = mask
+	d.valid = true
+	return nil
+}
+
+// Select enables only the given channel.
+func (d *Device) Select(channel uint8) error {
+	if channel >= NumChannels {
+		return errInvalidChannel
+	}
+	return d.SetChannels(1 << channel)
+}
+
+// Disable disconnects all channels.
+func (d *Device) Disable() error {
+	return d.SetChannels(0)
+}
+
+// Invalidate forgets the cached channels, so that the control register is
+// written before the next transaction. Call it after the multiplexer has
+// been reset.
+func (d *Device) Invalidate() {
+	d.valid = false
+}
+
+// Channel returns an I2C bus for the given downstream channel. Any driver
+// can be constructed on it unchanged: every transaction first selects the
+// channel on the multiplexer if it is not already the only one enabled.
+func (d *Device) Channel(channel uint8) *Channel {
+	return &Channel{
+		d:       d,
+		channel: channel,
+	}
+}
+
+// Channel is a downstream bus of the multiplexer. It implements drivers.I2C.
+type Channel struct {
+	d       *Device
+	channel uint8
+}
+
+// ReadRegister implements drivers.I2C.
+func (c *Channel) ReadRegister(addr uint8, r uint8, buf []byte) error {
+	if err := c.d.Select(c.channel); err != nil {
+		return err
+	}
+	return c.d.bus.ReadRegister(addr, r, buf)
+}
+
+// WriteRegister implements drivers.I2C.
+func (c *Channel) WriteRegister(addr uint8, r uint8, buf []byte) error {
+	if err := c.d.Select(c.channel); err != nil {
+		return err
+	}
+	return c.d.bus.WriteRegister(addr, r, buf)
+}
+
+// Tx implements drivers.I2C.
+func (c *Channel) Tx(addr uint16, w, r []byte) error {
+	if err := c.d.Select(c.channel); err != nil {
+		return err
+	}
+	return c.d.bus.Tx(addr, w, r)
+}
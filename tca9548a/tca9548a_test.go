@@ -0,0 +1,130 @@
+package tca9548a
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+// fakeMux is a mock I2C bus with a TCA9548A on it, routing transactions
+// to the mock buses of the enabled channels.
+type fakeMux struct {
+	c        *qt.C
+	control  uint8
+	writes   int
+	err      error
+	channels [NumChannels]*tester.I2CBus
+}
+
+func newFakeMux(c *qt.C) *fakeMux {
+	m := &fakeMux{c: c}
+	for i := range m.channels {
+		m.channels[i] = tester.NewI2CBus(c)
+	}
+	return m
+}
+
+func (m *fakeMux) bus() drivers.I2C {
+	var found drivers.I2C
+	for i, bus := range m.channels {
+		if m.control&(1<<i) != 0 {
+			if found != nil {
+				m.c.Fatalf("several channels enabled: %#b", m.control)
+			}
+			found = bus
+		}
+	}
+	if found == nil {
+		m.c.Fatalf("no channel enabled")
+	}
+	return found
+}
+
+func (m *fakeMux) ReadRegister(addr uint8, r uint8, buf []byte) error {
+	return m.bus().ReadRegister(addr, r, buf)
+}
+
+func (m *fakeMux) WriteRegister(addr uint8, r uint8, buf []byte) error {
+	return m.bus().WriteRegister(addr, r, buf)
+}
+
+func (m *fakeMux) Tx(addr uint16, w, r []byte) error {
+	if addr != Address {
+		return m.bus().Tx(addr, w, r)
+	}
+	if m.err != nil {
+		return m.err
+	}
+	if len(w) == 1 {
+		m.control = w[0]
+		m.writes++
+	}
+	if len(r) == 1 {
+		r[0] = m.control
+	}
+	return nil
+}
+
+func TestDefaultI2CAddress(t *testing.T) {
+	c := qt.New(t)
+	dev := New(tester.NewI2CBus(c))
+	c.Assert(dev.Address, qt.Equals, uint16(Address))
+}
+
+func TestChannels(t *testing.T) {
+	c := qt.New(t)
+	mux := newFakeMux(c)
+	// Two sensors with the same address on different channels.
+	s0 := mux.channels[0].NewDevice(0x29)
+	s3 := mux.channels[3].NewDevice(0x29)
+	s0.Registers[0x10] = 0xaa
+	s3.Registers[0x10] = 0xbb
+
+	dev := New(mux)
+	c.Assert(dev.Connected(), qt.IsTrue)
+	ch0 := dev.Channel(0)
+	ch3 := dev.Channel(3)
+
+	buf := []byte{0}
+	c.Assert(ch0.ReadRegister(0x29, 0x10, buf), qt.IsNil)
+	c.Assert(buf[0], qt.Equals, byte(0xaa))
+	c.Assert(ch3.Tx(0x29, []byte{0x10}, buf), qt.IsNil)
+	c.Assert(buf[0], qt.Equals, byte(0xbb))
+	c.Assert(ch3.WriteRegister(0x29, 0x11, []byte{0x42}), qt.IsNil)
+	c.Assert(s3.Registers[0x11], qt.Equals, byte(0x42))
+	c.Assert(mux.writes, qt.Equals, 2)
+
+	// The active channel is cached.
+	ch3.ReadRegister(0x29, 0x10, buf)
+	c.Assert(mux.writes, qt.Equals, 2)
+
+	dev.Invalidate()
+	ch3.ReadRegister(0x29, 0x10, buf)
+	c.Assert(mux.writes, qt.Equals, 3)
+
+	c.Assert(dev.Disable(), qt.IsNil)
+	channels, err := dev.ReadChannels()
+	c.Assert(err, qt.IsNil)
+	c.Assert(channels, qt.Equals, uint8(0))
+}
+
+func TestSelectError(t *testing.T) {
+	c := qt.New(t)
+	mux := newFakeMux(c)
+	dev := New(mux)
+
+	c.Assert(dev.Select(NumChannels), qt.Equals, errInvalidChannel)
+
+	mux.err = errors.New("nack")
+	c.Assert(dev.Channel(1).Tx(0x29, []byte{0}, nil), qt.Equals, mux.err)
+	c.Assert(dev.Connected(), qt.IsFalse)
+
+	// A failed write is retried on the next transaction.
+	mux.err = nil
+	mux.channels[1].NewDevice(0x29)
+	c.Assert(dev.Channel(1).Tx(0x29, []byte{0}, nil), qt.IsNil)
+	c.Assert(mux.control, qt.Equals, uint8(1<<1))
+}
@@ -16,3 +16,43 @@ type I2CDevice interface {
 	// Addr returns the Device address.
 	Addr() uint8
 }
+
+// RegisterFlags modify how a register of a mock device behaves.
+type RegisterFlags uint8
+
+const (
+	// RegisterReadOnly makes the device ignore writes to the register.
+	RegisterReadOnly RegisterFlags = 1 << iota
+
+	// RegisterWriteOnly makes reads of the register return zero.
+	RegisterWriteOnly
+
+	// RegisterClearOnRead resets the register to zero after it is read.
+	RegisterClearOnRead
+)
+
+// Pointer describes how the register pointer of a mock device advances
+// during burst reads and writes. The zero value auto-increments the
+// pointer after every register without wrapping.
+type Pointer struct {
+	// NoAutoIncrement keeps the pointer on the same register for the
+	// whole burst.
+	NoAutoIncrement bool
+
+	// If WrapFrom is non-zero, the pointer moves to WrapTo after register
+	// WrapFrom has been accessed.
+	WrapFrom uint16
+	WrapTo   uint16
+}
+
+// next returns the register following r.
+func (p Pointer) next(r uint16) uint16 {
+	switch {
+	case p.NoAutoIncrement:
+		return r
+	case p.WrapFrom != 0 && r == p.WrapFrom:
+		return p.WrapTo
+	default:
+		return r + 1
+	}
+}
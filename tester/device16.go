@@ -1,6 +1,11 @@
 package tester
 
 // I2CDevice represents a mock I2C device on a mock I2C bus with 16-bit registers.
+//
+// A Tx writes the register pointer with the first byte, writes any
+// remaining big-endian words starting at that register and then reads
+// words starting at the resulting pointer. The pointer advances by one
+// register per word as described by Pointer.
 type I2CDevice16 struct {
 	c Failer
 	// addr is the i2c device address.
@@ -8,9 +13,16 @@ type I2CDevice16 struct {
 	// Registers holds the device registers. It can be inspected
 	// or changed as desired for testing.
 	Registers map[uint8]uint16
+	// Flags holds optional per-register behaviour.
+	Flags map[uint8]RegisterFlags
+	// Pointer controls how the register pointer advances.
+	Pointer Pointer
 	// If Err is non-nil, it will be returned as the error from the
 	// I2C methods.
 	Err error
+
+	// ptr is the current register pointer.
+	ptr uint16
 }
 
 // NewI2CDevice returns a new mock I2C device.
@@ -24,6 +36,7 @@ func NewI2CDevice16(c Failer, addr uint8) *I2CDevice16 {
 		c:         c,
 		addr:      addr,
 		Registers: map[uint8]uint16{},
+		Flags:     map[uint8]RegisterFlags{},
 	}
 }
 
@@ -42,13 +55,8 @@ func (d *I2CDevice16) ReadRegister(r uint8, buf []byte) error {
 		d.c.Fatalf("register read [%#x, %#x] oversized buffer", r, len(buf))
 	}
 
-	val, ok := d.Registers[r]
-	if !ok {
-		d.c.Fatalf("register read [%#x] unknown register", r)
-	}
-
-	buf[0] = byte(val >> 8)
-	buf[1] = byte(val & 0xff)
+	d.ptr = uint16(r)
+	d.read(buf)
 
 	return nil
 }
@@ -63,18 +71,66 @@ func (d *I2CDevice16) WriteRegister(r uint8, buf []byte) error {
 		d.c.Fatalf("register write [%#x, %#x] mis-sized write", r, len(buf))
 	}
 
-	_, ok := d.Registers[r]
-	if !ok {
-		d.c.Fatalf("register write [%#x] unknown register", r)
-	}
-
-	d.Registers[r] = uint16(buf[0])<<8 | uint16(buf[1])
+	d.ptr = uint16(r)
+	d.write(buf)
 
 	return nil
 }
 
 // Tx implements I2C.Tx.
-func (bus *I2CDevice16) Tx(w, r []byte) error {
-	// TODO: implement this
+func (d *I2CDevice16) Tx(w, r []byte) error {
+	if d.Err != nil {
+		return d.Err
+	}
+
+	if len(w) > 0 {
+		if len(w)%2 != 1 {
+			d.c.Fatalf("register write [%#x] mis-sized write", w)
+		}
+		d.ptr = uint16(w[0])
+		d.write(w[1:])
+	}
+	d.read(r)
+
 	return nil
 }
+
+// read reads big-endian words into buf starting at the register pointer.
+// A trailing odd byte receives the high byte of the next register.
+func (d *I2CDevice16) read(buf []byte) {
+	for i := 0; i < len(buf); i += 2 {
+		r := uint8(d.ptr)
+		val, ok := d.Registers[r]
+		if !ok {
+			d.c.Fatalf("register read [%#x] unknown register", r)
+		}
+		flags := d.Flags[r]
+		if flags&RegisterWriteOnly != 0 {
+			val = 0
+		}
+		if flags&RegisterClearOnRead != 0 {
+			d.Registers[r] = 0
+		}
+
+		buf[i] = byte(val >> 8)
+		if i+1 < len(buf) {
+			buf[i+1] = byte(val & 0xff)
+		}
+		d.ptr = d.Pointer.next(d.ptr)
+	}
+}
+
+// write writes big-endian words from buf starting at the register pointer.
+func (d *I2CDevice16) write(buf []byte) {
+	for i := 0; i+1 < len(buf); i += 2 {
+		r := uint8(d.ptr)
+		_, ok := d.Registers[r]
+		if !ok {
+			d.c.Fatalf("register write [%#x] unknown register", r)
+		}
+		if d.Flags[r]&RegisterReadOnly == 0 {
+			d.Registers[r] = uint16(buf[i])<<8 | uint16(buf[i+1])
+		}
+		d.ptr = d.Pointer.next(d.ptr)
+	}
+}
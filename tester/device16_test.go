@@ -40,3 +40,41 @@ func TestWrite16(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(d.Registers[9], qt.Equals, uint16(0xbead))
 }
+
+func TestTx16(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDevice16(c, 8)
+	bus.AddDevice(d)
+	d.Registers[1] = 0
+	d.Registers[2] = 0
+
+	err := bus.Tx(8, []byte{1, 0x12, 0x34, 0x56, 0x78}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(d.Registers[1], qt.Equals, uint16(0x1234))
+	c.Assert(d.Registers[2], qt.Equals, uint16(0x5678))
+
+	buf := make([]byte, 4)
+	err = bus.Tx(8, []byte{1}, buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf, qt.DeepEquals, []byte{0x12, 0x34, 0x56, 0x78})
+
+	d.Pointer.NoAutoIncrement = true
+	err = bus.Tx(8, []byte{2}, buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf, qt.DeepEquals, []byte{0x56, 0x78, 0x56, 0x78})
+}
+
+func TestTx16ClearOnRead(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDevice16(c, 8)
+	bus.AddDevice(d)
+	d.Registers[6] = 0x0408
+	d.Flags[6] = RegisterClearOnRead
+
+	buf := make([]byte, 2)
+	bus.ReadRegister(8, 6, buf)
+	c.Assert(buf, qt.DeepEquals, []byte{0x04, 0x08})
+	c.Assert(d.Registers[6], qt.Equals, uint16(0))
+}
@@ -1,6 +1,10 @@
 package tester
 
 // I2CDevice represents a mock I2C device on a mock I2C bus with 8-bit registers.
+//
+// A Tx writes the register pointer with the first byte, writes any
+// remaining bytes starting at that register and then reads starting at the
+// resulting pointer. The pointer advances as described by Pointer.
 type I2CDevice8 struct {
 	c Failer
 	// addr is the i2c device address.
@@ -8,9 +12,20 @@ type I2CDevice8 struct {
 	// Registers holds the device registers. It can be inspected
 	// or changed as desired for testing.
 	Registers [MaxRegisters]uint8
+	// Flags holds optional per-register behaviour.
+	Flags map[uint8]RegisterFlags
+	// Pointer controls how the register pointer advances.
+	Pointer Pointer
+	// FIFO holds the pending data of FIFO registers. Each read of a
+	// register in FIFO returns the next byte of its queue, and the
+	// register pointer stays on the register during burst reads.
+	FIFO map[uint8][]byte
 	// If Err is non-nil, it will be returned as the error from the
 	// I2C methods.
 	Err error
+
+	// ptr is the current register pointer.
+	ptr uint16
 }
 
 // NewI2CDevice returns a new mock I2C device.
@@ -23,8 +38,10 @@ func NewI2CDevice(c Failer, addr uint8) *I2CDevice8 {
 // NewI2CDevice8 returns a new mock I2C device.
 func NewI2CDevice8(c Failer, addr uint8) *I2CDevice8 {
 	return &I2CDevice8{
-		c:    c,
-		addr: addr,
+		c:     c,
+		addr:  addr,
+		Flags: map[uint8]RegisterFlags{},
+		FIFO:  map[uint8][]byte{},
 	}
 }
 
@@ -39,7 +56,8 @@ func (d *I2CDevice8) ReadRegister(r uint8, buf []byte) error {
 		return d.Err
 	}
 	d.assertRegisterRange(r, buf)
-	copy(buf, d.Registers[r:])
+	d.ptr = uint16(r)
+	d.read(buf)
 	return nil
 }
 
@@ -49,23 +67,79 @@ func (d *I2CDevice8) WriteRegister(r uint8, buf []byte) error {
 		return d.Err
 	}
 	d.assertRegisterRange(r, buf)
-	copy(d.Registers[r:], buf)
+	d.ptr = uint16(r)
+	d.write(buf)
 	return nil
 }
 
 // Tx implements I2C.Tx.
-func (bus *I2CDevice8) Tx(w, r []byte) error {
-	// TODO: implement this
+func (d *I2CDevice8) Tx(w, r []byte) error {
+	if d.Err != nil {
+		return d.Err
+	}
+	if len(w) > 0 {
+		d.ptr = uint16(w[0])
+		d.write(w[1:])
+	}
+	d.read(r)
 	return nil
 }
 
+// read reads registers into buf starting at the register pointer.
+func (d *I2CDevice8) read(buf []byte) {
+	for i := range buf {
+		r := d.register()
+		if queue, ok := d.FIFO[r]; ok {
+			if len(queue) == 0 {
+				d.c.Fatalf("read from empty FIFO register [%#x]", r)
+			}
+			buf[i] = queue[0]
+			d.FIFO[r] = queue[1:]
+			continue
+		}
+		flags := d.Flags[r]
+		if flags&RegisterWriteOnly != 0 {
+			buf[i] = 0
+		} else {
+			buf[i] = d.Registers[r]
+		}
+		if flags&RegisterClearOnRead != 0 {
+			d.Registers[r] = 0
+		}
+		d.ptr = d.Pointer.next(d.ptr)
+	}
+}
+
+// write writes buf to the registers starting at the register pointer.
+func (d *I2CDevice8) write(buf []byte) {
+	for _, b := range buf {
+		r := d.register()
+		if d.Flags[r]&RegisterReadOnly == 0 {
+			d.Registers[r] = b
+		}
+		d.ptr = d.Pointer.next(d.ptr)
+	}
+}
+
+// register returns the register the pointer is on, asserting that it is
+// in range.
+func (d *I2CDevice8) register() uint8 {
+	if int(d.ptr) >= len(d.Registers) {
+		d.c.Fatalf("register pointer [%#x] out of range", d.ptr)
+	}
+	return uint8(d.ptr)
+}
+
 // assertRegisterRange asserts that reading or writing the given
 // register and subsequent registers is in range of the available registers.
 func (d *I2CDevice8) assertRegisterRange(r uint8, buf []byte) {
 	if int(r) >= len(d.Registers) {
 		d.c.Fatalf("register read/write [%#x, %#x] start out of range", r, int(r)+len(buf))
 	}
-	if int(r)+len(buf) > len(d.Registers) {
+	if _, ok := d.FIFO[r]; ok {
+		return
+	}
+	if d.Pointer == (Pointer{}) && int(r)+len(buf) > len(d.Registers) {
 		d.c.Fatalf("register read/write [%#x, %#x] end out of range", r, int(r)+len(buf))
 	}
 }
@@ -41,3 +41,97 @@ func TestWrite8(t *testing.T) {
 	c.Assert(d.Registers[9], qt.Equals, uint8(0xbe))
 	c.Assert(d.Registers[10], qt.Equals, uint8(0xad))
 }
+
+func TestTx8(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDevice8(c, 8)
+	bus.AddDevice(d)
+
+	err := bus.Tx(8, []byte{0x20, 0x11, 0x22}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(d.Registers[0x20], qt.Equals, uint8(0x11))
+	c.Assert(d.Registers[0x21], qt.Equals, uint8(0x22))
+
+	buf := []byte{0, 0, 0}
+	err = bus.Tx(8, []byte{0x20}, buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf, qt.DeepEquals, []byte{0x11, 0x22, 0})
+
+	// Reads without a pointer write continue at the current pointer.
+	d.Registers[0x23] = 0x33
+	err = bus.Tx(8, nil, buf[:1])
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf[0], qt.Equals, uint8(0x33))
+}
+
+func TestTx8Pointer(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDevice8(c, 8)
+	bus.AddDevice(d)
+	d.Registers[0x10] = 0xaa
+	d.Registers[0x11] = 0xbb
+
+	d.Pointer = Pointer{NoAutoIncrement: true}
+	buf := []byte{0, 0}
+	bus.Tx(8, []byte{0x10}, buf)
+	c.Assert(buf, qt.DeepEquals, []byte{0xaa, 0xaa})
+
+	d.Pointer = Pointer{WrapFrom: 0x11, WrapTo: 0x10}
+	buf = []byte{0, 0, 0}
+	bus.Tx(8, []byte{0x10}, buf)
+	c.Assert(buf, qt.DeepEquals, []byte{0xaa, 0xbb, 0xaa})
+}
+
+func TestTx8Flags(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDevice8(c, 8)
+	bus.AddDevice(d)
+	d.Registers[0] = 0x01
+	d.Registers[1] = 0x02
+	d.Registers[2] = 0x03
+	d.Flags[0] = RegisterReadOnly
+	d.Flags[1] = RegisterWriteOnly
+	d.Flags[2] = RegisterClearOnRead
+
+	bus.Tx(8, []byte{0, 0xff, 0xfe}, nil)
+	c.Assert(d.Registers[0], qt.Equals, uint8(0x01))
+	c.Assert(d.Registers[1], qt.Equals, uint8(0xfe))
+
+	buf := []byte{0, 0, 0}
+	bus.ReadRegister(8, 0, buf)
+	c.Assert(buf, qt.DeepEquals, []byte{0x01, 0, 0x03})
+	c.Assert(d.Registers[2], qt.Equals, uint8(0))
+}
+
+func TestFIFO8(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDevice8(c, 8)
+	bus.AddDevice(d)
+	d.Registers[0xfd] = 0x01
+	d.FIFO[0xfe] = []byte{0x11, 0x22, 0x33, 0x44}
+
+	buf := []byte{0, 0, 0}
+	bus.ReadRegister(8, 0xfe, buf)
+	c.Assert(buf, qt.DeepEquals, []byte{0x11, 0x22, 0x33})
+	c.Assert(d.FIFO[0xfe], qt.DeepEquals, []byte{0x44})
+
+	// Other registers are not affected.
+	bus.Tx(8, []byte{0xfd}, buf[:2])
+	c.Assert(buf[:2], qt.DeepEquals, []byte{0x01, 0x44})
+}
+
+func TestBusAllowMissing(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	bus.AllowMissing = true
+	bus.AddDevice(NewI2CDevice8(c, 0x10))
+
+	c.Assert(bus.Tx(0x10, nil, nil), qt.IsNil)
+	c.Assert(bus.Tx(0x11, []byte{0}, nil), qt.Equals, ErrNoDevice)
+	c.Assert(bus.ReadRegister(0x11, 0, make([]byte, 1)), qt.Equals, ErrNoDevice)
+	c.Assert(bus.WriteRegister(0x11, 0, []byte{1}), qt.Equals, ErrNoDevice)
+}
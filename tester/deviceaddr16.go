@@ -0,0 +1,104 @@
+package tester
+
+// I2CDeviceAddr16 represents a mock I2C device on a mock I2C bus with
+// 8-bit registers addressed by a 16-bit big-endian register pointer, such
+// as the VL53L1X.
+//
+// A Tx writes the register pointer with the first two bytes, writes any
+// remaining bytes starting at that register and then reads starting at the
+// resulting pointer. The pointer advances as described by Pointer.
+type I2CDeviceAddr16 struct {
+	c Failer
+	// addr is the i2c device address.
+	addr uint8
+	// Registers holds the device registers. It can be inspected
+	// or changed as desired for testing.
+	Registers map[uint16]uint8
+	// Flags holds optional per-register behaviour.
+	Flags map[uint16]RegisterFlags
+	// Pointer controls how the register pointer advances.
+	Pointer Pointer
+	// If Err is non-nil, it will be returned as the error from the
+	// I2C methods.
+	Err error
+
+	// ptr is the current register pointer.
+	ptr uint16
+}
+
+// NewI2CDeviceAddr16 returns a new mock I2C device.
+//
+// To use this mock, populate the Registers map with known / expected
+// registers. Attempts by the code under test to access a register that
+// has not been populated into the map will be treated as an error.
+func NewI2CDeviceAddr16(c Failer, addr uint8) *I2CDeviceAddr16 {
+	return &I2CDeviceAddr16{
+		c:         c,
+		addr:      addr,
+		Registers: map[uint16]uint8{},
+		Flags:     map[uint16]RegisterFlags{},
+	}
+}
+
+// Addr returns the Device address.
+func (d *I2CDeviceAddr16) Addr() uint8 {
+	return d.addr
+}
+
+// ReadRegister implements I2C.ReadRegister. The register pointer is set to
+// r, so only the first 256 registers can be reached.
+func (d *I2CDeviceAddr16) ReadRegister(r uint8, buf []byte) error {
+	return d.Tx([]byte{0, r}, buf)
+}
+
+// WriteRegister implements I2C.WriteRegister. The register pointer is set
+// to r, so only the first 256 registers can be reached.
+func (d *I2CDeviceAddr16) WriteRegister(r uint8, buf []byte) error {
+	return d.Tx(append([]byte{0, r}, buf...), nil)
+}
+
+// Tx implements I2C.Tx.
+func (d *I2CDeviceAddr16) Tx(w, r []byte) error {
+	if d.Err != nil {
+		return d.Err
+	}
+
+	if len(w) == 1 {
+		d.c.Fatalf("register write [%#x] incomplete register pointer", w)
+	}
+	if len(w) >= 2 {
+		d.ptr = uint16(w[0])<<8 | uint16(w[1])
+		for _, b := range w[2:] {
+			reg := d.register()
+			if d.Flags[reg]&RegisterReadOnly == 0 {
+				d.Registers[reg] = b
+			}
+			d.ptr = d.Pointer.next(d.ptr)
+		}
+	}
+
+	for i := range r {
+		reg := d.register()
+		flags := d.Flags[reg]
+		if flags&RegisterWriteOnly != 0 {
+			r[i] = 0
+		} else {
+			r[i] = d.Registers[reg]
+		}
+		if flags&RegisterClearOnRead != 0 {
+			d.Registers[reg] = 0
+		}
+		d.ptr = d.Pointer.next(d.ptr)
+	}
+
+	return nil
+}
+
+// register returns the register the pointer is on, asserting that it is
+// known.
+func (d *I2CDeviceAddr16) register() uint16 {
+	if _, ok := d.Registers[d.ptr]; !ok {
+		d.c.Fatalf("register access [%#x] unknown register", d.ptr)
+	}
+	return d.ptr
+}
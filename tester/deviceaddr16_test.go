@@ -0,0 +1,35 @@
+package tester
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestTxAddr16(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDeviceAddr16(c, 0x29)
+	bus.AddDevice(d)
+	d.Registers[0x010f] = 0xea
+	d.Registers[0x0110] = 0xcc
+	d.Registers[0x0089] = 0
+
+	buf := make([]byte, 2)
+	err := bus.Tx(0x29, []byte{0x01, 0x0f}, buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf, qt.DeepEquals, []byte{0xea, 0xcc})
+
+	err = bus.Tx(0x29, []byte{0x00, 0x89, 0x42}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(d.Registers[0x0089], qt.Equals, uint8(0x42))
+}
+
+func TestTxAddr16Unknown(t *testing.T) {
+	c := qt.New(t)
+	f := &recordingFailer{}
+	d := NewI2CDeviceAddr16(f, 0x29)
+
+	d.Tx([]byte{0x12, 0x34}, make([]byte, 1))
+	c.Assert(f.msgs, qt.HasLen, 1)
+}
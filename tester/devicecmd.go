@@ -49,22 +49,16 @@ func (d *I2CDeviceCmd) Addr() uint8 {
 	return d.addr
 }
 
-// ReadRegister implements I2C.ReadRegister.
+// ReadRegister implements I2C.ReadRegister. The register is treated as
+// a single byte command.
 func (d *I2CDeviceCmd) ReadRegister(r uint8, buf []byte) error {
-	if d.Err != nil {
-		return d.Err
-	}
-
-	return nil
+	return d.Tx([]byte{r}, buf)
 }
 
-// WriteRegister implements I2C.WriteRegister.
+// WriteRegister implements I2C.WriteRegister. The register followed by
+// the data is treated as a command.
 func (d *I2CDeviceCmd) WriteRegister(r uint8, buf []byte) error {
-	if d.Err != nil {
-		return d.Err
-	}
-
-	return nil
+	return d.Tx(append([]byte{r}, buf...), nil)
 }
 
 // Tx implements I2C.Tx.
@@ -73,6 +67,11 @@ func (d *I2CDeviceCmd) Tx(w, r []byte) error {
 		return d.Err
 	}
 
+	if len(w) == 0 && len(r) == 0 {
+		// Address-only transaction, as used to probe for devices.
+		return nil
+	}
+
 	if len(w) == 0 && len(d.pendingResponse) != 0 {
 		return d.respond(r)
 	}
@@ -0,0 +1,23 @@
+package tester
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestCmdReadRegister(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDeviceCmd(c, 8)
+	d.Commands = map[uint8]*Cmd{
+		0xd0: {Command: []byte{0xd0}, Mask: []byte{0xff}, Response: []byte{0x60}},
+	}
+	bus.AddDevice(d)
+
+	buf := []byte{0}
+	err := bus.ReadRegister(8, 0xd0, buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf[0], qt.Equals, uint8(0x60))
+	c.Assert(d.Commands[0xd0].Invocations, qt.Equals, 1)
+}
@@ -0,0 +1,216 @@
+package tester
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode is the way a mock display quantizes the colors it is given.
+type ColorMode uint8
+
+const (
+	// ColorModeRGB888 stores colors unchanged, with full opacity.
+	ColorModeRGB888 ColorMode = iota
+
+	// ColorModeMonochrome turns a pixel on (white) when any of its color
+	// channels is non-zero, like the ssd1306.
+	ColorModeMonochrome
+
+	// ColorModeRGB565 truncates colors to 5 bits of red, 6 bits of green
+	// and 5 bits of blue, like the st7789 and ili9341.
+	ColorModeRGB565
+)
+
+// Display implements the Displayer interface in memory for testing.
+//
+// Pixels are quantized according to Mode when they are set, so that the
+// framebuffer holds what a real panel would show.
+type Display struct {
+	width  int16
+	height int16
+
+	// Mode is the color quantization of the display.
+	Mode ColorMode
+
+	// Displays is the number of times Display has been called.
+	Displays int
+
+	// If Err is non-nil, it will be returned as the error from Display.
+	Err error
+
+	buffer *image.RGBA
+}
+
+// NewDisplay returns a new mock display of the given size, with all
+// pixels black.
+func NewDisplay(width, height int16, mode ColorMode) *Display {
+	d := &Display{
+		width:  width,
+		height: height,
+		Mode:   mode,
+		buffer: image.NewRGBA(image.Rect(0, 0, int(width), int(height))),
+	}
+	d.Clear()
+	return d
+}
+
+// Size implements Displayer.Size.
+func (d *Display) Size() (x, y int16) {
+	return d.width, d.height
+}
+
+// SetPixel implements Displayer.SetPixel.
+func (d *Display) SetPixel(x, y int16, c color.RGBA) {
+	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+		return
+	}
+	d.buffer.SetRGBA(int(x), int(y), d.quantize(c))
+}
+
+// Display implements Displayer.Display.
+func (d *Display) Display() error {
+	d.Displays++
+	return d.Err
+}
+
+// Clear sets all pixels to black.
+func (d *Display) Clear() {
+	for i := 0; i < len(d.buffer.Pix); i += 4 {
+		d.buffer.Pix[i] = 0
+		d.buffer.Pix[i+1] = 0
+		d.buffer.Pix[i+2] = 0
+		d.buffer.Pix[i+3] = 0xff
+	}
+}
+
+// Pixel returns the color of the given pixel.
+func (d *Display) Pixel(x, y int16) color.RGBA {
+	return d.buffer.RGBAAt(int(x), int(y))
+}
+
+// Image returns a copy of the framebuffer.
+func (d *Display) Image() *image.RGBA {
+	img := image.NewRGBA(d.buffer.Rect)
+	copy(img.Pix, d.buffer.Pix)
+	return img
+}
+
+// WritePNG writes the framebuffer to w as a PNG image.
+func (d *Display) WritePNG(w io.Writer) error {
+	return png.Encode(w, d.buffer)
+}
+
+// AssertGolden asserts that the framebuffer matches the golden PNG image
+// at path. See AssertGoldenImage.
+func (d *Display) AssertGolden(c Failer, path string, tolerance uint8) {
+	AssertGoldenImage(c, d.buffer, path, tolerance)
+}
+
+func (d *Display) quantize(c color.RGBA) color.RGBA {
+	switch d.Mode {
+	case ColorModeMonochrome:
+		if c.R != 0 || c.G != 0 || c.B != 0 {
+			return color.RGBA{0xff, 0xff, 0xff, 0xff}
+		}
+		return color.RGBA{0, 0, 0, 0xff}
+	case ColorModeRGB565:
+		// Expand back to 8 bits by replicating the high bits, which is
+		// how the panels map 565 colors to their full range.
+		r := c.R & 0xf8
+		g := c.G & 0xfc
+		b := c.B & 0xf8
+		return color.RGBA{r | r>>5, g | g>>6, b | b>>5, 0xff}
+	default:
+		c.A = 0xff
+		return c
+	}
+}
+
+// AssertGoldenImage asserts that img matches the golden PNG image at path.
+// Pixels match when none of their color channels differ by more than
+// tolerance.
+//
+// On mismatch, a diff image is written next to the golden file with a
+// ".diff.png" suffix: matching pixels are shown dimmed and mismatching
+// pixels in red. When the UpdateGoldenEnv environment variable is set, the
+// golden file is written instead.
+func AssertGoldenImage(c Failer, img image.Image, path string, tolerance uint8) {
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := writePNG(path, img); err != nil {
+			c.Fatalf("writing golden image: %v", err)
+		}
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		c.Fatalf("reading golden image: %v", err)
+		return
+	}
+	want, err := png.Decode(f)
+	f.Close()
+	if err != nil {
+		c.Fatalf("decoding golden image %s: %v", path, err)
+		return
+	}
+
+	bounds := img.Bounds()
+	if want.Bounds().Size() != bounds.Size() {
+		c.Fatalf("image size %v differs from golden image %s size %v", bounds.Size(), path, want.Bounds().Size())
+		return
+	}
+
+	diff := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	mismatches := 0
+	first := image.Point{}
+	wantMin := want.Bounds().Min
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			g := color.RGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.RGBA)
+			w := color.RGBAModel.Convert(want.At(wantMin.X+x, wantMin.Y+y)).(color.RGBA)
+			if channelDiff(g.R, w.R) > tolerance || channelDiff(g.G, w.G) > tolerance ||
+				channelDiff(g.B, w.B) > tolerance || channelDiff(g.A, w.A) > tolerance {
+				if mismatches == 0 {
+					first = image.Pt(x, y)
+				}
+				mismatches++
+				diff.SetRGBA(x, y, color.RGBA{0xff, 0, 0, 0xff})
+			} else {
+				diff.SetRGBA(x, y, color.RGBA{w.R / 4, w.G / 4, w.B / 4, 0xff})
+			}
+		}
+	}
+	if mismatches == 0 {
+		return
+	}
+
+	diffPath := strings.TrimSuffix(path, ".png") + ".diff.png"
+	if err := writePNG(diffPath, diff); err != nil {
+		c.Fatalf("writing diff image: %v", err)
+		return
+	}
+	c.Fatalf("image differs from %s in %d pixels, first at %v (diff written to %s)", path, mismatches, first, diffPath)
+}
+
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,85 @@
+package tester
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+)
+
+var _ drivers.Displayer = (*Display)(nil)
+
+// drawCross draws a diagonal cross in a few colors.
+func drawCross(d drivers.Displayer) {
+	w, h := d.Size()
+	for i := int16(0); i < w && i < h; i++ {
+		d.SetPixel(i, i, color.RGBA{0xff, 0x10, 0x10, 0xff})
+		d.SetPixel(w-1-i, i, color.RGBA{0x20, 0x80, 0xc3, 0xff})
+	}
+	d.Display()
+}
+
+func TestDisplayQuantize(t *testing.T) {
+	c := qt.New(t)
+
+	d := NewDisplay(4, 4, ColorModeMonochrome)
+	d.SetPixel(1, 1, color.RGBA{0, 0, 1, 0})
+	c.Assert(d.Pixel(1, 1), qt.Equals, color.RGBA{0xff, 0xff, 0xff, 0xff})
+	c.Assert(d.Pixel(0, 0), qt.Equals, color.RGBA{0, 0, 0, 0xff})
+
+	d = NewDisplay(4, 4, ColorModeRGB565)
+	d.SetPixel(1, 1, color.RGBA{0x20, 0x80, 0xc3, 0xff})
+	c.Assert(d.Pixel(1, 1), qt.Equals, color.RGBA{0x21, 0x82, 0xc6, 0xff})
+
+	// Out of range pixels are ignored.
+	d.SetPixel(4, 0, color.RGBA{0xff, 0xff, 0xff, 0xff})
+	d.SetPixel(-1, 0, color.RGBA{0xff, 0xff, 0xff, 0xff})
+}
+
+func TestDisplayPNG(t *testing.T) {
+	c := qt.New(t)
+	d := NewDisplay(8, 6, ColorModeRGB888)
+	drawCross(d)
+	c.Assert(d.Displays, qt.Equals, 1)
+
+	var buf bytes.Buffer
+	c.Assert(d.WritePNG(&buf), qt.IsNil)
+	img, err := png.Decode(&buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(img.Bounds().Dx(), qt.Equals, 8)
+	c.Assert(color.RGBAModel.Convert(img.At(2, 2)), qt.Equals, color.Color(color.RGBA{0xff, 0x10, 0x10, 0xff}))
+}
+
+func TestDisplayGolden(t *testing.T) {
+	c := qt.New(t)
+	d := NewDisplay(8, 6, ColorModeRGB565)
+	drawCross(d)
+	d.AssertGolden(c, "testdata/cross.png", 0)
+}
+
+func TestDisplayGoldenMismatch(t *testing.T) {
+	c := qt.New(t)
+	dir := c.Mkdir()
+	golden, err := ioutil.ReadFile("testdata/cross.png")
+	c.Assert(err, qt.IsNil)
+	path := filepath.Join(dir, "cross.png")
+	c.Assert(ioutil.WriteFile(path, golden, 0644), qt.IsNil)
+
+	// RGB888 differs slightly from the RGB565 golden image.
+	d := NewDisplay(8, 6, ColorModeRGB888)
+	drawCross(d)
+	d.AssertGolden(c, path, 8)
+
+	f := &recordingFailer{}
+	d.AssertGolden(f, path, 0)
+	c.Assert(f.msgs, qt.HasLen, 1)
+	c.Assert(f.msgs[0], qt.Contains, "in 6 pixels")
+	_, err = os.Stat(filepath.Join(dir, "cross.diff.png"))
+	c.Assert(err, qt.IsNil)
+}
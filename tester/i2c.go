@@ -1,11 +1,24 @@
 package tester
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoDevice is returned by an I2CBus with AllowMissing set for
+// transactions to an address without a device.
+var ErrNoDevice = errors.New("tester: no device at address")
 
 // I2CBus implements the I2C interface in memory for testing.
 type I2CBus struct {
+	// AllowMissing makes transactions to addresses without a device fail
+	// with ErrNoDevice, like a real bus reporting a NACK, instead of
+	// aborting the test.
+	AllowMissing bool
+
 	c       Failer
 	devices []I2CDevice
+	trace   *Trace
 }
 
 // NewI2CBus returns an I2CBus mock I2C instance that uses c to flag errors
@@ -36,19 +49,56 @@ func (bus *I2CBus) NewDevice(addr uint8) *I2CDevice8 {
 	return dev
 }
 
+// Record starts recording all transactions on the bus into a new trace,
+// which is returned.
+func (bus *I2CBus) Record() *Trace {
+	bus.trace = &Trace{}
+	return bus.trace
+}
+
 // ReadRegister implements I2C.ReadRegister.
 func (bus *I2CBus) ReadRegister(addr uint8, r uint8, buf []byte) error {
-	return bus.FindDevice(addr).ReadRegister(r, buf)
+	if bus.missing(addr) {
+		return ErrNoDevice
+	}
+	err := bus.FindDevice(addr).ReadRegister(r, buf)
+	bus.trace.add("i2c", uint16(addr), []byte{r}, buf)
+	return err
 }
 
 // WriteRegister implements I2C.WriteRegister.
 func (bus *I2CBus) WriteRegister(addr uint8, r uint8, buf []byte) error {
-	return bus.FindDevice(addr).WriteRegister(r, buf)
+	if bus.missing(addr) {
+		return ErrNoDevice
+	}
+	err := bus.FindDevice(addr).WriteRegister(r, buf)
+	bus.trace.add("i2c", uint16(addr), append([]byte{r}, buf...), nil)
+	return err
 }
 
 // Tx implements I2C.Tx.
 func (bus *I2CBus) Tx(addr uint16, w, r []byte) error {
-	return bus.FindDevice(uint8(addr)).Tx(w, r)
+	if bus.missing(uint8(addr)) {
+		return ErrNoDevice
+	}
+	written := append([]byte(nil), w...)
+	err := bus.FindDevice(uint8(addr)).Tx(w, r)
+	bus.trace.add("i2c", addr, written, r)
+	return err
+}
+
+// missing returns whether there is no device at addr and AllowMissing is
+// set.
+func (bus *I2CBus) missing(addr uint8) bool {
+	if !bus.AllowMissing {
+		return false
+	}
+	for _, dev := range bus.devices {
+		if dev.Addr() == addr {
+			return false
+		}
+	}
+	return true
 }
 
 // FindDevice returns the device with the given address.
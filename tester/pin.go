@@ -0,0 +1,137 @@
+package tester
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+// PinEvent is a recorded level change of a mock pin.
+type PinEvent struct {
+	Time time.Time
+	High bool
+}
+
+// Pin implements the Pin, ConfigurablePin and InterruptPin interfaces in
+// memory for testing.
+//
+// The level of the line is what the pin drives while it is an output. While
+// it is an input, the line is at the level driven from outside with Drive,
+// or, when nothing drives it, high if PullUp is set or the pin is configured
+// with a pull-up, and low otherwise. Every change of the line level is
+// recorded in Events.
+type Pin struct {
+	// Mode is the mode the pin was last configured in.
+	Mode drivers.PinMode
+
+	// PullUp models an external pull-up resistor on the line.
+	PullUp bool
+
+	// Events holds every change of the line level.
+	Events []PinEvent
+
+	out      bool
+	ext      bool
+	driven   bool
+	level    bool
+	change   drivers.PinChange
+	callback func(drivers.Pin)
+}
+
+// NewPin returns a new mock pin configured as an output driving low.
+func NewPin() *Pin {
+	return &Pin{}
+}
+
+// Configure implements ConfigurablePin.Configure.
+func (p *Pin) Configure(mode drivers.PinMode) {
+	p.Mode = mode
+	p.update()
+}
+
+// Set implements Pin.Set.
+func (p *Pin) Set(high bool) {
+	p.out = high
+	p.update()
+}
+
+// High drives the pin high.
+func (p *Pin) High() {
+	p.Set(true)
+}
+
+// Low drives the pin low.
+func (p *Pin) Low() {
+	p.Set(false)
+}
+
+// Get implements Pin.Get.
+func (p *Pin) Get() bool {
+	return p.level
+}
+
+// SetInterrupt implements InterruptPin.SetInterrupt. The callback is called
+// synchronously when the line level changes.
+func (p *Pin) SetInterrupt(change drivers.PinChange, callback func(drivers.Pin)) error {
+	if callback != nil && change&drivers.PinToggle == 0 {
+		return errors.New("tester: invalid pin change")
+	}
+	p.change = change
+	p.callback = callback
+	return nil
+}
+
+// Drive drives the line to the given level from outside, as a device
+// connected to the pin would. It only affects the line while the pin is an
+// input.
+func (p *Pin) Drive(high bool) {
+	p.ext = high
+	p.driven = true
+	p.update()
+}
+
+// Release stops driving the line from outside.
+func (p *Pin) Release() {
+	p.driven = false
+	p.update()
+}
+
+// Levels returns the sequence of line levels recorded in Events.
+func (p *Pin) Levels() []bool {
+	levels := make([]bool, len(p.Events))
+	for i, e := range p.Events {
+		levels[i] = e.High
+	}
+	return levels
+}
+
+// Reset clears the recorded events.
+func (p *Pin) Reset() {
+	p.Events = nil
+}
+
+// update recomputes the line level, recording and signalling changes.
+func (p *Pin) update() {
+	var level bool
+	switch {
+	case p.Mode == drivers.PinOutput:
+		level = p.out
+	case p.driven:
+		level = p.ext
+	default:
+		level = p.PullUp || p.Mode == drivers.PinInputPullup
+	}
+	if level == p.level {
+		return
+	}
+	p.level = level
+	p.Events = append(p.Events, PinEvent{Time: time.Now(), High: level})
+
+	if p.callback == nil {
+		return
+	}
+	if (level && p.change&drivers.PinRising != 0) || (!level && p.change&drivers.PinFalling != 0) {
+		p.callback(p)
+	}
+}
@@ -0,0 +1,47 @@
+package tester
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+)
+
+var _ drivers.InterruptPin = (*Pin)(nil)
+var _ drivers.ConfigurablePin = (*Pin)(nil)
+
+func TestPinLevels(t *testing.T) {
+	c := qt.New(t)
+	p := NewPin()
+	drivers.ConfigurePin(p, drivers.PinOutput)
+	p.High()
+	p.High()
+	p.Low()
+	c.Assert(p.Levels(), qt.DeepEquals, []bool{true, false})
+
+	// An input follows the external driver, or the pull-up when released.
+	drivers.ConfigurePin(p, drivers.PinInput)
+	p.Drive(true)
+	c.Assert(p.Get(), qt.IsTrue)
+	p.Release()
+	c.Assert(p.Get(), qt.IsFalse)
+	drivers.ConfigurePin(p, drivers.PinInputPullup)
+	c.Assert(p.Get(), qt.IsTrue)
+	c.Assert(p.Levels(), qt.DeepEquals, []bool{true, false, true, false, true})
+}
+
+func TestPinInterrupt(t *testing.T) {
+	c := qt.New(t)
+	p := NewPin()
+	p.Configure(drivers.PinInput)
+
+	rising := 0
+	err := p.SetInterrupt(drivers.PinRising, func(drivers.Pin) { rising++ })
+	c.Assert(err, qt.IsNil)
+	p.Drive(true)
+	p.Drive(false)
+	p.Drive(true)
+	c.Assert(rising, qt.Equals, 2)
+
+	c.Assert(p.SetInterrupt(0, func(drivers.Pin) {}), qt.Not(qt.IsNil))
+}
@@ -0,0 +1,89 @@
+package tester
+
+import "bytes"
+
+// I2CDeviceReplay represents a mock I2C device that replays the
+// transactions of a recorded trace.
+//
+// Every transaction the device receives must write the same bytes as the
+// next recorded transaction for its address. The recorded read bytes are
+// returned to the driver.
+type I2CDeviceReplay struct {
+	c Failer
+
+	// addr is the i2c device address.
+	addr uint8
+
+	// Transactions holds the recorded transactions that have not been
+	// replayed yet.
+	Transactions []Transaction
+
+	// If Err is non-nil, it will be returned as the error from the
+	// I2C methods.
+	Err error
+}
+
+// NewI2CDeviceReplay returns a new mock I2C device that replays the
+// transactions for addr in trace.
+func NewI2CDeviceReplay(c Failer, addr uint8, trace *Trace) *I2CDeviceReplay {
+	return &I2CDeviceReplay{
+		c:            c,
+		addr:         addr,
+		Transactions: trace.Filter("i2c", uint16(addr)),
+	}
+}
+
+// Addr returns the Device address.
+func (d *I2CDeviceReplay) Addr() uint8 {
+	return d.addr
+}
+
+// ReadRegister implements I2C.ReadRegister.
+func (d *I2CDeviceReplay) ReadRegister(r uint8, buf []byte) error {
+	return d.Tx([]byte{r}, buf)
+}
+
+// WriteRegister implements I2C.WriteRegister.
+func (d *I2CDeviceReplay) WriteRegister(r uint8, buf []byte) error {
+	return d.Tx(append([]byte{r}, buf...), nil)
+}
+
+// Tx implements I2C.Tx.
+func (d *I2CDeviceReplay) Tx(w, r []byte) error {
+	if d.Err != nil {
+		return d.Err
+	}
+
+	if len(d.Transactions) == 0 {
+		d.c.Fatalf("replay [%#x] unexpected transaction w:%x", d.addr, w)
+		return nil
+	}
+	tx := d.Transactions[0]
+	d.Transactions = d.Transactions[1:]
+
+	if !bytes.Equal(tx.W, w) && (len(tx.W) != 0 || len(w) != 0) {
+		d.c.Fatalf("replay [%#x] write mismatch (expected: w:%x, got: w:%x)", d.addr, tx.W, w)
+	}
+	if len(tx.R) != len(r) {
+		d.c.Fatalf("replay [%#x] read size mismatch (expected: %d, got: %d)", d.addr, len(tx.R), len(r))
+	}
+	copy(r, tx.R)
+	return nil
+}
+
+// AssertDone asserts that the whole trace has been replayed.
+func (d *I2CDeviceReplay) AssertDone() {
+	if len(d.Transactions) != 0 {
+		d.c.Fatalf("replay [%#x] %d transactions not replayed, next: %s", d.addr, len(d.Transactions), d.Transactions[0])
+	}
+}
+
+// NewSPIDeviceReplay returns a scripted mock SPI device that expects the
+// SPI transactions of trace and responds with the recorded data.
+func NewSPIDeviceReplay(c Failer, trace *Trace) *SPIDeviceScript {
+	d := NewSPIDeviceScript(c)
+	for _, tx := range trace.Filter("spi", 0) {
+		d.Expect(tx.W, tx.R)
+	}
+	return d
+}
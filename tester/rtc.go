@@ -0,0 +1,118 @@
+package tester
+
+import (
+	"bytes"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+// BCDClock describes the time and date registers of a real-time clock that
+// stores the time in BCD and the years from 2000 to 2099, for CheckBCDClock.
+type BCDClock struct {
+	// Registers are the seven time and date registers of the mock device:
+	// the second, minute and hour, the weekday and day of the month, the
+	// month and the year.
+	Registers []uint8
+
+	// WeekdayBase is the value of the weekday register on Sunday. The
+	// register counts up to Saturday, so it holds 0-6 or 1-7.
+	WeekdayBase uint8
+
+	// DayFirst is set when the day of the month comes before the weekday.
+	DayFirst bool
+
+	// Hour12 is set when the hour register has a 12-hour mode.
+	Hour12 bool
+}
+
+// bcdTime is a time and the values of its registers, with the weekday
+// counted from Sunday as 0.
+type bcdTime struct {
+	t                                               time.Time
+	second, minute, hour, weekday, day, month, year uint8
+}
+
+var bcdTimes = []bcdTime{
+	{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), 0x00, 0x00, 0x00, 0x06, 0x01, 0x01, 0x00},
+	{time.Date(2000, 2, 29, 12, 34, 56, 0, time.UTC), 0x56, 0x34, 0x12, 0x02, 0x29, 0x02, 0x00},
+	{time.Date(2023, 1, 1, 9, 5, 7, 0, time.UTC), 0x07, 0x05, 0x09, 0x00, 0x01, 0x01, 0x23},
+	{time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC), 0x59, 0x59, 0x23, 0x04, 0x29, 0x02, 0x24},
+	{time.Date(2099, 12, 31, 23, 59, 59, 0, time.UTC), 0x59, 0x59, 0x23, 0x04, 0x31, 0x12, 0x99},
+}
+
+// registers returns the register values of t in the order of clock.
+func (clock *BCDClock) registers(t bcdTime) []uint8 {
+	weekday := t.weekday + clock.WeekdayBase
+	if clock.DayFirst {
+		return []uint8{t.second, t.minute, t.hour, t.day, weekday, t.month, t.year}
+	}
+	return []uint8{t.second, t.minute, t.hour, weekday, t.day, t.month, t.year}
+}
+
+// CheckBCDClock checks that rtc stores times in the registers described by
+// clock and reads them back, in UTC. It also checks that the years outside
+// 2000 to 2099 are rejected without changing the registers, and that hours
+// in 12-hour mode are read correctly if the clock has one.
+func CheckBCDClock(c Failer, rtc drivers.RTC, clock BCDClock) {
+	for _, test := range bcdTimes {
+		if err := rtc.SetTime(test.t); err != nil {
+			c.Fatalf("SetTime(%v): %v", test.t, err)
+		}
+		if want := clock.registers(test); !bytes.Equal(clock.Registers, want) {
+			c.Fatalf("SetTime(%v): registers % x, want % x", test.t, clock.Registers, want)
+		}
+		checkReadTime(c, rtc, test.t)
+	}
+
+	// The time is stored in UTC.
+	zone := time.FixedZone("UTC-5", -5*60*60)
+	if err := rtc.SetTime(time.Date(2023, 12, 31, 22, 0, 0, 0, zone)); err != nil {
+		c.Fatalf("SetTime: %v", err)
+	}
+	checkReadTime(c, rtc, time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC))
+
+	before := append([]uint8(nil), clock.Registers...)
+	for _, t := range []time.Time{
+		time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2100, 2, 28, 0, 0, 0, 0, time.UTC),
+	} {
+		if err := rtc.SetTime(t); err != drivers.ErrTimeRange {
+			c.Fatalf("SetTime(%v): got error %v, want %v", t, err, drivers.ErrTimeRange)
+		}
+		if !bytes.Equal(clock.Registers, before) {
+			c.Fatalf("SetTime(%v) changed the registers to % x", t, clock.Registers)
+		}
+	}
+
+	if !clock.Hour12 {
+		return
+	}
+	// In 12-hour mode, bit 6 of the hour is set and bit 5 is set after
+	// noon.
+	for _, test := range []struct {
+		hour uint8
+		want int
+	}{
+		{0x52, 0},  // 12 AM
+		{0x41, 1},  // 1 AM
+		{0x72, 12}, // 12 PM
+		{0x71, 23}, // 11 PM
+	} {
+		leap := bcdTimes[3]
+		leap.hour = test.hour
+		copy(clock.Registers, clock.registers(leap))
+		checkReadTime(c, rtc, time.Date(2024, 2, 29, test.want, 59, 59, 0, time.UTC))
+	}
+}
+
+func checkReadTime(c Failer, rtc drivers.RTC, want time.Time) {
+	got, err := rtc.ReadTime()
+	if err != nil {
+		c.Fatalf("ReadTime: %v", err)
+	}
+	if !got.Equal(want) || got.Location() != time.UTC {
+		c.Fatalf("ReadTime: got %v, want %v", got, want)
+	}
+}
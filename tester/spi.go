@@ -0,0 +1,98 @@
+package tester
+
+// SPIDevice represents a mock device attached to a mock SPI bus.
+type SPIDevice interface {
+	// Tx implements SPI.Tx.
+	Tx(w, r []byte) error
+
+	// Transfer implements SPI.Transfer.
+	Transfer(b byte) (byte, error)
+
+	// Selected returns whether the chip select line of the device is
+	// currently asserted.
+	Selected() bool
+}
+
+// SPIBus implements the SPI interface in memory for testing.
+//
+// Traffic is routed to the device whose chip select is asserted. Since
+// most drivers drive the chip select pin themselves, a bus with a single
+// device routes all traffic to it when no device is explicitly selected.
+type SPIBus struct {
+	c       Failer
+	devices []SPIDevice
+	trace   *Trace
+}
+
+// NewSPIBus returns an SPIBus mock SPI instance that uses c to flag errors
+// if they happen. After creating a SPI instance, add devices to it with
+// AddDevice before using the SPI interface.
+func NewSPIBus(c Failer) *SPIBus {
+	return &SPIBus{
+		c: c,
+	}
+}
+
+// AddDevice adds a new mock device to the mock SPI bus.
+func (bus *SPIBus) AddDevice(d SPIDevice) {
+	bus.devices = append(bus.devices, d)
+}
+
+// NewDevice creates a new scripted device and adds it to the mock SPI bus.
+func (bus *SPIBus) NewDevice() *SPIDeviceScript {
+	dev := NewSPIDeviceScript(bus.c)
+	bus.AddDevice(dev)
+	return dev
+}
+
+// Record starts recording all transactions on the bus into a new trace,
+// which is returned.
+func (bus *SPIBus) Record() *Trace {
+	bus.trace = &Trace{}
+	return bus.trace
+}
+
+// Tx implements SPI.Tx.
+func (bus *SPIBus) Tx(w, r []byte) error {
+	if w != nil && r != nil && len(w) != len(r) {
+		bus.c.Fatalf("spi tx with mismatched buffers (w: %d bytes, r: %d bytes)", len(w), len(r))
+	}
+	// w and r may be the same buffer, so keep a copy of the written data.
+	written := append([]byte(nil), w...)
+	if w == nil {
+		written = make([]byte, len(r))
+	}
+	err := bus.FindDevice().Tx(w, r)
+	bus.trace.add("spi", 0, written, r)
+	return err
+}
+
+// Transfer implements SPI.Transfer.
+func (bus *SPIBus) Transfer(b byte) (byte, error) {
+	rb, err := bus.FindDevice().Transfer(b)
+	bus.trace.add("spi", 0, []byte{b}, []byte{rb})
+	return rb, err
+}
+
+// FindDevice returns the device that currently has its chip select
+// asserted.
+func (bus *SPIBus) FindDevice() SPIDevice {
+	var found SPIDevice
+	for _, dev := range bus.devices {
+		if !dev.Selected() {
+			continue
+		}
+		if found != nil {
+			bus.c.Fatalf("more than one device selected on spi bus")
+		}
+		found = dev
+	}
+	if found != nil {
+		return found
+	}
+	if len(bus.devices) == 1 {
+		return bus.devices[0]
+	}
+	bus.c.Fatalf("no device selected on spi bus")
+	panic("unreachable")
+}
@@ -0,0 +1,86 @@
+package tester
+
+import (
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// recordingFailer records failures instead of aborting the test, so that
+// the failure paths of the mocks can be checked.
+type recordingFailer struct {
+	msgs []string
+}
+
+func (f *recordingFailer) Fatalf(format string, a ...interface{}) {
+	f.msgs = append(f.msgs, fmt.Sprintf(format, a...))
+}
+
+func TestSPIScript(t *testing.T) {
+	c := qt.New(t)
+	bus := NewSPIBus(c)
+	d := bus.NewDevice()
+	d.Expect([]byte{0x80 | 0x12, 0}, []byte{0, 0xd1})
+	d.Expect([]byte{0x05}, nil)
+
+	buf := []byte{0x80 | 0x12, 0}
+	err := bus.Tx(buf, buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf[1], qt.Equals, byte(0xd1))
+
+	_, err = bus.Transfer(0x05)
+	c.Assert(err, qt.IsNil)
+	d.AssertDone()
+
+	c.Assert(d.Transactions, qt.HasLen, 2)
+	c.Assert(d.Transactions[1].W, qt.DeepEquals, []byte{0x05})
+}
+
+func TestSPIReadOnly(t *testing.T) {
+	c := qt.New(t)
+	bus := NewSPIBus(c)
+	d := bus.NewDevice()
+	d.Expect(nil, []byte{1, 2, 3})
+
+	buf := make([]byte, 3)
+	c.Assert(bus.Tx(nil, buf), qt.IsNil)
+	c.Assert(buf, qt.DeepEquals, []byte{1, 2, 3})
+	c.Assert(d.Transactions[0].W, qt.DeepEquals, []byte{0, 0, 0})
+}
+
+func TestSPIChipSelect(t *testing.T) {
+	c := qt.New(t)
+	bus := NewSPIBus(c)
+	a := bus.NewDevice()
+	b := bus.NewDevice()
+	a.Expect([]byte{0xaa}, nil)
+	b.Expect([]byte{0xbb}, nil)
+
+	b.Select()
+	bus.Transfer(0xbb)
+	b.Deselect()
+	a.Select()
+	bus.Transfer(0xaa)
+	a.Deselect()
+
+	a.AssertDone()
+	b.AssertDone()
+	c.Assert(a.Transactions[0].Frame, qt.Equals, 1)
+}
+
+func TestSPIUnexpected(t *testing.T) {
+	c := qt.New(t)
+	f := &recordingFailer{}
+	bus := NewSPIBus(f)
+	d := bus.NewDevice()
+	d.Expect([]byte{0x01}, nil)
+
+	bus.Transfer(0x02)
+	bus.Transfer(0x03)
+	c.Assert(f.msgs, qt.HasLen, 2)
+
+	d.Expect([]byte{0x04}, nil)
+	d.AssertDone()
+	c.Assert(f.msgs, qt.HasLen, 3)
+}
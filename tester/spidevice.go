@@ -0,0 +1,136 @@
+package tester
+
+import "bytes"
+
+// SPIExchange is a single expected Tx or Transfer call on a scripted SPI
+// device.
+//
+// A call matches when the written bytes equal W. If W is nil, any write of
+// the same length as Response is accepted. Response is returned to the
+// driver in the read buffer.
+type SPIExchange struct {
+	W        []byte
+	Response []byte
+}
+
+// SPITransaction is a recorded Tx or Transfer call on a mock SPI device.
+type SPITransaction struct {
+	// Frame is the number of the chip select frame the transaction
+	// happened in. It is incremented every time the device is selected.
+	Frame int
+	W     []byte
+	R     []byte
+}
+
+// SPIDeviceScript represents a mock SPI device that checks the traffic it
+// receives against a script of expected exchanges.
+//
+// Exchanges are added with Expect and consumed in order. Traffic that does
+// not match the next exchange, or arrives once the script is exhausted, is
+// reported through the Failer. Every call is appended to Transactions.
+type SPIDeviceScript struct {
+	c Failer
+
+	// Script holds the exchanges that have not happened yet.
+	Script []*SPIExchange
+
+	// Transactions holds every call received by the device.
+	Transactions []SPITransaction
+
+	// If Err is non-nil, it will be returned as the error from the
+	// SPI methods.
+	Err error
+
+	selected bool
+	frame    int
+}
+
+// NewSPIDeviceScript returns a new scripted mock SPI device.
+func NewSPIDeviceScript(c Failer) *SPIDeviceScript {
+	return &SPIDeviceScript{
+		c: c,
+	}
+}
+
+// Expect appends an exchange to the script. If w is nil the written bytes
+// are not checked.
+func (d *SPIDeviceScript) Expect(w, response []byte) *SPIExchange {
+	e := &SPIExchange{W: w, Response: response}
+	d.Script = append(d.Script, e)
+	return e
+}
+
+// Select asserts the chip select line of the device.
+func (d *SPIDeviceScript) Select() {
+	if !d.selected {
+		d.frame++
+	}
+	d.selected = true
+}
+
+// Deselect releases the chip select line of the device.
+func (d *SPIDeviceScript) Deselect() {
+	d.selected = false
+}
+
+// Selected returns whether the chip select line of the device is asserted.
+func (d *SPIDeviceScript) Selected() bool {
+	return d.selected
+}
+
+// Tx implements SPI.Tx.
+func (d *SPIDeviceScript) Tx(w, r []byte) error {
+	if d.Err != nil {
+		return d.Err
+	}
+
+	n := len(w)
+	if w == nil {
+		n = len(r)
+	}
+	written := w
+	if written == nil {
+		written = make([]byte, n)
+	}
+
+	if len(d.Script) == 0 {
+		d.c.Fatalf("unexpected spi tx %#x", written)
+		return nil
+	}
+	e := d.Script[0]
+	d.Script = d.Script[1:]
+
+	if e.W != nil && !bytes.Equal(e.W, written) {
+		d.c.Fatalf("spi tx mismatch (expected: %#x, got: %#x)", e.W, written)
+	}
+	if e.Response != nil && len(e.Response) != n {
+		d.c.Fatalf("spi response size mismatch (expected: %d, got: %d)", len(e.Response), n)
+	}
+	if r != nil {
+		for i := range r {
+			r[i] = 0
+		}
+		copy(r, e.Response)
+	}
+
+	d.Transactions = append(d.Transactions, SPITransaction{
+		Frame: d.frame,
+		W:     append([]byte(nil), written...),
+		R:     append([]byte(nil), r...),
+	})
+	return nil
+}
+
+// Transfer implements SPI.Transfer.
+func (d *SPIDeviceScript) Transfer(b byte) (byte, error) {
+	r := []byte{0}
+	err := d.Tx([]byte{b}, r)
+	return r[0], err
+}
+
+// AssertDone asserts that every scripted exchange has happened.
+func (d *SPIDeviceScript) AssertDone() {
+	if len(d.Script) != 0 {
+		d.c.Fatalf("%d spi exchanges did not happen, next: %#x", len(d.Script), d.Script[0].W)
+	}
+}
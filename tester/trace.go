@@ -0,0 +1,199 @@
+package tester
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a
+// non-empty value, makes AssertGolden rewrite golden files instead of
+// comparing against them.
+const UpdateGoldenEnv = "TESTER_UPDATE_GOLDEN"
+
+// Transaction is a single recorded bus transaction.
+//
+// I2C transactions are recorded as they appear on the wire: W holds the
+// bytes written to the device, including the register pointer, and R the
+// bytes read back afterwards. SPI transactions hold the bytes shifted out
+// in W and the bytes shifted in in R. UART transactions hold either the
+// written or the read data.
+type Transaction struct {
+	// Bus is the kind of bus: "i2c", "spi" or "uart".
+	Bus string
+
+	// Addr is the device address for I2C transactions.
+	Addr uint16
+
+	W []byte
+	R []byte
+}
+
+// String returns the transaction in the trace text format, for example
+// "i2c 0x76 w:f7 r:0011223344".
+func (t Transaction) String() string {
+	s := t.Bus
+	if t.Bus == "i2c" {
+		s += fmt.Sprintf(" 0x%02x", t.Addr)
+	}
+	if len(t.W) > 0 {
+		s += " w:" + hex.EncodeToString(t.W)
+	}
+	if len(t.R) > 0 {
+		s += " r:" + hex.EncodeToString(t.R)
+	}
+	return s
+}
+
+// Trace is an ordered log of bus transactions.
+type Trace struct {
+	Transactions []Transaction
+}
+
+// add records a transaction, copying the buffers.
+func (t *Trace) add(bus string, addr uint16, w, r []byte) {
+	if t == nil {
+		return
+	}
+	tx := Transaction{Bus: bus, Addr: addr}
+	if len(w) > 0 {
+		tx.W = append([]byte(nil), w...)
+	}
+	if len(r) > 0 {
+		tx.R = append([]byte(nil), r...)
+	}
+	t.Transactions = append(t.Transactions, tx)
+}
+
+// String returns the trace in its text format, one transaction per line.
+func (t *Trace) String() string {
+	var b strings.Builder
+	for _, tx := range t.Transactions {
+		b.WriteString(tx.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Filter returns the transactions of the trace on the given bus and, for
+// I2C, the given address.
+func (t *Trace) Filter(bus string, addr uint16) []Transaction {
+	var txs []Transaction
+	for _, tx := range t.Transactions {
+		if tx.Bus == bus && (bus != "i2c" || tx.Addr == addr) {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+// ParseTrace parses a trace in its text format. Empty lines and lines
+// starting with '#' are ignored.
+func ParseTrace(s string) (*Trace, error) {
+	t := &Trace{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		tx, err := parseTransaction(line)
+		if err != nil {
+			return nil, fmt.Errorf("trace line %d: %v", n, err)
+		}
+		t.Transactions = append(t.Transactions, tx)
+	}
+	return t, nil
+}
+
+func parseTransaction(line string) (Transaction, error) {
+	fields := strings.Fields(line)
+	tx := Transaction{Bus: fields[0]}
+	switch tx.Bus {
+	case "i2c":
+		if len(fields) < 2 {
+			return tx, errors.New("missing address")
+		}
+		addr, err := strconv.ParseUint(fields[1], 0, 16)
+		if err != nil {
+			return tx, err
+		}
+		tx.Addr = uint16(addr)
+		fields = fields[2:]
+	case "spi", "uart":
+		fields = fields[1:]
+	default:
+		return tx, fmt.Errorf("unknown bus %q", tx.Bus)
+	}
+
+	for _, f := range fields {
+		var err error
+		switch {
+		case strings.HasPrefix(f, "w:"):
+			tx.W, err = hex.DecodeString(f[2:])
+		case strings.HasPrefix(f, "r:"):
+			tx.R, err = hex.DecodeString(f[2:])
+		default:
+			err = fmt.Errorf("unknown field %q", f)
+		}
+		if err != nil {
+			return tx, err
+		}
+	}
+	return tx, nil
+}
+
+// ReadTrace reads a trace from a file in its text format.
+func ReadTrace(path string) (*Trace, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTrace(string(data))
+}
+
+// AssertGolden asserts that the trace matches the golden file at path.
+//
+// When the UpdateGoldenEnv environment variable is set, the golden file is
+// written instead.
+func (t *Trace) AssertGolden(c Failer, path string) {
+	got := t.String()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			c.Fatalf("writing golden trace: %v", err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		c.Fatalf("reading golden trace: %v", err)
+		return
+	}
+	if bytes.Equal(want, []byte(got)) {
+		return
+	}
+
+	gotLines := strings.Split(got, "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			c.Fatalf("trace differs from %s at line %d\nwant: %s\ngot:  %s", path, i+1, w, g)
+			return
+		}
+	}
+	c.Fatalf("trace differs from %s", path)
+}
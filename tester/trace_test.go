@@ -0,0 +1,90 @@
+package tester
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestTraceRecord(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := bus.NewDevice(0x76)
+	d.Registers[0xd0] = 0x60
+	trace := bus.Record()
+
+	buf := []byte{0}
+	bus.ReadRegister(0x76, 0xd0, buf)
+	bus.WriteRegister(0x76, 0xf4, []byte{0x27})
+	bus.Tx(0x76, []byte{0xf4}, buf)
+
+	c.Assert(trace.String(), qt.Equals, "i2c 0x76 w:d0 r:60\ni2c 0x76 w:f427\ni2c 0x76 w:f4 r:27\n")
+	trace.AssertGolden(c, "testdata/i2c.trace")
+}
+
+func TestTraceParse(t *testing.T) {
+	c := qt.New(t)
+	trace, err := ReadTrace("testdata/i2c.trace")
+	c.Assert(err, qt.IsNil)
+	c.Assert(trace.Transactions, qt.HasLen, 3)
+	c.Assert(trace.Transactions[0], qt.DeepEquals, Transaction{Bus: "i2c", Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}})
+
+	_, err = ParseTrace("can 0x12 w:00\n")
+	c.Assert(err, qt.ErrorMatches, `trace line 1: unknown bus "can"`)
+}
+
+func TestTraceGoldenMismatch(t *testing.T) {
+	c := qt.New(t)
+	f := &recordingFailer{}
+	trace, _ := ParseTrace("i2c 0x76 w:d0 r:61\n")
+	trace.AssertGolden(f, "testdata/i2c.trace")
+	c.Assert(f.msgs, qt.HasLen, 1)
+	c.Assert(f.msgs[0], qt.Contains, "line 1")
+}
+
+func TestReplayI2C(t *testing.T) {
+	c := qt.New(t)
+	trace, err := ReadTrace("testdata/i2c.trace")
+	c.Assert(err, qt.IsNil)
+
+	bus := NewI2CBus(c)
+	d := NewI2CDeviceReplay(c, 0x76, trace)
+	bus.AddDevice(d)
+
+	buf := []byte{0}
+	bus.ReadRegister(0x76, 0xd0, buf)
+	c.Assert(buf[0], qt.Equals, uint8(0x60))
+	bus.WriteRegister(0x76, 0xf4, []byte{0x27})
+	bus.Tx(0x76, []byte{0xf4}, buf)
+	c.Assert(buf[0], qt.Equals, uint8(0x27))
+	d.AssertDone()
+}
+
+func TestReplaySPI(t *testing.T) {
+	c := qt.New(t)
+	bus := NewSPIBus(c)
+	d := bus.NewDevice()
+	d.Expect([]byte{0x9f, 0, 0, 0}, []byte{0, 0xef, 0x40, 0x18})
+	trace := bus.Record()
+	buf := []byte{0x9f, 0, 0, 0}
+	bus.Tx(buf, buf)
+	c.Assert(trace.String(), qt.Equals, "spi w:9f000000 r:00ef4018\n")
+
+	replay := NewSPIBus(c)
+	replay.AddDevice(NewSPIDeviceReplay(c, trace))
+	buf = []byte{0x9f, 0, 0, 0}
+	replay.Tx(buf, buf)
+	c.Assert(buf, qt.DeepEquals, []byte{0, 0xef, 0x40, 0x18})
+}
+
+func TestTraceUART(t *testing.T) {
+	c := qt.New(t)
+	u := NewUART(c)
+	u.Reply([]byte("AT\r\n"), []byte("OK\r\n"))
+	trace := u.Record()
+
+	u.Write([]byte("AT\r\n"))
+	buf := make([]byte, 8)
+	u.Read(buf)
+	c.Assert(trace.String(), qt.Equals, "uart w:41540d0a\nuart r:4f4b0d0a\n")
+}
@@ -0,0 +1,217 @@
+package tester
+
+import (
+	"bytes"
+	"time"
+)
+
+// UARTRule is a canned reply of a mock UART.
+//
+// When the data written by the driver contains Match, Response is queued
+// for the driver to read. If ChunkSize is non-zero the response is
+// delivered in chunks of at most ChunkSize bytes, and each chunk becomes
+// readable Gap after the previous one.
+type UARTRule struct {
+	Match     []byte
+	Response  []byte
+	ChunkSize int
+	Gap       time.Duration
+
+	// Once removes the rule after it has been triggered a single time.
+	Once bool
+
+	Invocations int
+}
+
+// uartChunk is data that becomes readable at a given time.
+type uartChunk struct {
+	at   time.Time
+	data []byte
+}
+
+// UART implements the UART interface in memory for testing.
+//
+// Data for the driver to read is either preloaded with Feed and FeedAfter
+// or produced in reply to writes by rules added with Reply. Like
+// machine.UART, Read never blocks: it returns whatever data is currently
+// available.
+type UART struct {
+	c Failer
+
+	// Written holds all data written by the driver.
+	Written []byte
+
+	// Rules are the replies the UART sends in response to writes.
+	Rules []*UARTRule
+
+	// If Err is non-nil, it will be returned as the error from Read and
+	// Write.
+	Err error
+
+	rx      []uartChunk
+	pending []byte
+	trace   *Trace
+}
+
+// NewUART returns a new mock UART that uses c to flag errors if they
+// happen.
+func NewUART(c Failer) *UART {
+	return &UART{
+		c: c,
+	}
+}
+
+// Feed queues data that can be read immediately.
+func (u *UART) Feed(data []byte) {
+	u.FeedAfter(0, data)
+}
+
+// FeedAfter queues data that becomes readable after d has elapsed since
+// the last queued data became readable.
+func (u *UART) FeedAfter(d time.Duration, data []byte) {
+	at := time.Now()
+	if len(u.rx) > 0 && u.rx[len(u.rx)-1].at.After(at) {
+		at = u.rx[len(u.rx)-1].at
+	}
+	u.rx = append(u.rx, uartChunk{
+		at:   at.Add(d),
+		data: append([]byte(nil), data...),
+	})
+}
+
+// Reply adds a rule that queues response whenever match is written.
+func (u *UART) Reply(match, response []byte) *UARTRule {
+	rule := &UARTRule{
+		Match:    match,
+		Response: response,
+	}
+	u.Rules = append(u.Rules, rule)
+	return rule
+}
+
+// Record starts recording all reads and writes into a new trace, which is
+// returned.
+func (u *UART) Record() *Trace {
+	u.trace = &Trace{}
+	return u.trace
+}
+
+// Read implements UART.Read.
+func (u *UART) Read(buf []byte) (int, error) {
+	if u.Err != nil {
+		return 0, u.Err
+	}
+
+	now := time.Now()
+	n := 0
+	for n < len(buf) && len(u.rx) > 0 && !u.rx[0].at.After(now) {
+		copied := copy(buf[n:], u.rx[0].data)
+		n += copied
+		u.rx[0].data = u.rx[0].data[copied:]
+		if len(u.rx[0].data) == 0 {
+			u.rx = u.rx[1:]
+		}
+	}
+	if n > 0 {
+		u.trace.add("uart", 0, nil, buf[:n])
+	}
+	return n, nil
+}
+
+// Write implements UART.Write.
+func (u *UART) Write(data []byte) (int, error) {
+	if u.Err != nil {
+		return 0, u.Err
+	}
+
+	u.Written = append(u.Written, data...)
+	u.trace.add("uart", 0, data, nil)
+	u.pending = append(u.pending, data...)
+
+	for {
+		rule, end := u.findRule()
+		if rule == nil {
+			break
+		}
+		u.pending = u.pending[end:]
+		rule.Invocations++
+		if rule.Once {
+			u.removeRule(rule)
+		}
+		u.queue(rule)
+	}
+	return len(data), nil
+}
+
+// Buffered implements UART.Buffered.
+func (u *UART) Buffered() int {
+	now := time.Now()
+	n := 0
+	for _, chunk := range u.rx {
+		if chunk.at.After(now) {
+			break
+		}
+		n += len(chunk.data)
+	}
+	return n
+}
+
+// AssertDone asserts that all queued data has been read and that every
+// rule marked Once has been triggered.
+func (u *UART) AssertDone() {
+	for _, chunk := range u.rx {
+		if len(chunk.data) != 0 {
+			u.c.Fatalf("uart data not read: %q", chunk.data)
+			return
+		}
+	}
+	for _, rule := range u.Rules {
+		if rule.Once {
+			u.c.Fatalf("uart rule %q never triggered", rule.Match)
+			return
+		}
+	}
+}
+
+// findRule returns the rule whose match appears first in the pending
+// written data, along with the end offset of the match.
+func (u *UART) findRule() (*UARTRule, int) {
+	var found *UARTRule
+	start, end := -1, 0
+	for _, rule := range u.Rules {
+		if len(rule.Match) == 0 {
+			continue
+		}
+		i := bytes.Index(u.pending, rule.Match)
+		if i < 0 || (start >= 0 && i >= start) {
+			continue
+		}
+		found, start, end = rule, i, i+len(rule.Match)
+	}
+	return found, end
+}
+
+func (u *UART) removeRule(rule *UARTRule) {
+	for i, r := range u.Rules {
+		if r == rule {
+			u.Rules = append(u.Rules[:i], u.Rules[i+1:]...)
+			return
+		}
+	}
+}
+
+// queue schedules the response of the rule.
+func (u *UART) queue(rule *UARTRule) {
+	if rule.ChunkSize <= 0 {
+		u.FeedAfter(rule.Gap, rule.Response)
+		return
+	}
+	for data := rule.Response; len(data) > 0; {
+		n := rule.ChunkSize
+		if n > len(data) {
+			n = len(data)
+		}
+		u.FeedAfter(rule.Gap, data[:n])
+		data = data[n:]
+	}
+}
@@ -0,0 +1,74 @@
+package tester
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+)
+
+var _ drivers.UART = (*UART)(nil)
+
+func TestUARTFeed(t *testing.T) {
+	c := qt.New(t)
+	u := NewUART(c)
+	u.Feed([]byte("$GPGGA,"))
+	u.Feed([]byte("123519"))
+
+	c.Assert(u.Buffered(), qt.Equals, 13)
+	buf := make([]byte, 10)
+	n, err := u.Read(buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(buf[:n]), qt.Equals, "$GPGGA,123")
+	n, _ = u.Read(buf)
+	c.Assert(string(buf[:n]), qt.Equals, "519")
+	u.AssertDone()
+}
+
+func TestUARTReply(t *testing.T) {
+	c := qt.New(t)
+	u := NewUART(c)
+	rule := u.Reply([]byte("AT\r\n"), []byte("\r\nOK\r\n"))
+
+	u.Write([]byte("AT"))
+	c.Assert(u.Buffered(), qt.Equals, 0)
+	u.Write([]byte("\r\n"))
+	c.Assert(u.Buffered(), qt.Equals, 6)
+	c.Assert(rule.Invocations, qt.Equals, 1)
+
+	u.Write([]byte("AT\r\nAT\r\n"))
+	c.Assert(rule.Invocations, qt.Equals, 3)
+	c.Assert(string(u.Written), qt.Equals, "AT\r\nAT\r\nAT\r\n")
+}
+
+func TestUARTChunked(t *testing.T) {
+	c := qt.New(t)
+	u := NewUART(c)
+	rule := u.Reply([]byte("AT+GMR\r\n"), []byte("0123456789"))
+	rule.ChunkSize = 4
+	rule.Gap = 20 * time.Millisecond
+	rule.Once = true
+
+	u.Write([]byte("AT+GMR\r\n"))
+	c.Assert(u.Rules, qt.HasLen, 0)
+	c.Assert(u.Buffered(), qt.Equals, 0)
+
+	time.Sleep(25 * time.Millisecond)
+	c.Assert(u.Buffered() >= 4, qt.IsTrue)
+
+	time.Sleep(50 * time.Millisecond)
+	buf := make([]byte, 16)
+	n, _ := u.Read(buf)
+	c.Assert(string(buf[:n]), qt.Equals, "0123456789")
+	u.AssertDone()
+}
+
+func TestUARTAssertDone(t *testing.T) {
+	c := qt.New(t)
+	f := &recordingFailer{}
+	u := NewUART(f)
+	u.Feed([]byte("unread"))
+	u.AssertDone()
+	c.Assert(f.msgs, qt.HasLen, 1)
+}
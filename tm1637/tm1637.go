@@ -4,19 +4,20 @@
 package tm1637
 
 import (
-	"machine"
 	"time"
+
+	"tinygo.org/x/drivers"
 )
 
 // Device wraps the pins of the TM1637.
 type Device struct {
-	clk        machine.Pin
-	dio        machine.Pin
+	clk        drivers.Pin
+	dio        drivers.Pin
 	brightness uint8
 }
 
 // New creates a new TM1637 device.
-func New(clk machine.Pin, dio machine.Pin, brightness uint8) Device {
+func New(clk drivers.Pin, dio drivers.Pin, brightness uint8) Device {
 	return Device{clk: clk, dio: dio, brightness: brightness}
 }
 
@@ -24,8 +25,8 @@ func New(clk machine.Pin, dio machine.Pin, brightness uint8) Device {
 func (d *Device) Configure() {
 	pinMode(d.clk, false)
 	pinMode(d.dio, false)
-	d.clk.Low() // required for future pull-down
-	d.dio.Low() // required for future pull-down
+	d.clk.Set(false) // required for future pull-down
+	d.dio.Set(false) // required for future pull-down
 }
 
 // Brightness sets the brightness of the display (0-7).
@@ -147,16 +148,16 @@ func delaytm() {
 	time.Sleep(time.Microsecond * time.Duration(TM1637_DELAY))
 }
 
-func pinMode(pin machine.Pin, mode bool) {
+func pinMode(pin drivers.Pin, mode bool) {
 	// TM1637 has internal pull-up resistors for both CLK and DIO pins.
 	// Set them to input mode will pull them high,
 	// and set them to output mode will pull them down
 	// (since we did so in the beginning.)
 	// The High()/Low() method don't work on some boards.
 	if mode {
-		pin.Configure(machine.PinConfig{Mode: machine.PinInput})
+		drivers.ConfigurePin(pin, drivers.PinInput)
 	} else {
-		pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+		drivers.ConfigurePin(pin, drivers.PinOutput)
 	}
 }
 
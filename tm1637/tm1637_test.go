@@ -0,0 +1,105 @@
+package tm1637
+
+import (
+	"sort"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+// decode turns the recorded clk/dio waveforms back into the frames that
+// were sent, using the start (dio falls while clk is high) and stop (dio
+// rises while clk is high) conditions and sampling dio on rising clk edges.
+// The ack bit after every byte is dropped.
+func decode(clk, dio *tester.Pin) [][]byte {
+	type edge struct {
+		clk bool
+		tester.PinEvent
+	}
+	var edges []edge
+	for _, e := range clk.Events {
+		edges = append(edges, edge{true, e})
+	}
+	for _, e := range dio.Events {
+		edges = append(edges, edge{false, e})
+	}
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].Time.Before(edges[j].Time) })
+
+	var frames [][]byte
+	var frame []byte
+	var bits, n uint
+	inFrame := false
+	clkHigh, dioHigh := false, false
+	for _, e := range edges {
+		if e.clk {
+			clkHigh = e.High
+			if clkHigh && inFrame {
+				if n < 8 && dioHigh {
+					bits |= 1 << n
+				}
+				n++
+				if n == 9 {
+					frame = append(frame, byte(bits))
+					bits, n = 0, 0
+				}
+			}
+			continue
+		}
+		dioHigh = e.High
+		if !clkHigh {
+			continue
+		}
+		if dioHigh && inFrame {
+			frames = append(frames, frame)
+			inFrame = false
+		} else if !dioHigh {
+			frame, bits, n = nil, 0, 0
+			inFrame = true
+		}
+	}
+	return frames
+}
+
+func newTestDevice() (*Device, *tester.Pin, *tester.Pin) {
+	clk := tester.NewPin()
+	dio := tester.NewPin()
+	// The TM1637 has internal pull-ups on both lines.
+	clk.PullUp = true
+	dio.PullUp = true
+	d := New(clk, dio, 7)
+	d.Configure()
+	return &d, clk, dio
+}
+
+func TestStartStop(t *testing.T) {
+	c := qt.New(t)
+	d, clk, dio := newTestDevice()
+	c.Assert(clk.Mode, qt.Equals, drivers.PinOutput)
+
+	d.stop()
+	c.Assert(clk.Levels(), qt.DeepEquals, []bool{true})
+	c.Assert(dio.Levels(), qt.DeepEquals, []bool{true})
+	c.Assert(clk.Events[0].Time.Before(dio.Events[0].Time), qt.IsTrue)
+
+	clk.Reset()
+	dio.Reset()
+	d.start()
+	c.Assert(dio.Levels(), qt.DeepEquals, []bool{false})
+	c.Assert(clk.Levels(), qt.DeepEquals, []bool{false})
+	c.Assert(dio.Events[0].Time.Before(clk.Events[0].Time), qt.IsTrue)
+}
+
+func TestDisplayDigit(t *testing.T) {
+	c := qt.New(t)
+	d, clk, dio := newTestDevice()
+	d.stop()
+
+	d.DisplayDigit(5, 1)
+	c.Assert(decode(clk, dio), qt.DeepEquals, [][]byte{
+		{TM1637_CMD1},
+		{TM1637_CMD2 | 1, 0x6D},
+		{TM1637_CMD3 | TM1637_DSP_ON | 7},
+	})
+}
@@ -10,6 +10,9 @@ import "tinygo.org/x/drivers"
 type Device struct {
 	bus     drivers.I2C
 	address uint8
+
+	// last value read by Update
+	temperature int32
 }
 
 // Config is the configuration for the TMP102.
@@ -66,3 +69,17 @@ func (d *Device) ReadTemperature() (temperature int32, err error) {
 
 	return temperature / 10, nil
 }
+
+// Update reads the temperature. Use Temperature to get the value.
+func (d *Device) Update(which drivers.Measurement) (err error) {
+	if which&drivers.Temperature != 0 {
+		d.temperature, err = d.ReadTemperature()
+	}
+	return
+}
+
+// Temperature returns the temperature in celsius milli degrees (°C/1000)
+// read by the last call to Update.
+func (d *Device) Temperature() int32 {
+	return d.temperature
+}
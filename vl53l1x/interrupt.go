@@ -0,0 +1,63 @@
+package vl53l1x
+
+// InterruptConfig is the configuration of the GPIO1 interrupt pin.
+type InterruptConfig struct {
+	// Mode selects the measurements that signal an interrupt.
+	Mode InterruptMode
+
+	// Low and High are the distance thresholds in mm of the INTERRUPT_BELOW,
+	// INTERRUPT_ABOVE, INTERRUPT_OUTSIDE and INTERRUPT_INSIDE modes.
+	Low, High uint16
+
+	// NoTarget also signals measurements without a target in the
+	// threshold modes.
+	NoTarget bool
+
+	// ActiveHigh drives GPIO1 high on interrupt instead of low.
+	ActiveHigh bool
+}
+
+// ConfigureInterrupt configures when and how the GPIO1 pin signals an
+// interrupt. The pin stays active until the measurement is read with Read or
+// ReadResult. In the threshold modes, a blocking Read only returns
+// measurements that match the thresholds.
+func (d *Device) ConfigureInterrupt(config InterruptConfig) error {
+	var gpio uint8
+	switch config.Mode {
+	case INTERRUPT_DATA_READY:
+		gpio = 0x20 // new sample ready
+	case INTERRUPT_BELOW, INTERRUPT_ABOVE, INTERRUPT_OUTSIDE, INTERRUPT_INSIDE:
+		gpio = uint8(config.Mode - INTERRUPT_BELOW)
+		if config.NoTarget {
+			gpio |= 0x40
+		}
+	default:
+		return errInterruptMode
+	}
+
+	mux, err := d.readReg(GPIO_HV_MUX_CTRL)
+	if err != nil {
+		return err
+	}
+	// Bit 4 is set for an active low interrupt.
+	mux |= 0x10
+	if config.ActiveHigh {
+		mux &^= 0x10
+	}
+
+	w := registerWriter{d: d}
+	w.writeReg16Bit(SYSTEM_THRESH_HIGH, config.High)
+	w.writeReg16Bit(SYSTEM_THRESH_LOW, config.Low)
+	w.writeReg(SYSTEM_INTERRUPT_CONFIG_GPIO, gpio)
+	w.writeReg(GPIO_HV_MUX_CTRL, mux)
+	if w.err != nil {
+		return w.err
+	}
+	d.activeHigh = config.ActiveHigh
+	return nil
+}
+
+// ClearInterrupt releases the GPIO1 pin without reading the measurement.
+func (d *Device) ClearInterrupt() error {
+	return d.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01)
+}
@@ -15,12 +15,14 @@ const (
 	MM_CONFIG_OUTER_OFFSET_MM                               = 0x0022
 	DSS_CONFIG_TARGET_TOTAL_RATE_MCPS                       = 0x0024
 	PAD_I2C_HV_EXTSUP_CONFIG                                = 0x002E
+	GPIO_HV_MUX_CTRL                                        = 0x0030
 	GPIO_TIO_HV_STATUS                                      = 0x0031
 	SIGMA_ESTIMATOR_EFFECTIVE_PULSE_WIDTH_NS                = 0x0036
 	SIGMA_ESTIMATOR_EFFECTIVE_AMBIENT_WIDTH_NS              = 0x0037
 	ALGO_CROSSTALK_COMPENSATION_VALID_HEIGHT_MM             = 0x0039
 	ALGO_RANGE_MIN_CLIP                                     = 0x003F
 	ALGO_CONSISTENCY_CHECK_TOLERANCE                        = 0x0040
+	SYSTEM_INTERRUPT_CONFIG_GPIO                            = 0x0046
 	CAL_CONFIG_VCSEL_START                                  = 0x0047
 	PHASECAL_CONFIG_TIMEOUT_MACROP                          = 0x004B
 	PHASECAL_CONFIG_OVERRIDE                                = 0x004D
@@ -40,6 +42,8 @@ const (
 	RANGE_CONFIG_VALID_PHASE_HIGH                           = 0x0069
 	SYSTEM_INTERMEASUREMENT_PERIOD                          = 0x006C
 	SYSTEM_GROUPED_PARAMETER_HOLD_0                         = 0x0071
+	SYSTEM_THRESH_HIGH                                      = 0x0072
+	SYSTEM_THRESH_LOW                                       = 0x0074
 	SYSTEM_SEED_CONFIG                                      = 0x0077
 	SD_CONFIG_WOI_SD0                                       = 0x0078
 	SD_CONFIG_WOI_SD1                                       = 0x0079
@@ -89,3 +93,19 @@ const (
 
 	None RangeStatus = 255
 )
+
+// InterruptMode selects when the GPIO1 pin signals an interrupt.
+type InterruptMode uint8
+
+const (
+	// INTERRUPT_DATA_READY signals every new measurement.
+	INTERRUPT_DATA_READY InterruptMode = iota
+	// INTERRUPT_BELOW signals measurements closer than the low threshold.
+	INTERRUPT_BELOW
+	// INTERRUPT_ABOVE signals measurements further than the high threshold.
+	INTERRUPT_ABOVE
+	// INTERRUPT_OUTSIDE signals measurements outside of the thresholds.
+	INTERRUPT_OUTSIDE
+	// INTERRUPT_INSIDE signals measurements between the thresholds.
+	INTERRUPT_INSIDE
+)
@@ -18,6 +18,36 @@ import (
 type DistanceMode uint8
 type RangeStatus uint8
 
+// ErrTimeout is returned when the device does not become ready in time.
+var ErrTimeout = errors.New("vl53l1x: timeout")
+
+var (
+	errNotConnected  = errors.New("vl53l1x: device not found")
+	errDistanceMode  = errors.New("vl53l1x: invalid distance mode")
+	errBudget        = errors.New("vl53l1x: timing budget out of range")
+	errROI           = errors.New("vl53l1x: ROI value out of range")
+	errInterruptMode = errors.New("vl53l1x: invalid interrupt mode")
+	errAddresses     = errors.New("vl53l1x: addresses must be unique and match the XSHUT pins")
+)
+
+// Result is a single ranging measurement.
+type Result struct {
+	// Distance is the distance to the target in mm.
+	Distance uint16
+
+	// Status tells whether Distance is valid.
+	Status RangeStatus
+
+	// SignalRate is the peak signal rate in count per second (cps).
+	SignalRate int32
+
+	// AmbientRate is the ambient rate in count per second (cps).
+	AmbientRate int32
+
+	// SPADCount is the number of SPADs enabled for the measurement.
+	SPADCount uint16
+}
+
 type rangingData struct {
 	mm              uint16
 	status          RangeStatus
@@ -45,6 +75,7 @@ type Device struct {
 	calibrated         bool
 	VHVInit            uint8
 	VHVTimeout         uint8
+	activeHigh         bool
 	rangingData        rangingData
 	results            resultBuffer
 }
@@ -62,80 +93,179 @@ func New(bus drivers.I2C) Device {
 	}
 }
 
+// Provision brings up several sensors that share a bus and gives each of
+// them its own address. The XSHUT pin of every sensor must be connected to
+// one of the xshut pins, and addresses holds the new address of each
+// sensor. Only the last address may be the default Address, as the other
+// sensors boot at that address.
+//
+// All sensors are shut down first, then each is started in turn,
+// configured with Configure and moved to its address. On error, Provision
+// returns the sensors provisioned so far, so the sensor that failed is the
+// one at index len(devices).
+func Provision(bus drivers.I2C, xshut []drivers.Pin, addresses []uint8, use2v8Mode bool) ([]Device, error) {
+	if len(addresses) != len(xshut) {
+		return nil, errAddresses
+	}
+	for i, address := range addresses {
+		if address&0x7F == Address && i != len(addresses)-1 {
+			return nil, errAddresses
+		}
+		for _, other := range addresses[:i] {
+			if address&0x7F == other&0x7F {
+				return nil, errAddresses
+			}
+		}
+	}
+
+	for _, pin := range xshut {
+		drivers.ConfigurePin(pin, drivers.PinOutput)
+		pin.Set(false)
+	}
+	time.Sleep(1 * time.Millisecond)
+
+	devices := make([]Device, 0, len(xshut))
+	for i, pin := range xshut {
+		pin.Set(true)
+		// Wait for the boot of the firmware (tBOOT is 1.2ms).
+		time.Sleep(2 * time.Millisecond)
+
+		d := New(bus)
+		err := d.Configure(use2v8Mode)
+		if err != nil {
+			return devices, err
+		}
+		err = d.SetAddress(addresses[i])
+		if err != nil {
+			return devices, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, nil
+}
+
 // Connected returns whether a VL53L1X has been found.
 // It does a "who am I" request and checks the response.
 func (d *Device) Connected() bool {
-	return d.readReg16Bit(WHO_AM_I) == CHIP_ID
+	id, err := d.readReg16Bit(WHO_AM_I)
+	return err == nil && id == CHIP_ID
 }
 
-// Configure sets up the device for communication
-func (d *Device) Configure(use2v8Mode bool) bool {
+// Configure sets up the device for communication. It returns an error if
+// no VL53L1X is found or the device does not boot in time.
+func (d *Device) Configure(use2v8Mode bool) error {
 	if !d.Connected() {
-		return false
+		return errNotConnected
+	}
+	err := d.writeReg(SOFT_RESET, 0x00)
+	if err != nil {
+		return err
 	}
-	d.writeReg(SOFT_RESET, 0x00)
 	time.Sleep(100 * time.Microsecond)
-	d.writeReg(SOFT_RESET, 0x01)
+	err = d.writeReg(SOFT_RESET, 0x01)
+	if err != nil {
+		return err
+	}
 	time.Sleep(1 * time.Millisecond)
 
 	start := time.Now()
-	for (d.readReg(FIRMWARE_SYSTEM_STATUS) & 0x01) == 0 {
+	for {
+		status, err := d.readReg(FIRMWARE_SYSTEM_STATUS)
+		if err != nil {
+			return err
+		}
+		if status&0x01 != 0 {
+			break
+		}
 		elapsed := time.Since(start)
 		if d.timeout > 0 && uint32(elapsed.Seconds()*1000) > d.timeout {
-			return false
+			return ErrTimeout
 		}
 	}
+	// The reset restores the active low interrupt polarity.
+	d.activeHigh = false
 
 	if use2v8Mode {
-		d.writeReg(PAD_I2C_HV_EXTSUP_CONFIG, d.readReg(PAD_I2C_HV_EXTSUP_CONFIG)|0x01)
+		config, err := d.readReg(PAD_I2C_HV_EXTSUP_CONFIG)
+		if err != nil {
+			return err
+		}
+		err = d.writeReg(PAD_I2C_HV_EXTSUP_CONFIG, config|0x01)
+		if err != nil {
+			return err
+		}
 	}
 
-	d.fastOscillatorFreq = d.readReg16Bit(OSC_MEASURED_FAST_OSC_FREQUENCY)
-	d.oscillatorOffset = d.readReg16Bit(RESULT_OSC_CALIBRATE_VAL)
+	d.fastOscillatorFreq, err = d.readReg16Bit(OSC_MEASURED_FAST_OSC_FREQUENCY)
+	if err != nil {
+		return err
+	}
+	d.oscillatorOffset, err = d.readReg16Bit(RESULT_OSC_CALIBRATE_VAL)
+	if err != nil {
+		return err
+	}
+
+	w := registerWriter{d: d}
 
 	// static config
-	d.writeReg16Bit(DSS_CONFIG_TARGET_TOTAL_RATE_MCPS, TARGETRATE)
-	d.writeReg(GPIO_TIO_HV_STATUS, 0x02)
-	d.writeReg(SIGMA_ESTIMATOR_EFFECTIVE_PULSE_WIDTH_NS, 8)
-	d.writeReg(SIGMA_ESTIMATOR_EFFECTIVE_AMBIENT_WIDTH_NS, 16)
-	d.writeReg(ALGO_CROSSTALK_COMPENSATION_VALID_HEIGHT_MM, 0xFF)
-	d.writeReg(ALGO_RANGE_MIN_CLIP, 0)
-	d.writeReg(ALGO_CONSISTENCY_CHECK_TOLERANCE, 2)
+	w.writeReg16Bit(DSS_CONFIG_TARGET_TOTAL_RATE_MCPS, TARGETRATE)
+	w.writeReg(GPIO_TIO_HV_STATUS, 0x02)
+	w.writeReg(SIGMA_ESTIMATOR_EFFECTIVE_PULSE_WIDTH_NS, 8)
+	w.writeReg(SIGMA_ESTIMATOR_EFFECTIVE_AMBIENT_WIDTH_NS, 16)
+	w.writeReg(ALGO_CROSSTALK_COMPENSATION_VALID_HEIGHT_MM, 0xFF)
+	w.writeReg(ALGO_RANGE_MIN_CLIP, 0)
+	w.writeReg(ALGO_CONSISTENCY_CHECK_TOLERANCE, 2)
 
 	// general config
-	d.writeReg16Bit(SYSTEM_THRESH_RATE_HIGH, 0x0000)
-	d.writeReg16Bit(SYSTEM_THRESH_RATE_LOW, 0x0000)
-	d.writeReg(DSS_CONFIG_APERTURE_ATTENUATION, 0x38)
+	w.writeReg16Bit(SYSTEM_THRESH_RATE_HIGH, 0x0000)
+	w.writeReg16Bit(SYSTEM_THRESH_RATE_LOW, 0x0000)
+	w.writeReg(DSS_CONFIG_APERTURE_ATTENUATION, 0x38)
 
 	// timing config
-	d.writeReg16Bit(RANGE_CONFIG_SIGMA_THRESH, 360)
-	d.writeReg16Bit(RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS, 192)
+	w.writeReg16Bit(RANGE_CONFIG_SIGMA_THRESH, 360)
+	w.writeReg16Bit(RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS, 192)
 
 	// dynamic config
-	d.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD_0, 0x01)
-	d.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD_1, 0x01)
-	d.writeReg(SD_CONFIG_QUANTIFIER, 2)
+	w.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD_0, 0x01)
+	w.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD_1, 0x01)
+	w.writeReg(SD_CONFIG_QUANTIFIER, 2)
 
-	d.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD, 0x00)
-	d.writeReg(SYSTEM_SEED_CONFIG, 1)
+	w.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD, 0x00)
+	w.writeReg(SYSTEM_SEED_CONFIG, 1)
 
 	// Low power auto mode
-	d.writeReg(SYSTEM_SEQUENCE_CONFIG, 0x8B) // VHV, PHASECAL, DSS1, RANGE
-	d.writeReg16Bit(DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, 200<<8)
-	d.writeReg(DSS_CONFIG_ROI_MODE_CONTROL, 2) // REQUESTED_EFFFECTIVE_SPADS
-
-	d.SetDistanceMode(d.mode)
-	d.SetMeasurementTimingBudget(50000)
+	w.writeReg(SYSTEM_SEQUENCE_CONFIG, 0x8B) // VHV, PHASECAL, DSS1, RANGE
+	w.writeReg16Bit(DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, 200<<8)
+	w.writeReg(DSS_CONFIG_ROI_MODE_CONTROL, 2) // REQUESTED_EFFFECTIVE_SPADS
+	if w.err != nil {
+		return w.err
+	}
 
-	d.writeReg16Bit(ALGO_PART_TO_PART_RANGE_OFFSET_MM, d.readReg16Bit(MM_CONFIG_OUTER_OFFSET_MM)*4)
+	err = d.SetDistanceMode(d.mode)
+	if err != nil {
+		return err
+	}
+	err = d.SetMeasurementTimingBudget(50000)
+	if err != nil {
+		return err
+	}
 
-	return true
+	offset, err := d.readReg16Bit(MM_CONFIG_OUTER_OFFSET_MM)
+	if err != nil {
+		return err
+	}
+	return d.writeReg16Bit(ALGO_PART_TO_PART_RANGE_OFFSET_MM, offset*4)
 }
 
-// SetAddress sets the I2C address which this device listens to.
-func (d *Device) SetAddress(address uint8) {
-	d.writeReg(I2C_SLAVE_DEVICE_ADDRESS, address)
-	d.Address = uint16(address)
+// SetAddress sets the I2C address which this device listens to. The
+// address is lost when the device is reset or powered down.
+func (d *Device) SetAddress(address uint8) error {
+	err := d.writeReg(I2C_SLAVE_DEVICE_ADDRESS, address&0x7F)
+	if err != nil {
+		return err
+	}
+	d.Address = uint16(address & 0x7F)
+	return nil
 }
 
 // GetAddress returns the I2C address which this device listens to.
@@ -153,127 +283,179 @@ func (d *Device) SetTimeout(timeout uint32) {
 // SHORT: 136cm (dark) - 135cm (strong ambient light)
 // MEDIUM: 290cm (dark) - 76cm (strong ambient light)
 // LONG: 360cm (dark) - 73cm (strong ambient light)
-// It returns false if an invalid mode is provided
-func (d *Device) SetDistanceMode(mode DistanceMode) bool {
-	budgetMicroseconds := d.GetMeasurementTimingBudget()
+// It returns an error if an invalid mode is provided or the device does not
+// respond.
+func (d *Device) SetDistanceMode(mode DistanceMode) error {
+	var vcselA, vcselB, phaseHigh, phase uint8
 	switch mode {
 	case SHORT:
-		// timing config
-		d.writeReg(RANGE_CONFIG_VCSEL_PERIOD_A, 0x07)
-		d.writeReg(RANGE_CONFIG_VCSEL_PERIOD_B, 0x05)
-		d.writeReg(RANGE_CONFIG_VALID_PHASE_HIGH, 0x38)
-
-		// dynamic config
-		d.writeReg(SD_CONFIG_WOI_SD0, 0x07)
-		d.writeReg(SD_CONFIG_WOI_SD1, 0x05)
-		d.writeReg(SD_CONFIG_INITIAL_PHASE_SD0, 6)
-		d.writeReg(SD_CONFIG_INITIAL_PHASE_SD1, 6)
+		vcselA, vcselB, phaseHigh, phase = 0x07, 0x05, 0x38, 6
 	case MEDIUM:
-		// timing config
-		d.writeReg(RANGE_CONFIG_VCSEL_PERIOD_A, 0x0B)
-		d.writeReg(RANGE_CONFIG_VCSEL_PERIOD_B, 0x09)
-		d.writeReg(RANGE_CONFIG_VALID_PHASE_HIGH, 0x78)
-
-		// dynamic config
-		d.writeReg(SD_CONFIG_WOI_SD0, 0x0B)
-		d.writeReg(SD_CONFIG_WOI_SD1, 0x09)
-		d.writeReg(SD_CONFIG_INITIAL_PHASE_SD0, 10)
-		d.writeReg(SD_CONFIG_INITIAL_PHASE_SD1, 10)
+		vcselA, vcselB, phaseHigh, phase = 0x0B, 0x09, 0x78, 10
 	case LONG:
-		// timing config
-		d.writeReg(RANGE_CONFIG_VCSEL_PERIOD_A, 0x0F)
-		d.writeReg(RANGE_CONFIG_VCSEL_PERIOD_B, 0x0D)
-		d.writeReg(RANGE_CONFIG_VALID_PHASE_HIGH, 0xB8)
-
-		// dynamic config
-		d.writeReg(SD_CONFIG_WOI_SD0, 0x0F)
-		d.writeReg(SD_CONFIG_WOI_SD1, 0x0D)
-		d.writeReg(SD_CONFIG_INITIAL_PHASE_SD0, 14)
-		d.writeReg(SD_CONFIG_INITIAL_PHASE_SD1, 14)
+		vcselA, vcselB, phaseHigh, phase = 0x0F, 0x0D, 0xB8, 14
 	default:
-		return false
+		return errDistanceMode
+	}
+	budgetMicroseconds, err := d.GetMeasurementTimingBudget()
+	if err != nil {
+		return err
+	}
+
+	w := registerWriter{d: d}
+
+	// timing config
+	w.writeReg(RANGE_CONFIG_VCSEL_PERIOD_A, vcselA)
+	w.writeReg(RANGE_CONFIG_VCSEL_PERIOD_B, vcselB)
+	w.writeReg(RANGE_CONFIG_VALID_PHASE_HIGH, phaseHigh)
+
+	// dynamic config
+	w.writeReg(SD_CONFIG_WOI_SD0, vcselA)
+	w.writeReg(SD_CONFIG_WOI_SD1, vcselB)
+	w.writeReg(SD_CONFIG_INITIAL_PHASE_SD0, phase)
+	w.writeReg(SD_CONFIG_INITIAL_PHASE_SD1, phase)
+	if w.err != nil {
+		return w.err
 	}
 
-	d.SetMeasurementTimingBudget(budgetMicroseconds)
+	err = d.SetMeasurementTimingBudget(budgetMicroseconds)
+	if err != nil {
+		return err
+	}
 	d.mode = mode
-	return true
+	return nil
 }
 
-// GetMeasurementTimingBudget returns the timing budget in microseconds
-func (d *Device) GetMeasurementTimingBudget() uint32 {
-	macroPeriod := d.calculateMacroPeriod(uint32(d.readReg(RANGE_CONFIG_VCSEL_PERIOD_A)))
-	rangeConfigTimeout := timeoutMclksToMicroseconds(decodeTimeout(d.readReg16Bit(RANGE_CONFIG_TIMEOUT_MACROP_A)), macroPeriod)
-	return 2 * uint32(rangeConfigTimeout) * TIMING_GUARD
+// GetMeasurementTimingBudget returns the timing budget in microseconds,
+// which is twice the range timeout plus TIMING_GUARD, the inverse of
+// SetMeasurementTimingBudget.
+func (d *Device) GetMeasurementTimingBudget() (uint32, error) {
+	vcselPeriod, err := d.readReg(RANGE_CONFIG_VCSEL_PERIOD_A)
+	if err != nil {
+		return 0, err
+	}
+	timeout, err := d.readReg16Bit(RANGE_CONFIG_TIMEOUT_MACROP_A)
+	if err != nil {
+		return 0, err
+	}
+	macroPeriod := d.calculateMacroPeriod(uint32(vcselPeriod))
+	rangeConfigTimeout := timeoutMclksToMicroseconds(decodeTimeout(timeout), macroPeriod)
+	return 2*uint32(rangeConfigTimeout) + TIMING_GUARD, nil
 }
 
 // SetMeasurementTimingBudget configures the timing budget in microseconds
-// It returns false if an invalid timing budget is provided
-func (d *Device) SetMeasurementTimingBudget(budgetMicroseconds uint32) bool {
+// It returns an error if an invalid timing budget is provided or the device
+// does not respond.
+func (d *Device) SetMeasurementTimingBudget(budgetMicroseconds uint32) error {
 	if budgetMicroseconds <= TIMING_GUARD {
-		return false
+		return errBudget
 	}
 	budgetMicroseconds -= TIMING_GUARD
 	if budgetMicroseconds > 1100000 {
-		return false
+		return errBudget
 	}
 	rangeConfigTimeout := budgetMicroseconds / 2
 	// Update Macro Period for Range A VCSEL Period
-	macroPeriod := d.calculateMacroPeriod(uint32(d.readReg(RANGE_CONFIG_VCSEL_PERIOD_A)))
+	vcselPeriod, err := d.readReg(RANGE_CONFIG_VCSEL_PERIOD_A)
+	if err != nil {
+		return err
+	}
+	macroPeriod := d.calculateMacroPeriod(uint32(vcselPeriod))
 
 	// Update Phase timeout - uses Timing A
 	phasecalTimeoutMclks := timeoutMicrosecondsToMclks(1000, macroPeriod)
 	if phasecalTimeoutMclks > 0xFF {
 		phasecalTimeoutMclks = 0xFF
 	}
-	d.writeReg(PHASECAL_CONFIG_TIMEOUT_MACROP, uint8(phasecalTimeoutMclks))
+	w := registerWriter{d: d}
+	w.writeReg(PHASECAL_CONFIG_TIMEOUT_MACROP, uint8(phasecalTimeoutMclks))
 
 	// Update MM Timing A timeout
-	d.writeReg16Bit(MM_CONFIG_TIMEOUT_MACROP_A, encodeTimeout(timeoutMicrosecondsToMclks(1, macroPeriod)))
+	w.writeReg16Bit(MM_CONFIG_TIMEOUT_MACROP_A, encodeTimeout(timeoutMicrosecondsToMclks(1, macroPeriod)))
 	// Update Range Timing A timeout
-	d.writeReg16Bit(RANGE_CONFIG_TIMEOUT_MACROP_A, encodeTimeout(timeoutMicrosecondsToMclks(rangeConfigTimeout, macroPeriod)))
+	w.writeReg16Bit(RANGE_CONFIG_TIMEOUT_MACROP_A, encodeTimeout(timeoutMicrosecondsToMclks(rangeConfigTimeout, macroPeriod)))
+	if w.err != nil {
+		return w.err
+	}
+
+	vcselPeriod, err = d.readReg(RANGE_CONFIG_VCSEL_PERIOD_B)
+	if err != nil {
+		return err
+	}
+	macroPeriod = d.calculateMacroPeriod(uint32(vcselPeriod))
 
-	macroPeriod = d.calculateMacroPeriod(uint32(d.readReg(RANGE_CONFIG_VCSEL_PERIOD_B)))
 	// Update MM Timing B timeout
-	d.writeReg16Bit(MM_CONFIG_TIMEOUT_MACROP_B, encodeTimeout(timeoutMicrosecondsToMclks(1, macroPeriod)))
+	w.writeReg16Bit(MM_CONFIG_TIMEOUT_MACROP_B, encodeTimeout(timeoutMicrosecondsToMclks(1, macroPeriod)))
 	// Update Range Timing B timeout
-	d.writeReg16Bit(RANGE_CONFIG_TIMEOUT_MACROP_B, encodeTimeout(timeoutMicrosecondsToMclks(rangeConfigTimeout, macroPeriod)))
-
-	return true
+	w.writeReg16Bit(RANGE_CONFIG_TIMEOUT_MACROP_B, encodeTimeout(timeoutMicrosecondsToMclks(rangeConfigTimeout, macroPeriod)))
+	return w.err
 }
 
 // Read stores in the buffer the values of the sensor and returns
-// the current distance in mm
-func (d *Device) Read(blocking bool) uint16 {
+// the current distance in mm. In blocking mode, it waits for a new
+// measurement and returns ErrTimeout if none is ready within the timeout.
+func (d *Device) Read(blocking bool) (uint16, error) {
+	result, err := d.ReadResult(blocking)
+	return result.Distance, err
+}
+
+// ReadResult reads a measurement like Read, and returns it with the range
+// status, signal and ambient rates and SPAD count. The range status tells
+// whether the distance is valid; errors are only returned when the device
+// could not be read.
+func (d *Device) ReadResult(blocking bool) (Result, error) {
 	if blocking {
 		start := time.Now()
 
-		for !d.dataReady() {
+		for {
+			ready, err := d.dataReady()
+			if err != nil {
+				return Result{}, err
+			}
+			if ready {
+				break
+			}
 			elapsed := time.Since(start)
 			if d.timeout > 0 && uint32(elapsed.Seconds()*1000) > d.timeout {
-				d.rangingData.status = None
-				d.rangingData.mm = 0
-				d.rangingData.signalRateMCPS = 0
-				d.rangingData.ambientRateMCPS = 0
-				return d.rangingData.mm
+				d.rangingData = rangingData{status: None}
+				return Result{Status: None}, ErrTimeout
 			}
 		}
 	}
-	d.readResults()
+	err := d.readResults()
+	if err != nil {
+		return Result{}, err
+	}
 
 	if !d.calibrated {
-		d.setupManualCalibration()
+		err = d.setupManualCalibration()
+		if err != nil {
+			return Result{}, err
+		}
 		d.calibrated = true
 	}
 
-	d.updateDSS()
+	err = d.updateDSS()
+	if err != nil {
+		return Result{}, err
+	}
 	d.getRangingData()
-	d.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01) //sys_interrupt_clear_range
+	err = d.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01) //sys_interrupt_clear_range
+	if err != nil {
+		return Result{}, err
+	}
 
-	return d.rangingData.mm
+	return Result{
+		Distance:    d.rangingData.mm,
+		Status:      d.rangingData.status,
+		SignalRate:  d.rangingData.signalRateMCPS,
+		AmbientRate: d.rangingData.ambientRateMCPS,
+		SPADCount:   d.results.effectiveSPADCount >> 8, // 8.8 fixed point
+	}, nil
 }
 
 // updateDSS updates the DSS
-func (d *Device) updateDSS() {
+func (d *Device) updateDSS() error {
 	spadCount := d.results.effectiveSPADCount
 	if spadCount != 0 {
 		totalRatePerSpad := uint32(d.results.signalRateCrosstalkMCPSSD0) + uint32(d.results.ambientRateMCPSSD0)
@@ -287,19 +469,21 @@ func (d *Device) updateDSS() {
 			if requireSpads > 0xFFFF {
 				requireSpads = 0xFFFF
 			}
-			d.writeReg16Bit(DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, uint16(requireSpads))
-			return
+			return d.writeReg16Bit(DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, uint16(requireSpads))
 		}
 	}
-	d.writeReg16Bit(DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, 0x8000)
+	return d.writeReg16Bit(DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, 0x8000)
 }
 
 // readResults read the register and stores the data in the results buffer
-func (d *Device) readResults() {
+func (d *Device) readResults() error {
 	data := make([]byte, 17)
 	msb := byte((RESULT_RANGE_STATUS >> 8) & 0xFF)
 	lsb := byte(RESULT_RANGE_STATUS & 0xFF)
-	d.bus.Tx(d.Address, []byte{msb, lsb}, data)
+	err := d.bus.Tx(d.Address, []byte{msb, lsb}, data)
+	if err != nil {
+		return drivers.NotResponding(err)
+	}
 	d.results.status = data[0]
 	// data[1] report_status : not used
 	d.results.streamCount = data[2]
@@ -310,11 +494,13 @@ func (d *Device) readResults() {
 	// data[11] , data[12] phase_sd0 : not used
 	d.results.mmCrosstalkSD0 = readUint(data[13], data[14])
 	d.results.signalRateCrosstalkMCPSSD0 = readUint(data[15], data[16])
+	return nil
 }
 
 // dataReady returns true when the data is ready to be read
-func (d *Device) dataReady() bool {
-	return (d.readReg(GPIO_TIO_HV_STATUS) & 0x01) == 0
+func (d *Device) dataReady() (bool, error) {
+	status, err := d.readReg(GPIO_TIO_HV_STATUS)
+	return (status&0x01 != 0) == d.activeHigh, err
 }
 
 // Distance returns the distance in mm
@@ -382,103 +568,200 @@ func (d *Device) getRangingData() {
 		d.rangingData.status = None
 	}
 
-	d.rangingData.signalRateMCPS = 1000000 * int32(d.results.signalRateCrosstalkMCPSSD0) / (1 << 7)
-	d.rangingData.ambientRateMCPS = 1000000 * int32(d.results.ambientRateMCPSSD0) / (1 << 7)
+	// The rates are 9.7 fixed point; up to 512 MCPS fits in an int32.
+	d.rangingData.signalRateMCPS = int32(int64(d.results.signalRateCrosstalkMCPSSD0) * 1000000 >> 7)
+	d.rangingData.ambientRateMCPS = int32(int64(d.results.ambientRateMCPSSD0) * 1000000 >> 7)
 }
 
 // setupManualCalibration configures the manual calibration
-func (d *Device) setupManualCalibration() {
+func (d *Device) setupManualCalibration() (err error) {
 	// save original VHV configs
-	d.VHVInit = d.readReg(VHV_CONFIG_INIT)
-	d.VHVTimeout = d.readReg(VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND)
+	d.VHVInit, err = d.readReg(VHV_CONFIG_INIT)
+	if err != nil {
+		return err
+	}
+	d.VHVTimeout, err = d.readReg(VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND)
+	if err != nil {
+		return err
+	}
+
+	w := registerWriter{d: d}
 
 	// disable VHV init
-	d.writeReg(VHV_CONFIG_INIT, d.VHVInit&0x7F)
+	w.writeReg(VHV_CONFIG_INIT, d.VHVInit&0x7F)
 
 	// set loop bound to tuning param
-	d.writeReg(VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND, (d.VHVTimeout&0x03)+(3<<2))
+	w.writeReg(VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND, (d.VHVTimeout&0x03)+(3<<2))
 
 	// override phasecal
-	d.writeReg(PHASECAL_CONFIG_OVERRIDE, 0x01)
-	d.writeReg(CAL_CONFIG_VCSEL_START, d.readReg(PHASECAL_RESULT_VCSEL_START))
+	w.writeReg(PHASECAL_CONFIG_OVERRIDE, 0x01)
+	if w.err != nil {
+		return w.err
+	}
+	start, err := d.readReg(PHASECAL_RESULT_VCSEL_START)
+	if err != nil {
+		return err
+	}
+	return d.writeReg(CAL_CONFIG_VCSEL_START, start)
 }
 
 // StartContinuous starts the continuous sensing mode
-func (d *Device) StartContinuous(periodMs uint32) {
-	d.writeReg32Bit(SYSTEM_INTERMEASUREMENT_PERIOD, periodMs*uint32(d.oscillatorOffset))
-	d.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01) // sys_interrupt_clear_range
-	d.writeReg(SYSTEM_MODE_START, 0x40)      // mode_range_timed
+func (d *Device) StartContinuous(periodMs uint32) error {
+	w := registerWriter{d: d}
+	w.writeReg32Bit(SYSTEM_INTERMEASUREMENT_PERIOD, periodMs*uint32(d.oscillatorOffset))
+	w.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01) // sys_interrupt_clear_range
+	w.writeReg(SYSTEM_MODE_START, 0x40)      // mode_range_timed
+	return w.err
 }
 
 // StopContinuous stops the continuous sensing mode
-func (d *Device) StopContinuous() {
-	d.writeReg(SYSTEM_MODE_START, 0x80) // mode_range_abort
+func (d *Device) StopContinuous() error {
+	err := d.writeReg(SYSTEM_MODE_START, 0x80) // mode_range_abort
+	if err != nil {
+		return err
+	}
 
 	d.calibrated = false
 
+	w := registerWriter{d: d}
+
 	// restore vhv configs
 	if d.VHVInit != 0 {
-		d.writeReg(VHV_CONFIG_INIT, d.VHVInit)
+		w.writeReg(VHV_CONFIG_INIT, d.VHVInit)
 	}
 	if d.VHVTimeout != 0 {
-		d.writeReg(VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND, d.VHVTimeout)
+		w.writeReg(VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND, d.VHVTimeout)
 	}
 
 	// remove phasecal override
-	d.writeReg(PHASECAL_CONFIG_OVERRIDE, 0x00)
+	w.writeReg(PHASECAL_CONFIG_OVERRIDE, 0x00)
+	return w.err
 }
 
 // SetROI sets the 'region of interest' for x and y coordinates. Valid ranges are from 4/4 to 16/16.
+// Sizes larger than 10 move the center of the region back to the center of
+// the SPAD array, so SetROICenter must be called after SetROI.
 func (d *Device) SetROI(x, y uint8) error {
 	if !validROIRange(x, y) {
-		return errors.New("ROI value out of range")
+		return errROI
 	}
 
 	if x > 10 || y > 10 {
-		d.writeReg(ROI_CONFIG_USER_ROI_CENTRE_SPAD, 199)
+		err := d.writeReg(ROI_CONFIG_USER_ROI_CENTRE_SPAD, 199)
+		if err != nil {
+			return err
+		}
 	}
 
-	d.writeReg(ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE, (y-1)<<4|(x-1))
-	return nil
+	return d.writeReg(ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE, (y-1)<<4|(x-1))
 }
 
 // GetROI returns the currently configured 'region of interest' for x and y coordinates.
 func (d *Device) GetROI() (x, y uint8, err error) {
-	reg := d.readReg(ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE)
+	reg, err := d.readReg(ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE)
+	if err != nil {
+		return 0, 0, err
+	}
 
 	x = (reg & 0x0f) + 1
 	y = ((reg & 0xf0) >> 4) + 1
 
 	if !validROIRange(x, y) {
-		err = errors.New("ROI value out of range")
+		err = errROI
 	}
 
 	return
 }
 
+// SetROICenter moves the center of the 'region of interest' to the SPAD at
+// column x and row y of the 16x16 SPAD array, counted from 0 at the bottom
+// left corner of the SPAD map in the user manual (UM2555). The lens mirrors
+// the field of view on the array. The default center is 8/8, and the whole
+// region must fit in the array.
+func (d *Device) SetROICenter(x, y uint8) error {
+	if x > 15 || y > 15 {
+		return errROI
+	}
+	return d.writeReg(ROI_CONFIG_USER_ROI_CENTRE_SPAD, roiCenterSPAD(x, y))
+}
+
+// GetROICenter returns the column and row of the SPAD at the center of the
+// 'region of interest'.
+func (d *Device) GetROICenter() (x, y uint8, err error) {
+	spad, err := d.readReg(ROI_CONFIG_USER_ROI_CENTRE_SPAD)
+	if err != nil {
+		return 0, 0, err
+	}
+	x, y = roiCenterPosition(spad)
+	return x, y, nil
+}
+
+// roiCenterSPAD returns the number of the SPAD at column x and row y. The
+// SPADs are numbered in columns of 8, the top half of the array from its
+// top left corner and the bottom half from its bottom right corner.
+func roiCenterSPAD(x, y uint8) uint8 {
+	if y > 7 {
+		return 128 + x<<3 + (15 - y)
+	}
+	return (15-x)<<3 + y
+}
+
+// roiCenterPosition is the inverse of roiCenterSPAD.
+func roiCenterPosition(spad uint8) (x, y uint8) {
+	if spad >= 128 {
+		return (spad - 128) >> 3, 15 - (spad & 0x07)
+	}
+	return 15 - spad>>3, spad & 0x07
+}
+
 func validROIRange(x, y uint8) bool {
 	return x >= 4 && x <= 16 && y >= 4 && y <= 16
 }
 
+// registerWriter writes a sequence of registers. It stops at the first
+// error, which is kept in err.
+type registerWriter struct {
+	d   *Device
+	err error
+}
+
+func (w *registerWriter) writeReg(reg uint16, value uint8) {
+	if w.err == nil {
+		w.err = w.d.writeReg(reg, value)
+	}
+}
+
+func (w *registerWriter) writeReg16Bit(reg uint16, value uint16) {
+	if w.err == nil {
+		w.err = w.d.writeReg16Bit(reg, value)
+	}
+}
+
+func (w *registerWriter) writeReg32Bit(reg uint16, value uint32) {
+	if w.err == nil {
+		w.err = w.d.writeReg32Bit(reg, value)
+	}
+}
+
 // writeReg sends a single byte to the specified register address
-func (d *Device) writeReg(reg uint16, value uint8) {
+func (d *Device) writeReg(reg uint16, value uint8) error {
 	msb := byte((reg >> 8) & 0xFF)
 	lsb := byte(reg & 0xFF)
-	d.bus.Tx(d.Address, []byte{msb, lsb, value}, nil)
+	return drivers.NotResponding(d.bus.Tx(d.Address, []byte{msb, lsb, value}, nil))
 }
 
 // writeReg16Bit sends two bytes to the specified register address
-func (d *Device) writeReg16Bit(reg uint16, value uint16) {
+func (d *Device) writeReg16Bit(reg uint16, value uint16) error {
 	data := make([]byte, 4)
 	data[0] = byte((reg >> 8) & 0xFF)
 	data[1] = byte(reg & 0xFF)
 	data[2] = byte((value >> 8) & 0xFF)
 	data[3] = byte(value & 0xFF)
-	d.bus.Tx(d.Address, data, nil)
+	return drivers.NotResponding(d.bus.Tx(d.Address, data, nil))
 }
 
 // writeReg32Bit sends four bytes to the specified register address
-func (d *Device) writeReg32Bit(reg uint16, value uint32) {
+func (d *Device) writeReg32Bit(reg uint16, value uint32) error {
 	data := make([]byte, 6)
 	data[0] = byte((reg >> 8) & 0xFF)
 	data[1] = byte(reg & 0xFF)
@@ -486,36 +769,45 @@ func (d *Device) writeReg32Bit(reg uint16, value uint32) {
 	data[3] = byte((value >> 16) & 0xFF)
 	data[4] = byte((value >> 8) & 0xFF)
 	data[5] = byte(value & 0xFF)
-	d.bus.Tx(d.Address, data, nil)
+	return drivers.NotResponding(d.bus.Tx(d.Address, data, nil))
 }
 
 // readReg reads a single byte from the specified address
-func (d *Device) readReg(reg uint16) uint8 {
+func (d *Device) readReg(reg uint16) (uint8, error) {
 	data := []byte{0}
 	msb := byte((reg >> 8) & 0xFF)
 	lsb := byte(reg & 0xFF)
-	d.bus.Tx(d.Address, []byte{msb, lsb}, data)
-	return data[0]
+	err := d.bus.Tx(d.Address, []byte{msb, lsb}, data)
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return data[0], nil
 }
 
 // readReg16Bit reads two bytes from the specified address
 // and returns it as a uint16
-func (d *Device) readReg16Bit(reg uint16) uint16 {
+func (d *Device) readReg16Bit(reg uint16) (uint16, error) {
 	data := []byte{0, 0}
 	msb := byte((reg >> 8) & 0xFF)
 	lsb := byte(reg & 0xFF)
-	d.bus.Tx(d.Address, []byte{msb, lsb}, data)
-	return readUint(data[0], data[1])
+	err := d.bus.Tx(d.Address, []byte{msb, lsb}, data)
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return readUint(data[0], data[1]), nil
 }
 
 // readReg32Bit reads four bytes from the specified address
 // and returns it as a uint32
-func (d *Device) readReg32Bit(reg uint16) uint32 {
+func (d *Device) readReg32Bit(reg uint16) (uint32, error) {
 	data := make([]byte, 4)
 	msb := byte((reg >> 8) & 0xFF)
 	lsb := byte(reg & 0xFF)
-	d.bus.Tx(d.Address, []byte{msb, lsb}, data)
-	return readUint32(data)
+	err := d.bus.Tx(d.Address, []byte{msb, lsb}, data)
+	if err != nil {
+		return 0, drivers.NotResponding(err)
+	}
+	return readUint32(data), nil
 }
 
 // readUint converts two bytes to uint16
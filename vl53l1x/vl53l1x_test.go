@@ -0,0 +1,370 @@
+package vl53l1x
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+// newRangingDevice returns a device whose result registers hold a
+// complete measurement of 1000mm with the given signal and ambient rates,
+// in 9.7 fixed point.
+func newRangingDevice(c *qt.C, signal, ambient uint16) (Device, *tester.I2CDeviceAddr16) {
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDeviceAddr16(c, Address)
+	result := []uint8{
+		9, 0, 1, // range complete, stream count 1
+		0x01, 0x00, // 1 effective SPAD (8.8 fixed point)
+		0x00, 0x00,
+		uint8(ambient >> 8), uint8(ambient),
+		0x00, 0x00, 0x00, 0x00,
+		0x03, 0xE8, // 1000
+		uint8(signal >> 8), uint8(signal),
+	}
+	for i, b := range result {
+		fake.Registers[RESULT_RANGE_STATUS+uint16(i)] = b
+	}
+	for _, reg := range []uint16{
+		GPIO_TIO_HV_STATUS,
+		VHV_CONFIG_INIT,
+		VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND,
+		PHASECAL_CONFIG_OVERRIDE,
+		PHASECAL_RESULT_VCSEL_START,
+		CAL_CONFIG_VCSEL_START,
+		DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT,
+		DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT + 1,
+		SYSTEM_INTERRUPT_CLEAR,
+	} {
+		fake.Registers[reg] = 0
+	}
+	bus.AddDevice(fake)
+	return New(bus), fake
+}
+
+func TestRead(t *testing.T) {
+	c := qt.New(t)
+	// Signal rate 1 MCPS, ambient rate 0.5 MCPS.
+	dev, fake := newRangingDevice(c, 0x0080, 0x0040)
+	mm, err := dev.Read(true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(mm, qt.Equals, uint16(982))
+	c.Assert(dev.Distance(), qt.Equals, int32(982))
+	c.Assert(dev.Status(), qt.Equals, RangeValid)
+	c.Assert(dev.SignalRate(), qt.Equals, int32(1000000))
+	c.Assert(dev.AmbientRate(), qt.Equals, int32(500000))
+	c.Assert(fake.Registers[DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT], qt.Equals, uint8(0x0D))
+	c.Assert(fake.Registers[DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT+1], qt.Equals, uint8(0x55))
+	c.Assert(fake.Registers[SYSTEM_INTERRUPT_CLEAR], qt.Equals, uint8(0x01))
+
+	got, err := dev.ReadResult(false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, Result{
+		Distance:    982,
+		Status:      RangeValid,
+		SignalRate:  1000000,
+		AmbientRate: 500000,
+		SPADCount:   1,
+	})
+}
+
+func TestReadHighRates(t *testing.T) {
+	c := qt.New(t)
+	// 20 MCPS and the largest rate, 511.99 MCPS, overflow an int32 in µCPS
+	// before the fixed point is removed.
+	dev, _ := newRangingDevice(c, 0x0A00, 0xFFFF)
+	_, err := dev.Read(true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(dev.SignalRate(), qt.Equals, int32(20000000))
+	c.Assert(dev.AmbientRate(), qt.Equals, int32(511992187))
+}
+
+func TestReadTimeout(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDeviceAddr16(c, Address)
+	fake.Registers[GPIO_TIO_HV_STATUS] = 0x01
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.SetTimeout(1)
+	mm, err := dev.Read(true)
+	c.Assert(err, qt.Equals, ErrTimeout)
+	c.Assert(mm, qt.Equals, uint16(0))
+	c.Assert(dev.Status(), qt.Equals, None)
+
+	// With an active high interrupt, the same status means data ready.
+	dev.activeHigh = true
+	ready, err := dev.dataReady()
+	c.Assert(err, qt.IsNil)
+	c.Assert(ready, qt.IsTrue)
+}
+
+func TestConfigureInterrupt(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFake(c, Address)
+	fake.Registers[GPIO_HV_MUX_CTRL] = 0x11
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	err := dev.ConfigureInterrupt(InterruptConfig{
+		Mode:       INTERRUPT_INSIDE,
+		Low:        100,
+		High:       500,
+		NoTarget:   true,
+		ActiveHigh: true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(fake.Registers[SYSTEM_INTERRUPT_CONFIG_GPIO], qt.Equals, uint8(0x43))
+	c.Assert(fake.Registers[GPIO_HV_MUX_CTRL], qt.Equals, uint8(0x01))
+	c.Assert(fake.Registers[SYSTEM_THRESH_HIGH], qt.Equals, uint8(0x01))
+	c.Assert(fake.Registers[SYSTEM_THRESH_HIGH+1], qt.Equals, uint8(0xF4))
+	c.Assert(fake.Registers[SYSTEM_THRESH_LOW], qt.Equals, uint8(0x00))
+	c.Assert(fake.Registers[SYSTEM_THRESH_LOW+1], qt.Equals, uint8(0x64))
+	c.Assert(dev.activeHigh, qt.IsTrue)
+
+	err = dev.ConfigureInterrupt(InterruptConfig{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(fake.Registers[SYSTEM_INTERRUPT_CONFIG_GPIO], qt.Equals, uint8(0x20))
+	c.Assert(fake.Registers[GPIO_HV_MUX_CTRL], qt.Equals, uint8(0x11))
+	c.Assert(dev.activeHigh, qt.IsFalse)
+
+	err = dev.ConfigureInterrupt(InterruptConfig{Mode: INTERRUPT_INSIDE + 1})
+	c.Assert(err, qt.Equals, errInterruptMode)
+}
+
+func TestROICenter(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFake(c, Address)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.SetROICenter(8, 8), qt.IsNil)
+	c.Assert(fake.Registers[ROI_CONFIG_USER_ROI_CENTRE_SPAD], qt.Equals, uint8(199))
+	c.Assert(dev.SetROICenter(16, 0), qt.Equals, errROI)
+
+	seen := map[uint8]bool{}
+	for x := uint8(0); x < 16; x++ {
+		for y := uint8(0); y < 16; y++ {
+			c.Assert(dev.SetROICenter(x, y), qt.IsNil)
+			spad := fake.Registers[ROI_CONFIG_USER_ROI_CENTRE_SPAD]
+			c.Assert(seen[spad], qt.IsFalse)
+			seen[spad] = true
+			gx, gy, err := dev.GetROICenter()
+			c.Assert(err, qt.IsNil)
+			c.Assert([]uint8{gx, gy}, qt.DeepEquals, []uint8{x, y})
+		}
+	}
+	c.Assert(roiCenterSPAD(0, 15), qt.Equals, uint8(128))
+	c.Assert(roiCenterSPAD(15, 0), qt.Equals, uint8(0))
+}
+
+func TestProvision(t *testing.T) {
+	c := qt.New(t)
+	bus := &xshutBus{}
+	var xshut []drivers.Pin
+	for i := 0; i < 3; i++ {
+		pin := tester.NewPin()
+		bus.sensors = append(bus.sensors, newFake(c, Address))
+		bus.xshut = append(bus.xshut, pin)
+		xshut = append(xshut, pin)
+	}
+
+	devices, err := Provision(bus, xshut, []uint8{0x30, 0x31, Address}, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(devices, qt.HasLen, 3)
+	for i, address := range []uint8{0x30, 0x31, Address} {
+		c.Assert(devices[i].GetAddress(), qt.Equals, address)
+		c.Assert(bus.sensors[i].Registers[I2C_SLAVE_DEVICE_ADDRESS], qt.Equals, address)
+		c.Assert(bus.sensors[i].Registers[PAD_I2C_HV_EXTSUP_CONFIG], qt.Equals, uint8(0x01))
+		c.Assert(bus.xshut[i].Get(), qt.IsTrue)
+		c.Assert(bus.xshut[i].Levels(), qt.DeepEquals, []bool{true})
+	}
+
+	// A sensor that is not found is reported by the index of the error.
+	for _, sensor := range bus.sensors {
+		sensor.Registers[I2C_SLAVE_DEVICE_ADDRESS] = Address
+	}
+	bus.sensors[1].Registers[WHO_AM_I] = 0
+	devices, err = Provision(bus, xshut, []uint8{0x30, 0x31, 0x32}, false)
+	c.Assert(err, qt.Equals, errNotConnected)
+	c.Assert(devices, qt.HasLen, 1)
+
+	for _, addresses := range [][]uint8{
+		{0x30, 0x31},
+		{0x30, 0x30, 0x31},
+		{Address, 0x30, 0x31},
+	} {
+		_, err = Provision(bus, xshut, addresses, false)
+		c.Assert(err, qt.Equals, errAddresses)
+	}
+}
+
+func TestConfigureError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := newFake(c, Address)
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Configure(false), qt.IsNil)
+	budget, err := dev.GetMeasurementTimingBudget()
+	c.Assert(err, qt.IsNil)
+	c.Assert(budget > 49000 && budget < 51000, qt.IsTrue, qt.Commentf("budget %d", budget))
+	c.Assert(dev.SetDistanceMode(LONG+1), qt.Equals, errDistanceMode)
+	c.Assert(dev.SetMeasurementTimingBudget(TIMING_GUARD), qt.Equals, errBudget)
+	c.Assert(dev.SetMeasurementTimingBudget(2000000), qt.Equals, errBudget)
+
+	fake.Registers[FIRMWARE_SYSTEM_STATUS] = 0
+	dev.SetTimeout(1)
+	c.Assert(dev.Configure(false), qt.Equals, ErrTimeout)
+
+	busErr := errors.New("bus error")
+	fake.Err = busErr
+	c.Assert(dev.Configure(false), qt.Equals, errNotConnected)
+	err = dev.SetAddress(0x30)
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	c.Assert(dev.GetAddress(), qt.Equals, uint8(Address))
+}
+
+func TestMeasurementTimingBudget(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.AddDevice(newFake(c, Address))
+	dev := New(bus)
+	c.Assert(dev.Configure(false), qt.IsNil)
+
+	// The budget read back is the budget set, including the timing guard,
+	// up to the resolution of the timeout registers.
+	for _, mode := range []DistanceMode{SHORT, MEDIUM, LONG} {
+		c.Assert(dev.SetDistanceMode(mode), qt.IsNil)
+		for _, budget := range []uint32{20000, 33000, 100000, 500000} {
+			c.Assert(dev.SetMeasurementTimingBudget(budget), qt.IsNil)
+			got, err := dev.GetMeasurementTimingBudget()
+			c.Assert(err, qt.IsNil)
+			diff := int64(got) - int64(budget)
+			c.Assert(diff > -int64(budget)/50 && diff < int64(budget)/50, qt.IsTrue,
+				qt.Commentf("mode %d: budget %d, got %d", mode, budget, got))
+		}
+	}
+}
+
+// newFake returns a mock device that boots and supports Configure.
+func newFake(c *qt.C, addr uint8) *tester.I2CDeviceAddr16 {
+	fake := tester.NewI2CDeviceAddr16(c, addr)
+	for reg := uint16(0); reg <= WHO_AM_I+1; reg++ {
+		fake.Registers[reg] = 0
+	}
+	fake.Registers[I2C_SLAVE_DEVICE_ADDRESS] = addr
+	fake.Registers[FIRMWARE_SYSTEM_STATUS] = 0x01
+	fake.Registers[OSC_MEASURED_FAST_OSC_FREQUENCY] = 0xB0
+	fake.Registers[WHO_AM_I] = CHIP_ID >> 8
+	fake.Registers[WHO_AM_I+1] = CHIP_ID & 0xFF
+	return fake
+}
+
+// xshutBus is a bus of sensors with their XSHUT pins. Transfers go to the
+// powered sensor at the address it was given.
+type xshutBus struct {
+	sensors []*tester.I2CDeviceAddr16
+	xshut   []*tester.Pin
+}
+
+func (b *xshutBus) ReadRegister(addr uint8, r uint8, buf []byte) error {
+	return b.Tx(uint16(addr), []byte{0, r}, buf)
+}
+
+func (b *xshutBus) WriteRegister(addr uint8, r uint8, buf []byte) error {
+	return b.Tx(uint16(addr), append([]byte{0, r}, buf...), nil)
+}
+
+func (b *xshutBus) Tx(addr uint16, w, r []byte) error {
+	var found *tester.I2CDeviceAddr16
+	for i, sensor := range b.sensors {
+		if !b.xshut[i].Get() || uint16(sensor.Registers[I2C_SLAVE_DEVICE_ADDRESS]) != addr {
+			continue
+		}
+		if found != nil {
+			return errors.New("address conflict")
+		}
+		found = sensor
+	}
+	if found == nil {
+		return errors.New("nack")
+	}
+	return found.Tx(w, r)
+}
+
+func TestReadError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDeviceAddr16(c, Address)
+	busErr := errors.New("bus error")
+	fake.Err = busErr
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	c.Assert(dev.Connected(), qt.IsFalse)
+	for _, blocking := range []bool{true, false} {
+		_, err := dev.Read(blocking)
+		c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+		c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	}
+	_, _, err := dev.GetROI()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+	_, err = dev.GetMeasurementTimingBudget()
+	c.Assert(errors.Is(err, drivers.ErrNotResponding), qt.IsTrue)
+}
+
+// failingBus fails the writes to a register and records the registers
+// written.
+type failingBus struct {
+	drivers.I2C
+	fail   uint16
+	err    error
+	writes []uint16
+}
+
+func (b *failingBus) Tx(addr uint16, w, r []byte) error {
+	if len(w) > 2 {
+		reg := uint16(w[0])<<8 | uint16(w[1])
+		b.writes = append(b.writes, reg)
+		if reg == b.fail {
+			return b.err
+		}
+	}
+	return b.I2C.Tx(addr, w, r)
+}
+
+func TestConfigureWriteError(t *testing.T) {
+	c := qt.New(t)
+	busErr := errors.New("bus error")
+	for _, reg := range []uint16{SOFT_RESET, SIGMA_ESTIMATOR_EFFECTIVE_PULSE_WIDTH_NS, RANGE_CONFIG_VCSEL_PERIOD_B} {
+		bus := tester.NewI2CBus(c)
+		bus.AddDevice(newFake(c, Address))
+		failing := &failingBus{I2C: bus, fail: reg, err: busErr}
+
+		dev := New(failing)
+		err := dev.Configure(false)
+		c.Assert(errors.Is(err, busErr), qt.IsTrue, qt.Commentf("register %#x", reg))
+		// Nothing is written after the failed write.
+		c.Assert(failing.writes[len(failing.writes)-1], qt.Equals, reg)
+	}
+
+	bus := tester.NewI2CBus(c)
+	fake := newFake(c, Address)
+	bus.AddDevice(fake)
+	failing := &failingBus{I2C: bus, fail: VHV_CONFIG_INIT, err: busErr}
+	dev := New(failing)
+	c.Assert(dev.Configure(false), qt.IsNil)
+	c.Assert(errors.Is(dev.setupManualCalibration(), busErr), qt.IsTrue)
+	c.Assert(failing.writes[len(failing.writes)-1], qt.Equals, uint16(VHV_CONFIG_INIT))
+	dev.VHVInit = 0x80
+	err := dev.StopContinuous()
+	c.Assert(errors.Is(err, busErr), qt.IsTrue)
+	c.Assert(failing.writes[len(failing.writes)-1], qt.Equals, uint16(VHV_CONFIG_INIT))
+}